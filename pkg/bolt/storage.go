@@ -4,10 +4,14 @@ package bolt
 
 import (
 	"bytes"
+	"errors"
+	"fmt"
+	"time"
 
 	"github.com/d8a-tech/d8a/pkg/storage"
 	"github.com/d8a-tech/d8a/pkg/util"
 	bolt "go.etcd.io/bbolt"
+	berrors "go.etcd.io/bbolt/errors"
 )
 
 const (
@@ -283,9 +287,31 @@ func (b *boltSet) Delete(key, value []byte) error {
 	})
 }
 
+// ErrLocked is returned when the database is locked by another process for longer than
+// the open timeout.
+var ErrLocked = errors.New("database is locked by another process")
+
+// KVOption configures the BoltDB-backed KV.
+type KVOption func(*bolt.Options)
+
+// WithOpenTimeout makes opening the database fail with ErrLocked if another process holds
+// its lock for longer than the timeout, instead of waiting for it forever.
+func WithOpenTimeout(timeout time.Duration) KVOption {
+	return func(o *bolt.Options) {
+		o.Timeout = timeout
+	}
+}
+
 // NewBoltKV creates a new KV implementation using BoltDB
-func NewBoltKV(dbPath string) (storage.KV, error) {
-	db, err := bolt.Open(dbPath, 0o600, nil)
+func NewBoltKV(dbPath string, opts ...KVOption) (storage.KV, error) {
+	options := *bolt.DefaultOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	db, err := bolt.Open(dbPath, 0o600, &options)
+	if errors.Is(err, berrors.ErrTimeout) {
+		return nil, fmt.Errorf("%s: %w", dbPath, ErrLocked)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -1,8 +1,11 @@
 package bolt
 
 import (
+	"errors"
 	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/d8a-tech/d8a/pkg/storage"
 	"github.com/sirupsen/logrus"
@@ -57,3 +60,21 @@ func TestBoltSet(t *testing.T) {
 	// Run the Set test suite
 	storage.SetTestSuite(t, set)
 }
+
+func TestBoltKV_OpenTimeoutWhenLocked(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "locked.db")
+	kv, err := NewBoltKV(dbPath)
+	if err != nil {
+		t.Fatalf("Failed to create BoltKV: %v", err)
+	}
+	defer func() {
+		if err := kv.(*boltKV).db.Close(); err != nil {
+			logrus.Error("failed to close test database: ", err)
+		}
+	}()
+
+	_, err = NewBoltKV(dbPath, WithOpenTimeout(50*time.Millisecond))
+	if !errors.Is(err, ErrLocked) {
+		t.Fatalf("Expected ErrLocked when the database is locked, got: %v", err)
+	}
+}
@@ -0,0 +1,170 @@
+// Package botdetection classifies tracking requests as bot traffic, based on the user
+// agent and the IP address they were sent from.
+package botdetection
+
+import (
+	"fmt"
+	"net/netip"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/archbottle/dd2/pkg/bots"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/dgraph-io/ristretto/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const (
+	// IsBotMetadataKey is the hit metadata key telling whether the hit was sent by a bot,
+	// "true" or "false". It's not set when bots aren't detected for the property.
+	IsBotMetadataKey = "is_bot"
+	// TrafficFilterNameMetadataKey is the hit metadata key holding the name of the bot
+	// filter which matched the hit.
+	TrafficFilterNameMetadataKey = "traffic_filter_name"
+)
+
+// Detector tells whether a request was sent by a bot.
+type Detector interface {
+	// Detect returns the name of what matched the request, or false if it doesn't
+	// look like bot traffic.
+	Detect(request *hits.ParsedRequest) (string, bool)
+}
+
+type multiDetector struct {
+	detectors []Detector
+}
+
+func (d *multiDetector) Detect(request *hits.ParsedRequest) (string, bool) {
+	for _, detector := range d.detectors {
+		if name, ok := detector.Detect(request); ok {
+			return name, true
+		}
+	}
+	return "", false
+}
+
+// NewMultiDetector creates a detector returning the first match of the given detectors.
+func NewMultiDetector(detectors ...Detector) Detector {
+	return &multiDetector{detectors: detectors}
+}
+
+type deviceDetector struct {
+	once    sync.Once
+	factory *bots.ParserFactory
+	cache   *ristretto.Cache[string, string]
+}
+
+// NewDeviceDetector creates a detector matching user agents against the bot database of
+// the dd2 device detector, the one behind the device columns. The database is loaded on
+// first use.
+func NewDeviceDetector() Detector {
+	return &deviceDetector{}
+}
+
+func (d *deviceDetector) init() {
+	factory, err := bots.NewParserFactory()
+	if err != nil {
+		logrus.Errorf("failed to load bot database, bots won't be detected by user agent: %v", err)
+		return
+	}
+	cache, err := ristretto.NewCache(&ristretto.Config[string, string]{
+		NumCounters: 100000,
+		MaxCost:     10000,
+		BufferItems: 64,
+	})
+	if err != nil {
+		logrus.Errorf("failed to create bot detection cache: %v", err)
+		return
+	}
+	d.factory = factory
+	d.cache = cache
+}
+
+func (d *deviceDetector) Detect(request *hits.ParsedRequest) (string, bool) {
+	d.once.Do(d.init)
+	if d.factory == nil {
+		return "", false
+	}
+	ua := request.Headers.Get("User-Agent")
+	if ua == "" {
+		return "", false
+	}
+	// Non-bots are cached as an empty name
+	if name, ok := d.cache.Get(ua); ok {
+		return name, name != ""
+	}
+	name := ""
+	if match := d.factory.Parse(ua); match != nil {
+		name = match.Name
+		if name == "" {
+			name = "Generic Bot"
+		}
+	}
+	d.cache.SetWithTTL(ua, name, 1, 5*time.Minute)
+	return name, name != ""
+}
+
+type userAgentPatternDetector struct {
+	patterns []*regexp.Regexp
+}
+
+// NewUserAgentPatternDetector creates a detector matching user agents against the given
+// regular expressions, case-insensitively. The pattern is the name of the match.
+func NewUserAgentPatternDetector(patterns []string) (Detector, error) {
+	compiled := make([]*regexp.Regexp, 0, len(patterns))
+	for _, pattern := range patterns {
+		re, err := regexp.Compile("(?i)" + pattern)
+		if err != nil {
+			return nil, fmt.Errorf("invalid bot user agent pattern %q: %w", pattern, err)
+		}
+		compiled = append(compiled, re)
+	}
+	return &userAgentPatternDetector{patterns: compiled}, nil
+}
+
+func (d *userAgentPatternDetector) Detect(request *hits.ParsedRequest) (string, bool) {
+	ua := request.Headers.Get("User-Agent")
+	for _, re := range d.patterns {
+		if re.MatchString(ua) {
+			return strings.TrimPrefix(re.String(), "(?i)"), true
+		}
+	}
+	return "", false
+}
+
+type ipRangeDetector struct {
+	prefixes []netip.Prefix
+}
+
+// NewIPRangeDetector creates a detector matching request IPs against the given CIDR
+// ranges, e.g. the published ranges of cloud providers. Matches are named after the range.
+func NewIPRangeDetector(cidrs []string) (Detector, error) {
+	prefixes := make([]netip.Prefix, 0, len(cidrs))
+	for _, cidr := range cidrs {
+		prefix, err := netip.ParsePrefix(strings.TrimSpace(cidr))
+		if err != nil {
+			return nil, fmt.Errorf("invalid bot IP range %q: %w", cidr, err)
+		}
+		prefixes = append(prefixes, prefix.Masked())
+	}
+	return &ipRangeDetector{prefixes: prefixes}, nil
+}
+
+func (d *ipRangeDetector) Detect(request *hits.ParsedRequest) (string, bool) {
+	if len(d.prefixes) == 0 {
+		return "", false
+	}
+	addr, err := netip.ParseAddr(request.IP)
+	if err != nil {
+		return "", false
+	}
+	addr = addr.Unmap()
+	for _, prefix := range d.prefixes {
+		if prefix.Contains(addr) {
+			return "datacenter " + prefix.String(), true
+		}
+	}
+	return "", false
+}
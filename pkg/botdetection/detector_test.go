@@ -0,0 +1,90 @@
+package botdetection
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func testRequest(ip, userAgent string) *hits.ParsedRequest {
+	return &hits.ParsedRequest{IP: ip, Headers: http.Header{"User-Agent": {userAgent}}}
+}
+
+const chromeUserAgent = "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 " +
+	"(KHTML, like Gecko) Chrome/124.0.0.0 Safari/537.36"
+
+func TestDetectors(t *testing.T) {
+	userAgentPatterns, err := NewUserAgentPatternDetector([]string{"headlesschrome", `^curl/`})
+	require.NoError(t, err)
+	ipRanges, err := NewIPRangeDetector([]string{"34.64.0.0/10", "2600:1f00::/24"})
+	require.NoError(t, err)
+	detector := NewMultiDetector(ipRanges, userAgentPatterns, NewDeviceDetector())
+
+	testCases := []struct {
+		name         string
+		request      *hits.ParsedRequest
+		expectedName string
+		expectedBot  bool
+	}{
+		{
+			name:    "browser",
+			request: testRequest("10.0.0.1", chromeUserAgent),
+		},
+		{
+			name: "known crawler",
+			request: testRequest("10.0.0.1",
+				"Mozilla/5.0 (compatible; Googlebot/2.1; +http://www.google.com/bot.html)"),
+			expectedName: "Googlebot",
+			expectedBot:  true,
+		},
+		{
+			name: "user agent pattern",
+			request: testRequest("10.0.0.1",
+				"Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) HeadlessChrome/124.0.0.0"),
+			expectedName: "headlesschrome",
+			expectedBot:  true,
+		},
+		{
+			name:         "datacenter ipv4",
+			request:      testRequest("34.80.1.2", chromeUserAgent),
+			expectedName: "datacenter 34.64.0.0/10",
+			expectedBot:  true,
+		},
+		{
+			name:         "datacenter ipv6",
+			request:      testRequest("2600:1f00::1", chromeUserAgent),
+			expectedName: "datacenter 2600:1f00::/24",
+			expectedBot:  true,
+		},
+		{
+			name:    "unparsable ip",
+			request: testRequest("unknown", chromeUserAgent),
+		},
+		{
+			name:    "no user agent",
+			request: &hits.ParsedRequest{IP: "10.0.0.1", Headers: http.Header{}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			name, isBot := detector.Detect(tc.request)
+
+			// then
+			assert.Equal(t, tc.expectedBot, isBot)
+			assert.Equal(t, tc.expectedName, name)
+		})
+	}
+}
+
+func TestNewDetectorsRejectInvalidConfig(t *testing.T) {
+	_, err := NewUserAgentPatternDetector([]string{"("})
+	assert.Error(t, err)
+
+	_, err = NewIPRangeDetector([]string{"10.0.0.0/33"})
+	assert.Error(t, err)
+}
@@ -2,19 +2,24 @@ package cmd
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
 	"time"
 
 	"github.com/d8a-tech/d8a/pkg/monitoring"
+	"github.com/d8a-tech/d8a/pkg/receiver"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 )
 
 type bootstrapResult struct {
 	cleanup func(context.Context)
+	// receiverOptions are the options of the receiver server of the command, if it has one.
+	receiverOptions []receiver.ServerOption
 }
 
 func bootstrap(
@@ -23,6 +28,10 @@ func bootstrap(
 	commandName string,
 	cmd *cli.Command,
 ) (*bootstrapResult, error) {
+	var metricsOptions []monitoring.MetricsOption
+	if cmd.Bool(monitoringPrometheusEnabledFlag.Name) {
+		metricsOptions = append(metricsOptions, monitoring.WithPrometheusExporter())
+	}
 	metricsSetup, err := monitoring.SetupMetrics(
 		ctx,
 		cmd.Bool(monitoringEnabledFlag.Name),
@@ -31,11 +40,27 @@ func bootstrap(
 		cmd.Bool(monitoringOTelInsecureFlag.Name),
 		"d8a",
 		"1.0.0",
+		metricsOptions...,
 	)
 	if err != nil {
 		return nil, fmt.Errorf("failed to setup metrics: %w", err)
 	}
 
+	var receiverOptions []receiver.ServerOption
+	var metricsServer *http.Server
+	if handler := metricsSetup.Handler(); handler != nil {
+		address := cmd.String(monitoringPrometheusAddressFlag.Name)
+		if address == "" {
+			if commandName == "worker" {
+				logrus.Warnf("%s has no receiver port to serve /metrics on, set %s",
+					commandName, monitoringPrometheusAddressFlag.Name)
+			}
+			receiverOptions = append(receiverOptions, receiver.WithMetricsHandler(handler))
+		} else {
+			metricsServer = startMetricsServer(address, handler)
+		}
+	}
+
 	startTelemetry(commandName, cmd.String(telemetryURLFlag.Name))
 
 	sigChan := make(chan os.Signal, 1)
@@ -46,13 +71,39 @@ func bootstrap(
 		cancel()
 	}()
 
+	watchPropertySettings(ctx, cmd)
+
 	cleanup := func(ctx context.Context) {
 		shutdownCtx, shutdownCancel := context.WithTimeout(ctx, 5*time.Second)
 		defer shutdownCancel()
+		if metricsServer != nil {
+			if err := metricsServer.Shutdown(shutdownCtx); err != nil {
+				logrus.Errorf("error shutting down metrics server: %v", err)
+			}
+		}
 		if err := metricsSetup.Shutdown(shutdownCtx); err != nil {
 			logrus.Errorf("error shutting down metrics: %v", err)
 		}
 	}
 
-	return &bootstrapResult{cleanup: cleanup}, nil
+	return &bootstrapResult{cleanup: cleanup, receiverOptions: receiverOptions}, nil
+}
+
+// startMetricsServer serves the metrics handler on /metrics of a separate HTTP server, for
+// metrics to be scraped from an admin port not exposed to the public like the receiver.
+func startMetricsServer(address string, handler http.Handler) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", handler)
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		logrus.Infof("serving Prometheus metrics on %s/metrics", address)
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logrus.Errorf("metrics server error: %v", err)
+		}
+	}()
+	return server
 }
@@ -0,0 +1,27 @@
+package cmd
+
+import (
+	"github.com/d8a-tech/d8a/pkg/botdetection"
+	"github.com/urfave/cli/v3"
+)
+
+// buildBotDetector creates the detector classifying hits of properties whose bot filter
+// mode is tag or drop, combining the device detector bot database with the configured
+// user agent patterns and IP ranges.
+func buildBotDetector(cmd *cli.Command) (botdetection.Detector, error) {
+	userAgentPatterns, err := botdetection.NewUserAgentPatternDetector(
+		cmd.StringSlice(botDetectionUserAgentPatternsFlag.Name),
+	)
+	if err != nil {
+		return nil, err
+	}
+	ipRanges, err := botdetection.NewIPRangeDetector(cmd.StringSlice(botDetectionIPRangesFlag.Name))
+	if err != nil {
+		return nil, err
+	}
+	return botdetection.NewMultiDetector(
+		ipRanges,
+		userAgentPatterns,
+		botdetection.NewDeviceDetector(),
+	), nil
+}
@@ -53,10 +53,7 @@ func migrate(
 		),
 		schema.NewStaticLayoutRegistry(
 			map[string]schema.Layout{},
-			schema.NewEmbeddedSessionColumnsLayout(
-				getTableNames(cmd).events,
-				getTableNames(cmd).sessionsColumnPrefix,
-			),
+			tableLayout(cmd),
 		),
 		schema.NewInterfaceDefinitionOrderKeeper(
 			columns.CoreInterfaces,
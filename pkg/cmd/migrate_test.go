@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"database/sql"
+	"net"
+	"testing"
+
+	"github.com/ClickHouse/clickhouse-go/v2"
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/currency"
+	"github.com/d8a-tech/d8a/pkg/dbip"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/testcontainers/testcontainers-go"
+	tcclickhouse "github.com/testcontainers/testcontainers-go/modules/clickhouse"
+	"github.com/urfave/cli/v3"
+)
+
+func TestTableLayout_SeparateSessionsTablesHaveTheDefaultHintColumns(t *testing.T) {
+	app := &cli.Command{
+		Name:  "d8a-test",
+		Flags: getServerFlags(),
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			// given
+			columnData, err := columnsRegistry(
+				cmd, currency.NewDummyConverter(1), dbip.NewUnavailableLookupProvider(),
+			).Get(cmd.String(propertyIDFlag.Name))
+			require.NoError(t, err)
+
+			// when
+			tables := tableLayout(cmd).Tables(columnData)
+
+			// then
+			require.Len(t, tables, 2)
+			for _, table := range tables {
+				// The default ClickHouse ORDER BY and PARTITION BY, and the BigQuery and
+				// PostgreSQL partition fields reference these columns
+				for _, name := range []string{"property_id", "date_utc", "session_id"} {
+					_, found := table.Schema.FieldsByName(name)
+					assert.True(t, found, "table %s should have the %s column", table.Table, name)
+				}
+				dateFields, _ := table.Schema.FieldsByName("date_utc")
+				require.NotEmpty(t, dateFields)
+				assert.Equal(t, arrow.FixedWidthTypes.Date32, dateFields[0].Type)
+			}
+			return nil
+		},
+	}
+
+	require.NoError(t, app.Run(context.Background(), []string{"d8a-test", "--warehouse-layout=separate-sessions"}))
+}
+
+func TestMigrate_SeparateSessionsLayoutWithClickHouseDefaults(t *testing.T) {
+	ctx := context.Background()
+
+	container, err := tcclickhouse.Run(ctx,
+		"clickhouse/clickhouse-server:23.3.8.21-alpine",
+		tcclickhouse.WithUsername("clickhouse"),
+		tcclickhouse.WithPassword("password"),
+		tcclickhouse.WithDatabase("d8a"),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		_ = testcontainers.TerminateContainer(container)
+	})
+
+	address, err := container.ConnectionHost(ctx)
+	require.NoError(t, err)
+	host, port, err := net.SplitHostPort(address)
+	require.NoError(t, err)
+
+	app := &cli.Command{
+		Name:  "d8a-test",
+		Flags: getServerFlags(),
+		Action: func(actionCtx context.Context, cmd *cli.Command) error {
+			whr := warehouseRegistry(actionCtx, cmd)
+			defer func() { _ = whr.Close() }()
+			return migrate(
+				actionCtx, cmd, cmd.String(propertyIDFlag.Name), whr,
+				currency.NewDummyConverter(1), dbip.NewUnavailableLookupProvider(),
+			)
+		},
+	}
+
+	// when: the order by and partition by flags keep their defaults
+	require.NoError(t, app.Run(ctx, []string{
+		"d8a-test",
+		"--warehouse-driver=clickhouse",
+		"--warehouse-clickhouse-host=" + host,
+		"--warehouse-clickhouse-port=" + port,
+		"--warehouse-clickhouse-database=d8a",
+		"--warehouse-clickhouse-username=clickhouse",
+		"--warehouse-clickhouse-password=password",
+		"--warehouse-layout=separate-sessions",
+	}))
+
+	// then
+	db := clickhouse.OpenDB(&clickhouse.Options{
+		Addr: []string{address},
+		Auth: clickhouse.Auth{Database: "d8a", Username: "clickhouse", Password: "password"},
+	})
+	defer func() { _ = db.Close() }()
+	for _, table := range []string{"events", "sessions"} {
+		var sortingKey, partitionKey string
+		err := db.QueryRowContext(ctx,
+			"SELECT sorting_key, partition_key FROM system.tables WHERE database = 'd8a' AND name = ?", table,
+		).Scan(&sortingKey, &partitionKey)
+		require.NotErrorIs(t, err, sql.ErrNoRows, "table %s should be created", table)
+		require.NoError(t, err)
+		assert.Equal(t, "property_id, date_utc, session_id", sortingKey)
+		assert.Equal(t, "toYYYYMM(date_utc)", partitionKey)
+	}
+}
@@ -0,0 +1,690 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/splitter"
+	"github.com/fsnotify/fsnotify"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+	"gopkg.in/yaml.v3"
+)
+
+// propertyFileConfig is a single entry of the `properties` list in the YAML config.
+// Every unset field inherits the value built from flags / top-level config keys.
+type propertyFileConfig struct {
+	ID            string                     `yaml:"id"`
+	Name          string                     `yaml:"name"`
+	MeasurementID string                     `yaml:"measurement_id"`
+	Protocol      string                     `yaml:"protocol"`
+	Settings      propertySettingsFileConfig `yaml:"settings"`
+	Sessions      propertySessionsFileConfig `yaml:"sessions"`
+	Filters       *properties.FiltersConfig  `yaml:"filters"`
+	TrackingPlan  *properties.TrackingPlan   `yaml:"tracking_plan"`
+	GA4           ga4PropertyFileConfig      `yaml:"ga4"`
+	Matomo        matomoCustomColumnsConfig  `yaml:"matomo"`
+}
+
+type ga4PropertyFileConfig struct {
+	ga4CustomColumnsConfig `yaml:",inline"`
+	APISecrets             []string `yaml:"api_secrets"`
+	ParamsMode             *string  `yaml:"params_mode"`
+}
+
+type propertySettingsFileConfig struct {
+	SplitByUserID              *bool                    `yaml:"split_by_user_id"`
+	SplitByCampaign            *bool                    `yaml:"split_by_campaign"`
+	SplitByTimeSinceFirstEvent *time.Duration           `yaml:"split_by_time_since_first_event"`
+	SplitByMaxEvents           *int                     `yaml:"split_by_max_events"`
+	IPMaskingLevel             *int                     `yaml:"ip_masking_level"`
+	BotFilterMode              *string                  `yaml:"bot_filter_mode"`
+	ClientIDMode               *string                  `yaml:"client_id_mode"`
+	OptOutMode                 *string                  `yaml:"opt_out_mode"`
+	RateLimits                 *rateLimitsFileConfig    `yaml:"rate_limits"`
+	Deduplication              *deduplicationFileConfig `yaml:"deduplication"`
+	AllowedDomains             []string                 `yaml:"allowed_domains"`
+	AllowedDomainsReportOnly   *bool                    `yaml:"allowed_domains_report_only"`
+	CORS                       *corsFileConfig          `yaml:"cors"`
+	IngestionAuth              *ingestionAuthFileConfig `yaml:"ingestion_auth"`
+	ExcludedURLParams          []string                 `yaml:"excluded_url_params"`
+}
+
+type corsFileConfig struct {
+	AllowedOrigins   []string       `yaml:"allowed_origins"`
+	AllowedHeaders   []string       `yaml:"allowed_headers"`
+	MaxAge           *time.Duration `yaml:"max_age"`
+	AllowCredentials *bool          `yaml:"allow_credentials"`
+}
+
+type deduplicationFileConfig struct {
+	Window       *time.Duration `yaml:"window"`
+	EventIDParam *string        `yaml:"event_id_param"`
+}
+
+type ingestionAuthFileConfig struct {
+	APIKeys     []string `yaml:"api_keys"`
+	HMACSecrets []string `yaml:"hmac_secrets"`
+}
+
+type rateLimitsFileConfig struct {
+	PerIP       *string `yaml:"per_ip"`
+	PerClientID *string `yaml:"per_client_id"`
+	PerProperty *string `yaml:"per_property"`
+	TagOnly     *bool   `yaml:"tag_only"`
+}
+
+type propertySessionsFileConfig struct {
+	Timeout            *time.Duration `yaml:"timeout"`
+	JoinBySessionStamp *bool          `yaml:"join_by_session_stamp"`
+	JoinByUserID       *bool          `yaml:"join_by_user_id"`
+}
+
+func (c *propertyFileConfig) hasCustomColumns() bool {
+	return len(c.GA4.Params) > 0 ||
+		len(c.Matomo.CustomDimensions) > 0 ||
+		len(c.Matomo.CustomVariables) > 0
+}
+
+// parsePropertiesConfig reads the `properties` list from a YAML config file.
+func parsePropertiesConfig(configFilePath string) ([]propertyFileConfig, error) {
+	// nolint:gosec // configFilePath comes from CLI, not user input
+	content, err := os.ReadFile(configFilePath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var rawConfig struct {
+		Properties []propertyFileConfig `yaml:"properties"`
+	}
+	if err := yaml.Unmarshal(content, &rawConfig); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal YAML: %w", err)
+	}
+
+	return rawConfig.Properties, nil
+}
+
+var (
+	psrLock      sync.Mutex
+	psrByCommand = map[*cli.Command]*properties.ReloadingSettingsRegistry{}
+)
+
+// propertySettings returns the property settings registry of the command. When the config
+// file declares a `properties` list, each entry is registered and lookups of unknown property
+// or measurement IDs fail. Otherwise a single property built from flags serves as the default.
+// The registry is shared by all components of the command, so they all see its reloads.
+func propertySettings(cmd *cli.Command) *properties.ReloadingSettingsRegistry {
+	psrLock.Lock()
+	defer psrLock.Unlock()
+	if registry, ok := psrByCommand[cmd]; ok {
+		return registry
+	}
+
+	// The receiver serves the endpoints of the protocols used when it starts, so a reload
+	// introducing another protocol would add properties nothing can receive hits for.
+	var servedProtocolIDs map[string]bool
+	registry, err := properties.NewReloadingSettingsRegistry(func() ([]properties.Settings, *properties.Settings, error) {
+		settingsList, fromList, err := loadPropertySettingsList(cmd)
+		if err != nil {
+			return nil, nil, err
+		}
+		if servedProtocolIDs == nil {
+			servedProtocolIDs = make(map[string]bool, len(settingsList))
+			for i := range settingsList {
+				servedProtocolIDs[settingsList[i].ProtocolID] = true
+			}
+		}
+		for i := range settingsList {
+			if !servedProtocolIDs[settingsList[i].ProtocolID] {
+				return nil, nil, fmt.Errorf(
+					"property %q: protocol %q isn't served by this instance, restart it to add the protocol",
+					settingsList[i].PropertyID, settingsList[i].ProtocolID,
+				)
+			}
+		}
+		if !fromList {
+			return nil, &settingsList[0], nil
+		}
+		return settingsList, nil, nil
+	})
+	if err != nil {
+		logrus.Panic(err)
+	}
+	psrByCommand[cmd] = registry
+	return registry
+}
+
+// configuredPropertyIDs returns the IDs of all properties served by this instance.
+func configuredPropertyIDs(cmd *cli.Command) []string {
+	settingsList, _ := propertySettingsList(cmd)
+	ids := make([]string, 0, len(settingsList))
+	for i := range settingsList {
+		ids = append(ids, settingsList[i].PropertyID)
+	}
+	return ids
+}
+
+// propertySettingsList returns settings of all configured properties. The boolean result
+// tells whether they come from the `properties` config list (true) or from flags (false).
+func propertySettingsList(cmd *cli.Command) ([]properties.Settings, bool) {
+	settingsList, fromList, err := loadPropertySettingsList(cmd)
+	if err != nil {
+		logrus.Panicf("invalid property settings: %v", err)
+	}
+	return settingsList, fromList
+}
+
+func loadPropertySettingsList(cmd *cli.Command) ([]properties.Settings, bool, error) {
+	defaults, err := defaultPropertySettings(cmd)
+	if err != nil {
+		return nil, false, err
+	}
+
+	var entries []propertyFileConfig
+	// Config file is optional; stat before parsing
+	if _, err := os.Stat(configFile); err == nil {
+		entries, err = parsePropertiesConfig(configFile)
+		if err != nil {
+			return nil, false, fmt.Errorf("failed to parse properties config: %w", err)
+		}
+	}
+	if len(entries) == 0 {
+		if err := properties.ValidateSettings(defaults); err != nil {
+			return nil, false, err
+		}
+		if err := validatePropertyFilters(defaults); err != nil {
+			return nil, false, err
+		}
+		if err := validateGA4ParamsModeDriver(cmd, defaults); err != nil {
+			return nil, false, err
+		}
+		applyGA4ParamsMode(defaults)
+		return []properties.Settings{*defaults}, false, nil
+	}
+
+	settingsList := make([]properties.Settings, 0, len(entries))
+	for idx := range entries {
+		settings, err := settingsFromPropertyConfig(defaults, &entries[idx], idx)
+		if err != nil {
+			return nil, false, err
+		}
+		if err := validatePropertyFilters(settings); err != nil {
+			return nil, false, fmt.Errorf("property %q: %w", settings.PropertyID, err)
+		}
+		if err := validateGA4ParamsModeDriver(cmd, settings); err != nil {
+			return nil, false, fmt.Errorf("property %q: %w", settings.PropertyID, err)
+		}
+		applyGA4ParamsMode(settings)
+		settingsList = append(settingsList, *settings)
+	}
+	if err := properties.ValidateSettingsList(settingsList); err != nil {
+		return nil, false, err
+	}
+
+	return settingsList, true, nil
+}
+
+// validatePropertyFilters makes sure filter expressions compile, so that a broken
+// expression is rejected when the config is loaded rather than when sessions are written.
+func validatePropertyFilters(settings *properties.Settings) error {
+	if len(settings.FiltersSafe().Conditions) == 0 {
+		return nil
+	}
+	_, err := splitter.NewFilter(settings.FiltersSafe())
+	return err
+}
+
+// validateGA4ParamsModeDriver rejects the params modes writing the params map columns for
+// the warehouse drivers having no map type, which would fail to create the table.
+func validateGA4ParamsModeDriver(cmd *cli.Command, settings *properties.Settings) error {
+	if !settings.GA4ParamsMode.WritesParamsMap() {
+		return nil
+	}
+	switch driver := strings.ToLower(cmd.String(warehouseDriverFlag.Name)); driver {
+	case "postgres", "duckdb":
+		return fmt.Errorf("GA4 params mode %q isn't supported by the %s warehouse driver", settings.GA4ParamsMode, driver)
+	default:
+		return nil
+	}
+}
+
+func defaultPropertySettings(cmd *cli.Command) (*properties.Settings, error) {
+	var filtersConfig properties.FiltersConfig
+	// Config file is optional; stat before parsing
+	if _, err := os.Stat(configFile); err == nil {
+		var parseErr error
+		filtersConfig, parseErr = properties.ParseFilterConfig(configFile)
+		if parseErr != nil {
+			return nil, fmt.Errorf("failed to parse filters config: %w", parseErr)
+		}
+	}
+	// Override fields from YAML with flag value (flag takes precedence)
+	filtersConfig.Fields = cmd.StringSlice(filtersFieldsFlag.Name)
+
+	// Parse and append JSON-encoded conditions from flag/env
+	flagConditions := cmd.StringSlice(filtersConditionsFlag.Name)
+	for _, conditionJSON := range flagConditions {
+		var condition properties.ConditionConfig
+		if err := json.Unmarshal([]byte(conditionJSON), &condition); err != nil {
+			logrus.Warnf("skipping invalid JSON condition %q: %v", conditionJSON, err)
+			continue
+		}
+		filtersConfig.Conditions = append(filtersConfig.Conditions, condition)
+	}
+
+	customColumns, err := loadProtocolCustomColumns(cmd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load protocol custom columns config: %w", err)
+	}
+
+	rateLimits := properties.RateLimitSettings{
+		TagOnly: cmd.Bool(propertySettingsRateLimitTagOnlyFlag.Name),
+	}
+	for _, limit := range []struct {
+		flag   *cli.StringFlag
+		target *properties.RateLimit
+	}{
+		{propertySettingsRateLimitPerIPFlag, &rateLimits.PerIP},
+		{propertySettingsRateLimitPerClientIDFlag, &rateLimits.PerClientID},
+		{propertySettingsRateLimitPerPropertyFlag, &rateLimits.PerProperty},
+	} {
+		if *limit.target, err = parseRateLimit(cmd.String(limit.flag.Name)); err != nil {
+			return nil, fmt.Errorf("--%s: %w", limit.flag.Name, err)
+		}
+	}
+
+	return &properties.Settings{
+		ProtocolID:                 cmd.String(protocolFlag.Name),
+		PropertyID:                 cmd.String(propertyIDFlag.Name),
+		PropertyName:               cmd.String(propertyNameFlag.Name),
+		PropertyMeasurementID:      "-",
+		SplitByUserID:              cmd.Bool(propertySettingsSplitByUserIDFlag.Name),
+		SplitByCampaign:            cmd.Bool(propertySettingsSplitByCampaignFlag.Name),
+		SplitByTimeSinceFirstEvent: cmd.Duration(propertySettingsSplitByTimeSinceFirstEventFlag.Name),
+		SplitByMaxEvents:           cmd.Int(propertySettingsSplitByMaxEventsFlag.Name),
+		ExcludedURLParams:          cmd.StringSlice(propertySettingsExcludedURLParamsFlag.Name),
+		SessionTimeout:             cmd.Duration(sessionsTimeoutFlag.Name),
+		SessionJoinBySessionStamp:  cmd.Bool(sessionsJoinBySessionStampFlag.Name),
+		SessionJoinByUserID:        cmd.Bool(sessionsJoinByUserIDFlag.Name),
+		IPMaskingLevel:             cmd.Int(propertySettingsIPMaskingLevelFlag.Name),
+		BotFilterMode:              properties.BotFilterMode(cmd.String(propertySettingsBotFilterModeFlag.Name)),
+		ClientIDMode:               properties.ClientIDMode(cmd.String(propertySettingsClientIDModeFlag.Name)),
+		OptOutMode:                 properties.OptOutMode(cmd.String(propertySettingsOptOutModeFlag.Name)),
+		RateLimits:                 rateLimits,
+		Deduplication: properties.DeduplicationSettings{
+			Window:       cmd.Duration(propertySettingsDeduplicationWindowFlag.Name),
+			EventIDParam: cmd.String(propertySettingsDeduplicationEventIDParamFlag.Name),
+		},
+		AllowedDomains:                cmd.StringSlice(propertySettingsAllowedDomainsFlag.Name),
+		AllowedDomainsReportOnly:      cmd.Bool(propertySettingsAllowedDomainsReportOnlyFlag.Name),
+		CORS:                          corsSettingsFromFlags(cmd),
+		MeasurementProtocolAPISecrets: cmd.StringSlice(ga4APISecretsFlag.Name),
+		GA4ParamsMode:                 properties.GA4ParamsMode(cmd.String(ga4ParamsModeFlag.Name)),
+		IngestionAuth: properties.IngestionAuthSettings{
+			APIKeys:     cmd.StringSlice(propertySettingsIngestionAPIKeysFlag.Name),
+			HMACSecrets: cmd.StringSlice(propertySettingsIngestionHMACSecretsFlag.Name),
+		},
+		Filters:       &filtersConfig,
+		CustomColumns: customColumns,
+	}, nil
+}
+
+func settingsFromPropertyConfig(
+	defaults *properties.Settings,
+	entry *propertyFileConfig,
+	idx int,
+) (*properties.Settings, error) {
+	pathPrefix := fmt.Sprintf("properties[%d]", idx)
+	if strings.TrimSpace(entry.ID) == "" {
+		return nil, fmt.Errorf("%s.id is required", pathPrefix)
+	}
+	if strings.TrimSpace(entry.MeasurementID) == "" {
+		return nil, fmt.Errorf("%s.measurement_id is required", pathPrefix)
+	}
+
+	settings := *defaults
+	settings.PropertyID = entry.ID
+	settings.PropertyMeasurementID = entry.MeasurementID
+	settings.PropertyName = entry.ID
+	if entry.Name != "" {
+		settings.PropertyName = entry.Name
+	}
+	if entry.Protocol != "" {
+		settings.ProtocolID = entry.Protocol
+	}
+
+	if err := applyPropertySettingsConfig(&settings, &entry.Settings); err != nil {
+		return nil, fmt.Errorf("%s.settings.%w", pathPrefix, err)
+	}
+	applySessionsConfig(&settings, &entry.Sessions)
+	applyGA4Config(&settings, &entry.GA4)
+
+	if entry.Filters != nil {
+		filters := *entry.Filters
+		if len(filters.Fields) == 0 {
+			filters.Fields = defaults.FiltersSafe().Fields
+		}
+		settings.Filters = &filters
+	}
+
+	if entry.TrackingPlan != nil {
+		settings.TrackingPlan = entry.TrackingPlan
+	}
+
+	// Custom columns declared on the property replace the top-level ones entirely,
+	// as shortcuts of one protocol make no sense for a property using another.
+	if entry.hasCustomColumns() {
+		customColumns, err := newProtocolCustomColumnNormalizer(newProtocolCustomColumnValidator()).Normalize(
+			protocolCustomColumnsConfig{GA4: entry.GA4.ga4CustomColumnsConfig, Matomo: entry.Matomo},
+		)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", pathPrefix, err)
+		}
+		settings.CustomColumns = customColumns
+	}
+
+	return &settings, nil
+}
+
+// applyPropertySettingsConfig overrides the settings with the ones set in the `settings`
+// section of a property.
+func applyPropertySettingsConfig(settings *properties.Settings, config *propertySettingsFileConfig) error {
+	if config.SplitByUserID != nil {
+		settings.SplitByUserID = *config.SplitByUserID
+	}
+	if config.SplitByCampaign != nil {
+		settings.SplitByCampaign = *config.SplitByCampaign
+	}
+	if config.SplitByTimeSinceFirstEvent != nil {
+		settings.SplitByTimeSinceFirstEvent = *config.SplitByTimeSinceFirstEvent
+	}
+	if config.SplitByMaxEvents != nil {
+		settings.SplitByMaxEvents = *config.SplitByMaxEvents
+	}
+	if config.IPMaskingLevel != nil {
+		settings.IPMaskingLevel = *config.IPMaskingLevel
+	}
+	if config.BotFilterMode != nil {
+		settings.BotFilterMode = properties.BotFilterMode(*config.BotFilterMode)
+	}
+	if config.ClientIDMode != nil {
+		settings.ClientIDMode = properties.ClientIDMode(*config.ClientIDMode)
+	}
+	if config.OptOutMode != nil {
+		settings.OptOutMode = properties.OptOutMode(*config.OptOutMode)
+	}
+	if config.RateLimits != nil {
+		rateLimits, err := applyRateLimitsConfig(settings.RateLimits, config.RateLimits)
+		if err != nil {
+			return fmt.Errorf("rate_limits.%w", err)
+		}
+		settings.RateLimits = rateLimits
+	}
+	if config.Deduplication != nil {
+		settings.Deduplication = applyDeduplicationConfig(settings.Deduplication, config.Deduplication)
+	}
+	if config.AllowedDomains != nil {
+		settings.AllowedDomains = append([]string(nil), config.AllowedDomains...)
+	}
+	if config.AllowedDomainsReportOnly != nil {
+		settings.AllowedDomainsReportOnly = *config.AllowedDomainsReportOnly
+	}
+	if config.CORS != nil {
+		settings.CORS = applyCORSConfig(settings.CORS, config.CORS)
+	}
+	if config.IngestionAuth != nil {
+		settings.IngestionAuth = applyIngestionAuthConfig(settings.IngestionAuth, config.IngestionAuth)
+	}
+	if config.ExcludedURLParams != nil {
+		settings.ExcludedURLParams = append([]string(nil), config.ExcludedURLParams...)
+	}
+	return nil
+}
+
+// applySessionsConfig overrides the settings with the ones set in the `sessions` section
+// of a property.
+func applySessionsConfig(settings *properties.Settings, config *propertySessionsFileConfig) {
+	if config.Timeout != nil {
+		settings.SessionTimeout = *config.Timeout
+	}
+	if config.JoinBySessionStamp != nil {
+		settings.SessionJoinBySessionStamp = *config.JoinBySessionStamp
+	}
+	if config.JoinByUserID != nil {
+		settings.SessionJoinByUserID = *config.JoinByUserID
+	}
+}
+
+// applyGA4Config overrides the settings with the ones set in the `ga4` section of a
+// property. The custom column shortcuts of the section are handled with the other
+// protocols' ones.
+func applyGA4Config(settings *properties.Settings, config *ga4PropertyFileConfig) {
+	if config.APISecrets != nil {
+		settings.MeasurementProtocolAPISecrets = append([]string(nil), config.APISecrets...)
+	}
+	if config.ParamsMode != nil {
+		settings.GA4ParamsMode = properties.GA4ParamsMode(*config.ParamsMode)
+	}
+}
+
+// applyDeduplicationConfig overrides the given deduplication settings with the ones set
+// in the config.
+func applyDeduplicationConfig(
+	deduplication properties.DeduplicationSettings,
+	config *deduplicationFileConfig,
+) properties.DeduplicationSettings {
+	if config.Window != nil {
+		deduplication.Window = *config.Window
+	}
+	if config.EventIDParam != nil {
+		deduplication.EventIDParam = *config.EventIDParam
+	}
+	return deduplication
+}
+
+// applyIngestionAuthConfig overrides the given ingestion auth settings with the ones set
+// in the config.
+func applyIngestionAuthConfig(
+	auth properties.IngestionAuthSettings,
+	config *ingestionAuthFileConfig,
+) properties.IngestionAuthSettings {
+	if config.APIKeys != nil {
+		auth.APIKeys = append([]string(nil), config.APIKeys...)
+	}
+	if config.HMACSecrets != nil {
+		auth.HMACSecrets = append([]string(nil), config.HMACSecrets...)
+	}
+	return auth
+}
+
+// applyRateLimitsConfig overrides the given rate limits with the ones set in the config.
+func applyRateLimitsConfig(
+	limits properties.RateLimitSettings,
+	config *rateLimitsFileConfig,
+) (properties.RateLimitSettings, error) {
+	for _, limit := range []struct {
+		name   string
+		value  *string
+		target *properties.RateLimit
+	}{
+		{"per_ip", config.PerIP, &limits.PerIP},
+		{"per_client_id", config.PerClientID, &limits.PerClientID},
+		{"per_property", config.PerProperty, &limits.PerProperty},
+	} {
+		if limit.value == nil {
+			continue
+		}
+		parsed, err := parseRateLimit(*limit.value)
+		if err != nil {
+			return limits, fmt.Errorf("%s: %w", limit.name, err)
+		}
+		*limit.target = parsed
+	}
+	if config.TagOnly != nil {
+		limits.TagOnly = *config.TagOnly
+	}
+	return limits, nil
+}
+
+// corsSettingsFromFlags returns the default CORS settings of properties, also used for
+// requests which aren't bound to a property.
+func corsSettingsFromFlags(cmd *cli.Command) *properties.CORSSettings {
+	return &properties.CORSSettings{
+		AllowedOrigins:   cmd.StringSlice(propertySettingsCORSAllowedOriginsFlag.Name),
+		AllowedHeaders:   cmd.StringSlice(propertySettingsCORSAllowedHeadersFlag.Name),
+		MaxAge:           cmd.Duration(propertySettingsCORSMaxAgeFlag.Name),
+		AllowCredentials: cmd.Bool(propertySettingsCORSAllowCredentialsFlag.Name),
+	}
+}
+
+// applyCORSConfig returns a copy of the given CORS settings, overridden with the ones set
+// in the config.
+func applyCORSConfig(defaults *properties.CORSSettings, config *corsFileConfig) *properties.CORSSettings {
+	cors := properties.CORSSettings{}
+	if defaults != nil {
+		cors = *defaults
+	}
+	if config.AllowedOrigins != nil {
+		cors.AllowedOrigins = append([]string(nil), config.AllowedOrigins...)
+	}
+	if config.AllowedHeaders != nil {
+		cors.AllowedHeaders = append([]string(nil), config.AllowedHeaders...)
+	}
+	if config.MaxAge != nil {
+		cors.MaxAge = *config.MaxAge
+	}
+	if config.AllowCredentials != nil {
+		cors.AllowCredentials = *config.AllowCredentials
+	}
+	return &cors
+}
+
+// parseRateLimit parses a rate limit written as <hits per second>:<burst>, e.g. 10:50.
+// An empty value is no limit.
+func parseRateLimit(value string) (properties.RateLimit, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return properties.RateLimit{}, nil
+	}
+	ratePart, burstPart, ok := strings.Cut(value, ":")
+	if !ok {
+		return properties.RateLimit{}, fmt.Errorf("rate limit must be <hits per second>:<burst>: %q", value)
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSpace(ratePart), 64)
+	if err != nil {
+		return properties.RateLimit{}, fmt.Errorf("invalid rate limit rate %q: %w", ratePart, err)
+	}
+	burst, err := strconv.Atoi(strings.TrimSpace(burstPart))
+	if err != nil {
+		return properties.RateLimit{}, fmt.Errorf("invalid rate limit burst %q: %w", burstPart, err)
+	}
+	return properties.RateLimit{Rate: rate, Burst: burst}, nil
+}
+
+// configWatchDebounce coalesces the bursts of file events written by a single save.
+const configWatchDebounce = 100 * time.Millisecond
+
+// watchPropertySettings starts reloading property settings when the config file changes
+// or the process receives SIGHUP, until the context is done.
+func watchPropertySettings(ctx context.Context, cmd *cli.Command) {
+	registry := propertySettings(cmd)
+	sighup := make(chan os.Signal, 1)
+	signal.Notify(sighup, syscall.SIGHUP)
+
+	var changes <-chan struct{}
+	if cmd.Bool(propertiesWatchConfigFlag.Name) && configFile != "" {
+		watched, err := watchConfigFile(ctx, configFile)
+		if err != nil {
+			logrus.Errorf("failed to watch config file, property settings reload on SIGHUP only: %v", err)
+		}
+		changes = watched
+	}
+
+	go func() {
+		defer signal.Stop(sighup)
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-sighup:
+				logrus.Info("received SIGHUP, reloading property settings")
+			case <-changes:
+				logrus.Info("config file changed, reloading property settings")
+			}
+			if err := registry.Reload(); err != nil {
+				logrus.Errorf("failed to reload property settings, keeping the previous ones: %v", err)
+				continue
+			}
+			logrus.Info("property settings reloaded")
+		}
+	}()
+}
+
+// watchConfigFile reports changes of the config file until the context is done. The
+// directory of the file is watched rather than the file itself, so that editors and
+// Kubernetes ConfigMaps replacing the file instead of writing to it are noticed as well.
+func watchConfigFile(ctx context.Context, path string) (<-chan struct{}, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return nil, fmt.Errorf("resolving config file path %q: %w", path, err)
+	}
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, fmt.Errorf("creating config file watcher: %w", err)
+	}
+	if err := watcher.Add(filepath.Dir(absPath)); err != nil {
+		_ = watcher.Close()
+		return nil, fmt.Errorf("watching directory of config file %q: %w", absPath, err)
+	}
+
+	changes := make(chan struct{}, 1)
+	go func() {
+		defer func() { _ = watcher.Close() }()
+		var debounce <-chan time.Time
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				if isConfigFileEvent(event, absPath) {
+					debounce = time.After(configWatchDebounce)
+				}
+			case watchErr, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				logrus.Warnf("config file watcher error: %v", watchErr)
+			case <-debounce:
+				debounce = nil
+				select {
+				case changes <- struct{}{}:
+				default:
+				}
+			}
+		}
+	}()
+	return changes, nil
+}
+
+// isConfigFileEvent tells whether a file event in the config directory may have changed
+// the config file. Kubernetes updates mounted ConfigMaps by swapping the ..data symlink.
+func isConfigFileEvent(event fsnotify.Event, configPath string) bool {
+	if event.Op == fsnotify.Chmod {
+		return false
+	}
+	name := filepath.Clean(event.Name)
+	return name == configPath || strings.HasPrefix(filepath.Base(name), "..data")
+}
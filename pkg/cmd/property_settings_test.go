@@ -2,9 +2,12 @@ package cmd
 
 import (
 	"context"
+	"os"
 	"testing"
 	"time"
 
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/fsnotify/fsnotify"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/urfave/cli/v3"
@@ -259,3 +262,543 @@ func TestPropertySettings_OverrideExcludedURLParams(t *testing.T) {
 
 	require.NoError(t, app.Run(context.Background(), args))
 }
+
+func TestPropertySettings_PropertiesListFromConfig(t *testing.T) {
+	// given
+	setDeliveryModeForTest(t, "")
+	configPath := writeConfigFile(t, `
+property:
+  settings:
+    split_by_max_events: 500
+    ingestion_auth:
+      api_keys: [top-level-key]
+ga4:
+  api_secrets: [top-level-secret]
+properties:
+  - id: shop
+    name: Shop
+    measurement_id: G-SHOP
+    protocol: ga4
+    settings:
+      ip_masking_level: 2
+      bot_filter_mode: drop
+      client_id_mode: cookieless
+      opt_out_mode: strip
+      rate_limits:
+        per_ip: "10:50"
+        tag_only: true
+      deduplication:
+        window: 2m
+        event_id_param: ep.event_id
+      allowed_domains: [shop.example.com, "*.shop.example.com"]
+      cors:
+        allowed_origins: [https://shop.example.com]
+        allow_credentials: true
+      ingestion_auth:
+        hmac_secrets: [shop-hmac-secret]
+      excluded_url_params: [ref]
+    sessions:
+      join_by_session_stamp: false
+    filters:
+      conditions:
+        - name: internal
+          type: exclude
+          expression: 'ip_address == "10.0.0.1"'
+    tracking_plan:
+      strict: true
+      events:
+        page_view: {}
+        purchase:
+          params:
+            transaction_id: {type: string, required: true}
+            coupon: {}
+    ga4:
+      api_secrets: [shop-secret]
+      params_mode: both
+      params:
+        - name: campaign_tier
+  - id: blog
+    measurement_id: "7"
+    protocol: matomo
+    settings:
+      split_by_campaign: false
+`)
+	setConfigFileForTest(t, configPath)
+	args := []string{"d8a-test", "--config=" + configPath}
+	setCurrentRunArgsForTest(t, args)
+
+	app := &cli.Command{
+		Name:  "d8a-test",
+		Flags: mergeFlags([]cli.Flag{configFlag}, getServerFlags()),
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			// when
+			registry := propertySettings(cmd)
+			shop, shopErr := registry.GetByMeasurementID("G-SHOP")
+			blog, blogErr := registry.GetByPropertyID("blog")
+
+			// then
+			require.NoError(t, shopErr)
+			require.NoError(t, blogErr)
+			assert.Equal(t, []string{"shop", "blog"}, configuredPropertyIDs(cmd))
+
+			assert.Equal(t, "shop", shop.PropertyID)
+			assert.Equal(t, "Shop", shop.PropertyName)
+			assert.Equal(t, "ga4", shop.ProtocolID)
+			assert.Equal(t, 2, shop.IPMaskingLevel)
+			assert.Equal(t, properties.BotFilterDrop, shop.BotFilterMode)
+			assert.Equal(t, properties.ClientIDModeCookieless, shop.ClientIDMode)
+			assert.Equal(t, properties.OptOutStrip, shop.OptOutMode)
+			assert.Equal(t, &properties.TrackingPlan{
+				Strict: true,
+				Events: map[string]properties.TrackingPlanEvent{
+					"page_view": {},
+					"purchase": {Params: map[string]properties.TrackingPlanParam{
+						"transaction_id": {Type: properties.TrackingPlanParamString, Required: true},
+						"coupon":         {},
+					}},
+				},
+			}, shop.TrackingPlan)
+			assert.Equal(t, properties.RateLimitSettings{
+				PerIP:   properties.RateLimit{Rate: 10, Burst: 50},
+				TagOnly: true,
+			}, shop.RateLimits)
+			assert.Equal(t, properties.DeduplicationSettings{
+				Window:       2 * time.Minute,
+				EventIDParam: "ep.event_id",
+			}, shop.Deduplication)
+			assert.Equal(t, []string{"shop.example.com", "*.shop.example.com"}, shop.AllowedDomains)
+			assert.False(t, shop.AllowedDomainsReportOnly)
+			assert.Equal(t, &properties.CORSSettings{
+				AllowedOrigins:   []string{"https://shop.example.com"},
+				AllowedHeaders:   []string{},
+				MaxAge:           24 * time.Hour,
+				AllowCredentials: true,
+			}, shop.CORS)
+			assert.False(t, shop.SessionJoinBySessionStamp)
+			assert.Equal(t, []string{"ref"}, shop.ExcludedURLParamsSafe())
+			assert.Equal(t, 500, shop.SplitByMaxEvents)
+			require.Len(t, shop.FiltersSafe().Conditions, 1)
+			assert.Equal(t, "internal", shop.FiltersSafe().Conditions[0].Name)
+			assert.Equal(t, []string{"ip_address"}, shop.FiltersSafe().Fields)
+			require.Len(t, shop.CustomColumnsSafe(), 1)
+			assert.Equal(t, "params_campaign_tier", shop.CustomColumnsSafe()[0].Name)
+			assert.Equal(t, []string{"shop-secret"}, shop.MeasurementProtocolAPISecrets)
+			assert.Equal(t, properties.GA4ParamsModeBoth, shop.GA4ParamsMode)
+			assert.Equal(t, properties.IngestionAuthSettings{
+				APIKeys:     []string{"top-level-key"},
+				HMACSecrets: []string{"shop-hmac-secret"},
+			}, shop.IngestionAuth)
+
+			assert.Equal(t, "blog", blog.PropertyName)
+			assert.Equal(t, "7", blog.PropertyMeasurementID)
+			assert.Equal(t, "matomo", blog.ProtocolID)
+			assert.False(t, blog.SplitByCampaign)
+			assert.True(t, blog.SplitByUserID)
+			assert.Equal(t, 500, blog.SplitByMaxEvents)
+			assert.Equal(t, historicalExcludedURLParams, blog.ExcludedURLParamsSafe())
+			assert.Equal(t, properties.BotFilterAllow, blog.BotFilterMode)
+			assert.Equal(t, properties.ClientIDModeProtocol, blog.ClientIDMode)
+			assert.Equal(t, properties.OptOutIgnore, blog.OptOutMode)
+			assert.Nil(t, blog.TrackingPlan)
+			assert.Equal(t, properties.RateLimitSettings{}, blog.RateLimits)
+			assert.Equal(t, properties.DeduplicationSettings{}, blog.Deduplication)
+			assert.Empty(t, blog.AllowedDomains)
+			require.NotNil(t, blog.CORS)
+			assert.Equal(t, []string{"*"}, blog.CORS.AllowedOrigins)
+			assert.False(t, blog.CORS.AllowCredentials)
+			assert.Empty(t, blog.FiltersSafe().Conditions)
+			assert.Equal(t, []string{"top-level-secret"}, blog.MeasurementProtocolAPISecrets)
+			assert.Equal(t, properties.GA4ParamsModeColumns, blog.GA4ParamsMode)
+			assert.Equal(t, []string{"top-level-key"}, blog.IngestionAuth.APIKeys)
+			assert.Empty(t, blog.IngestionAuth.HMACSecrets)
+			return nil
+		},
+	}
+
+	require.NoError(t, app.Run(context.Background(), args))
+}
+
+func TestPropertySettings_PropertiesListRejectsUnknownIDs(t *testing.T) {
+	// given
+	setDeliveryModeForTest(t, "")
+	configPath := writeConfigFile(t, `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+`)
+	setConfigFileForTest(t, configPath)
+	args := []string{"d8a-test", "--config=" + configPath}
+	setCurrentRunArgsForTest(t, args)
+
+	app := &cli.Command{
+		Name:  "d8a-test",
+		Flags: mergeFlags([]cli.Flag{configFlag}, getServerFlags()),
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			// when
+			registry := propertySettings(cmd)
+			_, measurementErr := registry.GetByMeasurementID("G-OTHER")
+			_, propertyErr := registry.GetByPropertyID(cmd.String(propertyIDFlag.Name))
+
+			// then
+			var unknownMeasurementID *properties.UnknownMeasurementIDError
+			assert.ErrorAs(t, measurementErr, &unknownMeasurementID)
+			var notFound *properties.NotFoundError
+			assert.ErrorAs(t, propertyErr, &notFound)
+			return nil
+		},
+	}
+
+	require.NoError(t, app.Run(context.Background(), args))
+}
+
+func TestPropertySettings_PropertiesListInvalidEntriesPanic(t *testing.T) {
+	testCases := []struct {
+		name   string
+		config string
+	}{
+		{
+			name: "missing measurement id",
+			config: `
+properties:
+  - id: shop
+`,
+		},
+		{
+			name: "duplicate measurement id",
+			config: `
+properties:
+  - id: shop
+    measurement_id: G-SAME
+  - id: blog
+    measurement_id: G-SAME
+`,
+		},
+		{
+			name: "full masking conflicts with inherited session stamp join",
+			config: `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    settings:
+      ip_masking_level: 4
+`,
+		},
+		{
+			name: "unknown bot filter mode",
+			config: `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    settings:
+      bot_filter_mode: block
+`,
+		},
+		{
+			name: "malformed rate limit",
+			config: `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    settings:
+      rate_limits:
+        per_client_id: fast
+`,
+		},
+		{
+			name: "allowed domain with a scheme",
+			config: `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    settings:
+      allowed_domains: [https://shop.example.com]
+`,
+		},
+		{
+			name: "CORS origin without a scheme",
+			config: `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    settings:
+      cors:
+        allowed_origins: [shop.example.com]
+`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			setDeliveryModeForTest(t, "")
+			configPath := writeConfigFile(t, testCase.config)
+			setConfigFileForTest(t, configPath)
+			args := []string{"d8a-test", "--config=" + configPath}
+			setCurrentRunArgsForTest(t, args)
+
+			app := &cli.Command{
+				Name:  "d8a-test",
+				Flags: newPropertySettingsFlagsForConfigTests(),
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					_ = propertySettings(cmd)
+					return nil
+				},
+			}
+
+			// then
+			assert.Panics(t, func() {
+				require.NoError(t, app.Run(context.Background(), args))
+			})
+		})
+	}
+}
+
+func TestPropertySettings_ParamsMapModeNeedsADriverWithMaps(t *testing.T) {
+	testCases := []struct {
+		name      string
+		args      []string
+		wantPanic bool
+	}{
+		{
+			name:      "map mode on postgres",
+			args:      []string{"--warehouse-driver=postgres", "--ga4-params-mode=map"},
+			wantPanic: true,
+		},
+		{
+			name:      "both mode on duckdb",
+			args:      []string{"--warehouse-driver=duckdb", "--ga4-params-mode=both"},
+			wantPanic: true,
+		},
+		{
+			name: "columns mode on postgres",
+			args: []string{"--warehouse-driver=postgres", "--ga4-params-mode=columns"},
+		},
+		{
+			name: "map mode on clickhouse",
+			args: []string{"--warehouse-driver=clickhouse", "--ga4-params-mode=map"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			setConfigFileForTest(t, "")
+			args := append([]string{"d8a-test"}, tc.args...)
+			setCurrentRunArgsForTest(t, args)
+
+			app := &cli.Command{
+				Name:  "d8a-test",
+				Flags: getServerFlags(),
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					_ = propertySettings(cmd)
+					return nil
+				},
+			}
+
+			// when + then
+			run := func() { require.NoError(t, app.Run(context.Background(), args)) }
+			if tc.wantPanic {
+				assert.Panics(t, run)
+			} else {
+				assert.NotPanics(t, run)
+			}
+		})
+	}
+}
+
+func TestWatchPropertySettings_ReloadsChangedConfigFile(t *testing.T) {
+	// given
+	setDeliveryModeForTest(t, "")
+	configPath := writeConfigFile(t, `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    settings:
+      excluded_url_params: [ref]
+`)
+	setConfigFileForTest(t, configPath)
+	args := []string{"d8a-test", "--config=" + configPath}
+	setCurrentRunArgsForTest(t, args)
+
+	app := &cli.Command{
+		Name:  "d8a-test",
+		Flags: mergeFlags([]cli.Flag{configFlag}, getServerFlags()),
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			registry := propertySettings(cmd)
+			reloaded := make(chan struct{}, 1)
+			registry.OnReload(func([]properties.Settings) { reloaded <- struct{}{} })
+			watchPropertySettings(ctx, cmd)
+
+			// when
+			require.NoError(t, os.WriteFile(configPath, []byte(`
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    settings:
+      excluded_url_params: [ref, gclid]
+  - id: blog
+    measurement_id: G-BLOG
+`), 0o600))
+
+			// then
+			select {
+			case <-reloaded:
+			case <-time.After(5 * time.Second):
+				t.Fatal("property settings were not reloaded")
+			}
+			shop, err := registry.GetByMeasurementID("G-SHOP")
+			require.NoError(t, err)
+			assert.Equal(t, []string{"ref", "gclid"}, shop.ExcludedURLParamsSafe())
+			blog, err := registry.GetByPropertyID("blog")
+			require.NoError(t, err)
+			assert.Equal(t, "G-BLOG", blog.PropertyMeasurementID)
+			return nil
+		},
+	}
+
+	require.NoError(t, app.Run(context.Background(), args))
+}
+
+func TestPropertySettings_ReloadRejectsInvalidConfig(t *testing.T) {
+	// given
+	setDeliveryModeForTest(t, "")
+	configPath := writeConfigFile(t, `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+`)
+	setConfigFileForTest(t, configPath)
+	args := []string{"d8a-test", "--config=" + configPath}
+	setCurrentRunArgsForTest(t, args)
+
+	app := &cli.Command{
+		Name:  "d8a-test",
+		Flags: mergeFlags([]cli.Flag{configFlag}, getServerFlags()),
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			registry := propertySettings(cmd)
+			require.NoError(t, os.WriteFile(configPath, []byte(`
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    filters:
+      conditions:
+        - name: broken
+          type: exclude
+          expression: 'ip_address =='
+`), 0o600))
+
+			// when
+			err := registry.Reload()
+
+			// then
+			require.Error(t, err)
+			assert.Same(t, registry, propertySettings(cmd))
+			shop, lookupErr := registry.GetByMeasurementID("G-SHOP")
+			require.NoError(t, lookupErr)
+			assert.Empty(t, shop.FiltersSafe().Conditions)
+			return nil
+		},
+	}
+
+	require.NoError(t, app.Run(context.Background(), args))
+}
+
+func TestPropertySettings_ReloadRejectsNewProtocol(t *testing.T) {
+	// given
+	setDeliveryModeForTest(t, "")
+	configPath := writeConfigFile(t, `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    protocol: ga4
+`)
+	setConfigFileForTest(t, configPath)
+	args := []string{"d8a-test", "--config=" + configPath}
+	setCurrentRunArgsForTest(t, args)
+
+	app := &cli.Command{
+		Name:  "d8a-test",
+		Flags: mergeFlags([]cli.Flag{configFlag}, getServerFlags()),
+		Action: func(_ context.Context, cmd *cli.Command) error {
+			registry := propertySettings(cmd)
+			require.NoError(t, os.WriteFile(configPath, []byte(`
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    protocol: ga4
+  - id: blog
+    measurement_id: "7"
+    protocol: matomo
+`), 0o600))
+
+			// when
+			err := registry.Reload()
+
+			// then
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), `protocol "matomo"`)
+			_, lookupErr := registry.GetByPropertyID("blog")
+			assert.Error(t, lookupErr)
+			return nil
+		},
+	}
+
+	require.NoError(t, app.Run(context.Background(), args))
+}
+
+func TestParseRateLimit(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       string
+		expected    properties.RateLimit
+		expectError bool
+	}{
+		{name: "empty is no limit", value: ""},
+		{name: "rate and burst", value: "10:50", expected: properties.RateLimit{Rate: 10, Burst: 50}},
+		{name: "fractional rate", value: " 0.5 : 2 ", expected: properties.RateLimit{Rate: 0.5, Burst: 2}},
+		{name: "missing burst", value: "10", expectError: true},
+		{name: "invalid rate", value: "fast:10", expectError: true},
+		{name: "invalid burst", value: "10:many", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			limit, err := parseRateLimit(tc.value)
+
+			// then
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, limit)
+		})
+	}
+}
+
+func TestIsConfigFileEvent(t *testing.T) {
+	testCases := []struct {
+		name     string
+		event    fsnotify.Event
+		expected bool
+	}{
+		{name: "write", event: fsnotify.Event{Name: "/etc/d8a/config.yaml", Op: fsnotify.Write}, expected: true},
+		{name: "replaced", event: fsnotify.Event{Name: "/etc/d8a/config.yaml", Op: fsnotify.Create}, expected: true},
+		{name: "chmod", event: fsnotify.Event{Name: "/etc/d8a/config.yaml", Op: fsnotify.Chmod}, expected: false},
+		{name: "other file", event: fsnotify.Event{Name: "/etc/d8a/other.yaml", Op: fsnotify.Write}, expected: false},
+		{name: "configmap swap", event: fsnotify.Event{Name: "/etc/d8a/..data", Op: fsnotify.Create}, expected: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			result := isConfigFileEvent(tc.event, "/etc/d8a/config.yaml")
+
+			// then
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
@@ -6,17 +6,35 @@ import (
 	"github.com/d8a-tech/d8a/pkg/protocol/d8a"
 	"github.com/d8a-tech/d8a/pkg/protocol/ga4"
 	"github.com/d8a-tech/d8a/pkg/protocol/matomo"
+	"github.com/d8a-tech/d8a/pkg/protocol/plausible"
+	"github.com/d8a-tech/d8a/pkg/protocol/segment"
+	"github.com/d8a-tech/d8a/pkg/protocol/snowplow"
+	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
 )
 
 func protocols(cmd *cli.Command, converter currency.Converter) []protocol.Protocol {
+	psr := propertySettings(cmd)
+	cors := *corsSettingsFromFlags(cmd)
 	return []protocol.Protocol{
-		ga4.NewGA4Protocol(converter, propertySettings(cmd)),
-		d8a.NewD8AProtocol(converter, propertySettings(cmd)),
+		ga4.NewGA4Protocol(converter, psr, ga4.WithDefaultCORSSettings(cors)),
+		d8a.NewD8AProtocol(converter, psr, ga4.WithDefaultCORSSettings(cors)),
 		matomo.NewMatomoProtocol(
-			matomo.NewFromIDSiteExtractor(propertySettings(cmd)),
-			propertySettings(cmd),
+			matomo.NewFromIDSiteExtractor(psr),
+			psr,
 			matomo.WithExtraTrackingEndpoints(cmd.StringSlice(matomoTrackingEndpointsFlag.Name)),
+			matomo.WithDefaultCORSSettings(cors),
+		),
+		segment.NewSegmentProtocol(segment.NewFromWriteKeyExtractor(psr), psr),
+		plausible.NewPlausibleProtocol(
+			plausible.NewFromDomainExtractor(psr),
+			psr,
+			plausible.WithDefaultCORSSettings(cors),
+		),
+		snowplow.NewSnowplowProtocol(
+			snowplow.NewFromAppIDExtractor(psr),
+			psr,
+			snowplow.WithDefaultCORSSettings(cors),
 		),
 	}
 }
@@ -30,3 +48,25 @@ func protocolByID(id string, cmd *cli.Command, converter currency.Converter) pro
 	}
 	return nil
 }
+
+// receiverProtocols returns the protocols used by at least one configured property,
+// so a single receiver serves the endpoints of all of them.
+func receiverProtocols(cmd *cli.Command, converter currency.Converter) []protocol.Protocol {
+	settingsList, _ := propertySettingsList(cmd)
+	used := make(map[string]bool, len(settingsList))
+	for i := range settingsList {
+		used[settingsList[i].ProtocolID] = true
+	}
+
+	selected := make([]protocol.Protocol, 0, len(used))
+	for _, p := range protocols(cmd, converter) {
+		if used[p.ID()] {
+			selected = append(selected, p)
+			delete(used, p.ID())
+		}
+	}
+	for id := range used {
+		logrus.Panicf("protocol %s not found", id)
+	}
+	return selected
+}
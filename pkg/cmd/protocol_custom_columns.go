@@ -11,6 +11,8 @@ import (
 	"gopkg.in/yaml.v3"
 )
 
+const ga4ParamsInterfaceID = schema.InterfaceID("ga4.protocols.d8a.tech/event/params")
+
 type protocolCustomColumnsConfig struct {
 	GA4    ga4CustomColumnsConfig    `yaml:"ga4"`
 	Matomo matomoCustomColumnsConfig `yaml:"matomo"`
@@ -250,10 +252,10 @@ func normalizeGA4ParamShortcut(entry ga4ParamShortcutConfig, idx int) (propertie
 		Name:      defaultOutputColumnName(entry.ColumnName, "params_", entry.Name),
 		Scope:     scope,
 		Type:      columnType,
-		DependsOn: schema.DependsOnEntry{Interface: schema.InterfaceID("ga4.protocols.d8a.tech/event/params")},
+		DependsOn: schema.DependsOnEntry{Interface: ga4ParamsInterfaceID},
 		Implementation: properties.NestedLookupConfig{
 			SourceScope:       properties.NestedLookupSourceScopeEvent,
-			SourceInterfaceID: schema.InterfaceID("ga4.protocols.d8a.tech/event/params"),
+			SourceInterfaceID: ga4ParamsInterfaceID,
 			SourceField:       "params",
 			MatchField:        "name",
 			MatchEquals:       entry.Name,
@@ -262,6 +264,23 @@ func normalizeGA4ParamShortcut(entry ga4ParamShortcutConfig, idx int) (propertie
 	}, nil
 }
 
+// applyGA4ParamsMode drops the custom columns of ga4.params shortcuts when the params map
+// columns replace them. It's applied to the final settings of a property, so that a property
+// inheriting the shortcuts may still switch back to writing them.
+func applyGA4ParamsMode(settings *properties.Settings) {
+	if settings.GA4ParamsMode != properties.GA4ParamsModeMap {
+		return
+	}
+	customColumns := make([]properties.CustomColumnConfig, 0, len(settings.CustomColumns))
+	for idx := range settings.CustomColumns {
+		if settings.CustomColumns[idx].Implementation.SourceInterfaceID == ga4ParamsInterfaceID {
+			continue
+		}
+		customColumns = append(customColumns, settings.CustomColumns[idx])
+	}
+	settings.CustomColumns = customColumns
+}
+
 func normalizeMatomoCustomDimensionShortcut(
 	entry matomoCustomDimensionShortcutConfig,
 	idx int,
@@ -355,6 +355,50 @@ ga4:
 	assert.Equal(t, "params_from_flag", columns[1].Name)
 }
 
+func TestApplyGA4ParamsMode(t *testing.T) {
+	testCases := []struct {
+		name        string
+		mode        properties.GA4ParamsMode
+		wantColumns []string
+	}{
+		{name: "default mode", mode: "", wantColumns: []string{"params_tier", "dimension1"}},
+		{name: "columns mode", mode: properties.GA4ParamsModeColumns, wantColumns: []string{"params_tier", "dimension1"}},
+		{name: "both mode", mode: properties.GA4ParamsModeBoth, wantColumns: []string{"params_tier", "dimension1"}},
+		{name: "map mode", mode: properties.GA4ParamsModeMap, wantColumns: []string{"dimension1"}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			settings := &properties.Settings{
+				GA4ParamsMode: tc.mode,
+				CustomColumns: []properties.CustomColumnConfig{
+					{
+						Name:           "params_tier",
+						Implementation: properties.NestedLookupConfig{SourceInterfaceID: ga4ParamsInterfaceID},
+					},
+					{
+						Name: "dimension1",
+						Implementation: properties.NestedLookupConfig{
+							SourceInterfaceID: schema.InterfaceID("matomo.protocols.d8a.tech/event/custom_dimensions"),
+						},
+					},
+				},
+			}
+
+			// when
+			applyGA4ParamsMode(settings)
+
+			// then
+			names := make([]string, 0, len(settings.CustomColumns))
+			for idx := range settings.CustomColumns {
+				names = append(names, settings.CustomColumns[idx].Name)
+			}
+			assert.Equal(t, tc.wantColumns, names)
+		})
+	}
+}
+
 type staticProtocolCustomColumnsSource struct {
 	values map[string][]string
 }
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/currency"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/fasthttp/router"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+	"github.com/valyala/fasthttp"
+)
+
+func protocolIDs(protocols []protocol.Protocol) []string {
+	ids := make([]string, 0, len(protocols))
+	for _, p := range protocols {
+		ids = append(ids, p.ID())
+	}
+	return ids
+}
+
+func TestReceiverProtocols(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   string
+		args     []string
+		expected []string
+	}{
+		{
+			name:     "single property uses protocol flag",
+			config:   "{}",
+			args:     []string{"--protocol=matomo"},
+			expected: []string{"matomo"},
+		},
+		{
+			name: "properties list registers every used protocol once",
+			config: `
+properties:
+  - id: shop
+    measurement_id: G-SHOP
+    protocol: ga4
+  - id: blog
+    measurement_id: "7"
+    protocol: matomo
+  - id: app
+    measurement_id: D-APP
+    protocol: d8a
+  - id: landing
+    measurement_id: G-LANDING
+  - id: backend
+    measurement_id: wk-backend
+    protocol: segment
+  - id: marketing
+    measurement_id: example.com
+    protocol: plausible
+  - id: mobile
+    measurement_id: shop-ios
+    protocol: snowplow
+`,
+			expected: []string{"ga4", "d8a", "matomo", "segment", "plausible", "snowplow"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			setDeliveryModeForTest(t, "")
+			configPath := writeConfigFile(t, tt.config)
+			setConfigFileForTest(t, configPath)
+			args := append([]string{"d8a-test", "--config=" + configPath}, tt.args...)
+			setCurrentRunArgsForTest(t, args)
+
+			app := &cli.Command{
+				Name:  "d8a-test",
+				Flags: mergeFlags([]cli.Flag{configFlag}, getServerFlags()),
+				Action: func(_ context.Context, cmd *cli.Command) error {
+					// when
+					selected := receiverProtocols(cmd, currency.NewDummyConverter(1))
+
+					// then
+					assert.Equal(t, tt.expected, protocolIDs(selected))
+					r := router.New()
+					assert.NotPanics(t, func() {
+						for _, p := range selected {
+							for _, endpoint := range p.Endpoints() {
+								for _, method := range endpoint.Methods {
+									r.Handle(method, endpoint.Path, func(*fasthttp.RequestCtx) {})
+								}
+							}
+						}
+					}, "endpoints of different protocols must not collide")
+					return nil
+				},
+			}
+
+			require.NoError(t, app.Run(context.Background(), args))
+		})
+	}
+}
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/rawlog"
+	"github.com/d8a-tech/d8a/pkg/receiver"
+	"github.com/d8a-tech/d8a/pkg/spools"
+	whFiles "github.com/d8a-tech/d8a/pkg/warehouse/files"
+	"github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/urfave/cli/v3"
+)
+
+// rawLogRetentionInterval is how often expired raw log segments are looked for.
+const rawLogRetentionInterval = time.Hour
+
+// buildRawLogStorage creates the storage keeping raw tracking requests, or a noop one when
+// the raw log is disabled. The cleanup func flushes the remaining requests.
+func buildRawLogStorage(
+	ctx context.Context,
+	cmd *cli.Command,
+) (storage receiver.RawLogStorage, cleanup func(), err error) {
+	if !cmd.Bool(rawLogEnabledFlag.Name) {
+		return receiver.NewNoopRawLogStorage(), func() {}, nil
+	}
+
+	format, err := rawlog.NewFormat(strings.ToLower(cmd.String(rawLogFormatFlag.Name)))
+	if err != nil {
+		return nil, nil, err
+	}
+	prefix := strings.Trim(cmd.String(rawLogPrefixFlag.Name), "/")
+
+	dest, err := buildRawLogDestination(ctx, cmd, prefix)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	factory, err := spools.NewFileFactory(
+		afero.NewOsFs(),
+		filepath.Join(cmd.String(storageSpoolDirectoryFlag.Name), "rawlog"),
+		spools.WithFailureStrategy(spools.NewQuarantineStrategy()),
+		spools.WithMaxFailures(3),
+		spools.WithMaxActiveSize(cmd.Int64(rawLogMaxSegmentSizeFlag.Name)),
+		spools.WithMaxBytesBeforeFlush(cmd.Int64(rawLogMaxSegmentSizeFlag.Name)),
+		spools.WithFlushInterval(cmd.Duration(rawLogMaxSegmentAgeFlag.Name)),
+		spools.WithFlushOnClose(true),
+	)
+	if err != nil {
+		return nil, nil, fmt.Errorf("creating raw log spool factory: %w", err)
+	}
+
+	rawLogStorage, err := rawlog.NewStorage(factory, dest.uploader, rawlog.WithFormat(format), rawlog.WithPrefix(prefix))
+	if err != nil {
+		if closeErr := factory.Close(); closeErr != nil {
+			logrus.WithError(closeErr).Error("failed to close raw log spool factory")
+		}
+		return nil, nil, fmt.Errorf("creating raw log storage: %w", err)
+	}
+
+	retentionCtx, cancelRetention := context.WithCancel(ctx)
+	if retention := cmd.Duration(rawLogRetentionFlag.Name); retention > 0 {
+		go rawlog.RunRetention(retentionCtx, dest.pruner, retention, rawLogRetentionInterval)
+	}
+
+	return rawLogStorage, func() {
+		cancelRetention()
+		if closeErr := factory.Close(); closeErr != nil {
+			logrus.WithError(closeErr).Error("failed to close raw log spool factory")
+		}
+	}, nil
+}
+
+// rawLogDestination is where raw log segments are uploaded, pruned and read back from.
+type rawLogDestination struct {
+	uploader whFiles.StreamUploader
+	pruner   rawlog.Pruner
+	source   rawlog.Source
+}
+
+func buildRawLogDestination(ctx context.Context, cmd *cli.Command, prefix string) (*rawLogDestination, error) {
+	switch storageType := strings.ToLower(cmd.String(rawLogStorageFlag.Name)); storageType {
+	case storageTypeS3, storageTypeGCS:
+		bucket, err := createWarehouseCDKBucket(ctx, storageType, cmd)
+		if err != nil {
+			return nil, fmt.Errorf("creating raw log object storage bucket: %w", err)
+		}
+		return &rawLogDestination{
+			uploader: whFiles.NewBlobUploader(bucket),
+			pruner:   rawlog.NewBlobPruner(bucket, prefix),
+			source:   rawlog.NewBlobSource(bucket, prefix),
+		}, nil
+	case storageTypeFilesystem:
+		filesystemPath := cmd.String(rawLogFilesystemPathFlag.Name)
+		if filesystemPath == "" {
+			return nil, fmt.Errorf("--%s is required when raw-log-storage=filesystem", rawLogFilesystemPathFlag.Name)
+		}
+		uploader, err := whFiles.NewFilesystemUploader(filesystemPath)
+		if err != nil {
+			return nil, fmt.Errorf("creating raw log filesystem uploader: %w", err)
+		}
+		segmentsDir := filepath.Join(filesystemPath, filepath.FromSlash(path.Clean(prefix)))
+		return &rawLogDestination{
+			uploader: uploader,
+			pruner:   rawlog.NewFilesystemPruner(segmentsDir),
+			source:   rawlog.NewFilesystemSource(segmentsDir),
+		}, nil
+	default:
+		return nil, fmt.Errorf("--%s must be set to s3, gcs, or filesystem", rawLogStorageFlag.Name)
+	}
+}
@@ -0,0 +1,95 @@
+package cmd
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/receiver"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/urfave/cli/v3"
+)
+
+func TestBuildRawLogStorage(t *testing.T) {
+	testCases := []struct {
+		name             string
+		args             []string
+		expectError      bool
+		expectNoop       bool
+		expectedSegments string
+	}{
+		{
+			name:       "disabled by default",
+			args:       []string{},
+			expectNoop: true,
+		},
+		{
+			name: "filesystem",
+			args: []string{
+				"--raw-log-enabled",
+				"--raw-log-format=ndjson",
+				"--raw-log-prefix=audit/raw",
+				"--raw-log-retention=0",
+			},
+			expectedSegments: "audit/raw/y=*/m=*/d=*/*.ndjson.gz",
+		},
+		{
+			name:        "unsupported format",
+			args:        []string{"--raw-log-enabled", "--raw-log-format=csv"},
+			expectError: true,
+		},
+		{
+			name:        "unsupported storage",
+			args:        []string{"--raw-log-enabled", "--raw-log-storage=ftp"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			baseDir := t.TempDir()
+			args := append([]string{
+				"d8a-test",
+				"--storage-spool-directory=" + filepath.Join(baseDir, "spool"),
+				"--raw-log-filesystem-path=" + filepath.Join(baseDir, "out"),
+			}, tc.args...)
+
+			app := &cli.Command{
+				Name:  "d8a-test",
+				Flags: mergeFlags([]cli.Flag{configFlag}, getServerFlags()),
+				Action: func(ctx context.Context, cmd *cli.Command) error {
+					// when
+					storage, cleanup, err := buildRawLogStorage(ctx, cmd)
+
+					// then
+					if tc.expectError {
+						assert.Error(t, err)
+						return nil
+					}
+					require.NoError(t, err)
+					if tc.expectNoop {
+						assert.IsType(t, &receiver.NoopRawLogStorage{}, storage)
+						cleanup()
+						return nil
+					}
+					require.NoError(t, storage.Store(&hits.ParsedRequest{
+						IP:                 "10.0.0.1",
+						ServerReceivedTime: time.Now(),
+						Path:               "/g/collect",
+					}))
+					cleanup()
+					segments, err := filepath.Glob(filepath.Join(baseDir, "out", tc.expectedSegments))
+					require.NoError(t, err)
+					assert.Len(t, segments, 1)
+					return nil
+				},
+			}
+
+			require.NoError(t, app.Run(context.Background(), args))
+		})
+	}
+}
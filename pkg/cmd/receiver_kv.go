@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/bolt"
+	"github.com/d8a-tech/d8a/pkg/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+)
+
+// receiverKVOpenTimeout is how long opening the receiver KV waits for the lock held by
+// another process, like a running server when replaying.
+const receiverKVOpenTimeout = 5 * time.Second
+
+// buildReceiverKV opens the KV storage of the receiver, kept in its own bolt database next
+// to the worker ones, as the receiver may run in another process. It holds the daily salts
+// of cookieless client IDs and the hits seen by deduplication.
+func buildReceiverKV(cmd *cli.Command) (kv storage.KV, cleanup func(), err error) {
+	dir := cmd.String(storageBoltDirectoryFlag.Name)
+	if err := os.MkdirAll(dir, 0o750); err != nil {
+		return nil, nil, fmt.Errorf("creating bolt directory: %w", err)
+	}
+	kv, err = bolt.NewBoltKV(filepath.Join(dir, "receiver_kv.db"), bolt.WithOpenTimeout(receiverKVOpenTimeout))
+	if errors.Is(err, bolt.ErrLocked) {
+		return nil, nil, fmt.Errorf(
+			"open receiver bolt kv: %w, e.g. by a running server, point --%s at a copy of the directory",
+			err, storageBoltDirectoryFlag.Name,
+		)
+	}
+	if err != nil {
+		return nil, nil, fmt.Errorf("open receiver bolt kv: %w", err)
+	}
+	return kv, func() {
+		if c, ok := kv.(interface{ Close() error }); ok {
+			if closeErr := c.Close(); closeErr != nil {
+				logrus.Error("failed to close receiver bolt kv:", closeErr)
+			}
+		}
+	}, nil
+}
@@ -20,6 +20,17 @@ func buildReceiverStorage(
 	ctx context.Context,
 	cmd *cli.Command,
 	publisher worker.Publisher,
+) (storage receiver.Storage, cleanup func(), err error) {
+	return buildReceiverStorageWithClock(ctx, cmd, publisher, time.Now)
+}
+
+// buildReceiverStorageWithClock is buildReceiverStorage timestamping processing pings with
+// the given clock, replaying past requests passes the replayed one.
+func buildReceiverStorageWithClock(
+	ctx context.Context,
+	cmd *cli.Command,
+	publisher worker.Publisher,
+	now func() time.Time,
 ) (storage receiver.Storage, cleanup func(), err error) {
 	backend := strings.ToLower(cmd.String(queueBackendFlag.Name))
 
@@ -27,7 +38,7 @@ func buildReceiverStorage(
 		backoffPublisher, bErr := publishers.NewBackoffPingingPublisher(
 			ctx,
 			publisher,
-			pings.NewProcessHitsPingTask(encoding.GzipJSONEncoder),
+			pings.NewProcessHitsPingTaskWithClock(encoding.GzipJSONEncoder, now),
 			publishers.WithMinInterval(5*time.Second),
 			publishers.WithIntervalExpFactor(1.5),
 			publishers.WithMaxInterval(5*time.Minute),
@@ -41,7 +52,7 @@ func buildReceiverStorage(
 			ctx,
 			publisher,
 			cmd.Duration(receiverBatchTimeoutFlag.Name),
-			pings.NewProcessHitsPingTask(encoding.GzipJSONEncoder),
+			pings.NewProcessHitsPingTaskWithClock(encoding.GzipJSONEncoder, now),
 		)
 	}
 
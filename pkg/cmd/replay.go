@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/encoding"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/pings"
+	"github.com/d8a-tech/d8a/pkg/rawlog"
+	"github.com/d8a-tech/d8a/pkg/receiver"
+	"github.com/d8a-tech/d8a/pkg/storage"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+)
+
+var replayFromFlag *cli.TimestampFlag = &cli.TimestampFlag{
+	Name:     "from",
+	Usage:    "Replay requests received at or after this time (RFC 3339, e.g. 2026-03-07T00:00:00Z)",
+	Required: true,
+	Config:   cli.TimestampConfig{Layouts: []string{time.RFC3339}},
+}
+
+var replayToFlag *cli.TimestampFlag = &cli.TimestampFlag{
+	Name:     "to",
+	Usage:    "Replay requests received before this time (RFC 3339, e.g. 2026-03-08T00:00:00Z)",
+	Required: true,
+	Config:   cli.TimestampConfig{Layouts: []string{time.RFC3339}},
+}
+
+func replayCommands() []*cli.Command {
+	return []*cli.Command{newReplayCommand()}
+}
+
+func newReplayCommand() *cli.Command {
+	return &cli.Command{
+		Name: "replay",
+		Usage: "Re-ingest raw log requests received in the given time range, publishing their hits to " +
+			"the queue as if they had just arrived",
+		Before: applyModeOverridesBefore,
+		Flags: mergeFlags(
+			[]cli.Flag{replayFromFlag, replayToFlag},
+			getServerFlags(),
+		),
+		Action: func(ctx context.Context, cmd *cli.Command) error {
+			from, to := cmd.Timestamp(replayFromFlag.Name), cmd.Timestamp(replayToFlag.Name)
+			if !from.Before(to) {
+				return fmt.Errorf("--%s must be before --%s", replayFromFlag.Name, replayToFlag.Name)
+			}
+
+			ctx, cancel := context.WithCancel(ctx)
+			defer cancel()
+
+			converter, cleanup, err := buildCurrencyConverter(cmd)
+			if err != nil {
+				return err
+			}
+			converter.Run(ctx)
+			defer cleanup()
+
+			bs, err := bootstrap(ctx, cancel, "replay", cmd)
+			if err != nil {
+				return err
+			}
+			defer bs.cleanup(context.Background()) //nolint:contextcheck // shutdown needs fresh context
+
+			dest, err := buildRawLogDestination(ctx, cmd, strings.Trim(cmd.String(rawLogPrefixFlag.Name), "/"))
+			if err != nil {
+				return err
+			}
+
+			queue, err := buildQueue(ctx, cmd)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if closeErr := queue.Cleanup(); closeErr != nil {
+					logrus.Error("failed to cleanup queue:", closeErr)
+				}
+			}()
+
+			clock := newReplayClock(from)
+			serverStorage, cleanupReceiverStorage, err := buildReceiverStorageWithClock(
+				ctx, cmd, queue.Publisher, clock.Now,
+			)
+			if err != nil {
+				return err
+			}
+			// Salts of the days within a bounded raw log retention are kept, replayed hits of
+			// cookieless properties get the client IDs they got when they were received. Hits
+			// seen by the receiver are not, replayed ones are only deduplicated among them.
+			receiverKV, cleanupReceiverKV, err := buildReceiverKV(cmd)
+			if err != nil {
+				cleanupReceiverStorage()
+				return err
+			}
+			defer cleanupReceiverKV()
+			server, err := buildReceiverServer(
+				cmd,
+				serverStorage,
+				receiver.NewNoopRawLogStorage(),
+				converter,
+				receiverKV,
+				storage.NewInMemoryKV(),
+			)
+			if err != nil {
+				cleanupReceiverStorage()
+				return err
+			}
+
+			// Segments are sealed at the latest one segment age after their first request,
+			// twice that leaves room for a delayed flush.
+			sealLag := 2 * cmd.Duration(rawLogMaxSegmentAgeFlag.Name)
+			stats, replayErr := replayRawLog(ctx, dest.source, server.Replayer(ctx), clock, from, to, sealLag)
+			// Hits still batched by the receiver storage must be published before the final ping
+			cleanupReceiverStorage()
+			if replayErr != nil {
+				return fmt.Errorf("replaying raw log after %d requests: %w", stats.replayed, replayErr)
+			}
+
+			// Advance the timing wheel to the end of the range, so sessions which were over by
+			// then are closed, no matter the current time.
+			ping, err := pings.NewProcessHitsPingTaskWithClock(encoding.GzipJSONEncoder, func() time.Time {
+				return to
+			})()
+			if err != nil {
+				return fmt.Errorf("creating final ping task: %w", err)
+			}
+			if err := queue.Publisher.Publish(ping); err != nil {
+				return fmt.Errorf("publishing final ping task: %w", err)
+			}
+
+			logrus.Infof("replayed %d requests received between %s and %s, %d rejected",
+				stats.replayed, from.Format(time.RFC3339), to.Format(time.RFC3339), stats.rejected)
+			return nil
+		},
+	}
+}
+
+// replayClock is the time of the last replayed request.
+type replayClock struct {
+	unixNano atomic.Int64
+}
+
+func newReplayClock(start time.Time) *replayClock {
+	c := &replayClock{}
+	c.unixNano.Store(start.UnixNano())
+	return c
+}
+
+// Now returns the replayed time.
+func (c *replayClock) Now() time.Time {
+	return time.Unix(0, c.unixNano.Load())
+}
+
+// Advance moves the clock to t, if it's after the replayed time.
+func (c *replayClock) Advance(t time.Time) {
+	for {
+		current := c.unixNano.Load()
+		if t.UnixNano() <= current || c.unixNano.CompareAndSwap(current, t.UnixNano()) {
+			return
+		}
+	}
+}
+
+type requestReplayer interface {
+	Replay(request *hits.ParsedRequest) error
+}
+
+type replayStats struct {
+	replayed int
+	rejected int
+}
+
+// replayRawLog replays requests received in [from, to) in the order they were received,
+// advancing the clock to every request. Requests rejected by the receiver are logged and
+// skipped.
+func replayRawLog(
+	ctx context.Context,
+	source rawlog.Source,
+	replayer requestReplayer,
+	clock *replayClock,
+	from, to time.Time,
+	sealLag time.Duration,
+) (replayStats, error) {
+	var stats replayStats
+	err := rawlog.Read(ctx, source, from, to, sealLag, func(request *hits.ParsedRequest) error {
+		clock.Advance(request.ServerReceivedTime)
+		err := replayer.Replay(request)
+		if errors.Is(err, receiver.ErrReplayRejected) {
+			logrus.Warnf("skipping replayed request: %v", err)
+			stats.rejected++
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		stats.replayed++
+		return nil
+	})
+	return stats, err
+}
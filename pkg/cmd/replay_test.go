@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/rawlog"
+	"github.com/d8a-tech/d8a/pkg/receiver"
+	"github.com/d8a-tech/d8a/pkg/spools"
+	whFiles "github.com/d8a-tech/d8a/pkg/warehouse/files"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingReplayer struct {
+	clock      *replayClock
+	clockTimes []time.Time
+	rejectIP   string
+	err        error
+}
+
+func (r *recordingReplayer) Replay(request *hits.ParsedRequest) error {
+	r.clockTimes = append(r.clockTimes, r.clock.Now())
+	if request.IP == r.rejectIP {
+		return fmt.Errorf("%w: status 400", receiver.ErrReplayRejected)
+	}
+	return r.err
+}
+
+func TestReplayRawLog(t *testing.T) {
+	from := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	testCases := []struct {
+		name               string
+		replayErr          error
+		expectedStats      replayStats
+		expectedClockTimes []time.Time
+		expectError        bool
+	}{
+		{
+			name:          "replays requests in range",
+			expectedStats: replayStats{replayed: 2, rejected: 1},
+			expectedClockTimes: []time.Time{
+				from.Add(time.Minute),
+				from.Add(2 * time.Minute),
+				from.Add(3 * time.Minute),
+			},
+		},
+		{
+			name:               "stops on storage error",
+			replayErr:          assert.AnError,
+			expectedClockTimes: []time.Time{from.Add(time.Minute)},
+			expectError:        true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			dir := t.TempDir()
+			uploader, err := whFiles.NewFilesystemUploader(dir)
+			require.NoError(t, err)
+			factory, err := spools.NewFileFactory(afero.NewMemMapFs(), "/spool", spools.WithFlushOnClose(true))
+			require.NoError(t, err)
+			storage, err := rawlog.NewStorage(factory, uploader, rawlog.WithNowFunc(func() time.Time {
+				return from.Add(5 * time.Minute)
+			}))
+			require.NoError(t, err)
+			for i, receivedAt := range []time.Time{
+				from.Add(-time.Minute),
+				from.Add(time.Minute),
+				from.Add(2 * time.Minute),
+				from.Add(3 * time.Minute),
+			} {
+				require.NoError(t, storage.Store(&hits.ParsedRequest{
+					IP:                 fmt.Sprintf("10.0.0.%d", i),
+					Method:             "POST",
+					Path:               "/g/collect",
+					ServerReceivedTime: receivedAt,
+				}))
+			}
+			require.NoError(t, factory.Close())
+
+			clock := newReplayClock(from)
+			replayer := &recordingReplayer{clock: clock, rejectIP: "10.0.0.2", err: tc.replayErr}
+
+			// when
+			stats, err := replayRawLog(
+				context.Background(),
+				rawlog.NewFilesystemSource(filepath.Join(dir, rawlog.DefaultPrefix)),
+				replayer,
+				clock,
+				from,
+				to,
+				time.Hour,
+			)
+
+			// then
+			if tc.expectError {
+				assert.ErrorIs(t, err, tc.replayErr)
+			} else {
+				require.NoError(t, err)
+			}
+			assert.Equal(t, tc.expectedStats, stats)
+			require.Len(t, replayer.clockTimes, len(tc.expectedClockTimes))
+			for i, expected := range tc.expectedClockTimes {
+				assert.True(t, expected.Equal(replayer.clockTimes[i]), replayer.clockTimes[i])
+			}
+		})
+	}
+}
+
+func TestReplayClockNeverMovesBack(t *testing.T) {
+	// given
+	start := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	clock := newReplayClock(start)
+
+	// when
+	clock.Advance(start.Add(time.Minute))
+	clock.Advance(start.Add(-time.Minute))
+
+	// then
+	assert.True(t, start.Add(time.Minute).Equal(clock.Now()))
+}
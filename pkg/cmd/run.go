@@ -3,17 +3,17 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"os"
 	"time"
 
 	"github.com/d8a-tech/d8a/pkg/columns"
 	"github.com/d8a-tech/d8a/pkg/currency"
+	"github.com/d8a-tech/d8a/pkg/dbip"
 	"github.com/d8a-tech/d8a/pkg/properties"
-	"github.com/d8a-tech/d8a/pkg/protocol"
 	"github.com/d8a-tech/d8a/pkg/receiver"
 	"github.com/d8a-tech/d8a/pkg/schema"
+	"github.com/d8a-tech/d8a/pkg/storage"
 	"github.com/d8a-tech/d8a/pkg/telemetry"
 	"github.com/d8a-tech/d8a/pkg/util"
 	"github.com/d8a-tech/d8a/pkg/worker"
@@ -153,18 +153,22 @@ func Run(ctx context.Context, cancel context.CancelFunc, args []string) error {
 					geoProvider.Run(ctx)
 					defer geoCleanup()
 
-					protocol := protocolByID(cmd.String(protocolFlag.Name), cmd, converter)
+					settings, err := propertySettings(cmd).GetByPropertyID(cmd.String(propertyIDFlag.Name))
+					if err != nil {
+						return err
+					}
+					protocol := protocolByID(settings.ProtocolID, cmd, converter)
 					if protocol == nil {
-						return fmt.Errorf("protocol %s not found", cmd.String(protocolFlag.Name))
+						return fmt.Errorf("protocol %s not found", settings.ProtocolID)
 					}
 					cr := columnsRegistry(cmd, converter, geoProvider) // nolint:contextcheck // false positive
-					columnData, err := cr.Get(cmd.String("property-id"))
+					columnData, err := cr.Get(settings.PropertyID)
 					if err != nil {
 						return err
 					}
 					ordering := schema.NewInterfaceDefinitionOrderKeeper(
 						columns.CoreInterfaces,
-						protocol.Interfaces(), // This hardcodes ga4, it's fine for now for OSS
+						protocol.Interfaces(),
 					)
 					columnData = schema.Sorted(columnData, ordering)
 					formatters := map[string]columnsFormatter{
@@ -222,9 +226,20 @@ func Run(ctx context.Context, cancel context.CancelFunc, args []string) error {
 						}
 					}()
 
-					if err := migrate(ctx, cmd, cmd.String(propertyIDFlag.Name), whr, converter, geoProvider); err != nil {
-						return fmt.Errorf("failed to migrate: %w", err)
+					for _, propertyID := range configuredPropertyIDs(cmd) {
+						if err := migrate(ctx, cmd, propertyID, whr, converter, geoProvider); err != nil {
+							return fmt.Errorf("failed to migrate property %s: %w", propertyID, err)
+						}
 					}
+					// Properties added or changed by a settings reload may need new tables or columns
+					propertySettings(cmd).OnReload(func(settingsList []properties.Settings) {
+						for i := range settingsList {
+							propertyID := settingsList[i].PropertyID
+							if err := migrate(ctx, cmd, propertyID, whr, converter, geoProvider); err != nil {
+								logrus.Errorf("failed to migrate property %s after settings reload: %v", propertyID, err)
+							}
+						}
+					})
 
 					bs, err := bootstrap(ctx, cancel, "server", cmd)
 					if err != nil {
@@ -270,7 +285,22 @@ func Run(ctx context.Context, cancel context.CancelFunc, args []string) error {
 					}()
 
 					// Start server and handle its error
-					server := buildReceiverServer(cmd, serverStorage, converter)
+					rawLogStorage, cleanupRawLog, err := buildRawLogStorage(ctx, cmd)
+					if err != nil {
+						return err
+					}
+					defer cleanupRawLog()
+					receiverKV, cleanupReceiverKV, err := buildReceiverKV(cmd)
+					if err != nil {
+						return err
+					}
+					defer cleanupReceiverKV()
+					server, err := buildReceiverServer(
+						cmd, serverStorage, rawLogStorage, converter, receiverKV, receiverKV, bs.receiverOptions...,
+					)
+					if err != nil {
+						return err
+					}
 					serverErr := server.Run(ctx)
 					if serverErr != nil {
 						logrus.Errorf("server error: %v", serverErr)
@@ -330,7 +360,22 @@ func Run(ctx context.Context, cancel context.CancelFunc, args []string) error {
 						return err
 					}
 					defer cleanupReceiverStorage()
-					server := buildReceiverServer(cmd, serverStorage, converter)
+					rawLogStorage, cleanupRawLog, err := buildRawLogStorage(ctx, cmd)
+					if err != nil {
+						return err
+					}
+					defer cleanupRawLog()
+					receiverKV, cleanupReceiverKV, err := buildReceiverKV(cmd)
+					if err != nil {
+						return err
+					}
+					defer cleanupReceiverKV()
+					server, err := buildReceiverServer(
+						cmd, serverStorage, rawLogStorage, converter, receiverKV, receiverKV, bs.receiverOptions...,
+					)
+					if err != nil {
+						return err
+					}
 					return server.Run(ctx)
 				},
 			},
@@ -463,6 +508,7 @@ func Run(ctx context.Context, cancel context.CancelFunc, args []string) error {
 		},
 	}
 
+	app.Commands = append(app.Commands, replayCommands()...)
 	app.Commands = append(app.Commands, localfetchCommands()...)
 
 	if err := app.Run(ctx, append([]string{os.Args[0]}, args...)); err != nil {
@@ -472,90 +518,73 @@ func Run(ctx context.Context, cancel context.CancelFunc, args []string) error {
 	return nil
 }
 
-// buildReceiverServer constructs a receiver.Server from CLI flags and the given storage.
-// For as long as we don't support multi-property, we return a single protocol.
-func buildReceiverServer(cmd *cli.Command, storage receiver.Storage, converter currency.Converter) *receiver.Server {
-	currentProtocol := protocolByID(cmd.String(protocolFlag.Name), cmd, converter)
-	if currentProtocol == nil {
-		logrus.Panicf("protocol %s not found", cmd.String(protocolFlag.Name))
+// buildReceiverServer constructs a receiver.Server from CLI flags and the given storages.
+// Endpoints of every protocol used by a configured property are registered, hits are
+// routed to properties by PropertyProtocolMatchesTheEndpointProtocol.
+// Cookieless client IDs are salted with the salts kept in saltKV and hits are deduplicated
+// with the hits seen kept in deduplicationKV. The given options are applied after the ones
+// built from the flags.
+func buildReceiverServer(
+	cmd *cli.Command,
+	hitStorage receiver.Storage,
+	rawLogStorage receiver.RawLogStorage,
+	converter currency.Converter,
+	saltKV storage.KV,
+	deduplicationKV storage.KV,
+	opts ...receiver.ServerOption,
+) (*receiver.Server, error) {
+	settingsRegistry := propertySettings(cmd)
+	botDetector, err := buildBotDetector(cmd)
+	if err != nil {
+		return nil, err
 	}
+	cookielessSalt := receiver.NewDailySalt(saltKV, cookielessSaltRetention(cmd))
 
-	settingsRegistry := propertySettings(cmd)
+	validationRules := receiver.HitValidatingRuleSet(
+		1024*util.SafeIntToUint32(cmd.Int(receiverMaxHitKbytesFlag.Name)),
+		settingsRegistry,
+	)
+	serverOptions := append([]receiver.ServerOption{
+		receiver.WithHost(cmd.String(serverHostFlag.Name)),
+		receiver.WithHitProcessingRule(receiver.NewMultipleHitProcessingRule(
+			receiver.BotFiltering(settingsRegistry, botDetector),
+			receiver.CookielessClientID(settingsRegistry, cookielessSalt),
+			receiver.RateLimiting(settingsRegistry),
+			receiver.OptOutEnforcement(settingsRegistry),
+			receiver.IPMasking(settingsRegistry),
+		)),
+		// Rate limits were enforced when the requests were received, requests rejected then
+		// aren't in the raw log. Replays run at their own pace, they aren't limited again.
+		receiver.WithReplayHitProcessingRule(receiver.NewMultipleHitProcessingRule(
+			receiver.BotFiltering(settingsRegistry, botDetector),
+			receiver.CookielessClientID(settingsRegistry, cookielessSalt),
+			receiver.OptOutEnforcement(settingsRegistry),
+			receiver.IPMasking(settingsRegistry),
+		)),
+		// Tracking plan violations were counted when the requests were received
+		receiver.WithReplayHitValidatingRule(validationRules),
+		receiver.WithIngestionAuth(settingsRegistry),
+		trustedProxiesOption(cmd.StringSlice(serverTrustedProxiesFlag.Name)),
+		receiver.WithProxyOnlyHeaders(dbip.EdgeGeoHeaders...),
+	}, opts...)
 
 	return receiver.NewServer(
-		storage,
-		receiver.NewNoopRawLogStorage(),
-		receiver.HitValidatingRuleSet(
-			1024*util.SafeIntToUint32(cmd.Int(receiverMaxHitKbytesFlag.Name)),
-			settingsRegistry,
-		),
-		[]protocol.Protocol{currentProtocol},
+		receiver.NewDeduplicatingStorage(hitStorage, settingsRegistry, deduplicationKV),
+		rawLogStorage,
+		receiver.NewMultipleHitValidatingRule(validationRules, receiver.TrackingPlanViolations(settingsRegistry)),
+		receiverProtocols(cmd, converter),
 		cmd.Int(serverPortFlag.Name),
-		receiver.WithHost(cmd.String(serverHostFlag.Name)),
-		receiver.WithHitProcessingRule(receiver.IPMasking(settingsRegistry)),
-		trustedProxiesOption(cmd.StringSlice(serverTrustedProxiesFlag.Name)),
-	)
+		serverOptions...,
+	), nil
 }
 
-func propertySettings(cmd *cli.Command) properties.SettingsRegistry {
-	settings := &properties.Settings{
-		ProtocolID:                 cmd.String(protocolFlag.Name),
-		PropertyID:                 cmd.String(propertyIDFlag.Name),
-		PropertyName:               cmd.String(propertyNameFlag.Name),
-		PropertyMeasurementID:      "-",
-		SplitByUserID:              cmd.Bool(propertySettingsSplitByUserIDFlag.Name),
-		SplitByCampaign:            cmd.Bool(propertySettingsSplitByCampaignFlag.Name),
-		SplitByTimeSinceFirstEvent: cmd.Duration(propertySettingsSplitByTimeSinceFirstEventFlag.Name),
-		SplitByMaxEvents:           cmd.Int(propertySettingsSplitByMaxEventsFlag.Name),
-		ExcludedURLParams:          cmd.StringSlice(propertySettingsExcludedURLParamsFlag.Name),
-		SessionTimeout:             cmd.Duration(sessionsTimeoutFlag.Name),
-		SessionJoinBySessionStamp:  cmd.Bool(sessionsJoinBySessionStampFlag.Name),
-		SessionJoinByUserID:        cmd.Bool(sessionsJoinByUserIDFlag.Name),
-		IPMaskingLevel:             cmd.Int(propertySettingsIPMaskingLevelFlag.Name),
-		Filters: func() *properties.FiltersConfig {
-			var filtersConfig properties.FiltersConfig
-			// Config file is optional; stat before parsing
-			if _, err := os.Stat(configFile); err == nil {
-				var parseErr error
-				filtersConfig, parseErr = properties.ParseFilterConfig(configFile)
-				if parseErr != nil {
-					logrus.Panicf("failed to parse filters config: %v", parseErr)
-				}
-			}
-			// Override fields from YAML with flag value (flag takes precedence)
-			filtersConfig.Fields = cmd.StringSlice(filtersFieldsFlag.Name)
-
-			// Parse and append JSON-encoded conditions from flag/env
-			flagConditions := cmd.StringSlice(filtersConditionsFlag.Name)
-			for _, conditionJSON := range flagConditions {
-				var condition properties.ConditionConfig
-				if err := json.Unmarshal([]byte(conditionJSON), &condition); err != nil {
-					logrus.Warnf("skipping invalid JSON condition %q: %v", conditionJSON, err)
-					continue
-				}
-				filtersConfig.Conditions = append(filtersConfig.Conditions, condition)
-			}
-
-			return &filtersConfig
-		}(),
-		CustomColumns: func() []properties.CustomColumnConfig {
-			customColumns, loadErr := loadProtocolCustomColumns(cmd)
-			if loadErr != nil {
-				logrus.Panicf("failed to load protocol custom columns config: %v", loadErr)
-			}
-
-			return customColumns
-		}(),
+// cookielessSaltRetention returns how long the salts of cookieless client IDs are kept past
+// their day: as long as the raw log, for replayed requests to get the client IDs they got
+// when received. Without a raw log, or with one kept forever, only the salt of the current
+// day is kept.
+func cookielessSaltRetention(cmd *cli.Command) time.Duration {
+	if !cmd.Bool(rawLogEnabledFlag.Name) {
+		return 0
 	}
-
-	if err := properties.ValidateSettings(settings); err != nil {
-		logrus.Panicf("invalid property settings: %v", err)
-	}
-
-	return properties.NewStaticSettingsRegistry(
-		[]properties.Settings{},
-		properties.WithDefaultConfig(
-			settings,
-		),
-	)
+	return cmd.Duration(rawLogRetentionFlag.Name)
 }
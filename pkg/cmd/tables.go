@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 
 	"github.com/d8a-tech/d8a/pkg/columns/eventcolumns"
@@ -9,6 +10,8 @@ import (
 	"github.com/d8a-tech/d8a/pkg/currency"
 	"github.com/d8a-tech/d8a/pkg/customcolumns"
 	"github.com/d8a-tech/d8a/pkg/dbip"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/protocol/ga4"
 	"github.com/d8a-tech/d8a/pkg/schema"
 	"github.com/sirupsen/logrus"
 	"github.com/urfave/cli/v3"
@@ -31,6 +34,31 @@ func getTableNames(cmd *cli.Command) tables {
 	}
 }
 
+// ga4BigQueryExportLayout is the warehouse-layout writing rows shaped like the GA4 BigQuery export
+const ga4BigQueryExportLayout = "ga4-bigquery-export"
+
+func tableLayout(cmd *cli.Command) schema.Layout {
+	tableNames := getTableNames(cmd)
+	switch cmd.String(warehouseLayoutFlag.Name) {
+	case "embedded", "":
+		return schema.NewEmbeddedSessionColumnsLayout(tableNames.events, tableNames.sessionsColumnPrefix)
+	case "separate-sessions":
+		sessionsTable := cmd.String(warehouseSessionsTableFlag.Name)
+		if sessionsTable == "" || sessionsTable == tableNames.events {
+			logrus.Fatalf("warehouse-sessions-table must be non-empty and differ from warehouse-table")
+		}
+		return schema.NewSeparateSessionsTableLayout(tableNames.events, sessionsTable)
+	case ga4BigQueryExportLayout:
+		if driver := strings.ToLower(cmd.String(warehouseDriverFlag.Name)); driver != "bigquery" {
+			logrus.Fatalf("warehouse-layout=%s requires warehouse-driver=bigquery, got %q", ga4BigQueryExportLayout, driver)
+		}
+		return ga4.NewBigQueryExportLayout(tableNames.events)
+	default:
+		logrus.Fatalf("unsupported warehouse-layout: %s", cmd.String(warehouseLayoutFlag.Name))
+		return nil
+	}
+}
+
 var crLock = sync.Mutex{}
 var cr map[string]schema.ColumnsRegistry
 
@@ -40,26 +68,23 @@ func columnsRegistry(
 	geoProvider dbip.LookupProvider,
 ) schema.ColumnsRegistry {
 	psr := propertySettings(cmd)
-	settings, err := psr.GetByPropertyID(cmd.String(propertyIDFlag.Name))
-	if err != nil {
-		logrus.Panicf("failed to get property settings: %v", err)
-	}
-	protocol := protocolByID(settings.ProtocolID, cmd, converter)
-	if protocol == nil {
-		logrus.Panicf("protocol %s not found", settings.ProtocolID)
-	}
 	crLock.Lock()
 	defer crLock.Unlock()
 	if cr == nil {
 		cr = make(map[string]schema.ColumnsRegistry)
 	}
 
-	cacheKey := settings.ProtocolID + ":" + fmt.Sprintf("%T", converter)
+	// Protocols are resolved from the shared settings registry on every lookup, so the
+	// registry stays valid when property settings are reloaded.
+	cacheKey := fmt.Sprintf("%p", psr) + ":" + fmt.Sprintf("%T", converter)
 	cacheKey += ":" + fmt.Sprintf("%p", geoProvider)
 	if registry, ok := cr[cacheKey]; ok {
 		return registry
 	}
 
+	if cmd.Bool(geoEdgeHeadersEnabledFlag.Name) {
+		geoProvider = dbip.NewEdgeHeadersLookupProvider(geoProvider)
+	}
 	var opts []columnset.ColumnSetOption
 	opts = append(opts, columnset.WithGeoProvider(geoProvider))
 
@@ -75,10 +100,10 @@ func columnsRegistry(
 
 	opts = append(opts, columnset.WithCustomColumnsRegistry(
 		customcolumns.NewCustomColumnsPropertySettingsRegistry(psr, customcolumns.NewBuilder()),
-	))
+	), columnset.WithCustomColumnsRegistry(ga4.NewParamsMapColumnsRegistry(psr)))
 
-	registry := columnset.DefaultColumnRegistry(
-		protocol,
+	registry := columnset.ColumnRegistry(
+		protocol.NewFromPropertySettingsRegistry(psr, protocols(cmd, converter)),
 		psr,
 		opts...,
 	)
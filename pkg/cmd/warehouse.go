@@ -13,11 +13,13 @@ import (
 	"cloud.google.com/go/bigquery"
 	"github.com/ClickHouse/clickhouse-go/v2"
 	"github.com/d8a-tech/d8a/pkg/bolt"
+	"github.com/d8a-tech/d8a/pkg/protocol/ga4"
 	"github.com/d8a-tech/d8a/pkg/spools"
 	"github.com/d8a-tech/d8a/pkg/warehouse"
 	whBigQuery "github.com/d8a-tech/d8a/pkg/warehouse/bigquery"
 	whClickhouse "github.com/d8a-tech/d8a/pkg/warehouse/clickhouse"
 	whFiles "github.com/d8a-tech/d8a/pkg/warehouse/files"
+	whPostgres "github.com/d8a-tech/d8a/pkg/warehouse/postgres"
 	pgGzip "github.com/parquet-go/parquet-go/compress/gzip"
 	"github.com/parquet-go/parquet-go/compress/snappy"
 	"github.com/parquet-go/parquet-go/compress/zstd"
@@ -45,6 +47,10 @@ func warehouseRegistry(ctx context.Context, cmd *cli.Command) warehouse.Registry
 		return createBigQueryWarehouse(ctx, cmd)
 	case "clickhouse":
 		return createClickHouseWarehouse(ctx, cmd)
+	case "postgres":
+		return createPostgresWarehouse(ctx, cmd)
+	case "duckdb":
+		return createDuckDBWarehouse(cmd)
 	case "files":
 		return createFilesWarehouse(ctx, cmd)
 	case "console", "":
@@ -119,6 +125,13 @@ func createBigQueryPartitionOption(cmd *cli.Command) whBigQuery.BigQueryTableDri
 	if partitionField == "" {
 		return nil
 	}
+	// The GA4 export has no date column of its own, the layout adds one to partition by
+	if cmd.String(warehouseLayoutFlag.Name) == ga4BigQueryExportLayout &&
+		partitionField != ga4.BigQueryExportPartitionField {
+		logrus.Warnf("partitioning the %s layout by %s instead of %s",
+			ga4BigQueryExportLayout, ga4.BigQueryExportPartitionField, partitionField)
+		partitionField = ga4.BigQueryExportPartitionField
+	}
 
 	intervalRaw := strings.ToUpper(strings.TrimSpace(cmd.String(warehouseBigQueryPartitionIntervalFlag.Name)))
 	var interval whBigQuery.PartitionInterval
@@ -241,6 +254,45 @@ func createClickHouseWarehouse(ctx context.Context, cmd *cli.Command) warehouse.
 	return warehouse.NewStaticDriverRegistry(driver)
 }
 
+func createPostgresWarehouse(ctx context.Context, cmd *cli.Command) warehouse.Registry {
+	connString := strings.TrimSpace(cmd.String(warehousePostgresConnectionStringFlag.Name))
+	if connString == "" {
+		logrus.Fatalf("warehouse-postgres-connection-string must be set when warehouse-driver=postgres")
+	}
+
+	schema := strings.TrimSpace(cmd.String(warehousePostgresSchemaFlag.Name))
+	if schema == "" {
+		schema = warehousePostgresSchemaFlag.Value
+	}
+
+	var opts []whPostgres.Option
+	if partitionField := strings.TrimSpace(cmd.String(warehousePostgresPartitionFieldFlag.Name)); partitionField != "" {
+		intervalRaw := strings.ToUpper(strings.TrimSpace(cmd.String(warehousePostgresPartitionIntervalFlag.Name)))
+		var interval whPostgres.PartitionInterval
+		switch intervalRaw {
+		case string(whPostgres.PartitionIntervalDay):
+			interval = whPostgres.PartitionIntervalDay
+		case string(whPostgres.PartitionIntervalMonth), "":
+			interval = whPostgres.PartitionIntervalMonth
+		case string(whPostgres.PartitionIntervalYear):
+			interval = whPostgres.PartitionIntervalYear
+		default:
+			logrus.Fatalf("unsupported postgres partition interval %q (expected DAY, MONTH, YEAR)", intervalRaw)
+		}
+		opts = append(opts, whPostgres.WithPartitionBy(whPostgres.PartitioningConfig{
+			Field:    partitionField,
+			Interval: interval,
+		}))
+	}
+
+	driver, err := whPostgres.NewPostgresTableDriver(ctx, connString, schema, opts...)
+	if err != nil {
+		logrus.Fatalf("failed to create PostgreSQL warehouse driver: %v", err)
+	}
+
+	return warehouse.NewStaticDriverRegistry(driver)
+}
+
 func createFilesWarehouse(ctx context.Context, cmd *cli.Command) warehouse.Registry {
 	format := cmd.String(warehouseFilesFormatFlag.Name)
 
@@ -0,0 +1,26 @@
+//go:build cgo && duckdb_arrow
+
+package cmd
+
+import (
+	"strings"
+
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	whDuckDB "github.com/d8a-tech/d8a/pkg/warehouse/duckdb"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+)
+
+func createDuckDBWarehouse(cmd *cli.Command) warehouse.Registry {
+	path := strings.TrimSpace(cmd.String(warehouseDuckDBPathFlag.Name))
+	if path == "" {
+		logrus.Fatalf("warehouse-duckdb-path must be set when warehouse-driver=duckdb")
+	}
+
+	driver, err := whDuckDB.NewDuckDBTableDriver(path)
+	if err != nil {
+		logrus.Fatalf("failed to create DuckDB warehouse driver: %v", err)
+	}
+
+	return warehouse.NewStaticDriverRegistry(driver)
+}
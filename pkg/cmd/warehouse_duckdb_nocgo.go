@@ -0,0 +1,14 @@
+//go:build !cgo || !duckdb_arrow
+
+package cmd
+
+import (
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/sirupsen/logrus"
+	"github.com/urfave/cli/v3"
+)
+
+func createDuckDBWarehouse(*cli.Command) warehouse.Registry {
+	logrus.Fatalf("warehouse-driver=duckdb requires a binary built with CGO_ENABLED=1 and -tags duckdb_arrow")
+	return nil
+}
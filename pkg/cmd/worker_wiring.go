@@ -11,6 +11,7 @@ import (
 	"github.com/d8a-tech/d8a/pkg/dbip"
 	"github.com/d8a-tech/d8a/pkg/encoding"
 	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
 	"github.com/d8a-tech/d8a/pkg/protosessions"
 	"github.com/d8a-tech/d8a/pkg/receiver"
 	"github.com/d8a-tech/d8a/pkg/schema"
@@ -55,20 +56,19 @@ func buildWorkerRuntime(
 	cr := columnsRegistry(cmd, converter, geoProvider) // nolint:contextcheck // false positive
 	layoutRegistry := schema.NewStaticLayoutRegistry(
 		map[string]schema.Layout{},
-		schema.NewEmbeddedSessionColumnsLayout(
-			getTableNames(cmd).events,
-			getTableNames(cmd).sessionsColumnPrefix,
-		),
+		tableLayout(cmd),
 	)
 	splitterRegistry := splitter.NewFromPropertySettingsRegistry(
 		propertySettings(cmd),
 		splitter.WithCapacity(5),
-		splitter.WithTTL(30*24*time.Hour), // Static config, so no need to invalidate
+		splitter.WithTTL(30*24*time.Hour), // Invalidated explicitly on settings reload
 	)
 	// Special case for OSS - on top of registry we validate if rules compile right away
-	_, err = splitterRegistry.SessionModifier(cmd.String(propertyIDFlag.Name))
-	if err != nil {
-		logrus.Panicf("failed to create session modifier: %v", err)
+	for _, propertyID := range configuredPropertyIDs(cmd) {
+		_, err = splitterRegistry.SessionModifier(propertyID)
+		if err != nil {
+			logrus.Panicf("failed to create session modifier for property %s: %v", propertyID, err)
+		}
 	}
 
 	var inMemCleanup func()
@@ -99,6 +99,14 @@ func buildWorkerRuntime(
 		layoutRegistry,
 		splitterRegistry,
 	)
+	// Drop cached session modifiers, columns and layouts once property settings change
+	propertySettings(cmd).OnReload(func([]properties.Settings) {
+		for _, c := range []any{splitterRegistry, sessionWriter} {
+			if invalidator, ok := c.(interface{ InvalidateCaches() }); ok {
+				invalidator.InvalidateCaches()
+			}
+		}
+	})
 	if cmd.Bool(storageSpoolEnabledFlag.Name) {
 		spoolDir := filepath.Join(cmd.String(storageSpoolDirectoryFlag.Name), "sessionwriter")
 
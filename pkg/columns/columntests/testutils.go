@@ -368,6 +368,12 @@ func SetColumnsRegistry(columnsRegistry schema.ColumnsRegistry) CaseConfigFunc {
 	}
 }
 
+func SetLayoutRegistry(layoutRegistry schema.LayoutRegistry) CaseConfigFunc {
+	return func(t *testing.T, c *CaseConfig) {
+		c.layoutRegistry = layoutRegistry
+	}
+}
+
 func SetSplitterRegistry(splitterRegistry splitter.Registry) CaseConfigFunc {
 	return func(t *testing.T, c *CaseConfig) {
 		c.splitterRegistry = splitterRegistry
@@ -10,21 +10,24 @@ import (
 
 // CoreInterfaces are the core columns that are always present in the schema.
 var CoreInterfaces = struct {
-	EventID           schema.Interface
-	EventName         schema.Interface
-	EventPropertyID   schema.Interface
-	EventPropertyName schema.Interface
-	EventDateUTC      schema.Interface
-	EventTimestampUTC schema.Interface
-	EventClientID     schema.Interface
-	EventUserID       schema.Interface
-	EventIPAddress    schema.Interface
-	EventPageLocation schema.Interface
-	EventPageHostname schema.Interface
-	EventPagePath     schema.Interface
-	EventPageTitle    schema.Interface
-	EventPageReferrer schema.Interface
-	EventPlatform     schema.Interface
+	EventID                     schema.Interface
+	EventName                   schema.Interface
+	EventPropertyID             schema.Interface
+	EventPropertyName           schema.Interface
+	EventDateUTC                schema.Interface
+	EventTimestampUTC           schema.Interface
+	EventClientID               schema.Interface
+	EventUserID                 schema.Interface
+	EventIPAddress              schema.Interface
+	EventPageLocation           schema.Interface
+	EventPageHostname           schema.Interface
+	EventPagePath               schema.Interface
+	EventPageTitle              schema.Interface
+	EventPageReferrer           schema.Interface
+	EventPlatform               schema.Interface
+	EventIsBot                  schema.Interface
+	EventPrivacyOptOut          schema.Interface
+	EventTrackingPlanViolations schema.Interface
 
 	// Event UTM parameters
 	EventUtmCampaign        schema.Interface
@@ -254,6 +257,22 @@ var CoreInterfaces = struct {
 			Metadata: arrow.NewMetadata([]string{meta.ClickhouseLowCardinalityMetadata}, []string{"true"}),
 		},
 	},
+	EventIsBot: schema.Interface{
+		ID:    "core.d8a.tech/events/is_bot",
+		Field: &arrow.Field{Name: "is_bot", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+	},
+	EventPrivacyOptOut: schema.Interface{
+		ID:    "core.d8a.tech/events/privacy_opt_out",
+		Field: &arrow.Field{Name: "privacy_opt_out", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+	},
+	EventTrackingPlanViolations: schema.Interface{
+		ID: "core.d8a.tech/events/tracking_plan_violations",
+		Field: &arrow.Field{
+			Name:     "tracking_plan_violations",
+			Type:     arrow.ListOf(arrow.BinaryTypes.String),
+			Nullable: true,
+		},
+	},
 	EventUtmCampaign: schema.Interface{
 		ID:    "core.d8a.tech/events/utm_campaign",
 		Field: &arrow.Field{Name: "utm_campaign", Type: arrow.BinaryTypes.String, Nullable: true},
@@ -0,0 +1,25 @@
+package eventcolumns
+
+import (
+	"github.com/d8a-tech/d8a/pkg/botdetection"
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// IsBotColumn is the column telling whether an event was sent by a bot
+var IsBotColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventIsBot.ID,
+	columns.CoreInterfaces.EventIsBot.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		v, ok := event.BoundHit.Metadata[botdetection.IsBotMetadataKey]
+		if !ok {
+			return nil, nil // nolint:nilnil // nil is valid
+		}
+		return v == "true", nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Is Bot",
+		"Whether the event was sent by a bot or crawler, detected by user agent or datacenter IP range. Empty unless the property's bot filter mode is 'tag' or 'drop'.", // nolint:lll // it's a description
+	),
+)
@@ -0,0 +1,25 @@
+package eventcolumns
+
+import (
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/privacysignals"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// PrivacyOptOutColumn is the column telling whether the visitor opted out of tracking
+var PrivacyOptOutColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventPrivacyOptOut.ID,
+	columns.CoreInterfaces.EventPrivacyOptOut.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		v, ok := event.BoundHit.Metadata[privacysignals.OptOutMetadataKey]
+		if !ok {
+			return nil, nil // nolint:nilnil // nil is valid
+		}
+		return v == "true", nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Privacy Opt Out",
+		"Whether the visitor opted out of tracking, with the Sec-GPC or DNT header, or GA4 consent params denying analytics storage or ads personalization. Empty unless the property's opt-out mode is 'flag' or 'strip'.", // nolint:lll // it's a description
+	),
+)
@@ -1,12 +1,14 @@
 package eventcolumns
 
 import (
+	"github.com/d8a-tech/d8a/pkg/botdetection"
 	"github.com/d8a-tech/d8a/pkg/columns"
 	"github.com/d8a-tech/d8a/pkg/schema"
 )
 
 // SSETrafficFilterName is a session-scoped event column that reads event metadata
-// set by the filter system during testing mode (when test_mode: true).
+// set by the filter system during testing mode (when test_mode: true), falling back
+// to the bot filter tagging the hit in the receiver.
 var SSETrafficFilterName = columns.NewSimpleSessionScopedEventColumn(
 	columns.CoreInterfaces.SSETrafficFilterName.ID,
 	columns.CoreInterfaces.SSETrafficFilterName.Field,
@@ -16,7 +18,11 @@ var SSETrafficFilterName = columns.NewSimpleSessionScopedEventColumn(
 		}
 		v, ok := s.Events[i].Metadata["traffic_filter_name"]
 		if !ok {
-			return nil, nil //nolint:nilnil // nil is a valid value for this column
+			botFilterName, tagged := s.Events[i].BoundHit.Metadata[botdetection.TrafficFilterNameMetadataKey]
+			if !tagged {
+				return nil, nil //nolint:nilnil // nil is a valid value for this column
+			}
+			return botFilterName, nil
 		}
 		trafficType, ok := v.(string)
 		if !ok {
@@ -27,6 +33,6 @@ var SSETrafficFilterName = columns.NewSimpleSessionScopedEventColumn(
 	columns.WithSessionScopedEventColumnRequired(false),
 	columns.WithSessionScopedEventColumnDocs(
 		"Traffic Type",
-		"Name of the traffic filter that matched this event in testing mode. If the filter were active, this event would have been excluded.", // nolint:lll // it's a description
+		"Name of the traffic filter that matched this event in testing mode. If the filter were active, this event would have been excluded. Bot traffic tagged by the bot filter is named 'bot: <name of the bot>'.", // nolint:lll // it's a description
 	),
 )
@@ -11,6 +11,7 @@ import (
 	"github.com/d8a-tech/d8a/pkg/protocol"
 	"github.com/d8a-tech/d8a/pkg/protocolschema"
 	"github.com/d8a-tech/d8a/pkg/schema"
+	"github.com/d8a-tech/d8a/pkg/trackingplan"
 )
 
 // columnSetConfig holds the configuration for column set initialization.
@@ -71,6 +72,23 @@ func DefaultColumnRegistry(
 	theProtocol protocol.Protocol,
 	psr properties.SettingsRegistry,
 	opts ...ColumnSetOption,
+) schema.ColumnsRegistry {
+	return ColumnRegistry(
+		protocol.NewStaticRegistry(
+			map[string]protocol.Protocol{},
+			theProtocol,
+		),
+		psr,
+		opts...,
+	)
+}
+
+// ColumnRegistry returns a column registry, which resolves protocol columns per property
+// using the given protocol registry.
+func ColumnRegistry(
+	protocolRegistry protocol.Registry,
+	psr properties.SettingsRegistry,
+	opts ...ColumnSetOption,
 ) schema.ColumnsRegistry {
 	cfg := newDefaultColumnSetConfig()
 	for _, opt := range opts {
@@ -93,12 +111,13 @@ func DefaultColumnRegistry(
 	registries := []schema.ColumnsRegistry{
 		schema.NewStaticColumnsRegistry(
 			map[string]schema.Columns{},
-			schema.NewColumns(sessionColumns(), eventColumns(psr), sessionScopedEventColumns()),
+			schema.NewColumns(
+				sessionColumns(),
+				append(eventColumns(psr), trackingplan.ViolationsColumn(psr, protocolRegistry)),
+				sessionScopedEventColumns(),
+			),
 		),
-		protocolschema.NewFromProtocolColumnsRegistry(protocol.NewStaticRegistry(
-			map[string]protocol.Protocol{},
-			theProtocol,
-		)),
+		protocolschema.NewFromProtocolColumnsRegistry(protocolRegistry),
 		schema.NewStaticColumnsRegistry(
 			map[string]schema.Columns{},
 			schema.NewColumns([]schema.SessionColumn{}, injectedColumns, []schema.SessionScopedEventColumn{}),
@@ -117,6 +136,8 @@ func eventColumns(psr properties.SettingsRegistry) []schema.EventColumn {
 		eventcolumns.EventIDColumn,
 		eventcolumns.EventNameColumn,
 		eventcolumns.IPAddressColumn,
+		eventcolumns.IsBotColumn,
+		eventcolumns.PrivacyOptOutColumn,
 		eventcolumns.ClientIDColumn,
 		eventcolumns.UserIDColumn,
 		eventcolumns.PropertyIDColumn,
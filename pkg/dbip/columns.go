@@ -76,6 +76,14 @@ func (t *GeoColumnFactory) Column(
 					return getValue(event, typedComputedRecord)
 				}
 			}
+			// Requests geolocated by headers aren't cached by IP, other requests from the
+			// same IP may not have the headers
+			if requestProvider, ok := t.provider.(RequestLookupProvider); ok {
+				if record, found := requestProvider.LookupRequest(event.BoundHit.MustParsedRequest()); found {
+					event.Metadata[geoRecordMetadataKey] = record
+					return getValue(event, record)
+				}
+			}
 			// Check if for given IP there is a cache hit (calculated for other event)
 			cacheHit, ok := t.cache.Get(event.BoundHit.MustParsedRequest().IP)
 			if ok {
@@ -107,3 +107,50 @@ func TestDBIPColumns_UsesMaskedIPAlreadyPresentOnHit(t *testing.T) {
 	assert.Equal(t, netip.MustParseAddr("192.168.1.0"), provider.lookupIP)
 	assert.Equal(t, "Masked City", event.Values[columns.CoreInterfaces.GeoCity.Field.Name])
 }
+
+func TestDBIPColumns_EdgeHeadersTakePrecedenceOverIPLookup(t *testing.T) {
+	testCases := []struct {
+		name            string
+		country         string
+		expectedCountry string
+		expectLookup    bool
+	}{
+		{
+			name:            "edge header",
+			country:         "DE",
+			expectedCountry: "Germany",
+		},
+		{
+			name:            "no edge header",
+			expectedCountry: "Poland",
+			expectLookup:    true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			fallback := &recordingLookupProvider{result: &dbip.LookupResult{Country: "Poland"}}
+			factory, err := dbip.NewGeoColumnFactory(
+				dbip.NewEdgeHeadersLookupProvider(fallback),
+				dbip.CacheConfig{MaxEntries: 10},
+			)
+			require.NoError(t, err)
+
+			hit := hits.New()
+			hit.Request.IP = "80.68.239.25"
+			if tc.country != "" {
+				hit.Request.Headers.Set("CF-IPCountry", tc.country)
+			}
+			event := schema.NewEvent(hit)
+
+			// when
+			writeErr := dbip.CountryColumn(factory).Write(event)
+
+			// then
+			require.NoError(t, writeErr)
+			assert.Equal(t, tc.expectedCountry, event.Values[columns.CoreInterfaces.GeoCountry.Field.Name])
+			assert.Equal(t, tc.expectLookup, fallback.lookupIP.IsValid())
+		})
+	}
+}
@@ -0,0 +1,125 @@
+package dbip
+
+import (
+	"net/netip"
+	"strings"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"golang.org/x/text/language"
+	"golang.org/x/text/language/display"
+)
+
+// RequestLookupProvider is a LookupProvider which can also geolocate a request without
+// looking up its IP, e.g. from headers set by a CDN.
+type RequestLookupProvider interface {
+	LookupProvider
+	// LookupRequest returns the geolocation of the request, false if the request doesn't
+	// tell it and its IP needs to be looked up.
+	LookupRequest(request *hits.ParsedRequest) (*LookupResult, bool)
+}
+
+// edgeHeaderSet are the geolocation headers set by a CDN or an edge proxy.
+type edgeHeaderSet struct {
+	country string
+	region  string
+	city    string
+}
+
+var edgeHeaderSets = []edgeHeaderSet{
+	{country: "CF-IPCountry", region: "CF-Region", city: "CF-IPCity"},
+	{
+		country: "CloudFront-Viewer-Country",
+		region:  "CloudFront-Viewer-Country-Region-Name",
+		city:    "CloudFront-Viewer-City",
+	},
+	{country: "X-Geo-Country", region: "X-Geo-Region", city: "X-Geo-City"},
+}
+
+// EdgeGeoHeaders are the request headers geolocation is taken from by the provider created
+// with NewEdgeHeadersLookupProvider. They can be set by anyone sending a request, so the
+// receiver must only keep them for requests which came through a trusted proxy.
+var EdgeGeoHeaders = func() []string {
+	headers := make([]string, 0, 3*len(edgeHeaderSets))
+	for _, set := range edgeHeaderSets {
+		headers = append(headers, set.country, set.region, set.city)
+	}
+	return headers
+}()
+
+// continentRegions are the UN M.49 regions of the continents, in the order they're
+// checked. North America includes Central America and the Caribbean.
+var continentRegions = []language.Region{
+	language.MustParseRegion("003"),
+	language.MustParseRegion("005"),
+	language.MustParseRegion("002"),
+	language.MustParseRegion("142"),
+	language.MustParseRegion("150"),
+	language.MustParseRegion("009"),
+}
+
+var antarctica = language.MustParseRegion("AQ")
+
+type edgeHeadersLookupProvider struct {
+	fallback LookupProvider
+}
+
+// NewEdgeHeadersLookupProvider creates a provider taking the country, region and city of
+// requests from the headers set by Cloudflare (CF-IPCountry, CF-Region, CF-IPCity),
+// CloudFront (CloudFront-Viewer-Country, CloudFront-Viewer-Country-Region-Name,
+// CloudFront-Viewer-City) or a proxy setting X-Geo-Country, X-Geo-Region and X-Geo-City.
+// Countries are ISO 3166-1 alpha-2 codes, the continent is derived from the country.
+// Requests without a known country are looked up in the fallback provider.
+func NewEdgeHeadersLookupProvider(fallback LookupProvider) RequestLookupProvider {
+	if fallback == nil {
+		fallback = NewUnavailableLookupProvider()
+	}
+	return &edgeHeadersLookupProvider{fallback: fallback}
+}
+
+// Lookup implements LookupProvider.
+func (p *edgeHeadersLookupProvider) Lookup(ip netip.Addr) (*LookupResult, error) {
+	return p.fallback.Lookup(ip)
+}
+
+// LookupRequest implements RequestLookupProvider.
+func (p *edgeHeadersLookupProvider) LookupRequest(request *hits.ParsedRequest) (*LookupResult, bool) {
+	for _, set := range edgeHeaderSets {
+		country, ok := parseCountry(request.Headers.Get(set.country))
+		if !ok {
+			continue
+		}
+		return &LookupResult{
+			Country:   display.English.Regions().Name(country),
+			Continent: continentName(country),
+			Region:    strings.TrimSpace(request.Headers.Get(set.region)),
+			City:      strings.TrimSpace(request.Headers.Get(set.city)),
+		}, true
+	}
+	return nil, false
+}
+
+// parseCountry parses an ISO 3166-1 alpha-2 country code. Codes CDNs use for unknown
+// countries or Tor, like XX and T1, aren't countries.
+func parseCountry(code string) (language.Region, bool) {
+	code = strings.TrimSpace(code)
+	if len(code) != 2 {
+		return language.Region{}, false
+	}
+	region, err := language.ParseRegion(code)
+	if err != nil || !region.IsCountry() {
+		return language.Region{}, false
+	}
+	return region, true
+}
+
+func continentName(country language.Region) string {
+	if country == antarctica {
+		return "Antarctica"
+	}
+	for _, continent := range continentRegions {
+		if continent.Contains(country) {
+			return display.English.Regions().Name(continent)
+		}
+	}
+	return ""
+}
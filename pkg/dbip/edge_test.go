@@ -0,0 +1,85 @@
+package dbip
+
+import (
+	"net/http"
+	"net/netip"
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEdgeHeadersLookupProvider_LookupRequest(t *testing.T) {
+	testCases := []struct {
+		name     string
+		headers  http.Header
+		expected *LookupResult
+	}{
+		{
+			name: "cloudflare",
+			headers: http.Header{
+				"Cf-Ipcountry": {"PL"},
+				"Cf-Region":    {"Lower Silesia"},
+				"Cf-Ipcity":    {"Wroclaw"},
+			},
+			expected: &LookupResult{City: "Wroclaw", Country: "Poland", Continent: "Europe", Region: "Lower Silesia"},
+		},
+		{
+			name: "cloudfront",
+			headers: http.Header{
+				"Cloudfront-Viewer-Country":             {"US"},
+				"Cloudfront-Viewer-Country-Region-Name": {"Washington"},
+				"Cloudfront-Viewer-City":                {"Seattle"},
+			},
+			expected: &LookupResult{City: "Seattle", Country: "United States", Continent: "North America", Region: "Washington"},
+		},
+		{
+			name:     "generic country only",
+			headers:  http.Header{"X-Geo-Country": {"br"}},
+			expected: &LookupResult{Country: "Brazil", Continent: "South America"},
+		},
+		{
+			name:     "antarctica",
+			headers:  http.Header{"X-Geo-Country": {"AQ"}},
+			expected: &LookupResult{Country: "Antarctica", Continent: "Antarctica"},
+		},
+		{
+			name:    "unknown country",
+			headers: http.Header{"Cf-Ipcountry": {"XX"}},
+		},
+		{
+			name:    "tor",
+			headers: http.Header{"Cf-Ipcountry": {"T1"}, "Cf-Ipcity": {"Nowhere"}},
+		},
+		{
+			name: "no headers",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			provider := NewEdgeHeadersLookupProvider(nil)
+
+			// when
+			result, found := provider.LookupRequest(&hits.ParsedRequest{Headers: tc.headers})
+
+			// then
+			assert.Equal(t, tc.expected != nil, found)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestEdgeHeadersLookupProvider_LookupFallsBack(t *testing.T) {
+	// given
+	provider := NewEdgeHeadersLookupProvider(NewStaticLookupProvider(&LookupResult{Country: "Poland"}, nil))
+
+	// when
+	result, err := provider.Lookup(netip.MustParseAddr("1.1.1.1"))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, "Poland", result.Country)
+}
@@ -3,12 +3,16 @@ package monitoring
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 	"github.com/sirupsen/logrus"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/exporters/otlp/otlpmetric/otlpmetricgrpc"
+	otelprometheus "go.opentelemetry.io/otel/exporters/prometheus"
 	"go.opentelemetry.io/otel/metric"
 	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -62,6 +66,7 @@ func WithAttributes(attrs ...attribute.KeyValue) metric.RecordOption {
 // MetricsSetup encapsulates metrics configuration and lifecycle.
 type MetricsSetup struct {
 	meterProvider *sdkmetric.MeterProvider
+	handler       http.Handler
 }
 
 // Shutdown gracefully shuts down the metrics provider.
@@ -72,8 +77,30 @@ func (m *MetricsSetup) Shutdown(ctx context.Context) error {
 	return nil
 }
 
-// SetupMetrics initializes OTel metrics if enabled.
-// If disabled, does nothing - OTel will use its built-in noop provider.
+// Handler returns the handler serving the metrics in the Prometheus text format, nil if
+// the Prometheus exporter isn't enabled.
+func (m *MetricsSetup) Handler() http.Handler {
+	return m.handler
+}
+
+type metricsConfig struct {
+	prometheus bool
+}
+
+// MetricsOption configures SetupMetrics.
+type MetricsOption func(*metricsConfig)
+
+// WithPrometheusExporter makes the metrics available to Prometheus scrapes through
+// MetricsSetup.Handler, in addition to the OTLP push if it's enabled.
+func WithPrometheusExporter() MetricsOption {
+	return func(c *metricsConfig) {
+		c.prometheus = true
+	}
+}
+
+// SetupMetrics initializes OTel metrics if enabled, pushing them to the OTel collector.
+// If neither the push nor the Prometheus exporter are enabled, does nothing - OTel will use
+// its built-in noop provider.
 func SetupMetrics(
 	ctx context.Context,
 	enabled bool,
@@ -81,8 +108,13 @@ func SetupMetrics(
 	exportInterval time.Duration,
 	insecure bool,
 	serviceName, serviceVersion string,
+	opts ...MetricsOption,
 ) (*MetricsSetup, error) {
-	if !enabled {
+	config := metricsConfig{}
+	for _, opt := range opts {
+		opt(&config)
+	}
+	if !enabled && !config.prometheus {
 		return &MetricsSetup{meterProvider: nil}, nil
 	}
 
@@ -96,29 +128,42 @@ func SetupMetrics(
 		return nil, fmt.Errorf("failed to create resource: %w", err)
 	}
 
-	exporterOptions := []otlpmetricgrpc.Option{
-		otlpmetricgrpc.WithEndpoint(otelEndpoint),
-	}
-	if insecure {
-		exporterOptions = append(exporterOptions, otlpmetricgrpc.WithInsecure())
+	providerOptions := []sdkmetric.Option{sdkmetric.WithResource(res)}
+	if enabled {
+		exporterOptions := []otlpmetricgrpc.Option{
+			otlpmetricgrpc.WithEndpoint(otelEndpoint),
+		}
+		if insecure {
+			exporterOptions = append(exporterOptions, otlpmetricgrpc.WithInsecure())
+		}
+
+		metricExporter, err := otlpmetricgrpc.New(ctx, exporterOptions...)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+		}
+		providerOptions = append(providerOptions, sdkmetric.WithReader(sdkmetric.NewPeriodicReader(
+			metricExporter,
+			sdkmetric.WithInterval(exportInterval),
+		)))
+		logrus.Infof(
+			"OTel metrics configured with endpoint %s (export interval: %v, insecure: %v)",
+			otelEndpoint, exportInterval, insecure,
+		)
 	}
 
-	metricExporter, err := otlpmetricgrpc.New(ctx, exporterOptions...)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create metric exporter: %w", err)
+	var handler http.Handler
+	if config.prometheus {
+		registry := prometheus.NewRegistry()
+		promExporter, err := otelprometheus.New(otelprometheus.WithRegisterer(registry))
+		if err != nil {
+			return nil, fmt.Errorf("failed to create prometheus exporter: %w", err)
+		}
+		providerOptions = append(providerOptions, sdkmetric.WithReader(promExporter))
+		handler = promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+		logrus.Info("Prometheus metrics exporter configured")
 	}
 
-	meterProvider := sdkmetric.NewMeterProvider(
-		sdkmetric.WithResource(res),
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(
-			metricExporter,
-			sdkmetric.WithInterval(exportInterval),
-		)),
-	)
+	meterProvider := sdkmetric.NewMeterProvider(providerOptions...)
 	otel.SetMeterProvider(meterProvider)
-	logrus.Infof(
-		"OTel metrics configured with endpoint %s (export interval: %v, insecure: %v)",
-		otelEndpoint, exportInterval, insecure,
-	)
-	return &MetricsSetup{meterProvider: meterProvider}, nil
+	return &MetricsSetup{meterProvider: meterProvider, handler: handler}, nil
 }
@@ -1,9 +1,15 @@
 package monitoring
 
 import (
+	"context"
+	"net/http"
+	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
 )
 
 func TestByteBuckets(t *testing.T) {
@@ -30,3 +36,32 @@ func TestByteBuckets(t *testing.T) {
 		512 << 20,
 	}, ByteBuckets)
 }
+
+func TestSetupMetrics_PrometheusExporter(t *testing.T) {
+	// given
+	counter, err := otel.GetMeterProvider().Meter("test").Int64Counter("test.hits.received")
+	require.NoError(t, err)
+	setup, err := SetupMetrics(
+		context.Background(), false, "", time.Second, false, "d8a", "test", WithPrometheusExporter(),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = setup.Shutdown(context.Background()) })
+	counter.Add(context.Background(), 3)
+
+	// when
+	recorder := httptest.NewRecorder()
+	setup.Handler().ServeHTTP(recorder, httptest.NewRequest(http.MethodGet, "/metrics", http.NoBody))
+
+	// then
+	assert.Equal(t, http.StatusOK, recorder.Code)
+	assert.Regexp(t, `(?m)^test_hits_received_total\{.*\} 3$`, recorder.Body.String())
+}
+
+func TestSetupMetrics_DisabledHasNoHandler(t *testing.T) {
+	// when
+	setup, err := SetupMetrics(context.Background(), false, "", time.Second, false, "d8a", "test")
+
+	// then
+	require.NoError(t, err)
+	assert.Nil(t, setup.Handler())
+}
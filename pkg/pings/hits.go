@@ -22,6 +22,16 @@ const PingTimestampMetadataKey = "ping-timestamp"
 // with pinging publisher to make the handler advance processing ticks.
 func NewProcessHitsPingTask(
 	encoder encoding.EncoderFunc,
+) func() (*worker.Task, error) {
+	return NewProcessHitsPingTaskWithClock(encoder, time.Now)
+}
+
+// NewProcessHitsPingTaskWithClock creates a new empty process hits task, timestamped with the
+// given clock instead of the wall clock. Replaying past requests uses it to advance processing
+// ticks in step with the replayed time.
+func NewProcessHitsPingTaskWithClock(
+	encoder encoding.EncoderFunc,
+	now func() time.Time,
 ) func() (*worker.Task, error) {
 	return func() (*worker.Task, error) {
 		taskData, err := worker.SerializeTaskData(encoder, hits.HitProcessingTask{
@@ -32,7 +42,7 @@ func NewProcessHitsPingTask(
 		}
 		return worker.NewTask(hits.HitProcessingTaskName, map[string]string{
 			IsPingMetadataKey:        IsPingMetadataValue,
-			PingTimestampMetadataKey: time.Now().Format(time.RFC3339),
+			PingTimestampMetadataKey: now().Format(time.RFC3339),
 		}, taskData), nil
 	}
 }
@@ -0,0 +1,72 @@
+// Package privacysignals reads the signals visitors send to opt out of being tracked:
+// the Global Privacy Control and Do Not Track headers, and the consent params of GA4.
+package privacysignals
+
+import (
+	"github.com/d8a-tech/d8a/pkg/hits"
+)
+
+const (
+	// OptOutMetadataKey is the hit metadata key telling whether the visitor opted out of
+	// tracking, "true" or "false". It's not set when signals are ignored for the property.
+	OptOutMetadataKey = "privacy_opt_out"
+	// IdentifiersStrippedMetadataKey is the hit metadata key set to "true" when the
+	// identifiers of the hit were stripped, so that it can't be joined with other hits.
+	IdentifiersStrippedMetadataKey = "identifiers_stripped"
+)
+
+// Signal names, as returned by Signals.
+const (
+	SignalGPC = "gpc"
+	SignalDNT = "dnt"
+	SignalGCS = "gcs"
+	SignalGCD = "gcd"
+)
+
+// ClickIDParams are the page URL params holding ad click identifiers.
+var ClickIDParams = []string{
+	"gclid",
+	"dclid",
+	"srsltid",
+	"gbraid",
+	"wbraid",
+	"fbclid",
+	"msclkid",
+}
+
+// gcdDeniedStates are the gcd letters of consent types denied at the time of the hit:
+// denied by default without update, denied by default and update, denied by update
+// without default and granted by default but denied by update.
+var gcdDeniedStates = map[byte]bool{'p': true, 'q': true, 'm': true, 'u': true}
+
+// gcdAnalyticsStorageIndex is the position of the analytics_storage state in gcd,
+// e.g. "13r3p3r3r5", after the version and the ad_storage state.
+const gcdAnalyticsStorageIndex = 4
+
+// Signals returns the opt-out signals present in the request:
+//   - gpc: the Sec-GPC header is 1,
+//   - dnt: the DNT header is 1,
+//   - gcs: the GA4 gcs param, e.g. G100, has analytics_storage denied,
+//   - gcd: the GA4 gcd param, e.g. 13p3p3p3p5, has analytics_storage denied.
+func Signals(request *hits.ParsedRequest) []string {
+	var signals []string
+	if request.Headers.Get("Sec-GPC") == "1" {
+		signals = append(signals, SignalGPC)
+	}
+	if request.Headers.Get("DNT") == "1" {
+		signals = append(signals, SignalDNT)
+	}
+	if gcs := request.QueryParams.Get("gcs"); len(gcs) == 4 && gcs[:2] == "G1" && gcs[3] == '0' {
+		signals = append(signals, SignalGCS)
+	}
+	if gcd := request.QueryParams.Get("gcd"); len(gcd) > gcdAnalyticsStorageIndex &&
+		gcdDeniedStates[gcd[gcdAnalyticsStorageIndex]] {
+		signals = append(signals, SignalGCD)
+	}
+	return signals
+}
+
+// IdentifiersStripped tells whether the identifiers of the hit were stripped.
+func IdentifiersStripped(hit *hits.Hit) bool {
+	return hit.Metadata[IdentifiersStrippedMetadataKey] == "true"
+}
@@ -0,0 +1,90 @@
+package privacysignals
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSignals(t *testing.T) {
+	testCases := []struct {
+		name        string
+		headers     http.Header
+		queryParams url.Values
+		expected    []string
+	}{
+		{
+			name: "no signals",
+		},
+		{
+			name:     "global privacy control",
+			headers:  http.Header{"Sec-Gpc": {"1"}},
+			expected: []string{SignalGPC},
+		},
+		{
+			name:     "do not track",
+			headers:  http.Header{"Dnt": {"1"}},
+			expected: []string{SignalDNT},
+		},
+		{
+			name:    "do not track disabled",
+			headers: http.Header{"Dnt": {"0"}},
+		},
+		{
+			name:        "non-personalized ads is not an opt-out",
+			queryParams: url.Values{"npa": {"1"}},
+		},
+		{
+			name:        "gcs with analytics storage denied",
+			queryParams: url.Values{"gcs": {"G110"}},
+			expected:    []string{SignalGCS},
+		},
+		{
+			name:        "gcs with analytics storage granted",
+			queryParams: url.Values{"gcs": {"G101"}},
+		},
+		{
+			name:        "gcd with analytics storage denied by default",
+			queryParams: url.Values{"gcd": {"13r3p3r3r5"}},
+			expected:    []string{SignalGCD},
+		},
+		{
+			name:        "gcd with analytics storage denied by update",
+			queryParams: url.Values{"gcd": {"13v3u3v3v5"}},
+			expected:    []string{SignalGCD},
+		},
+		{
+			name:        "gcd with analytics storage granted by update",
+			queryParams: url.Values{"gcd": {"13p3r3p3p5"}},
+		},
+		{
+			name:        "gcd with analytics storage not set",
+			queryParams: url.Values{"gcd": {"13l3l3l3l1"}},
+		},
+		{
+			name:        "every signal",
+			headers:     http.Header{"Sec-Gpc": {"1"}, "Dnt": {"1"}},
+			queryParams: url.Values{"npa": {"1"}, "gcs": {"G100"}, "gcd": {"13q3q3q3q5"}},
+			expected:    []string{SignalGPC, SignalDNT, SignalGCS, SignalGCD},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// given
+			request := &hits.ParsedRequest{Headers: testCase.headers, QueryParams: testCase.queryParams}
+			if request.Headers == nil {
+				request.Headers = http.Header{}
+			}
+
+			// when
+			signals := Signals(request)
+
+			// then
+			assert.Equal(t, testCase.expected, signals)
+		})
+	}
+}
@@ -13,3 +13,15 @@ func (e *NotFoundError) Error() string {
 func NewNotFoundError(propertyID string) *NotFoundError {
 	return &NotFoundError{PropertyID: propertyID}
 }
+
+type UnknownMeasurementIDError struct {
+	MeasurementID string
+}
+
+func (e *UnknownMeasurementIDError) Error() string {
+	return fmt.Sprintf("unknown property measurement ID %q", e.MeasurementID)
+}
+
+func NewUnknownMeasurementIDError(measurementID string) *UnknownMeasurementIDError {
+	return &UnknownMeasurementIDError{MeasurementID: measurementID}
+}
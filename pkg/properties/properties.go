@@ -25,12 +25,210 @@ type Settings struct {
 	SessionJoinByUserID       bool
 	IPMaskingLevel            int
 
+	// MeasurementProtocolAPISecrets are the secrets accepted by the GA4 Measurement
+	// Protocol endpoint. Without any, the endpoint rejects requests of the property.
+	MeasurementProtocolAPISecrets []string
+
+	// IngestionAuth are the credentials accepted by the authenticated variant of the
+	// tracking endpoints. Without any, the variant rejects requests of the property.
+	IngestionAuth IngestionAuthSettings
+
+	// BotFilterMode is what happens to hits sent by bots, BotFilterAllow when empty.
+	BotFilterMode BotFilterMode
+
+	RateLimits RateLimitSettings
+
+	Deduplication DeduplicationSettings
+
+	// ClientIDMode is how client IDs of hits are set, ClientIDModeProtocol when empty.
+	ClientIDMode ClientIDMode
+
+	// OptOutMode is what happens to hits of visitors who opted out of tracking with GPC,
+	// DNT or GA4 consent params, OptOutIgnore when empty.
+	OptOutMode OptOutMode
+
+	// AllowedDomains are the hostnames hits of the property may be sent from, checked
+	// against the page location, Origin and Referer. "*.example.com" matches subdomains of
+	// example.com. Hits from anywhere are accepted when empty.
+	AllowedDomains []string
+	// AllowedDomainsReportOnly keeps hits from other domains, marking them instead of
+	// rejecting the request.
+	AllowedDomainsReportOnly bool
+
+	// CORS is the CORS policy of the tracking endpoints for requests of the property, the
+	// default policy of the protocol when nil.
+	CORS *CORSSettings
+
+	// TrackingPlan declares the events of the property and their params, events aren't
+	// validated when nil.
+	TrackingPlan *TrackingPlan
+
+	// GA4ParamsMode is how GA4 event params are written besides the params column,
+	// GA4ParamsModeColumns when empty.
+	GA4ParamsMode GA4ParamsMode
+
 	Filters           *FiltersConfig
 	CustomColumns     []CustomColumnConfig
 	ExcludedURLParams []string
 	Metadata          map[string]any
 }
 
+// BotFilterMode tells what happens to hits classified as bot traffic.
+type BotFilterMode string
+
+const (
+	// BotFilterAllow keeps bot hits without classifying them.
+	BotFilterAllow BotFilterMode = "allow"
+	// BotFilterTag keeps bot hits, marking them with the is_bot and traffic_filter_name columns.
+	BotFilterTag BotFilterMode = "tag"
+	// BotFilterDrop drops bot hits in the receiver.
+	BotFilterDrop BotFilterMode = "drop"
+)
+
+// ClientIDMode tells where client IDs of hits come from.
+type ClientIDMode string
+
+const (
+	// ClientIDModeProtocol uses the client ID sent by the tracker, usually stored in a cookie.
+	ClientIDModeProtocol ClientIDMode = "protocol"
+	// ClientIDModeCookieless replaces the client ID with a hash of the IP, the user agent,
+	// the property ID and a salt rotated daily, so that no identifier is stored client-side.
+	ClientIDModeCookieless ClientIDMode = "cookieless"
+)
+
+// OptOutMode tells what happens to hits of visitors who opted out of tracking.
+type OptOutMode string
+
+const (
+	// OptOutIgnore keeps hits of opted-out visitors without looking at their signals.
+	OptOutIgnore OptOutMode = "ignore"
+	// OptOutFlag keeps hits of opted-out visitors, marking them with the privacy_opt_out column.
+	OptOutFlag OptOutMode = "flag"
+	// OptOutStrip keeps hits of opted-out visitors, marked, without their client ID, user
+	// ID, IP and click IDs.
+	OptOutStrip OptOutMode = "strip"
+	// OptOutDrop drops hits of opted-out visitors in the receiver.
+	OptOutDrop OptOutMode = "drop"
+)
+
+// GA4ParamsMode tells how GA4 event params are written to the warehouse.
+type GA4ParamsMode string
+
+const (
+	// GA4ParamsModeColumns writes the params declared in the ga4.params config to columns
+	// of their own.
+	GA4ParamsModeColumns GA4ParamsMode = "columns"
+	// GA4ParamsModeMap writes all params to the params_map and params_number_map columns,
+	// maps of param names to string and number values, instead of columns of their own.
+	GA4ParamsModeMap GA4ParamsMode = "map"
+	// GA4ParamsModeBoth writes the declared params to columns of their own and all params
+	// to the params map columns.
+	GA4ParamsModeBoth GA4ParamsMode = "both"
+)
+
+// WritesParamsMap tells whether GA4 event params are written to the params map columns.
+func (m GA4ParamsMode) WritesParamsMap() bool {
+	return m == GA4ParamsModeMap || m == GA4ParamsModeBoth
+}
+
+// RateLimit is a token bucket limit of hits, refilled with Rate hits per second up to Burst
+// hits. A zero Rate disables the limit.
+type RateLimit struct {
+	Rate  float64
+	Burst int
+}
+
+// Enabled tells whether the limit applies.
+func (l RateLimit) Enabled() bool {
+	return l.Rate > 0
+}
+
+// RateLimitSettings are the hit rate limits of a property, enforced by the receiver.
+type RateLimitSettings struct {
+	PerIP       RateLimit
+	PerProperty RateLimit
+	PerClientID RateLimit
+	// TagOnly keeps hits over the limit, marking them instead of rejecting the request.
+	TagOnly bool
+}
+
+// DeduplicationSettings tell how the receiver recognizes duplicate hits, e.g. retries of a
+// request or hits sent both with sendBeacon and fetch.
+type DeduplicationSettings struct {
+	// Window is how long after a hit the same hit is dropped as a duplicate. Duplicates are
+	// kept when zero.
+	Window time.Duration
+	// EventIDParam is the param holding an ID of the event set by the tracker, e.g.
+	// ep.event_id. Hits with the same ID are duplicates. When empty, or for hits without
+	// the param, hits of the same client ID with the same params and body are duplicates.
+	EventIDParam string
+}
+
+// IngestionAuthSettings are the credentials of server-to-server callers sending hits of a
+// property.
+type IngestionAuthSettings struct {
+	// APIKeys are accepted in the X-D8A-API-Key header.
+	APIKeys []string
+	// HMACSecrets are the secrets requests may be signed with, in the X-D8A-Signature header.
+	HMACSecrets []string
+}
+
+// Enabled tells whether the property accepts authenticated requests at all.
+func (s IngestionAuthSettings) Enabled() bool {
+	return len(s.APIKeys) > 0 || len(s.HMACSecrets) > 0
+}
+
+// CORSSettings are the CORS response headers of tracking endpoints.
+type CORSSettings struct {
+	// AllowedOrigins are the origins allowed to send requests, e.g. https://shop.example.com.
+	// "https://*.example.com" allows subdomains of example.com and "*" allows any origin.
+	AllowedOrigins []string
+	// AllowedHeaders are the request headers allowed in preflight responses. The headers
+	// requested by the browser are allowed when empty.
+	AllowedHeaders []string
+	// MaxAge is how long browsers may cache preflight responses.
+	MaxAge time.Duration
+	// AllowCredentials allows requests with credentials from origins allowed by name or by a
+	// subdomain pattern. Origins allowed by "*" never send credentials.
+	AllowCredentials bool
+}
+
+// TrackingPlan declares the events a property is expected to send.
+type TrackingPlan struct {
+	// Events are the allowed events, by event name.
+	Events map[string]TrackingPlanEvent `yaml:"events"`
+	// Strict drops events which don't conform to the plan, instead of only reporting
+	// their violations.
+	Strict bool `yaml:"strict"`
+}
+
+// TrackingPlanEvent declares the params of an event. Params which aren't declared are
+// violations.
+type TrackingPlanEvent struct {
+	Params map[string]TrackingPlanParam `yaml:"params"`
+}
+
+// TrackingPlanParam declares an event param.
+type TrackingPlanParam struct {
+	// Type is the type of the param values, any type when empty.
+	Type     TrackingPlanParamType `yaml:"type"`
+	Required bool                  `yaml:"required"`
+}
+
+// TrackingPlanParamType is the type of the values of an event param.
+type TrackingPlanParamType string
+
+const (
+	// TrackingPlanParamString accepts any value.
+	TrackingPlanParamString TrackingPlanParamType = "string"
+	// TrackingPlanParamNumber accepts numbers and strings holding one.
+	TrackingPlanParamNumber TrackingPlanParamType = "number"
+	// TrackingPlanParamInteger accepts whole numbers and strings holding one.
+	TrackingPlanParamInteger TrackingPlanParamType = "integer"
+	// TrackingPlanParamBoolean accepts booleans and the strings true and false.
+	TrackingPlanParamBoolean TrackingPlanParamType = "boolean"
+)
+
 // FiltersSafe returns the filters configuration, ensuring it is never nil.
 //
 //nolint:gocritic // hugeParam: Settings receiver is expected to be passed by value as per API contract.
@@ -1,9 +1,5 @@
 package properties
 
-import (
-	"fmt"
-)
-
 // SettingsRegistry is a registry of property configurations.
 type SettingsRegistry interface {
 	GetByMeasurementID(trackingID string) (*Settings, error)
@@ -25,7 +21,7 @@ func (s StaticSettingsRegistry) GetByMeasurementID(trackingID string) (*Settings
 		if s.defaultConfig != nil {
 			return s.defaultConfig, nil
 		}
-		return nil, fmt.Errorf("unknown property tracking ID: %s", trackingID)
+		return nil, NewUnknownMeasurementIDError(trackingID)
 	}
 	return property, nil
 }
@@ -37,7 +33,7 @@ func (s StaticSettingsRegistry) GetByPropertyID(propertyID string) (*Settings, e
 		if s.defaultConfig != nil {
 			return s.defaultConfig, nil
 		}
-		return nil, fmt.Errorf("unknown property ID: %s", propertyID)
+		return nil, NewNotFoundError(propertyID)
 	}
 	return property, nil
 }
@@ -0,0 +1,61 @@
+package properties
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStaticSettingsRegistry_LookupByMeasurementAndPropertyID(t *testing.T) {
+	// given
+	registry := NewStaticSettingsRegistry([]Settings{
+		{PropertyID: "shop", PropertyMeasurementID: "G-SHOP"},
+		{PropertyID: "blog", PropertyMeasurementID: "G-BLOG"},
+	})
+
+	// when
+	byMeasurementID, measurementErr := registry.GetByMeasurementID("G-BLOG")
+	byPropertyID, propertyErr := registry.GetByPropertyID("shop")
+
+	// then
+	require.NoError(t, measurementErr)
+	require.NoError(t, propertyErr)
+	assert.Equal(t, "blog", byMeasurementID.PropertyID)
+	assert.Equal(t, "G-SHOP", byPropertyID.PropertyMeasurementID)
+}
+
+func TestStaticSettingsRegistry_UnknownIDsWithoutDefault(t *testing.T) {
+	// given
+	registry := NewStaticSettingsRegistry([]Settings{
+		{PropertyID: "shop", PropertyMeasurementID: "G-SHOP"},
+	})
+
+	// when
+	_, measurementErr := registry.GetByMeasurementID("G-UNKNOWN")
+	_, propertyErr := registry.GetByPropertyID("unknown")
+
+	// then
+	var unknownMeasurementID *UnknownMeasurementIDError
+	require.ErrorAs(t, measurementErr, &unknownMeasurementID)
+	assert.Equal(t, "G-UNKNOWN", unknownMeasurementID.MeasurementID)
+	var notFound *NotFoundError
+	require.ErrorAs(t, propertyErr, &notFound)
+	assert.Equal(t, "unknown", notFound.PropertyID)
+}
+
+func TestStaticSettingsRegistry_UnknownIDsFallBackToDefault(t *testing.T) {
+	// given
+	defaultSettings := &Settings{PropertyID: "default", PropertyMeasurementID: "-"}
+	registry := NewStaticSettingsRegistry([]Settings{}, WithDefaultConfig(defaultSettings))
+
+	// when
+	byMeasurementID, measurementErr := registry.GetByMeasurementID("G-UNKNOWN")
+	byPropertyID, propertyErr := registry.GetByPropertyID("unknown")
+
+	// then
+	require.NoError(t, measurementErr)
+	require.NoError(t, propertyErr)
+	assert.Same(t, defaultSettings, byMeasurementID)
+	assert.Same(t, defaultSettings, byPropertyID)
+}
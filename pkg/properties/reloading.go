@@ -0,0 +1,94 @@
+package properties
+
+import (
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// SettingsLoader loads the settings of all properties. The default settings, if not nil,
+// are returned for lookups of IDs missing from the list.
+type SettingsLoader func() (settingsList []Settings, defaultSettings *Settings, err error)
+
+// ReloadListener is called with the settings swapped in by a reload. Without a list of
+// properties, it receives the default settings as the only entry.
+type ReloadListener func(settingsList []Settings)
+
+// ReloadingSettingsRegistry is a settings registry, which can replace its settings at
+// runtime. Lookups always see either the old or the new settings, never a mix of both.
+type ReloadingSettingsRegistry struct {
+	loader    SettingsLoader
+	current   atomic.Pointer[SettingsRegistry]
+	reloadMu  sync.Mutex
+	listeners []ReloadListener
+}
+
+// GetByMeasurementID gets a property configuration by tracking ID.
+func (r *ReloadingSettingsRegistry) GetByMeasurementID(trackingID string) (*Settings, error) {
+	return (*r.current.Load()).GetByMeasurementID(trackingID)
+}
+
+// GetByPropertyID gets a property configuration by property ID.
+func (r *ReloadingSettingsRegistry) GetByPropertyID(propertyID string) (*Settings, error) {
+	return (*r.current.Load()).GetByPropertyID(propertyID)
+}
+
+// OnReload registers a function called after every successful reload.
+func (r *ReloadingSettingsRegistry) OnReload(listener ReloadListener) {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+// Reload loads and validates the settings and swaps them in. If loading or validation
+// fails, the previous settings stay in place.
+func (r *ReloadingSettingsRegistry) Reload() error {
+	r.reloadMu.Lock()
+	defer r.reloadMu.Unlock()
+
+	swapped, err := r.load()
+	if err != nil {
+		return err
+	}
+	for _, listener := range r.listeners {
+		listener(swapped)
+	}
+	return nil
+}
+
+// load swaps in freshly loaded settings and returns them, as passed to reload listeners.
+func (r *ReloadingSettingsRegistry) load() ([]Settings, error) {
+	settingsList, defaultSettings, err := r.loader()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load property settings: %w", err)
+	}
+	if err := ValidateSettingsList(settingsList); err != nil {
+		return nil, fmt.Errorf("invalid property settings: %w", err)
+	}
+
+	var opts []StaticSettingsRegistryOptions
+	swapped := settingsList
+	if defaultSettings != nil {
+		if err := ValidateSettings(defaultSettings); err != nil {
+			return nil, fmt.Errorf("invalid default property settings: %w", err)
+		}
+		opts = append(opts, WithDefaultConfig(defaultSettings))
+		if len(settingsList) == 0 {
+			swapped = []Settings{*defaultSettings}
+		}
+	}
+
+	registry := NewStaticSettingsRegistry(settingsList, opts...)
+	r.current.Store(&registry)
+	return swapped, nil
+}
+
+// NewReloadingSettingsRegistry creates a new reloading settings registry, loading the
+// initial settings right away.
+func NewReloadingSettingsRegistry(loader SettingsLoader) (*ReloadingSettingsRegistry, error) {
+	r := &ReloadingSettingsRegistry{loader: loader}
+	if _, err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
@@ -0,0 +1,151 @@
+package properties
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type loaderStub struct {
+	settingsList    []Settings
+	defaultSettings *Settings
+	err             error
+}
+
+func (l *loaderStub) load() ([]Settings, *Settings, error) {
+	return l.settingsList, l.defaultSettings, l.err
+}
+
+func TestReloadingSettingsRegistry_ReloadSwapsSettings(t *testing.T) {
+	// given
+	loader := &loaderStub{settingsList: []Settings{
+		{PropertyID: "shop", PropertyMeasurementID: "G-SHOP", ExcludedURLParams: []string{"ref"}},
+	}}
+	registry, err := NewReloadingSettingsRegistry(loader.load)
+	require.NoError(t, err)
+	reloads := 0
+	var swapped []Settings
+	registry.OnReload(func(settingsList []Settings) {
+		reloads++
+		swapped = settingsList
+	})
+
+	// when
+	loader.settingsList = []Settings{
+		{PropertyID: "shop", PropertyMeasurementID: "G-SHOP", ExcludedURLParams: []string{"ref", "gclid"}},
+		{PropertyID: "blog", PropertyMeasurementID: "G-BLOG"},
+	}
+	reloadErr := registry.Reload()
+
+	// then
+	require.NoError(t, reloadErr)
+	assert.Equal(t, 1, reloads)
+	assert.Equal(t, loader.settingsList, swapped)
+	shop, err := registry.GetByPropertyID("shop")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ref", "gclid"}, shop.ExcludedURLParams)
+	blog, err := registry.GetByMeasurementID("G-BLOG")
+	require.NoError(t, err)
+	assert.Equal(t, "blog", blog.PropertyID)
+}
+
+func TestReloadingSettingsRegistry_FailedReloadKeepsPreviousSettings(t *testing.T) {
+	tests := []struct {
+		name            string
+		settingsList    []Settings
+		defaultSettings *Settings
+		err             error
+	}{
+		{
+			name: "loader error",
+			err:  errors.New("broken yaml"),
+		},
+		{
+			name: "invalid property",
+			settingsList: []Settings{
+				{PropertyID: "shop", PropertyMeasurementID: "G-SHOP", IPMaskingLevel: 7},
+			},
+		},
+		{
+			name: "duplicate measurement ID",
+			settingsList: []Settings{
+				{PropertyID: "shop", PropertyMeasurementID: "G-SHOP"},
+				{PropertyID: "blog", PropertyMeasurementID: "G-SHOP"},
+			},
+		},
+		{
+			name:            "invalid default settings",
+			defaultSettings: &Settings{PropertyID: "default", IPMaskingLevel: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			loader := &loaderStub{settingsList: []Settings{
+				{PropertyID: "shop", PropertyMeasurementID: "G-SHOP", IPMaskingLevel: 1},
+			}}
+			registry, err := NewReloadingSettingsRegistry(loader.load)
+			require.NoError(t, err)
+			reloads := 0
+			registry.OnReload(func([]Settings) { reloads++ })
+
+			// when
+			loader.settingsList, loader.defaultSettings, loader.err = tt.settingsList, tt.defaultSettings, tt.err
+			reloadErr := registry.Reload()
+
+			// then
+			require.Error(t, reloadErr)
+			assert.Zero(t, reloads)
+			shop, err := registry.GetByMeasurementID("G-SHOP")
+			require.NoError(t, err)
+			assert.Equal(t, 1, shop.IPMaskingLevel)
+		})
+	}
+}
+
+func TestReloadingSettingsRegistry_DefaultSettings(t *testing.T) {
+	// given
+	defaultSettings := &Settings{PropertyID: "default"}
+	loader := &loaderStub{defaultSettings: defaultSettings}
+
+	// when
+	registry, err := NewReloadingSettingsRegistry(loader.load)
+
+	// then
+	require.NoError(t, err)
+	settings, err := registry.GetByMeasurementID("G-ANY")
+	require.NoError(t, err)
+	assert.Same(t, defaultSettings, settings)
+}
+
+func TestReloadingSettingsRegistry_ReloadPassesDefaultSettingsToListeners(t *testing.T) {
+	// given
+	loader := &loaderStub{defaultSettings: &Settings{PropertyID: "default"}}
+	registry, err := NewReloadingSettingsRegistry(loader.load)
+	require.NoError(t, err)
+	var swapped []Settings
+	registry.OnReload(func(settingsList []Settings) { swapped = settingsList })
+
+	// when
+	loader.defaultSettings = &Settings{PropertyID: "default", IPMaskingLevel: 2}
+	reloadErr := registry.Reload()
+
+	// then
+	require.NoError(t, reloadErr)
+	assert.Equal(t, []Settings{{PropertyID: "default", IPMaskingLevel: 2}}, swapped)
+}
+
+func TestNewReloadingSettingsRegistry_InvalidInitialSettings(t *testing.T) {
+	// given
+	loader := &loaderStub{err: errors.New("broken yaml")}
+
+	// when
+	registry, err := NewReloadingSettingsRegistry(loader.load)
+
+	// then
+	require.Error(t, err)
+	assert.Nil(t, registry)
+}
@@ -26,6 +26,13 @@ func WithIPMaskingLevel(level int) TestSettingsOption {
 	}
 }
 
+// WithMeasurementProtocolAPISecrets sets the GA4 Measurement Protocol API secrets for test settings.
+func WithMeasurementProtocolAPISecrets(secrets ...string) TestSettingsOption {
+	return func(s *Settings) {
+		s.MeasurementProtocolAPISecrets = secrets
+	}
+}
+
 // NewTestSettingRegistry is a test property source that returns a static property configuration.
 func NewTestSettingRegistry(opts ...TestSettingsOption) SettingsRegistry {
 	settings := &Settings{
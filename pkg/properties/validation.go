@@ -1,6 +1,10 @@
 package properties
 
-import "fmt"
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
 
 // ValidateSettings validates property settings.
 func ValidateSettings(settings *Settings) error {
@@ -16,5 +20,139 @@ func ValidateSettings(settings *Settings) error {
 		return fmt.Errorf("session join by session stamp must be disabled when ip masking level is 4")
 	}
 
+	switch settings.BotFilterMode {
+	case "", BotFilterAllow, BotFilterTag, BotFilterDrop:
+	default:
+		return fmt.Errorf("bot filter mode must be allow, tag or drop: %q", settings.BotFilterMode)
+	}
+
+	switch settings.ClientIDMode {
+	case "", ClientIDModeProtocol, ClientIDModeCookieless:
+	default:
+		return fmt.Errorf("client ID mode must be protocol or cookieless: %q", settings.ClientIDMode)
+	}
+
+	switch settings.OptOutMode {
+	case "", OptOutIgnore, OptOutFlag, OptOutStrip, OptOutDrop:
+	default:
+		return fmt.Errorf("opt-out mode must be ignore, flag, strip or drop: %q", settings.OptOutMode)
+	}
+
+	switch settings.GA4ParamsMode {
+	case "", GA4ParamsModeColumns, GA4ParamsModeMap, GA4ParamsModeBoth:
+	default:
+		return fmt.Errorf("GA4 params mode must be columns, map or both: %q", settings.GA4ParamsMode)
+	}
+
+	for name, limit := range map[string]RateLimit{
+		"per IP":        settings.RateLimits.PerIP,
+		"per property":  settings.RateLimits.PerProperty,
+		"per client ID": settings.RateLimits.PerClientID,
+	} {
+		if limit.Rate < 0 {
+			return fmt.Errorf("%s rate limit must not be negative: %v", name, limit.Rate)
+		}
+		if limit.Enabled() && limit.Burst < 1 {
+			return fmt.Errorf("%s rate limit burst must be at least 1: %d", name, limit.Burst)
+		}
+	}
+
+	if settings.Deduplication.Window < 0 {
+		return fmt.Errorf("deduplication window must not be negative: %s", settings.Deduplication.Window)
+	}
+
+	for _, domain := range settings.AllowedDomains {
+		hostname := strings.TrimPrefix(domain, "*.")
+		if hostname == "" || strings.ContainsAny(hostname, "*/: ") {
+			return fmt.Errorf("allowed domain must be a hostname, optionally prefixed with *.: %q", domain)
+		}
+	}
+
+	for _, credentials := range [][]string{settings.IngestionAuth.APIKeys, settings.IngestionAuth.HMACSecrets} {
+		for _, credential := range credentials {
+			if strings.TrimSpace(credential) == "" {
+				return fmt.Errorf("ingestion API keys and HMAC secrets must not be empty")
+			}
+		}
+	}
+
+	if settings.TrackingPlan != nil {
+		if err := validateTrackingPlan(settings.TrackingPlan); err != nil {
+			return err
+		}
+	}
+
+	if settings.CORS != nil {
+		if err := validateCORSSettings(settings.CORS); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func validateCORSSettings(cors *CORSSettings) error {
+	for _, origin := range cors.AllowedOrigins {
+		if origin == "*" {
+			continue
+		}
+		u, err := url.Parse(strings.Replace(origin, "://*.", "://", 1))
+		if err != nil || u.Scheme == "" || u.Host == "" || u.Path != "" || strings.Contains(u.Host, "*") {
+			return fmt.Errorf("CORS allowed origin must be *, or a scheme and host like https://*.example.com: %q", origin)
+		}
+	}
+	if cors.MaxAge < 0 {
+		return fmt.Errorf("CORS max age must not be negative: %s", cors.MaxAge)
+	}
+	return nil
+}
+
+// ValidateSettingsList validates a list of property settings, making sure every entry is valid
+// and that property IDs and measurement IDs are unique across the list.
+func ValidateSettingsList(settingsList []Settings) error {
+	propertyIDs := make(map[string]struct{}, len(settingsList))
+	measurementIDs := make(map[string]struct{}, len(settingsList))
+	for i := range settingsList {
+		settings := &settingsList[i]
+		if settings.PropertyID == "" {
+			return fmt.Errorf("property #%d: property ID must not be empty", i)
+		}
+		if settings.PropertyMeasurementID == "" {
+			return fmt.Errorf("property %q: measurement ID must not be empty", settings.PropertyID)
+		}
+		if err := ValidateSettings(settings); err != nil {
+			return fmt.Errorf("property %q: %w", settings.PropertyID, err)
+		}
+		if _, exists := propertyIDs[settings.PropertyID]; exists {
+			return fmt.Errorf("duplicate property ID %q", settings.PropertyID)
+		}
+		propertyIDs[settings.PropertyID] = struct{}{}
+		if _, exists := measurementIDs[settings.PropertyMeasurementID]; exists {
+			return fmt.Errorf("duplicate measurement ID %q", settings.PropertyMeasurementID)
+		}
+		measurementIDs[settings.PropertyMeasurementID] = struct{}{}
+	}
+
+	return nil
+}
+
+func validateTrackingPlan(plan *TrackingPlan) error {
+	for eventName, event := range plan.Events {
+		if eventName == "" {
+			return fmt.Errorf("tracking plan event name must not be empty")
+		}
+		for paramName, param := range event.Params {
+			if paramName == "" {
+				return fmt.Errorf("tracking plan param name of event %q must not be empty", eventName)
+			}
+			switch param.Type {
+			case "", TrackingPlanParamString, TrackingPlanParamNumber, TrackingPlanParamInteger, TrackingPlanParamBoolean:
+			default:
+				return fmt.Errorf(
+					"tracking plan param type must be string, number, integer or boolean: %q", param.Type,
+				)
+			}
+		}
+	}
 	return nil
 }
@@ -2,6 +2,7 @@ package properties
 
 import (
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -58,6 +59,139 @@ func TestValidateSettings(t *testing.T) {
 			},
 			wantErr: "session join by session stamp must be disabled when ip masking level is 4",
 		},
+		{
+			name:     "valid bot filter mode",
+			settings: &Settings{BotFilterMode: BotFilterDrop},
+		},
+		{
+			name:     "invalid bot filter mode",
+			settings: &Settings{BotFilterMode: "block"},
+			wantErr:  `bot filter mode must be allow, tag or drop: "block"`,
+		},
+		{
+			name: "valid rate limits",
+			settings: &Settings{RateLimits: RateLimitSettings{
+				PerIP:       RateLimit{Rate: 10, Burst: 50},
+				PerClientID: RateLimit{Rate: 0.5, Burst: 1},
+			}},
+		},
+		{
+			name:     "negative rate limit",
+			settings: &Settings{RateLimits: RateLimitSettings{PerProperty: RateLimit{Rate: -1, Burst: 1}}},
+			wantErr:  "per property rate limit must not be negative: -1",
+		},
+		{
+			name:     "rate limit without burst",
+			settings: &Settings{RateLimits: RateLimitSettings{PerIP: RateLimit{Rate: 10}}},
+			wantErr:  "per IP rate limit burst must be at least 1: 0",
+		},
+		{
+			name:     "valid deduplication window",
+			settings: &Settings{Deduplication: DeduplicationSettings{Window: time.Minute, EventIDParam: "ep.event_id"}},
+		},
+		{
+			name:     "negative deduplication window",
+			settings: &Settings{Deduplication: DeduplicationSettings{Window: -time.Minute}},
+			wantErr:  "deduplication window must not be negative: -1m0s",
+		},
+		{
+			name:     "valid allowed domains",
+			settings: &Settings{AllowedDomains: []string{"example.com", "*.example.org"}},
+		},
+		{
+			name:     "allowed domain with a scheme",
+			settings: &Settings{AllowedDomains: []string{"https://example.com"}},
+			wantErr:  `allowed domain must be a hostname, optionally prefixed with *.: "https://example.com"`,
+		},
+		{
+			name:     "allowed domain with a wildcard in the middle",
+			settings: &Settings{AllowedDomains: []string{"shop.*.example.com"}},
+			wantErr:  `allowed domain must be a hostname, optionally prefixed with *.: "shop.*.example.com"`,
+		},
+		{
+			name: "valid CORS settings",
+			settings: &Settings{CORS: &CORSSettings{
+				AllowedOrigins: []string{"*", "https://shop.example.com", "https://*.example.org", "http://localhost:3000"},
+				MaxAge:         time.Hour,
+			}},
+		},
+		{
+			name:     "CORS origin without a scheme",
+			settings: &Settings{CORS: &CORSSettings{AllowedOrigins: []string{"shop.example.com"}}},
+			wantErr:  `CORS allowed origin must be *, or a scheme and host like https://*.example.com: "shop.example.com"`,
+		},
+		{
+			name:     "CORS origin with a path",
+			settings: &Settings{CORS: &CORSSettings{AllowedOrigins: []string{"https://shop.example.com/"}}},
+			wantErr:  `CORS allowed origin must be *, or a scheme and host like https://*.example.com: "https://shop.example.com/"`, //nolint:lll // test data
+		},
+		{
+			name:     "cookieless client ID mode",
+			settings: &Settings{ClientIDMode: ClientIDModeCookieless},
+		},
+		{
+			name:     "unknown client ID mode",
+			settings: &Settings{ClientIDMode: "fingerprint"},
+			wantErr:  `client ID mode must be protocol or cookieless: "fingerprint"`,
+		},
+		{
+			name:     "strip opt-out mode",
+			settings: &Settings{OptOutMode: OptOutStrip},
+		},
+		{
+			name:     "unknown opt-out mode",
+			settings: &Settings{OptOutMode: "anonymize"},
+			wantErr:  `opt-out mode must be ignore, flag, strip or drop: "anonymize"`,
+		},
+		{
+			name:     "map GA4 params mode",
+			settings: &Settings{GA4ParamsMode: GA4ParamsModeMap},
+		},
+		{
+			name:     "unknown GA4 params mode",
+			settings: &Settings{GA4ParamsMode: "json"},
+			wantErr:  `GA4 params mode must be columns, map or both: "json"`,
+		},
+		{
+			name: "valid ingestion credentials",
+			settings: &Settings{IngestionAuth: IngestionAuthSettings{
+				APIKeys:     []string{"key"},
+				HMACSecrets: []string{"secret"},
+			}},
+		},
+		{
+			name:     "empty ingestion HMAC secret",
+			settings: &Settings{IngestionAuth: IngestionAuthSettings{HMACSecrets: []string{""}}},
+			wantErr:  "ingestion API keys and HMAC secrets must not be empty",
+		},
+		{
+			name: "valid tracking plan",
+			settings: &Settings{TrackingPlan: &TrackingPlan{Events: map[string]TrackingPlanEvent{
+				"purchase": {Params: map[string]TrackingPlanParam{
+					"transaction_id": {Type: TrackingPlanParamString, Required: true},
+					"coupon":         {},
+				}},
+			}}},
+		},
+		{
+			name: "unknown tracking plan param type",
+			settings: &Settings{TrackingPlan: &TrackingPlan{Events: map[string]TrackingPlanEvent{
+				"purchase": {Params: map[string]TrackingPlanParam{"value": {Type: "float"}}},
+			}}},
+			wantErr: `tracking plan param type must be string, number, integer or boolean: "float"`,
+		},
+		{
+			name: "empty tracking plan event name",
+			settings: &Settings{TrackingPlan: &TrackingPlan{Events: map[string]TrackingPlanEvent{
+				"": {},
+			}}},
+			wantErr: "tracking plan event name must not be empty",
+		},
+		{
+			name:     "negative CORS max age",
+			settings: &Settings{CORS: &CORSSettings{MaxAge: -time.Second}},
+			wantErr:  "CORS max age must not be negative: -1s",
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -78,3 +212,68 @@ func TestValidateSettings(t *testing.T) {
 		})
 	}
 }
+
+func TestValidateSettingsList(t *testing.T) {
+	testCases := []struct {
+		name     string
+		settings []Settings
+		wantErr  string
+	}{
+		{
+			name: "valid list",
+			settings: []Settings{
+				{PropertyID: "shop", PropertyMeasurementID: "G-SHOP"},
+				{PropertyID: "blog", PropertyMeasurementID: "G-BLOG"},
+			},
+		},
+		{
+			name:     "empty property id",
+			settings: []Settings{{PropertyMeasurementID: "G-SHOP"}},
+			wantErr:  "property #0: property ID must not be empty",
+		},
+		{
+			name:     "empty measurement id",
+			settings: []Settings{{PropertyID: "shop"}},
+			wantErr:  `property "shop": measurement ID must not be empty`,
+		},
+		{
+			name: "duplicate property id",
+			settings: []Settings{
+				{PropertyID: "shop", PropertyMeasurementID: "G-SHOP"},
+				{PropertyID: "shop", PropertyMeasurementID: "G-BLOG"},
+			},
+			wantErr: `duplicate property ID "shop"`,
+		},
+		{
+			name: "duplicate measurement id",
+			settings: []Settings{
+				{PropertyID: "shop", PropertyMeasurementID: "G-SHOP"},
+				{PropertyID: "blog", PropertyMeasurementID: "G-SHOP"},
+			},
+			wantErr: `duplicate measurement ID "G-SHOP"`,
+		},
+		{
+			name: "invalid entry",
+			settings: []Settings{
+				{PropertyID: "shop", PropertyMeasurementID: "G-SHOP", IPMaskingLevel: 7},
+			},
+			wantErr: `property "shop": ip masking level must be between 0 and 4: 7`,
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			// when
+			err := ValidateSettingsList(testCase.settings)
+
+			// then
+			if testCase.wantErr == "" {
+				require.NoError(t, err)
+				return
+			}
+
+			require.Error(t, err)
+			assert.Equal(t, testCase.wantErr, err.Error())
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package protocol
+
+import (
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/valyala/fasthttp"
+)
+
+// DefaultCORSSettings are the CORS settings of protocols not given any: requests without
+// credentials from any origin are allowed, with the headers the browser asks for.
+var DefaultCORSSettings = properties.CORSSettings{
+	AllowedOrigins: []string{"*"},
+	MaxAge:         24 * time.Hour,
+}
+
+const defaultCORSAllowedHeaders = "Content-Type, X-Requested-With"
+
+// CORSSettingsFor returns the CORS settings of the property the request is for, or the
+// given defaults if the property can't be told, e.g. for preflights of batched requests.
+// Without a parsed request, the query params of the fasthttp request are used.
+func CORSSettingsFor(
+	ctx *RequestContext,
+	extractor PropertyIDExtractor,
+	psr properties.SettingsRegistry,
+	defaults properties.CORSSettings,
+) properties.CORSSettings {
+	if ctx.Parsed == nil {
+		queryParams := url.Values{}
+		for key, value := range ctx.FastHttp.QueryArgs().All() {
+			queryParams.Add(string(key), string(value))
+		}
+		ctx = &RequestContext{FastHttp: ctx.FastHttp, Parsed: &hits.ParsedRequest{QueryParams: queryParams}}
+	}
+	propertyID, err := extractor.PropertyID(ctx)
+	if err != nil {
+		return defaults
+	}
+	settings, err := psr.GetByPropertyID(propertyID)
+	if err != nil || settings.CORS == nil {
+		return defaults
+	}
+	return *settings.CORS
+}
+
+// SetCORSHeaders sets the CORS headers of a response to a tracking endpoint. Origins which
+// aren't allowed get no Access-Control-Allow-Origin, so browsers don't expose the response.
+// Only origins allowed by name or by a subdomain pattern are mirrored and may send
+// credentials, the ones allowed by "*" get "*".
+func SetCORSHeaders(ctx *fasthttp.RequestCtx, settings properties.CORSSettings, methods string) {
+	origin := string(ctx.Request.Header.Peek("Origin"))
+	switch {
+	case origin == "" || slices.Contains(settings.AllowedOrigins, "*"):
+		if slices.Contains(settings.AllowedOrigins, "*") {
+			ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
+		}
+	case corsOriginAllowed(origin, settings.AllowedOrigins):
+		ctx.Response.Header.Set("Access-Control-Allow-Origin", origin)
+		if settings.AllowCredentials {
+			ctx.Response.Header.Set("Access-Control-Allow-Credentials", "true")
+		}
+		ctx.Response.Header.Set("Vary", "Origin")
+	default:
+		ctx.Response.Header.Set("Vary", "Origin")
+	}
+
+	ctx.Response.Header.Set("Access-Control-Allow-Methods", methods)
+	switch requested := string(ctx.Request.Header.Peek("Access-Control-Request-Headers")); {
+	case len(settings.AllowedHeaders) > 0:
+		ctx.Response.Header.Set("Access-Control-Allow-Headers", strings.Join(settings.AllowedHeaders, ", "))
+	case requested != "":
+		ctx.Response.Header.Set("Access-Control-Allow-Headers", requested)
+	default:
+		ctx.Response.Header.Set("Access-Control-Allow-Headers", defaultCORSAllowedHeaders)
+	}
+	if settings.MaxAge > 0 {
+		ctx.Response.Header.Set("Access-Control-Max-Age", strconv.Itoa(int(settings.MaxAge.Seconds())))
+	}
+}
+
+// corsOriginAllowed tells whether the origin matches one of the allowed ones by name, or by
+// a pattern like "https://*.example.com", matching any subdomain of example.com over HTTPS.
+func corsOriginAllowed(origin string, allowed []string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(pattern)
+		if pattern == origin {
+			return true
+		}
+		prefix, suffix, wildcard := strings.Cut(pattern, "*")
+		if !wildcard || len(origin) <= len(prefix)+len(suffix) {
+			continue
+		}
+		if strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+			!strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/:") {
+			return true
+		}
+	}
+	return false
+}
@@ -38,6 +38,37 @@ func (p *d8aProtocol) Interfaces() any {
 	return p.child.Interfaces()
 }
 
+// PageLocation implements protocol.PageLocationProvider.
+func (p *d8aProtocol) PageLocation(hit *hits.Hit) string {
+	if provider, ok := p.child.(protocol.PageLocationProvider); ok {
+		return provider.PageLocation(hit)
+	}
+	return ""
+}
+
+// SetPageLocation implements protocol.PageLocationProvider.
+func (p *d8aProtocol) SetPageLocation(hit *hits.Hit, location string) {
+	if provider, ok := p.child.(protocol.PageLocationProvider); ok {
+		provider.SetPageLocation(hit, location)
+	}
+}
+
+// EventParams implements protocol.EventParamsProvider.
+func (p *d8aProtocol) EventParams(hit *hits.Hit) map[string]any {
+	if provider, ok := p.child.(protocol.EventParamsProvider); ok {
+		return provider.EventParams(hit)
+	}
+	return nil
+}
+
+// DefaultCORSSettings implements protocol.CORSSettingsProvider.
+func (p *d8aProtocol) DefaultCORSSettings() properties.CORSSettings {
+	if provider, ok := p.child.(protocol.CORSSettingsProvider); ok {
+		return provider.DefaultCORSSettings()
+	}
+	return protocol.DefaultCORSSettings
+}
+
 //go:embed static/wt.min.js
 var staticWebTracker []byte
 
@@ -50,15 +81,16 @@ func (p *d8aProtocol) Endpoints() []protocol.ProtocolEndpoint {
 		if endpoint.Path == "/g/collect" {
 			// Decorate only the tracking endpoint
 			endpoint.Path = "/d/c"
-		} else if strings.HasPrefix(endpoint.Path, "/g/") {
-			// Ignore all the others (static files, etc.)
+		} else if strings.HasPrefix(endpoint.Path, "/g/") || endpoint.Path == ga4.MeasurementProtocolPath {
+			// Ignore all the others (static files, server-side collection, etc.)
 			continue
 		}
 		newEndpoints = append(newEndpoints, endpoint)
 	}
+	cors := p.DefaultCORSSettings()
 	return append(newEndpoints, []protocol.ProtocolEndpoint{
-		ga4.StaticCORSEndpoint("/d/wt.min.js", "text/javascript", staticWebTracker),
-		ga4.StaticCORSEndpoint("/d/wt.min.js.map", "application/json", staticWebTrackerMap),
+		ga4.StaticCORSEndpoint("/d/wt.min.js", "text/javascript", staticWebTracker, cors),
+		ga4.StaticCORSEndpoint("/d/wt.min.js.map", "application/json", staticWebTrackerMap, cors),
 	}...)
 }
 
@@ -64,6 +64,8 @@ var ProtocolInterfaces = struct {
 	EventGtmDebug                schema.Interface
 	EventPageLoadHash            schema.Interface
 	EventParams                  schema.Interface
+	EventParamsMap               schema.Interface
+	EventParamsNumberMap         schema.Interface
 	EventParamAclid              schema.Interface
 	EventPrivacyAdsStorage       schema.Interface
 	EventPrivacyAnalyticsStorage schema.Interface
@@ -334,6 +336,20 @@ var ProtocolInterfaces = struct {
 			)),
 		},
 	},
+	EventParamsMap: schema.Interface{
+		ID: "ga4.protocols.d8a.tech/event/params_map",
+		Field: &arrow.Field{
+			Nullable: true,
+			Name:     "params_map", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String),
+		},
+	},
+	EventParamsNumberMap: schema.Interface{
+		ID: "ga4.protocols.d8a.tech/event/params_number_map",
+		Field: &arrow.Field{
+			Nullable: true,
+			Name:     "params_number_map", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64),
+		},
+	},
 	EventParamAclid: schema.Interface{
 		ID:    "ga4.protocols.d8a.tech/events/params_aclid",
 		Field: &arrow.Field{Name: "params_aclid", Type: arrow.BinaryTypes.String, Nullable: true},
@@ -12,44 +12,7 @@ var genericEventParamsColumn = columns.NewSimpleEventColumn(
 	ProtocolInterfaces.EventParams.ID,
 	ProtocolInterfaces.EventParams.Field,
 	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
-		params := make([]any, 0)
-		for qpName, qpValues := range event.BoundHit.MustParsedRequest().QueryParams {
-			if name, ok := strings.CutPrefix(qpName, "ep."); ok {
-				for _, qpValue := range qpValues {
-					params = append(params, map[string]any{
-						"name":         name,
-						"value_string": qpValue,
-						"value_number": nil,
-					})
-				}
-			} else if name, ok := strings.CutPrefix(qpName, "epn."); ok {
-				for _, qpValue := range qpValues {
-					numValue, err := columns.CastToFloat64OrNil(ProtocolInterfaces.EventParams.ID)(qpValue)
-					if err != nil || numValue == nil {
-						continue
-					}
-					params = append(params, map[string]any{
-						"name":         name,
-						"value_string": nil,
-						"value_number": numValue,
-					})
-				}
-			}
-		}
-		slices.SortFunc(params, func(a, b any) int {
-			aMap, ok := a.(map[string]any)
-			if !ok {
-				return 0
-			}
-			bMap, ok := b.(map[string]any)
-			if !ok {
-				return 0
-			}
-			aName, _ := aMap["name"].(string)
-			bName, _ := bMap["name"].(string)
-			return strings.Compare(aName, bName)
-		})
-		return params, nil
+		return prefixedQueryParams(event, "ep.", "epn.", ProtocolInterfaces.EventParams.ID), nil
 	},
 	columns.WithEventColumnDocs(
 		"Event Params",
@@ -57,6 +20,53 @@ var genericEventParamsColumn = columns.NewSimpleEventColumn(
 	),
 )
 
+// prefixedQueryParams collects the query params with the given string and number prefixes
+// as name/value entries, sorted by name.
+func prefixedQueryParams(
+	event *schema.Event,
+	stringPrefix, numberPrefix string,
+	interfaceID schema.InterfaceID,
+) []any {
+	params := make([]any, 0)
+	for qpName, qpValues := range event.BoundHit.MustParsedRequest().QueryParams {
+		if name, ok := strings.CutPrefix(qpName, stringPrefix); ok {
+			for _, qpValue := range qpValues {
+				params = append(params, map[string]any{
+					"name":         name,
+					"value_string": qpValue,
+					"value_number": nil,
+				})
+			}
+		} else if name, ok := strings.CutPrefix(qpName, numberPrefix); ok {
+			for _, qpValue := range qpValues {
+				numValue, err := columns.CastToFloat64OrNil(interfaceID)(qpValue)
+				if err != nil || numValue == nil {
+					continue
+				}
+				params = append(params, map[string]any{
+					"name":         name,
+					"value_string": nil,
+					"value_number": numValue,
+				})
+			}
+		}
+	}
+	slices.SortFunc(params, func(a, b any) int {
+		aMap, ok := a.(map[string]any)
+		if !ok {
+			return 0
+		}
+		bMap, ok := b.(map[string]any)
+		if !ok {
+			return 0
+		}
+		aName, _ := aMap["name"].(string)
+		bName, _ := bMap["name"].(string)
+		return strings.Compare(aName, bName)
+	})
+	return params
+}
+
 var eventContentGroupColumn = columns.FromQueryParamEventColumn(
 	ProtocolInterfaces.EventParamContentGroup.ID,
 	ProtocolInterfaces.EventParamContentGroup.Field,
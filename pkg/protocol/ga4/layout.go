@@ -0,0 +1,508 @@
+package ga4
+
+import (
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+var exportParamValueType = arrow.StructOf(
+	nullableField("string_value", arrow.BinaryTypes.String),
+	nullableField("int_value", arrow.PrimitiveTypes.Int64),
+	nullableField("float_value", arrow.PrimitiveTypes.Float64),
+	nullableField("double_value", arrow.PrimitiveTypes.Float64),
+)
+
+var exportUserPropertyValueType = arrow.StructOf(
+	nullableField("string_value", arrow.BinaryTypes.String),
+	nullableField("int_value", arrow.PrimitiveTypes.Int64),
+	nullableField("float_value", arrow.PrimitiveTypes.Float64),
+	nullableField("double_value", arrow.PrimitiveTypes.Float64),
+	nullableField("set_timestamp_micros", arrow.PrimitiveTypes.Int64),
+)
+
+// bigQueryExportSchema follows the schema of the GA4 BigQuery export, see
+// https://support.google.com/analytics/answer/7029846
+var bigQueryExportSchema = arrow.NewSchema([]arrow.Field{
+	nullableField("event_date", arrow.BinaryTypes.String),
+	nullableField("event_timestamp", arrow.PrimitiveTypes.Int64),
+	nullableField("event_name", arrow.BinaryTypes.String),
+	nullableField("event_params", arrow.ListOf(arrow.StructOf(
+		nullableField("key", arrow.BinaryTypes.String),
+		nullableField("value", exportParamValueType),
+	))),
+	nullableField("event_previous_timestamp", arrow.PrimitiveTypes.Int64),
+	nullableField("event_value_in_usd", arrow.PrimitiveTypes.Float64),
+	nullableField("event_bundle_sequence_id", arrow.PrimitiveTypes.Int64),
+	nullableField("event_server_timestamp_offset", arrow.PrimitiveTypes.Int64),
+	nullableField("user_id", arrow.BinaryTypes.String),
+	nullableField("user_pseudo_id", arrow.BinaryTypes.String),
+	nullableField("privacy_info", arrow.StructOf(
+		nullableField("analytics_storage", arrow.BinaryTypes.String),
+		nullableField("ads_storage", arrow.BinaryTypes.String),
+		nullableField("uses_transient_token", arrow.BinaryTypes.String),
+	)),
+	nullableField("user_properties", arrow.ListOf(arrow.StructOf(
+		nullableField("key", arrow.BinaryTypes.String),
+		nullableField("value", exportUserPropertyValueType),
+	))),
+	nullableField("user_first_touch_timestamp", arrow.PrimitiveTypes.Int64),
+	nullableField("device", arrow.StructOf(
+		nullableField("category", arrow.BinaryTypes.String),
+		nullableField("mobile_brand_name", arrow.BinaryTypes.String),
+		nullableField("mobile_model_name", arrow.BinaryTypes.String),
+		nullableField("mobile_marketing_name", arrow.BinaryTypes.String),
+		nullableField("mobile_os_hardware_model", arrow.BinaryTypes.String),
+		nullableField("operating_system", arrow.BinaryTypes.String),
+		nullableField("operating_system_version", arrow.BinaryTypes.String),
+		nullableField("vendor_id", arrow.BinaryTypes.String),
+		nullableField("advertising_id", arrow.BinaryTypes.String),
+		nullableField("language", arrow.BinaryTypes.String),
+		nullableField("is_limited_ad_tracking", arrow.BinaryTypes.String),
+		nullableField("time_zone_offset_seconds", arrow.PrimitiveTypes.Int64),
+		nullableField("browser", arrow.BinaryTypes.String),
+		nullableField("browser_version", arrow.BinaryTypes.String),
+		nullableField("web_info", arrow.StructOf(
+			nullableField("browser", arrow.BinaryTypes.String),
+			nullableField("browser_version", arrow.BinaryTypes.String),
+			nullableField("hostname", arrow.BinaryTypes.String),
+		)),
+	)),
+	nullableField("geo", arrow.StructOf(
+		nullableField("city", arrow.BinaryTypes.String),
+		nullableField("country", arrow.BinaryTypes.String),
+		nullableField("continent", arrow.BinaryTypes.String),
+		nullableField("region", arrow.BinaryTypes.String),
+		nullableField("sub_continent", arrow.BinaryTypes.String),
+		nullableField("metro", arrow.BinaryTypes.String),
+	)),
+	nullableField("traffic_source", arrow.StructOf(
+		nullableField("name", arrow.BinaryTypes.String),
+		nullableField("medium", arrow.BinaryTypes.String),
+		nullableField("source", arrow.BinaryTypes.String),
+	)),
+	nullableField("stream_id", arrow.BinaryTypes.String),
+	nullableField("platform", arrow.BinaryTypes.String),
+	nullableField("ecommerce", arrow.StructOf(
+		nullableField("total_item_quantity", arrow.PrimitiveTypes.Int64),
+		nullableField("purchase_revenue_in_usd", arrow.PrimitiveTypes.Float64),
+		nullableField("purchase_revenue", arrow.PrimitiveTypes.Float64),
+		nullableField("refund_value_in_usd", arrow.PrimitiveTypes.Float64),
+		nullableField("refund_value", arrow.PrimitiveTypes.Float64),
+		nullableField("shipping_value_in_usd", arrow.PrimitiveTypes.Float64),
+		nullableField("shipping_value", arrow.PrimitiveTypes.Float64),
+		nullableField("tax_value_in_usd", arrow.PrimitiveTypes.Float64),
+		nullableField("tax_value", arrow.PrimitiveTypes.Float64),
+		nullableField("unique_items", arrow.PrimitiveTypes.Int64),
+		nullableField("transaction_id", arrow.BinaryTypes.String),
+	)),
+	nullableField("items", arrow.ListOf(arrow.StructOf(
+		nullableField("item_id", arrow.BinaryTypes.String),
+		nullableField("item_name", arrow.BinaryTypes.String),
+		nullableField("item_brand", arrow.BinaryTypes.String),
+		nullableField("item_variant", arrow.BinaryTypes.String),
+		nullableField("item_category", arrow.BinaryTypes.String),
+		nullableField("item_category2", arrow.BinaryTypes.String),
+		nullableField("item_category3", arrow.BinaryTypes.String),
+		nullableField("item_category4", arrow.BinaryTypes.String),
+		nullableField("item_category5", arrow.BinaryTypes.String),
+		nullableField("price_in_usd", arrow.PrimitiveTypes.Float64),
+		nullableField("price", arrow.PrimitiveTypes.Float64),
+		nullableField("quantity", arrow.PrimitiveTypes.Int64),
+		nullableField("item_revenue_in_usd", arrow.PrimitiveTypes.Float64),
+		nullableField("item_revenue", arrow.PrimitiveTypes.Float64),
+		nullableField("item_refund_in_usd", arrow.PrimitiveTypes.Float64),
+		nullableField("item_refund", arrow.PrimitiveTypes.Float64),
+		nullableField("coupon", arrow.BinaryTypes.String),
+		nullableField("affiliation", arrow.BinaryTypes.String),
+		nullableField("location_id", arrow.BinaryTypes.String),
+		nullableField("item_list_id", arrow.BinaryTypes.String),
+		nullableField("item_list_name", arrow.BinaryTypes.String),
+		nullableField("item_list_index", arrow.BinaryTypes.String),
+		nullableField("promotion_id", arrow.BinaryTypes.String),
+		nullableField("promotion_name", arrow.BinaryTypes.String),
+		nullableField("creative_name", arrow.BinaryTypes.String),
+		nullableField("creative_slot", arrow.BinaryTypes.String),
+	))),
+	nullableField("collected_traffic_source", arrow.StructOf(
+		nullableField("manual_campaign_id", arrow.BinaryTypes.String),
+		nullableField("manual_campaign_name", arrow.BinaryTypes.String),
+		nullableField("manual_source", arrow.BinaryTypes.String),
+		nullableField("manual_medium", arrow.BinaryTypes.String),
+		nullableField("manual_term", arrow.BinaryTypes.String),
+		nullableField("manual_content", arrow.BinaryTypes.String),
+		nullableField("manual_source_platform", arrow.BinaryTypes.String),
+		nullableField("manual_creative_format", arrow.BinaryTypes.String),
+		nullableField("manual_marketing_tactic", arrow.BinaryTypes.String),
+		nullableField("gclid", arrow.BinaryTypes.String),
+		nullableField("dclid", arrow.BinaryTypes.String),
+		nullableField("srsltid", arrow.BinaryTypes.String),
+	)),
+	nullableField("is_active_user", arrow.FixedWidthTypes.Boolean),
+	// Not part of the GA4 export. The export shards tables by day, d8a writes a single
+	// table partitioned by this column instead.
+	nullableField(BigQueryExportPartitionField, arrow.FixedWidthTypes.Date32),
+}, nil)
+
+// BigQueryExportPartitionField is the DATE field the tables of the GA4 BigQuery export
+// layout are partitioned by.
+const BigQueryExportPartitionField = "date_utc"
+
+func nullableField(name string, dataType arrow.DataType) arrow.Field {
+	return arrow.Field{Name: name, Type: dataType, Nullable: true}
+}
+
+type bigQueryExportLayout struct {
+	table string
+}
+
+// NewBigQueryExportLayout creates a single table layout with rows shaped like the GA4
+// BigQuery export, so queries written against the export run against d8a data. The
+// values are taken from the GA4 protocol columns and the tracked hits, other columns
+// aren't written. Nested records are only supported by the BigQuery warehouse, and the
+// table should be partitioned by BigQueryExportPartitionField.
+func NewBigQueryExportLayout(table string) schema.Layout {
+	return &bigQueryExportLayout{table: table}
+}
+
+func (l *bigQueryExportLayout) Tables(_ schema.Columns) []schema.WithMeta {
+	return []schema.WithMeta{
+		{Schema: bigQueryExportSchema, Table: l.table, Scope: schema.ScopeMixed},
+	}
+}
+
+func (l *bigQueryExportLayout) ToRows(_ schema.Columns, sessions ...*schema.Session) ([]schema.TableRows, error) {
+	totalEvents := 0
+	for _, session := range sessions {
+		totalEvents += len(session.Events)
+	}
+	rows := make([]map[string]any, 0, totalEvents)
+	for _, session := range sessions {
+		for _, event := range session.Events {
+			rows = append(rows, exportRow(event, session.Values))
+		}
+	}
+	return []schema.TableRows{{Table: l.table, Rows: rows}}, nil
+}
+
+func exportRow(e *schema.Event, session map[string]any) map[string]any {
+	core := &columns.CoreInterfaces
+	ga4 := &ProtocolInterfaces
+	event := e.Values
+
+	var eventDate, eventTimestamp any
+	if date, ok := timeValue(event[core.EventDateUTC.Field.Name]); ok {
+		eventDate = date.Format("20060102")
+	}
+	if timestamp, ok := timeValue(event[core.EventTimestampUTC.Field.Name]); ok {
+		eventTimestamp = timestamp.UnixMicro()
+	}
+	var platform any
+	if p, ok := event[core.EventPlatform.Field.Name].(string); ok {
+		platform = strings.ToUpper(p)
+	}
+
+	return map[string]any{
+		"event_date":                    eventDate,
+		"event_timestamp":               eventTimestamp,
+		"event_name":                    event[core.EventName.Field.Name],
+		"event_params":                  exportEventParams(event, session),
+		"event_previous_timestamp":      nil,
+		"event_value_in_usd":            nil,
+		"event_bundle_sequence_id":      nil,
+		"event_server_timestamp_offset": nil,
+		"user_id":                       event[core.EventUserID.Field.Name],
+		"user_pseudo_id":                event[core.EventClientID.Field.Name],
+		"privacy_info": map[string]any{
+			"analytics_storage":    consentValue(event[ga4.EventPrivacyAnalyticsStorage.Field.Name]),
+			"ads_storage":          consentValue(event[ga4.EventPrivacyAdsStorage.Field.Name]),
+			"uses_transient_token": nil,
+		},
+		"user_properties":            exportUserProperties(e, eventTimestamp),
+		"user_first_touch_timestamp": nil,
+		"device": map[string]any{
+			"category":                 event[core.DeviceCategory.Field.Name],
+			"mobile_brand_name":        event[core.DeviceMobileBrandName.Field.Name],
+			"mobile_model_name":        event[core.DeviceMobileModelName.Field.Name],
+			"mobile_marketing_name":    nil,
+			"mobile_os_hardware_model": nil,
+			"operating_system":         event[core.DeviceOperatingSystem.Field.Name],
+			"operating_system_version": event[core.DeviceOperatingSystemVersion.Field.Name],
+			"vendor_id":                nil,
+			"advertising_id":           nil,
+			"language":                 event[core.DeviceLanguage.Field.Name],
+			"is_limited_ad_tracking":   nil,
+			"time_zone_offset_seconds": nil,
+			"browser":                  event[core.DeviceWebBrowser.Field.Name],
+			"browser_version":          event[core.DeviceWebBrowserVersion.Field.Name],
+			"web_info": map[string]any{
+				"browser":         event[core.DeviceWebBrowser.Field.Name],
+				"browser_version": event[core.DeviceWebBrowserVersion.Field.Name],
+				"hostname":        event[core.EventPageHostname.Field.Name],
+			},
+		},
+		"geo": map[string]any{
+			"city":          event[core.GeoCity.Field.Name],
+			"country":       event[core.GeoCountry.Field.Name],
+			"continent":     event[core.GeoContinent.Field.Name],
+			"region":        event[core.GeoRegion.Field.Name],
+			"sub_continent": event[core.GeoSubContinent.Field.Name],
+			"metro":         event[core.GeoMetro.Field.Name],
+		},
+		// d8a doesn't track users across sessions, the session traffic source is the closest match
+		"traffic_source": map[string]any{
+			"name":   session[core.SessionUtmCampaign.Field.Name],
+			"medium": session[core.SessionMedium.Field.Name],
+			"source": session[core.SessionSource.Field.Name],
+		},
+		"stream_id": event[ga4.EventMeasurementID.Field.Name],
+		"platform":  platform,
+		"ecommerce": map[string]any{
+			"total_item_quantity":     event[ga4.EventEcommerceItemsTotalQuantity.Field.Name],
+			"purchase_revenue_in_usd": event[ga4.EventEcommercePurchaseRevenueInUSD.Field.Name],
+			"purchase_revenue":        event[ga4.EventEcommercePurchaseRevenue.Field.Name],
+			"refund_value_in_usd":     event[ga4.EventEcommerceRefundValueInUSD.Field.Name],
+			"refund_value":            event[ga4.EventEcommerceRefundValue.Field.Name],
+			"shipping_value_in_usd":   event[ga4.EventEcommerceShippingValueInUSD.Field.Name],
+			"shipping_value":          event[ga4.EventEcommerceShippingValue.Field.Name],
+			"tax_value_in_usd":        event[ga4.EventEcommerceTaxValueInUSD.Field.Name],
+			"tax_value":               event[ga4.EventEcommerceTaxValue.Field.Name],
+			"unique_items":            event[ga4.EventEcommerceUniqueItems.Field.Name],
+			"transaction_id":          event[ga4.EventParamTransactionID.Field.Name],
+		},
+		"items": exportItems(event[ga4.EventItems.Field.Name]),
+		"collected_traffic_source": map[string]any{
+			"manual_campaign_id":      event[core.EventUtmID.Field.Name],
+			"manual_campaign_name":    event[core.EventUtmCampaign.Field.Name],
+			"manual_source":           event[core.EventUtmSource.Field.Name],
+			"manual_medium":           event[core.EventUtmMedium.Field.Name],
+			"manual_term":             event[core.EventUtmTerm.Field.Name],
+			"manual_content":          event[core.EventUtmContent.Field.Name],
+			"manual_source_platform":  event[core.EventUtmSourcePlatform.Field.Name],
+			"manual_creative_format":  event[core.EventUtmCreativeFormat.Field.Name],
+			"manual_marketing_tactic": event[core.EventUtmMarketingTactic.Field.Name],
+			"gclid":                   event[core.EventClickIDGclid.Field.Name],
+			"dclid":                   event[core.EventClickIDDclid.Field.Name],
+			"srsltid":                 event[core.EventClickIDSrsltid.Field.Name],
+		},
+		"is_active_user":             session[ga4.SessionIsEngaged.Field.Name],
+		BigQueryExportPartitionField: event[core.EventDateUTC.Field.Name],
+	}
+}
+
+// exportEventParams converts the generic event params to export params and adds the
+// parameters which GA4 collects automatically, unless they were sent explicitly.
+func exportEventParams(event, session map[string]any) []any {
+	core := &columns.CoreInterfaces
+	ga4 := &ProtocolInterfaces
+
+	params := make([]any, 0)
+	sent := map[string]bool{}
+	for _, param := range listOfMaps(event[ga4.EventParams.Field.Name]) {
+		name, _ := param["name"].(string)
+		sent[name] = true
+		params = append(params, map[string]any{
+			"key":   name,
+			"value": exportValue(coalesce(param["value_string"], param["value_number"])),
+		})
+	}
+
+	var gaSessionID any
+	if sessionID, ok := event[ga4.ClientSessionID.Field.Name].(string); ok {
+		if parsed, err := strconv.ParseInt(sessionID, 10, 64); err == nil {
+			gaSessionID = parsed
+		}
+	}
+	var sessionEngaged any
+	if engaged, ok := session[ga4.SessionIsEngaged.Field.Name].(bool); ok && engaged {
+		sessionEngaged = "1"
+	}
+	var entrances any
+	if entry, ok := event[core.SSEIsEntryPage.Field.Name].(bool); ok && entry {
+		entrances = int64(1)
+	}
+	var ignoreReferrer any
+	if ignore, ok := event[core.EventIgnoreReferrer.Field.Name].(bool); ok && ignore {
+		ignoreReferrer = "true"
+	}
+
+	collected := []struct {
+		key   string
+		value any
+	}{
+		{"page_location", event[core.EventPageLocation.Field.Name]},
+		{"page_title", event[core.EventPageTitle.Field.Name]},
+		{"page_referrer", event[core.EventPageReferrer.Field.Name]},
+		{"ga_session_id", gaSessionID},
+		{"ga_session_number", event[ga4.ClientSessionNumber.Field.Name]},
+		{"engagement_time_msec", event[ga4.EventParamEngagementTimeMs.Field.Name]},
+		{"session_engaged", sessionEngaged},
+		{"entrances", entrances},
+		{"ignore_referrer", ignoreReferrer},
+		{"campaign", event[core.EventUtmCampaign.Field.Name]},
+		{"source", event[core.EventUtmSource.Field.Name]},
+		{"medium", event[core.EventUtmMedium.Field.Name]},
+		{"term", event[core.EventUtmTerm.Field.Name]},
+		{"content", event[core.EventUtmContent.Field.Name]},
+		{"gclid", event[core.EventClickIDGclid.Field.Name]},
+	}
+	for _, param := range collected {
+		if param.value == nil || param.value == "" || sent[param.key] {
+			continue
+		}
+		params = append(params, map[string]any{"key": param.key, "value": exportValue(param.value)})
+	}
+
+	slices.SortStableFunc(params, func(a, b any) int {
+		aKey, _ := a.(map[string]any)["key"].(string)
+		bKey, _ := b.(map[string]any)["key"].(string)
+		return strings.Compare(aKey, bKey)
+	})
+	return params
+}
+
+// exportUserPropertiesInterfaceID identifies the user properties in errors of casting
+// their values, they're only written by the GA4 BigQuery export layout.
+const exportUserPropertiesInterfaceID = "ga4.protocols.d8a.tech/export/user_properties"
+
+// exportUserProperties converts the user properties sent with the event as up. and upn.
+// params to export user properties.
+func exportUserProperties(event *schema.Event, eventTimestamp any) []any {
+	properties := make([]any, 0)
+	if event.BoundHit == nil {
+		return properties
+	}
+	for _, property := range listOfMaps(prefixedQueryParams(event, "up.", "upn.", exportUserPropertiesInterfaceID)) {
+		value := exportValue(coalesce(property["value_string"], property["value_number"]))
+		value["set_timestamp_micros"] = eventTimestamp
+		properties = append(properties, map[string]any{"key": property["name"], "value": value})
+	}
+	return properties
+}
+
+var exportItemKeys = map[string]string{
+	itemKeyID:            "item_id",
+	itemKeyName:          "item_name",
+	itemKeyBrand:         "item_brand",
+	itemKeyVariant:       "item_variant",
+	itemKeyCategory:      "item_category",
+	itemKeyCategory2:     "item_category2",
+	itemKeyCategory3:     "item_category3",
+	itemKeyCategory4:     "item_category4",
+	itemKeyCategory5:     "item_category5",
+	itemKeyPriceInUSD:    "price_in_usd",
+	itemKeyPrice:         "price",
+	itemKeyRevenueInUSD:  "item_revenue_in_usd",
+	itemKeyRevenue:       "item_revenue",
+	itemKeyRefundInUSD:   "item_refund_in_usd",
+	itemKeyRefund:        "item_refund",
+	itemKeyCoupon:        "coupon",
+	itemKeyAffiliation:   "affiliation",
+	itemKeyLocationID:    "location_id",
+	itemKeyListID:        "item_list_id",
+	itemKeyListName:      "item_list_name",
+	itemKeyPromotionID:   "promotion_id",
+	itemKeyPromotionName: "promotion_name",
+	itemKeyCreativeName:  "creative_name",
+	itemKeyCreativeSlot:  "creative_slot",
+}
+
+func exportItems(items any) []any {
+	exported := make([]any, 0)
+	for _, item := range listOfMaps(items) {
+		exportedItem := make(map[string]any, len(exportItemKeys)+2)
+		for itemKey, exportKey := range exportItemKeys {
+			exportedItem[exportKey] = item[itemKey]
+		}
+		// The export keeps quantity as an integer and the list index as a string
+		exportedItem["quantity"] = nil
+		if quantity, ok := item[itemKeyQuantity].(float64); ok {
+			exportedItem["quantity"] = int64(quantity)
+		}
+		exportedItem["item_list_index"] = nil
+		if index, ok := item[itemKeyIndex].(float64); ok {
+			exportedItem["item_list_index"] = strconv.FormatFloat(index, 'f', -1, 64)
+		}
+		exported = append(exported, exportedItem)
+	}
+	return exported
+}
+
+// exportValue converts a parameter value to the export value record. Whole numbers are
+// stored as int_value and fractional ones as double_value, the same as GA4 does.
+func exportValue(value any) map[string]any {
+	result := map[string]any{"string_value": nil, "int_value": nil, "float_value": nil, "double_value": nil}
+	switch v := value.(type) {
+	case string:
+		result["string_value"] = v
+	case int64:
+		result["int_value"] = v
+	case int:
+		result["int_value"] = int64(v)
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < math.MaxInt64 {
+			result["int_value"] = int64(v)
+		} else {
+			result["double_value"] = v
+		}
+	case bool:
+		result["string_value"] = strconv.FormatBool(v)
+	}
+	return result
+}
+
+func consentValue(value any) any {
+	granted, ok := value.(bool)
+	if !ok {
+		return nil
+	}
+	if granted {
+		return "Yes"
+	}
+	return "No"
+}
+
+func timeValue(value any) (time.Time, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		for _, layout := range []string{time.RFC3339, time.DateOnly} {
+			if t, err := time.Parse(layout, v); err == nil {
+				return t, true
+			}
+		}
+	}
+	return time.Time{}, false
+}
+
+func listOfMaps(value any) []map[string]any {
+	switch v := value.(type) {
+	case []map[string]any:
+		return v
+	case []any:
+		result := make([]map[string]any, 0, len(v))
+		for _, elem := range v {
+			if m, ok := elem.(map[string]any); ok {
+				result = append(result, m)
+			}
+		}
+		return result
+	}
+	return nil
+}
+
+func coalesce(values ...any) any {
+	for _, value := range values {
+		if value != nil {
+			return value
+		}
+	}
+	return nil
+}
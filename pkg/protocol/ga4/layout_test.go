@@ -0,0 +1,169 @@
+package ga4
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/columns/columntests"
+	"github.com/d8a-tech/d8a/pkg/currency"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/schema"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/d8a-tech/d8a/pkg/warehouse/bigquery"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBigQueryExportLayout(t *testing.T) {
+	// given
+	columntests.ColumnTestCase(
+		t,
+		columntests.TestHits{columntests.TestHitOne()},
+		func(t *testing.T, closeErr error, whd *warehouse.MockWarehouseDriver) {
+			// when + then
+			require.NoError(t, closeErr)
+			require.Len(t, whd.WriteCalls, 1)
+			assert.Equal(t, "events_export", whd.WriteCalls[0].Table)
+			record := whd.WriteCalls[0].Records[0]
+			assert.ElementsMatch(t, fieldNamesOf(bigQueryExportSchema.Fields()), keysOf(record))
+
+			assert.Regexp(t, `^\d{8}$`, record["event_date"])
+			assert.NotNil(t, record[BigQueryExportPartitionField])
+			assert.IsType(t, int64(0), record["event_timestamp"])
+			assert.Equal(t, "page_view", record["event_name"])
+			assert.Equal(t, "WEB", record["platform"])
+			assert.Equal(t, "G-5T0Z13HKP4", record["stream_id"])
+			assert.NotEmpty(t, record["user_pseudo_id"])
+
+			params := map[string]map[string]any{}
+			for _, param := range listOfMaps(record["event_params"]) {
+				value, ok := param["value"].(map[string]any)
+				require.True(t, ok)
+				params[param["key"].(string)] = value
+			}
+			assert.Equal(t, "EUR", params["currency"]["string_value"])
+			assert.Equal(t, int64(42), params["score"]["int_value"])
+			assert.NotNil(t, params["page_location"]["string_value"])
+
+			userProperties := listOfMaps(record["user_properties"])
+			require.Len(t, userProperties, 1)
+			assert.Equal(t, "tier", userProperties[0]["key"])
+			userPropertyValue, ok := userProperties[0]["value"].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, "gold", userPropertyValue["string_value"])
+			assert.Equal(t, record["event_timestamp"], userPropertyValue["set_timestamp_micros"])
+
+			items := listOfMaps(record["items"])
+			require.Len(t, items, 1)
+			assert.Equal(t, "SKU_12345", items[0]["item_id"])
+			assert.Equal(t, int64(3), items[0]["quantity"])
+			assert.Equal(t, "5", items[0]["item_list_index"])
+			assert.Equal(t, 10.01, items[0]["price"])
+
+			t.Run("rows are accepted by the bigquery type mapper", func(t *testing.T) {
+				mapper := bigquery.NewFieldTypeMapper()
+				for _, field := range bigQueryExportSchema.Fields() {
+					bqType, err := mapper.ArrowToWarehouse(warehouse.ArrowType{
+						ArrowDataType: field.Type,
+						Nullable:      field.Nullable,
+					})
+					require.NoError(t, err, field.Name)
+					_, err = bqType.Format(record[field.Name], arrow.Metadata{})
+					assert.NoError(t, err, field.Name)
+				}
+			})
+		},
+		NewGA4Protocol(currency.NewDummyConverter(2), properties.NewTestSettingRegistry()),
+		columntests.SetLayoutRegistry(schema.NewStaticLayoutRegistry(
+			map[string]schema.Layout{},
+			NewBigQueryExportLayout("events_export"),
+		)),
+		columntests.EnsureQueryParam(0, "pr1", "idSKU_12345~lp5~pr10.01~qt3"),
+		columntests.EnsureQueryParam(0, "ep.currency", "EUR"),
+		columntests.EnsureQueryParam(0, "epn.score", "42"),
+		columntests.EnsureQueryParam(0, "up.tier", "gold"),
+	)
+}
+
+func TestBigQueryExportSchema_PartitionFieldIsADate(t *testing.T) {
+	// when
+	fields, found := bigQueryExportSchema.FieldsByName(BigQueryExportPartitionField)
+
+	// then
+	require.True(t, found)
+	assert.Equal(t, arrow.FixedWidthTypes.Date32, fields[0].Type)
+}
+
+func TestGA4ProtocolColumns_DontIncludeExportUserProperties(t *testing.T) {
+	// given
+	columns := NewGA4Protocol(currency.NewDummyConverter(1), properties.NewTestSettingRegistry()).Columns()
+
+	// when
+	names := make([]string, 0, len(columns.Event))
+	for _, column := range columns.Event {
+		names = append(names, column.Implements().Field.Name)
+	}
+
+	// then
+	assert.NotContains(t, names, "user_properties", "only the export layout writes user properties")
+}
+
+func TestExportValue(t *testing.T) {
+	testCases := []struct {
+		name     string
+		value    any
+		expected map[string]any
+	}{
+		{
+			name:     "string",
+			value:    "foo",
+			expected: map[string]any{"string_value": "foo", "int_value": nil, "float_value": nil, "double_value": nil},
+		},
+		{
+			name:     "whole number",
+			value:    float64(3),
+			expected: map[string]any{"string_value": nil, "int_value": int64(3), "float_value": nil, "double_value": nil},
+		},
+		{
+			name:     "fractional number",
+			value:    1.5,
+			expected: map[string]any{"string_value": nil, "int_value": nil, "float_value": nil, "double_value": 1.5},
+		},
+		{
+			name:     "int64",
+			value:    int64(7),
+			expected: map[string]any{"string_value": nil, "int_value": int64(7), "float_value": nil, "double_value": nil},
+		},
+		{
+			name:     "nil",
+			value:    nil,
+			expected: map[string]any{"string_value": nil, "int_value": nil, "float_value": nil, "double_value": nil},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			result := exportValue(tc.value)
+
+			// then
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func fieldNamesOf(fields []arrow.Field) []string {
+	names := make([]string, len(fields))
+	for i, field := range fields {
+		names[i] = field.Name
+	}
+	return names
+}
+
+func keysOf(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	return keys
+}
@@ -0,0 +1,307 @@
+package ga4
+
+import (
+	"bytes"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+)
+
+// MeasurementProtocolPath is the path of the GA4 Measurement Protocol endpoint, used for
+// sending events server-to-server.
+const MeasurementProtocolPath = "/mp/collect"
+
+// measurementProtocolMaxEvents is the limit of events in a single request, same as in GA4.
+const measurementProtocolMaxEvents = 25
+
+// measurementProtocolMaxEventAge is how far before the request timestamp_micros may be,
+// same as in GA4.
+const measurementProtocolMaxEventAge = 72 * time.Hour
+
+// measurementProtocolMaxClockSkew is how far after the request timestamp_micros may be, to
+// tolerate senders whose clocks are slightly ahead.
+const measurementProtocolMaxClockSkew = time.Minute
+
+type measurementProtocolPayload struct {
+	ClientID           string                                 `json:"client_id"`
+	UserID             string                                 `json:"user_id"`
+	TimestampMicros    json.Number                            `json:"timestamp_micros"`
+	NonPersonalizedAds bool                                   `json:"non_personalized_ads"`
+	UserProperties     map[string]measurementProtocolProperty `json:"user_properties"`
+	Events             []measurementProtocolEvent             `json:"events"`
+}
+
+type measurementProtocolProperty struct {
+	Value any `json:"value"`
+}
+
+type measurementProtocolEvent struct {
+	Name            string         `json:"name"`
+	TimestampMicros json.Number    `json:"timestamp_micros"`
+	Params          map[string]any `json:"params"`
+}
+
+// measurementProtocolSpecialParams are event params, which gtag sends as dedicated query
+// parameters instead of ep./epn. ones. They are translated, so the same columns read them.
+var measurementProtocolSpecialParams = map[string]string{
+	"page_location":        "dl",
+	"page_title":           "dt",
+	"page_referrer":        "dr",
+	"language":             "ul",
+	"screen_resolution":    "sr",
+	"session_id":           "sid",
+	"session_number":       "sct",
+	"engagement_time_msec": "_et",
+}
+
+// measurementProtocolItemKeys maps item fields to the prefixes of the gtag item format
+// parsed by parseItem.
+var measurementProtocolItemKeys = map[string]string{
+	"item_id":        "id",
+	"item_name":      "nm",
+	"affiliation":    "af",
+	"coupon":         "cp",
+	"discount":       "ds",
+	"index":          "lp",
+	"item_brand":     "br",
+	"item_category":  "ca",
+	"item_category2": "c2",
+	"item_category3": "c3",
+	"item_category4": "c4",
+	"item_category5": "c5",
+	"item_list_id":   "li",
+	"item_list_name": "ln",
+	"item_variant":   "va",
+	"location_id":    "lo",
+	"price":          "pr",
+	"quantity":       "qt",
+	"creative_name":  "cn",
+	"creative_slot":  "cs",
+	"promotion_id":   "pi",
+	"promotion_name": "pn",
+}
+
+// measurementProtocolHits creates a hit for every event of a Measurement Protocol request.
+// Events are translated to the gtag query parameter format, so all GA4 columns apply.
+func (p *ga4Protocol) measurementProtocolHits(ctx *protocol.RequestContext) ([]*hits.Hit, error) {
+	measurementID := ctx.Parsed.QueryParams.Get("measurement_id")
+	if measurementID == "" {
+		return nil, errors.New("`measurement_id` is a required query parameter for the measurement protocol")
+	}
+	// Replayed requests were verified when they were received, their secret is not stored
+	if _, replayed := protocol.ReplayedRequest(ctx.FastHttp); !replayed {
+		if err := p.checkAPISecret(measurementID, ctx.Parsed.QueryParams.Get("api_secret")); err != nil {
+			return nil, err
+		}
+	}
+	// The secret is removed from the request itself, so it's not written to the raw log
+	ctx.Parsed.QueryParams.Del("api_secret")
+
+	decoder := json.NewDecoder(bytes.NewReader(ctx.Parsed.Body))
+	decoder.UseNumber()
+	var payload measurementProtocolPayload
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid measurement protocol body: %w", err)
+	}
+	if err := payload.validate(); err != nil {
+		return nil, err
+	}
+	eventTimes, err := payload.eventTimes(ctx.Parsed.ServerReceivedTime)
+	if err != nil {
+		return nil, err
+	}
+
+	theHits := make([]*hits.Hit, 0, len(payload.Events))
+	for idx := range payload.Events {
+		requestCopy := ctx.Parsed.Clone()
+		requestCopy.QueryParams = payload.eventParams(measurementID, &payload.Events[idx])
+		requestCopy.ServerReceivedTime = eventTimes[idx]
+		hit, err := p.createHitBase(&protocol.RequestContext{
+			FastHttp: ctx.FastHttp,
+			Parsed:   requestCopy,
+		}, requestCopy.Body)
+		if err != nil {
+			return nil, fmt.Errorf("events[%d]: %w", idx, err)
+		}
+		theHits = append(theHits, hit)
+	}
+	return theHits, nil
+}
+
+// checkAPISecret makes sure the secret is one of the API secrets of the property. Properties
+// without any secret do not accept Measurement Protocol requests at all.
+func (p *ga4Protocol) checkAPISecret(measurementID, apiSecret string) error {
+	settings, err := p.psr.GetByMeasurementID(measurementID)
+	if err != nil {
+		return err
+	}
+	if len(settings.MeasurementProtocolAPISecrets) == 0 {
+		return fmt.Errorf("measurement protocol is not enabled for measurement ID %q", measurementID)
+	}
+	for _, secret := range settings.MeasurementProtocolAPISecrets {
+		if subtle.ConstantTimeCompare([]byte(secret), []byte(apiSecret)) == 1 {
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid `api_secret` for measurement ID %q", measurementID)
+}
+
+func (m *measurementProtocolPayload) validate() error {
+	if m.ClientID == "" {
+		return errors.New("`client_id` is required")
+	}
+	if len(m.Events) == 0 {
+		return errors.New("`events` must contain at least one event")
+	}
+	if len(m.Events) > measurementProtocolMaxEvents {
+		return fmt.Errorf("`events` must not contain more than %d events", measurementProtocolMaxEvents)
+	}
+	for idx := range m.Events {
+		if m.Events[idx].Name == "" {
+			return fmt.Errorf("events[%d].name is required", idx)
+		}
+	}
+	return nil
+}
+
+// eventTimes returns the time of every event: its own timestamp_micros, the one of the
+// request, or the time the request was received. Timestamps must be within the 72 hours
+// before the request was received, so events can be backdated the same way as in GA4.
+func (m *measurementProtocolPayload) eventTimes(receivedAt time.Time) ([]time.Time, error) {
+	requestTime, err := measurementProtocolTimestamp(m.TimestampMicros, receivedAt, receivedAt)
+	if err != nil {
+		return nil, fmt.Errorf("`timestamp_micros`: %w", err)
+	}
+	eventTimes := make([]time.Time, 0, len(m.Events))
+	for idx := range m.Events {
+		eventTime, err := measurementProtocolTimestamp(m.Events[idx].TimestampMicros, requestTime, receivedAt)
+		if err != nil {
+			return nil, fmt.Errorf("events[%d].timestamp_micros: %w", idx, err)
+		}
+		eventTimes = append(eventTimes, eventTime)
+	}
+	return eventTimes, nil
+}
+
+func measurementProtocolTimestamp(value json.Number, fallback, receivedAt time.Time) (time.Time, error) {
+	if value == "" {
+		return fallback, nil
+	}
+	micros, err := value.Int64()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("must be an integer: %w", err)
+	}
+	timestamp := time.UnixMicro(micros)
+	if timestamp.Before(receivedAt.Add(-measurementProtocolMaxEventAge)) {
+		return time.Time{}, fmt.Errorf("must not be more than %s in the past: %d", measurementProtocolMaxEventAge, micros)
+	}
+	if timestamp.After(receivedAt.Add(measurementProtocolMaxClockSkew)) {
+		return time.Time{}, fmt.Errorf("must not be in the future: %d", micros)
+	}
+	return timestamp, nil
+}
+
+// eventParams builds the gtag query parameters of a single event. The api_secret is
+// deliberately left out, so it is never stored with the hit, and so is timestamp_micros,
+// which sets the time of the hit instead.
+func (m *measurementProtocolPayload) eventParams(measurementID string, event *measurementProtocolEvent) url.Values {
+	params := url.Values{}
+	params.Set("v", "2")
+	params.Set("tid", measurementID)
+	params.Set("cid", m.ClientID)
+	params.Set("en", event.Name)
+	if m.UserID != "" {
+		params.Set("uid", m.UserID)
+	}
+	if m.NonPersonalizedAds {
+		params.Set("npa", "1")
+	}
+	for name, property := range m.UserProperties {
+		setTypedParam(params, "up", name, property.Value)
+	}
+
+	for name, value := range event.Params {
+		if name == "items" {
+			setItemParams(params, value)
+			continue
+		}
+		if key, ok := measurementProtocolSpecialParams[name]; ok {
+			if str, ok := paramValueToString(value); ok {
+				params.Set(key, str)
+			}
+			continue
+		}
+		setTypedParam(params, "ep", name, value)
+	}
+	return params
+}
+
+// setTypedParam sets a string value as <prefix>.name and a numeric one as <prefix>n.name,
+// the same way gtag does.
+func setTypedParam(params url.Values, prefix, name string, value any) {
+	if number, ok := value.(json.Number); ok {
+		params.Set(prefix+"n."+name, number.String())
+		return
+	}
+	if str, ok := paramValueToString(value); ok {
+		params.Set(prefix+"."+name, str)
+	}
+}
+
+func setItemParams(params url.Values, value any) {
+	items, ok := value.([]any)
+	if !ok {
+		return
+	}
+	for idx, item := range items {
+		fields, ok := item.(map[string]any)
+		if !ok {
+			continue
+		}
+		parts := make([]string, 0, len(fields))
+		for name, fieldValue := range fields {
+			prefix, ok := measurementProtocolItemKeys[name]
+			if !ok {
+				continue
+			}
+			str, ok := paramValueToString(fieldValue)
+			if !ok {
+				continue
+			}
+			// The tilde separates item fields in the gtag format
+			parts = append(parts, prefix+strings.ReplaceAll(str, "~", ""))
+		}
+		if len(parts) > 0 {
+			sort.Strings(parts)
+			params.Set("pr"+strconv.Itoa(idx+1), strings.Join(parts, "~"))
+		}
+	}
+}
+
+func paramValueToString(value any) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(encoded), true
+	}
+}
@@ -0,0 +1,276 @@
+package ga4
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/columns/columntests"
+	"github.com/d8a-tech/d8a/pkg/currency"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+const testPurchaseBody = `{
+	"client_id": "123.456",
+	"user_id": "user-1",
+	"timestamp_micros": 1741064767000000,
+	"user_properties": {"tier": {"value": "gold"}, "orders": {"value": 3}},
+	"events": [{
+		"name": "purchase",
+		"params": {
+			"transaction_id": "T-1",
+			"currency": "EUR",
+			"value": 30.5,
+			"page_location": "https://shop.example.com/checkout",
+			"session_id": "1741064000",
+			"items": [
+				{"item_id": "SKU-1", "item_name": "Shoe~Red", "price": 10.25, "quantity": 2},
+				{"item_id": "SKU-2", "price": 10, "quantity": 1}
+			]
+		}
+	}]
+}`
+
+// testReceivedTime is an hour after the timestamp_micros of testPurchaseBody.
+var testReceivedTime = time.UnixMicro(1741064767000000).Add(time.Hour)
+
+func measurementProtocolRequest(query, body string) *hits.ParsedRequest {
+	queryParams, _ := url.ParseQuery(query)
+	return &hits.ParsedRequest{
+		IP:                 "10.0.0.1",
+		Host:               "d8a.example.com",
+		Path:               MeasurementProtocolPath,
+		Method:             fasthttp.MethodPost,
+		ServerReceivedTime: testReceivedTime,
+		QueryParams:        queryParams,
+		Headers:            map[string][]string{},
+		Body:               []byte(body),
+	}
+}
+
+func TestMeasurementProtocolHits(t *testing.T) {
+	testCases := []struct {
+		name           string
+		query          string
+		body           string
+		expectedParams []map[string]string
+		expectedTimes  []time.Time
+		expectError    bool
+	}{
+		{
+			name:  "purchase",
+			query: "measurement_id=G-2VEWJC5YPE&api_secret=s3cret",
+			body:  testPurchaseBody,
+			expectedParams: []map[string]string{{
+				"tid":               "G-2VEWJC5YPE",
+				"cid":               "123.456",
+				"uid":               "user-1",
+				"en":                "purchase",
+				"ep.transaction_id": "T-1",
+				"ep.currency":       "EUR",
+				"epn.value":         "30.5",
+				"dl":                "https://shop.example.com/checkout",
+				"sid":               "1741064000",
+				"up.tier":           "gold",
+				"upn.orders":        "3",
+				"pr1":               "idSKU-1~nmShoeRed~pr10.25~qt2",
+				"pr2":               "idSKU-2~pr10~qt1",
+				"timestamp_micros":  "",
+				"api_secret":        "",
+				"measurement_id":    "",
+			}},
+			expectedTimes: []time.Time{time.UnixMicro(1741064767000000)},
+		},
+		{
+			name:  "one_hit_per_event",
+			query: "measurement_id=G-2VEWJC5YPE&api_secret=other",
+			body:  `{"client_id":"1.2","events":[{"name":"sign_up","params":{"method":"email"}},{"name":"login"}]}`,
+			expectedParams: []map[string]string{
+				{"en": "sign_up", "ep.method": "email"},
+				{"en": "login"},
+			},
+			expectedTimes: []time.Time{testReceivedTime, testReceivedTime},
+		},
+		{
+			name:  "event_timestamps",
+			query: "measurement_id=G-2VEWJC5YPE&api_secret=s3cret",
+			body: `{"client_id":"1.2","timestamp_micros":1741064767000000,"events":[
+				{"name":"sign_up","timestamp_micros":1741061167000000},
+				{"name":"login"}
+			]}`,
+			expectedParams: []map[string]string{{"en": "sign_up"}, {"en": "login"}},
+			expectedTimes:  []time.Time{time.UnixMicro(1741061167000000), time.UnixMicro(1741064767000000)},
+		},
+		{
+			name:        "wrong_api_secret",
+			query:       "measurement_id=G-2VEWJC5YPE&api_secret=nope",
+			body:        testPurchaseBody,
+			expectError: true,
+		},
+		{
+			name:        "missing_api_secret",
+			query:       "measurement_id=G-2VEWJC5YPE",
+			body:        testPurchaseBody,
+			expectError: true,
+		},
+		{
+			name:        "missing_measurement_id",
+			query:       "api_secret=s3cret",
+			body:        testPurchaseBody,
+			expectError: true,
+		},
+		{
+			name:        "missing_client_id",
+			query:       "measurement_id=G-2VEWJC5YPE&api_secret=s3cret",
+			body:        `{"events":[{"name":"login"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "no_events",
+			query:       "measurement_id=G-2VEWJC5YPE&api_secret=s3cret",
+			body:        `{"client_id":"1.2","events":[]}`,
+			expectError: true,
+		},
+		{
+			name:        "event_without_name",
+			query:       "measurement_id=G-2VEWJC5YPE&api_secret=s3cret",
+			body:        `{"client_id":"1.2","events":[{"params":{}}]}`,
+			expectError: true,
+		},
+		{
+			name:        "invalid_timestamp",
+			query:       "measurement_id=G-2VEWJC5YPE&api_secret=s3cret",
+			body:        `{"client_id":"1.2","timestamp_micros":1.5,"events":[{"name":"login"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "timestamp_older_than_72_hours",
+			query:       "measurement_id=G-2VEWJC5YPE&api_secret=s3cret",
+			body:        `{"client_id":"1.2","timestamp_micros":1740800000000000,"events":[{"name":"login"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "event_timestamp_in_the_future",
+			query:       "measurement_id=G-2VEWJC5YPE&api_secret=s3cret",
+			body:        `{"client_id":"1.2","events":[{"name":"login","timestamp_micros":1741075567000000}]}`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			ga4Protocol := NewGA4Protocol(
+				currency.NewDummyConverter(1),
+				properties.NewTestSettingRegistry(properties.WithMeasurementProtocolAPISecrets("s3cret", "other")),
+			)
+
+			// when
+			result, err := ga4Protocol.Hits(&fasthttp.RequestCtx{}, measurementProtocolRequest(tc.query, tc.body))
+
+			// then
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, result, len(tc.expectedParams))
+			for i, expectedParams := range tc.expectedParams {
+				assert.Equal(t, "1234567890", result[i].PropertyID)
+				for param, expectedValue := range expectedParams {
+					actualValue := result[i].MustParsedRequest().QueryParams.Get(param)
+					assert.Equal(t, expectedValue, actualValue, "Hit %d: Parameter %s should match", i, param)
+				}
+			}
+			for i, expectedTime := range tc.expectedTimes {
+				assert.True(t, expectedTime.Equal(result[i].MustParsedRequest().ServerReceivedTime), "Hit %d time", i)
+			}
+		})
+	}
+}
+
+func TestMeasurementProtocolRemovesAPISecretFromRequest(t *testing.T) {
+	// given
+	ga4Protocol := NewGA4Protocol(
+		currency.NewDummyConverter(1),
+		properties.NewTestSettingRegistry(properties.WithMeasurementProtocolAPISecrets("s3cret")),
+	)
+	request := measurementProtocolRequest("measurement_id=G-2VEWJC5YPE&api_secret=s3cret", testPurchaseBody)
+
+	// when
+	_, err := ga4Protocol.Hits(&fasthttp.RequestCtx{}, request)
+
+	// then
+	require.NoError(t, err)
+	assert.False(t, request.QueryParams.Has("api_secret"))
+	assert.Equal(t, "G-2VEWJC5YPE", request.QueryParams.Get("measurement_id"))
+}
+
+func TestMeasurementProtocolReplayedRequestWithoutAPISecret(t *testing.T) {
+	// given
+	ga4Protocol := NewGA4Protocol(
+		currency.NewDummyConverter(1),
+		properties.NewTestSettingRegistry(properties.WithMeasurementProtocolAPISecrets("s3cret")),
+	)
+	request := measurementProtocolRequest("measurement_id=G-2VEWJC5YPE", testPurchaseBody)
+	reqCtx := &fasthttp.RequestCtx{}
+	protocol.SetReplayedRequest(reqCtx, request)
+
+	// when
+	result, err := ga4Protocol.Hits(reqCtx, request)
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, result, 1)
+}
+
+func TestMeasurementProtocolDisabledWithoutAPISecrets(t *testing.T) {
+	// given
+	ga4Protocol := NewGA4Protocol(currency.NewDummyConverter(1), properties.NewTestSettingRegistry())
+
+	// when
+	_, err := ga4Protocol.Hits(
+		&fasthttp.RequestCtx{},
+		measurementProtocolRequest("measurement_id=G-2VEWJC5YPE&api_secret=", testPurchaseBody),
+	)
+
+	// then
+	assert.Error(t, err)
+}
+
+func TestMeasurementProtocolEventColumns(t *testing.T) {
+	// given
+	psr := properties.NewTestSettingRegistry(properties.WithMeasurementProtocolAPISecrets("s3cret"))
+	ga4Protocol := NewGA4Protocol(currency.NewDummyConverter(1), psr)
+	result, err := ga4Protocol.Hits(
+		&fasthttp.RequestCtx{},
+		measurementProtocolRequest("measurement_id=G-2VEWJC5YPE&api_secret=s3cret", testPurchaseBody),
+	)
+	require.NoError(t, err)
+
+	columntests.ColumnTestCase(
+		t,
+		columntests.TestHits(result),
+		func(t *testing.T, closeErr error, whd *warehouse.MockWarehouseDriver) {
+			// when + then
+			require.NoError(t, closeErr)
+			record := whd.WriteCalls[0].Records[0]
+			assert.Equal(t, "purchase", record["name"])
+			assert.Equal(t, "T-1", record["params_transaction_id"])
+			assert.Equal(t, "EUR", record["params_currency"])
+			assert.Equal(t, 30.5, record["params_value"])
+			assert.Equal(t, "https://shop.example.com/checkout", record["page_location"])
+			assert.Equal(t, 30.5, record["ecommerce_purchase_revenue"])
+			items, ok := record["ecommerce_items"].([]any)
+			require.True(t, ok)
+			assert.Len(t, items, 2)
+		},
+		ga4Protocol,
+	)
+}
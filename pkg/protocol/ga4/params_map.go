@@ -0,0 +1,79 @@
+package ga4
+
+import (
+	"fmt"
+
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// eventParamsMapColumn writes all the string params (ep.) of the event to a single map
+// column, so that params nobody declared a column for are kept without changing the table
+// schema.
+var eventParamsMapColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventParamsMap.ID,
+	ProtocolInterfaces.EventParamsMap.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		return paramsMapValues(event, ProtocolInterfaces.EventParamsMap.ID, "value_string"), nil
+	},
+	columns.WithEventColumnDocs(
+		"Params Map",
+		"All the string parameters associated with the event, as a map of parameter names to values. Written when the params mode of the property is map or both.", // nolint:lll // it's a description
+	),
+)
+
+// eventParamsNumberMapColumn is the eventParamsMapColumn of the number params (epn.), kept
+// apart so that values keep the type the prefix gave them.
+var eventParamsNumberMapColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventParamsNumberMap.ID,
+	ProtocolInterfaces.EventParamsNumberMap.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		return paramsMapValues(event, ProtocolInterfaces.EventParamsNumberMap.ID, "value_number"), nil
+	},
+	columns.WithEventColumnDocs(
+		"Params Number Map",
+		"All the number parameters associated with the event, as a map of parameter names to values. Written when the params mode of the property is map or both.", // nolint:lll // it's a description
+	),
+)
+
+// paramsMapValues collects the params of the event having a value under valueKey, which is
+// value_string for ep. params and value_number for epn. params.
+func paramsMapValues(event *schema.Event, interfaceID schema.InterfaceID, valueKey string) map[string]any {
+	paramsMap := make(map[string]any)
+	for _, param := range prefixedQueryParams(event, "ep.", "epn.", interfaceID) {
+		entry, ok := param.(map[string]any)
+		if !ok {
+			continue
+		}
+		name, ok := entry["name"].(string)
+		if !ok {
+			continue
+		}
+		if value, ok := entry[valueKey]; ok && value != nil {
+			paramsMap[name] = value
+		}
+	}
+	return paramsMap
+}
+
+type paramsMapColumnsRegistry struct {
+	psr properties.SettingsRegistry
+}
+
+// NewParamsMapColumnsRegistry creates a columns registry returning the params_map and
+// params_number_map columns for GA4 properties whose params mode writes it, and no columns for other properties.
+func NewParamsMapColumnsRegistry(psr properties.SettingsRegistry) schema.ColumnsRegistry {
+	return &paramsMapColumnsRegistry{psr: psr}
+}
+
+func (r *paramsMapColumnsRegistry) Get(propertyID string) (schema.Columns, error) {
+	settings, err := r.psr.GetByPropertyID(propertyID)
+	if err != nil {
+		return schema.Columns{}, fmt.Errorf("get settings for property %q: %w", propertyID, err)
+	}
+	if settings.ProtocolID != (&ga4Protocol{}).ID() || !settings.GA4ParamsMode.WritesParamsMap() {
+		return schema.Columns{}, nil
+	}
+	return schema.Columns{Event: []schema.EventColumn{eventParamsMapColumn, eventParamsNumberMapColumn}}, nil
+}
@@ -0,0 +1,93 @@
+package ga4
+
+import (
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/columns/columntests"
+	"github.com/d8a-tech/d8a/pkg/columnset"
+	"github.com/d8a-tech/d8a/pkg/currency"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func withParamsMode(protocolID string, mode properties.GA4ParamsMode) properties.TestSettingsOption {
+	return func(s *properties.Settings) {
+		s.ProtocolID = protocolID
+		s.GA4ParamsMode = mode
+	}
+}
+
+func TestParamsMapColumn(t *testing.T) {
+	// given
+	psr := properties.NewTestSettingRegistry(withParamsMode("ga4", properties.GA4ParamsModeMap))
+	theProtocol := NewGA4Protocol(currency.NewDummyConverter(2), psr)
+
+	columntests.ColumnTestCase(
+		t,
+		columntests.TestHits{columntests.TestHitOne()},
+		func(t *testing.T, closeErr error, whd *warehouse.MockWarehouseDriver) {
+			// when + then
+			require.NoError(t, closeErr)
+			require.Len(t, whd.WriteCalls, 1)
+			paramsMap, ok := whd.WriteCalls[0].Records[0]["params_map"].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, "EUR", paramsMap["currency"])
+			assert.Equal(t, "00123", paramsMap["zip"], "string params keep their value as sent")
+			assert.Equal(t, "seven", paramsMap["both"])
+			assert.NotContains(t, paramsMap, "score")
+			paramsNumberMap, ok := whd.WriteCalls[0].Records[0]["params_number_map"].(map[string]any)
+			require.True(t, ok)
+			assert.Equal(t, float64(42), paramsNumberMap["score"])
+			assert.Equal(t, 0.25, paramsNumberMap["ratio"])
+			assert.Equal(t, float64(7), paramsNumberMap["both"])
+			assert.NotContains(t, paramsNumberMap, "currency")
+		},
+		theProtocol,
+		columntests.SetColumnsRegistry(columnset.DefaultColumnRegistry(
+			theProtocol,
+			psr,
+			columnset.WithCustomColumnsRegistry(NewParamsMapColumnsRegistry(psr)),
+		)),
+		columntests.EnsureQueryParam(0, "ep.currency", "EUR"),
+		columntests.EnsureQueryParam(0, "ep.zip", "00123"),
+		columntests.EnsureQueryParam(0, "epn.score", "42"),
+		columntests.EnsureQueryParam(0, "epn.ratio", "0.25"),
+		columntests.EnsureQueryParam(0, "ep.both", "seven"),
+		columntests.EnsureQueryParam(0, "epn.both", "7"),
+	)
+}
+
+func TestParamsMapColumnsRegistry(t *testing.T) {
+	testCases := []struct {
+		name        string
+		protocolID  string
+		mode        properties.GA4ParamsMode
+		wantColumns int
+	}{
+		{name: "map mode", protocolID: "ga4", mode: properties.GA4ParamsModeMap, wantColumns: 2},
+		{name: "both mode", protocolID: "ga4", mode: properties.GA4ParamsModeBoth, wantColumns: 2},
+		{name: "columns mode", protocolID: "ga4", mode: properties.GA4ParamsModeColumns, wantColumns: 0},
+		{name: "default mode", protocolID: "ga4", mode: "", wantColumns: 0},
+		{name: "other protocol", protocolID: "matomo", mode: properties.GA4ParamsModeMap, wantColumns: 0},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			registry := NewParamsMapColumnsRegistry(
+				properties.NewTestSettingRegistry(withParamsMode(tc.protocolID, tc.mode)),
+			)
+
+			// when
+			columns, err := registry.Get("1234567890")
+
+			// then
+			require.NoError(t, err)
+			assert.Len(t, columns.Event, tc.wantColumns)
+			assert.Empty(t, columns.Session)
+			assert.Empty(t, columns.SessionScopedEvent)
+		})
+	}
+}
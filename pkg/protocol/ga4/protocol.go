@@ -4,6 +4,7 @@ import (
 	_ "embed"
 	"errors"
 	"net/url"
+	"strconv"
 	"strings"
 
 	"github.com/d8a-tech/d8a/pkg/currency"
@@ -19,6 +20,7 @@ type ga4Protocol struct {
 	psr       properties.SettingsRegistry
 
 	propertyIDExtractor protocol.PropertyIDExtractor
+	cors                properties.CORSSettings
 }
 
 func (p *ga4Protocol) ID() string {
@@ -26,17 +28,17 @@ func (p *ga4Protocol) ID() string {
 }
 
 func (p *ga4Protocol) Hits(reqCtx *fasthttp.RequestCtx, request *hits.ParsedRequest) ([]*hits.Hit, error) {
-	origin := string(reqCtx.Request.Header.Peek("Origin"))
-	if origin != "" {
-		reqCtx.Response.Header.Set("Access-Control-Allow-Origin", origin)
-		reqCtx.Response.Header.Set("Access-Control-Allow-Credentials", "true")
-		reqCtx.Response.Header.Set("Vary", "Origin")
-	} else {
-		reqCtx.Response.Header.Set("Access-Control-Allow-Origin", "*")
+	if request.Path == MeasurementProtocolPath {
+		return p.measurementProtocolHits(&protocol.RequestContext{
+			FastHttp: reqCtx,
+			Parsed:   request,
+		})
 	}
-	reqCtx.Response.Header.Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
-	reqCtx.Response.Header.Set("Access-Control-Allow-Headers", "Content-Type, X-Requested-With")
-	reqCtx.Response.Header.Set("Access-Control-Max-Age", "86400")
+
+	protocol.SetCORSHeaders(reqCtx, p.corsSettings(&protocol.RequestContext{
+		FastHttp: reqCtx,
+		Parsed:   request,
+	}), corsMethods)
 
 	// Parse body into lines (each line represents a hit)
 	bodyStr := strings.TrimSpace(string(request.Body))
@@ -222,6 +224,35 @@ func (p *ga4Protocol) EventName(request *hits.ParsedRequest) (string, error) {
 	return eventName, nil
 }
 
+// PageLocation implements protocol.PageLocationProvider.
+func (p *ga4Protocol) PageLocation(hit *hits.Hit) string {
+	return hit.MustParsedRequest().QueryParams.Get("dl")
+}
+
+// SetPageLocation implements protocol.PageLocationProvider.
+func (p *ga4Protocol) SetPageLocation(hit *hits.Hit, location string) {
+	hit.MustParsedRequest().QueryParams.Set("dl", location)
+}
+
+// EventParams implements protocol.EventParamsProvider, with the ep. params as strings and
+// the epn. ones as numbers.
+func (p *ga4Protocol) EventParams(hit *hits.Hit) map[string]any {
+	queryParams := hit.MustParsedRequest().QueryParams
+	params := protocol.PrefixedParams(queryParams, "ep.")
+	for key, values := range queryParams {
+		name, ok := strings.CutPrefix(key, "epn.")
+		if !ok || name == "" || len(values) == 0 {
+			continue
+		}
+		if number, err := strconv.ParseFloat(values[0], 64); err == nil {
+			params[name] = number
+			continue
+		}
+		params[name] = values[0]
+	}
+	return params
+}
+
 func (p *ga4Protocol) Interfaces() any {
 	return ProtocolInterfaces
 }
@@ -232,6 +263,18 @@ var staticDuplicatorJS []byte
 //go:embed static/gd.min.js.map
 var staticDuplicatorJSMap []byte
 
+const corsMethods = "POST, GET, OPTIONS"
+
+// corsSettings returns the CORS settings of the property the request is for.
+func (p *ga4Protocol) corsSettings(ctx *protocol.RequestContext) properties.CORSSettings {
+	return protocol.CORSSettingsFor(ctx, p.propertyIDExtractor, p.psr, p.cors)
+}
+
+// DefaultCORSSettings implements protocol.CORSSettingsProvider.
+func (p *ga4Protocol) DefaultCORSSettings() properties.CORSSettings {
+	return p.cors
+}
+
 func (p *ga4Protocol) Endpoints() []protocol.ProtocolEndpoint {
 	return []protocol.ProtocolEndpoint{
 		{
@@ -243,26 +286,16 @@ func (p *ga4Protocol) Endpoints() []protocol.ProtocolEndpoint {
 			Path:     "/g/collect",
 			IsCustom: true,
 			CustomHandler: func(ctx *fasthttp.RequestCtx) {
-				origin := string(ctx.Request.Header.Peek("Origin"))
-				if origin != "" {
-					ctx.Response.Header.Set("Access-Control-Allow-Origin", origin)
-					ctx.Response.Header.Set("Access-Control-Allow-Credentials", "true")
-					ctx.Response.Header.Set("Vary", "Origin")
-				} else {
-					ctx.Response.Header.Set("Access-Control-Allow-Origin", "*")
-				}
-				ctx.Response.Header.Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS")
-				if acrh := string(ctx.Request.Header.Peek("Access-Control-Request-Headers")); acrh != "" {
-					ctx.Response.Header.Set("Access-Control-Allow-Headers", acrh)
-				} else {
-					ctx.Response.Header.Set("Access-Control-Allow-Headers", "Content-Type, X-Requested-With")
-				}
-				ctx.Response.Header.Set("Access-Control-Max-Age", "86400")
+				protocol.SetCORSHeaders(ctx, p.corsSettings(&protocol.RequestContext{FastHttp: ctx}), corsMethods)
 				ctx.SetStatusCode(fasthttp.StatusNoContent)
 			},
 		},
-		StaticCORSEndpoint("/g/gd.min.js", "text/javascript", staticDuplicatorJS),
-		StaticCORSEndpoint("/g/gd.min.js.map", "application/json", staticDuplicatorJSMap),
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Path:    MeasurementProtocolPath,
+		},
+		StaticCORSEndpoint("/g/gd.min.js", "text/javascript", staticDuplicatorJS, p.cors),
+		StaticCORSEndpoint("/g/gd.min.js.map", "application/json", staticDuplicatorJSMap, p.cors),
 	}
 }
 
@@ -451,6 +484,14 @@ func WithPropertyIDExtractor(extractor protocol.PropertyIDExtractor) GA4Protocol
 	}
 }
 
+// WithDefaultCORSSettings sets the CORS settings of requests whose property doesn't have
+// any, or can't be told. protocol.DefaultCORSSettings are used otherwise.
+func WithDefaultCORSSettings(cors properties.CORSSettings) GA4ProtocolOption {
+	return func(p *ga4Protocol) {
+		p.cors = cors
+	}
+}
+
 type fromTidByMeasurementIDExtractor struct {
 	psr properties.SettingsRegistry
 }
@@ -481,6 +522,7 @@ func NewGA4Protocol(
 		converter:           converter,
 		psr:                 psr,
 		propertyIDExtractor: NewFromTidByMeasurementIDExtractor(psr),
+		cors:                protocol.DefaultCORSSettings,
 	}
 	for _, opt := range opts {
 		opt(p)
@@ -129,3 +129,29 @@ func TestHits(t *testing.T) {
 		})
 	}
 }
+
+func TestEventParams(t *testing.T) {
+	// given
+	p := &ga4Protocol{}
+	hit := hits.New()
+	hit.Request.QueryParams = url.Values{
+		"en":             {"purchase"},
+		"ep.coupon":      {"SUMMER"},
+		"epn.value":      {"12.5"},
+		"epn.quantity":   {"many"},
+		"dl":             {"https://shop.example.com/"},
+		"ep.":            {"ignored"},
+		"ep.transaction": {"T-1"},
+	}
+
+	// when
+	params := p.EventParams(hit)
+
+	// then
+	assert.Equal(t, map[string]any{
+		"coupon":      "SUMMER",
+		"value":       12.5,
+		"quantity":    "many",
+		"transaction": "T-1",
+	}, params)
+}
@@ -19,6 +19,7 @@ type matomoProtocol struct {
 	extractor              protocol.PropertyIDExtractor
 	psr                    properties.SettingsRegistry
 	extraTrackingEndpoints []string
+	cors                   properties.CORSSettings
 }
 
 func (p *matomoProtocol) ID() string {
@@ -26,6 +27,10 @@ func (p *matomoProtocol) ID() string {
 }
 
 func (p *matomoProtocol) Hits(fhCtx *fasthttp.RequestCtx, request *hits.ParsedRequest) ([]*hits.Hit, error) {
+	protocol.SetCORSHeaders(fhCtx, protocol.CORSSettingsFor(
+		&protocol.RequestContext{FastHttp: fhCtx, Parsed: request}, p.extractor, p.psr, p.cors,
+	), corsMethods)
+
 	body := bytes.TrimSpace(request.Body)
 	if len(body) > 0 && body[0] == '{' {
 		var payload struct {
@@ -59,22 +64,52 @@ func (p *matomoProtocol) Hits(fhCtx *fasthttp.RequestCtx, request *hits.ParsedRe
 	return []*hits.Hit{hit}, nil
 }
 
+const corsMethods = "POST, GET, OPTIONS"
+
+// DefaultCORSSettings implements protocol.CORSSettingsProvider.
+func (p *matomoProtocol) DefaultCORSSettings() properties.CORSSettings {
+	return p.cors
+}
+
 func (p *matomoProtocol) Endpoints() []protocol.ProtocolEndpoint {
 	paths := make([]string, 0, 1+len(p.extraTrackingEndpoints))
 	paths = append(paths, "/matomo.php")
 	paths = append(paths, p.extraTrackingEndpoints...)
 
-	endpoints := make([]protocol.ProtocolEndpoint, 0, len(paths))
+	endpoints := make([]protocol.ProtocolEndpoint, 0, 2*len(paths))
 	for _, trackingPath := range uniqueTrackingEndpoints(paths) {
-		endpoints = append(endpoints, protocol.ProtocolEndpoint{
-			Methods: []string{fasthttp.MethodPost, fasthttp.MethodGet},
-			Path:    trackingPath,
-		})
+		endpoints = append(endpoints,
+			protocol.ProtocolEndpoint{
+				Methods: []string{fasthttp.MethodPost, fasthttp.MethodGet},
+				Path:    trackingPath,
+			},
+			protocol.ProtocolEndpoint{
+				Methods:  []string{fasthttp.MethodOptions},
+				Path:     trackingPath,
+				IsCustom: true,
+				CustomHandler: func(ctx *fasthttp.RequestCtx) {
+					protocol.SetCORSHeaders(ctx, protocol.CORSSettingsFor(
+						&protocol.RequestContext{FastHttp: ctx}, p.extractor, p.psr, p.cors,
+					), corsMethods)
+					ctx.SetStatusCode(fasthttp.StatusNoContent)
+				},
+			},
+		)
 	}
 
 	return endpoints
 }
 
+// PageLocation implements protocol.PageLocationProvider.
+func (p *matomoProtocol) PageLocation(hit *hits.Hit) string {
+	return hit.MustParsedRequest().QueryParams.Get("url")
+}
+
+// SetPageLocation implements protocol.PageLocationProvider.
+func (p *matomoProtocol) SetPageLocation(hit *hits.Hit, location string) {
+	hit.MustParsedRequest().QueryParams.Set("url", location)
+}
+
 func (p *matomoProtocol) Interfaces() any {
 	return ProtocolInterfaces
 }
@@ -95,12 +130,20 @@ func WithExtraTrackingEndpoints(paths []string) MatomoProtocolOption {
 	}
 }
 
+// WithDefaultCORSSettings sets the CORS settings of requests whose property doesn't have
+// any, or can't be told. protocol.DefaultCORSSettings are used otherwise.
+func WithDefaultCORSSettings(cors properties.CORSSettings) MatomoProtocolOption {
+	return func(p *matomoProtocol) {
+		p.cors = cors
+	}
+}
+
 func NewMatomoProtocol(
 	extractor protocol.PropertyIDExtractor,
 	psr properties.SettingsRegistry,
 	opts ...MatomoProtocolOption,
 ) protocol.Protocol {
-	p := &matomoProtocol{extractor: extractor, psr: psr}
+	p := &matomoProtocol{extractor: extractor, psr: psr, cors: protocol.DefaultCORSSettings}
 	for _, opt := range opts {
 		opt(p)
 	}
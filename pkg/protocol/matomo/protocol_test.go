@@ -262,16 +262,36 @@ func TestHits(t *testing.T) {
 	}
 }
 
+// endpointsWithoutHandlers returns the paths and methods of the endpoints, custom handlers
+// can't be compared.
+func endpointsWithoutHandlers(endpoints []protocol.ProtocolEndpoint) []protocol.ProtocolEndpoint {
+	stripped := make([]protocol.ProtocolEndpoint, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		endpoint.CustomHandler = nil
+		stripped = append(stripped, endpoint)
+	}
+	return stripped
+}
+
+func trackingEndpoints(path string) []protocol.ProtocolEndpoint {
+	return []protocol.ProtocolEndpoint{
+		{
+			Methods: []string{fasthttp.MethodPost, fasthttp.MethodGet},
+			Path:    path,
+		},
+		{
+			Methods:  []string{fasthttp.MethodOptions},
+			Path:     path,
+			IsCustom: true,
+		},
+	}
+}
+
 func TestEndpoints(t *testing.T) {
 	t.Run("default endpoint only", func(t *testing.T) {
 		proto := NewMatomoProtocol(&testPropertyIDExtractor{}, testSettingsRegistry())
 
-		assert.Equal(t, []protocol.ProtocolEndpoint{
-			{
-				Methods: []string{fasthttp.MethodPost, fasthttp.MethodGet},
-				Path:    "/matomo.php",
-			},
-		}, proto.Endpoints())
+		assert.Equal(t, trackingEndpoints("/matomo.php"), endpointsWithoutHandlers(proto.Endpoints()))
 	})
 
 	t.Run("adds normalized extra endpoints", func(t *testing.T) {
@@ -281,19 +301,9 @@ func TestEndpoints(t *testing.T) {
 			WithExtraTrackingEndpoints([]string{"piwik.php", "/track", " ", "/matomo.php", "track"}),
 		)
 
-		assert.Equal(t, []protocol.ProtocolEndpoint{
-			{
-				Methods: []string{fasthttp.MethodPost, fasthttp.MethodGet},
-				Path:    "/matomo.php",
-			},
-			{
-				Methods: []string{fasthttp.MethodPost, fasthttp.MethodGet},
-				Path:    "/piwik.php",
-			},
-			{
-				Methods: []string{fasthttp.MethodPost, fasthttp.MethodGet},
-				Path:    "/track",
-			},
-		}, proto.Endpoints())
+		expected := trackingEndpoints("/matomo.php")
+		expected = append(expected, trackingEndpoints("/piwik.php")...)
+		expected = append(expected, trackingEndpoints("/track")...)
+		assert.Equal(t, expected, endpointsWithoutHandlers(proto.Endpoints()))
 	})
 }
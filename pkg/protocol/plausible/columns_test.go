@@ -0,0 +1,114 @@
+package plausible
+
+import (
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/columns/columntests"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nolint:funlen,lll // test code
+func TestPlausibleEventColumns(t *testing.T) {
+	const pageviewBody = `{"name":"pageview","url":"https://example.com/blog?ref=abc","domain":"example.com","referrer":"https://google.com/","props":{"author":"Jane","words":1200}}`
+	const purchaseBody = `{"n":"Purchase","u":"https://example.com/checkout","d":"example.com","i":false,"$":"{\"amount\":19.9,\"currency\":\"EUR\"}"}`
+	const outboundBody = `{"name":"Outbound Link: Click","url":"https://example.com/","domain":"example.com","props":{"url":"https://github.com/"}}`
+
+	testCases := []struct {
+		name        string
+		body        string
+		settingsOpt []properties.TestSettingsOption
+		fieldName   string
+		expected    any
+	}{
+		{name: "DateUTC", body: pageviewBody, fieldName: "date_utc", expected: "2025-03-04"},
+		{name: "TimestampUTC", body: pageviewBody, fieldName: "timestamp_utc", expected: "2025-03-04T05:06:07Z"},
+		{name: "PageLocation", body: pageviewBody, fieldName: "page_location", expected: "https://example.com/blog?ref=abc"},
+		{
+			name:        "PageLocation_ExcludedParams",
+			body:        pageviewBody,
+			settingsOpt: []properties.TestSettingsOption{properties.WithExcludedURLParams([]string{"ref"})},
+			fieldName:   "page_location",
+			expected:    "https://example.com/blog",
+		},
+		{name: "PageHostname", body: pageviewBody, fieldName: "page_hostname", expected: "example.com"},
+		{name: "PagePath", body: pageviewBody, fieldName: "page_path", expected: "/blog"},
+		{name: "PageReferrer", body: pageviewBody, fieldName: "page_referrer", expected: "https://google.com/"},
+		{name: "TrackingProtocol", body: pageviewBody, fieldName: "tracking_protocol", expected: "plausible"},
+		{name: "Platform", body: pageviewBody, fieldName: "platform", expected: "web"},
+		{name: "DeviceLanguage", body: pageviewBody, fieldName: "device_language", expected: "pl-PL,pl;q=0.9"},
+		{name: "Interactive_Default", body: pageviewBody, fieldName: "params_interactive", expected: true},
+		{name: "Interactive_False", body: purchaseBody, fieldName: "params_interactive", expected: false},
+		{name: "RevenueAmount", body: purchaseBody, fieldName: "params_revenue_amount", expected: 19.9},
+		{name: "RevenueAmount_Missing", body: pageviewBody, fieldName: "params_revenue_amount", expected: nil},
+		{name: "RevenueCurrency", body: purchaseBody, fieldName: "params_revenue_currency", expected: "EUR"},
+		{name: "LinkURL", body: outboundBody, fieldName: "params_link_url", expected: "https://github.com/"},
+		{name: "LinkURL_OnlyForLinkEvents", body: pageviewBody, fieldName: "params_link_url", expected: nil},
+		{
+			name:      "Props",
+			body:      pageviewBody,
+			fieldName: "props",
+			expected: []any{
+				map[string]any{"name": "author", "value": "Jane"},
+				map[string]any{"name": "words", "value": "1200"},
+			},
+		},
+		{name: "Props_Empty", body: purchaseBody, fieldName: "props", expected: []any{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			proto := NewPlausibleProtocol(
+				&staticPropertyIDExtractor{propertyID: "test_property_id"},
+				testSettingsRegistry(tc.settingsOpt...),
+			)
+
+			columntests.ColumnTestCase(
+				t,
+				columntests.TestHits{testHit(t, tc.body)},
+				func(t *testing.T, closeErr error, whd *warehouse.MockWarehouseDriver) {
+					require.NoError(t, closeErr)
+					require.NotEmpty(t, whd.WriteCalls, "expected at least one warehouse write call")
+					require.NotEmpty(t, whd.WriteCalls[0].Records, "expected at least one record written")
+					assert.Equal(t, tc.expected, whd.WriteCalls[0].Records[0][tc.fieldName])
+				},
+				proto,
+			)
+		})
+	}
+}
+
+// nolint:lll // test code
+func TestPlausibleSessionColumns(t *testing.T) {
+	// given
+	bodies := []string{
+		`{"name":"pageview","url":"https://example.com/","domain":"example.com"}`,
+		`{"name":"Outbound Link: Click","url":"https://example.com/","domain":"example.com","props":{"url":"https://github.com/"}}`,
+		`{"name":"Outbound Link: Click","url":"https://example.com/","domain":"example.com","props":{"url":"https://github.com/"}}`,
+		`{"name":"File Download","url":"https://example.com/","domain":"example.com","props":{"url":"https://example.com/a.pdf"}}`,
+		`{"name":"Purchase","url":"https://example.com/","domain":"example.com","revenue":{"amount":10,"currency":"USD"}}`,
+	}
+	testHits := make(columntests.TestHits, 0, len(bodies))
+	for _, body := range bodies {
+		testHits = append(testHits, testHit(t, body))
+	}
+	proto := NewPlausibleProtocol(&staticPropertyIDExtractor{propertyID: "test_property_id"}, testSettingsRegistry())
+
+	columntests.ColumnTestCase(
+		t,
+		testHits,
+		func(t *testing.T, closeErr error, whd *warehouse.MockWarehouseDriver) {
+			// when + then
+			require.NoError(t, closeErr)
+			record := whd.WriteCalls[0].Records[0]
+			assert.Equal(t, 2, record["session_total_outbound_clicks"])
+			assert.Equal(t, 1, record["session_unique_outbound_clicks"])
+			assert.Equal(t, 1, record["session_total_file_downloads"])
+			assert.Equal(t, 1, record["session_total_purchases"])
+			assert.Nil(t, record["session_total_scrolls"])
+		},
+		proto,
+	)
+}
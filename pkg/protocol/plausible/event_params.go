@@ -0,0 +1,89 @@
+package plausible
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+var eventParamsInteractiveColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventParamsInteractive.ID,
+	ProtocolInterfaces.EventParamsInteractive.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		// Events are interactive unless the tracker says otherwise
+		return event.BoundHit.MustParsedRequest().QueryParams.Get(interactiveParam) != "false", nil
+	},
+	columns.WithEventColumnDocs(
+		"Interactive",
+		"Whether the event is interactive, extracted from the interactive field. Non-interactive events don't affect the bounce rate in Plausible. Defaults to true.", // nolint:lll // it's a description
+	),
+)
+
+var eventParamsRevenueAmountColumn = columns.FromQueryParamEventColumn(
+	ProtocolInterfaces.EventParamsRevenueAmount.ID,
+	ProtocolInterfaces.EventParamsRevenueAmount.Field,
+	revenueAmountParam,
+	columns.WithEventColumnCast(columns.CastToFloat64OrNil(ProtocolInterfaces.EventParamsRevenueAmount.ID)),
+	columns.WithEventColumnDocs(
+		"Revenue Amount",
+		"The revenue of the event in its original currency, extracted from revenue.amount.",
+	),
+)
+
+var eventParamsRevenueCurrencyColumn = columns.FromQueryParamEventColumn(
+	ProtocolInterfaces.EventParamsRevenueCurrency.ID,
+	ProtocolInterfaces.EventParamsRevenueCurrency.Field,
+	revenueCurrencyParam,
+	columns.WithEventColumnCast(
+		columns.StrNilIfErrorOrEmpty(columns.CastToString(ProtocolInterfaces.EventParamsRevenueCurrency.ID)),
+	),
+	columns.WithEventColumnDocs(
+		"Revenue Currency",
+		"The ISO 4217 currency code of the revenue (e.g., 'USD', 'EUR'), extracted from revenue.currency.",
+	),
+)
+
+var eventParamsLinkURLColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventParamsLinkURL.ID,
+	ProtocolInterfaces.EventParamsLinkURL.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		if event.BoundHit.EventName != outboundLinkClickEventName && event.BoundHit.EventName != fileDownloadEventName {
+			return nil, nil //nolint:nilnil // only set for link events
+		}
+		linkURL := event.BoundHit.MustParsedRequest().QueryParams.Get(propsParamPrefix + "url")
+		if linkURL == "" {
+			return nil, nil //nolint:nilnil // link not present
+		}
+		return linkURL, nil
+	},
+	columns.WithEventColumnDocs(
+		"Link URL",
+		"The URL of the clicked link or downloaded file, extracted from props.url of outbound link click and file download events.", // nolint:lll // it's a description
+	),
+)
+
+var eventPropsColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventProps.ID,
+	ProtocolInterfaces.EventProps.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		params := event.BoundHit.MustParsedRequest().QueryParams
+		names := make([]string, 0)
+		for key := range params {
+			if name, ok := strings.CutPrefix(key, propsParamPrefix); ok {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+		props := make([]any, 0, len(names))
+		for _, name := range names {
+			props = append(props, map[string]any{"name": name, "value": params.Get(propsParamPrefix + name)})
+		}
+		return props, nil
+	},
+	columns.WithEventColumnDocs(
+		"Props",
+		"The custom properties of the event, extracted from the props field. Values are always written as strings, the same way Plausible stores them.", // nolint:lll // it's a description
+	),
+)
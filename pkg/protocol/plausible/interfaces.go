@@ -0,0 +1,43 @@
+package plausible
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// ProtocolInterfaces are the columns specific to the Plausible protocol.
+var ProtocolInterfaces = struct {
+	EventParamsInteractive     schema.Interface
+	EventParamsRevenueAmount   schema.Interface
+	EventParamsRevenueCurrency schema.Interface
+	EventParamsLinkURL         schema.Interface
+	EventProps                 schema.Interface
+}{
+	EventParamsInteractive: schema.Interface{
+		ID:    "plausible.protocols.d8a.tech/event/params_interactive",
+		Field: &arrow.Field{Name: "params_interactive", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
+	},
+	EventParamsRevenueAmount: schema.Interface{
+		ID:    "plausible.protocols.d8a.tech/event/params_revenue_amount",
+		Field: &arrow.Field{Name: "params_revenue_amount", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	},
+	EventParamsRevenueCurrency: schema.Interface{
+		ID:    "plausible.protocols.d8a.tech/event/params_revenue_currency",
+		Field: &arrow.Field{Name: "params_revenue_currency", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsLinkURL: schema.Interface{
+		ID:    "plausible.protocols.d8a.tech/event/params_link_url",
+		Field: &arrow.Field{Name: "params_link_url", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventProps: schema.Interface{
+		ID: "plausible.protocols.d8a.tech/event/props",
+		Field: &arrow.Field{
+			Name: "props",
+			Type: arrow.ListOf(arrow.StructOf(
+				arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "value", Type: arrow.BinaryTypes.String, Nullable: true},
+			)),
+			Nullable: true,
+		},
+	},
+}
@@ -0,0 +1,323 @@
+// Package plausible implements the Plausible Events API tracking protocol, as sent by
+// the Plausible tracking script.
+package plausible
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/schema"
+	"github.com/valyala/fasthttp"
+)
+
+// pageviewEventName is the name Plausible uses for page views.
+const pageviewEventName = "pageview"
+
+// Keys of the normalized event, as stored in the query params of a hit.
+const (
+	nameParam            = "name"
+	urlParam             = "url"
+	domainParam          = "domain"
+	referrerParam        = "referrer"
+	interactiveParam     = "interactive"
+	propsParamPrefix     = "props."
+	revenueAmountParam   = "revenue.amount"
+	revenueCurrencyParam = "revenue.currency"
+)
+
+// shortKeys maps the one-letter keys sent by the tracking script to the keys of the
+// Events API.
+var shortKeys = map[string]string{
+	"n": nameParam,
+	"u": urlParam,
+	"d": domainParam,
+	"r": referrerParam,
+	"i": interactiveParam,
+	"p": "props",
+	"m": "props",
+	"$": "revenue",
+}
+
+type plausibleProtocol struct {
+	extractor protocol.PropertyIDExtractor
+	psr       properties.SettingsRegistry
+	cors      properties.CORSSettings
+}
+
+func (p *plausibleProtocol) ID() string {
+	return "plausible"
+}
+
+// Hits turns the JSON body of a request into hits. The event is normalized into the
+// query params of the hit (props and revenue fields joined with dots, e.g. props.author),
+// so columns can read it like any other tracking parameter. A comma-separated domain
+// creates one hit per domain, the same way Plausible records an event for every site.
+func (p *plausibleProtocol) Hits(fhCtx *fasthttp.RequestCtx, request *hits.ParsedRequest) ([]*hits.Hit, error) {
+	params, err := normalizeEvent(request.Body)
+	p.setCORSHeaders(fhCtx, params)
+	if err != nil {
+		return nil, err
+	}
+
+	domains := strings.Split(params.Get(domainParam), ",")
+	theHits := make([]*hits.Hit, 0, len(domains))
+	for _, domain := range domains {
+		domain = strings.TrimSpace(domain)
+		if domain == "" {
+			continue
+		}
+		hit, err := p.createHit(fhCtx, request, params, domain)
+		if err != nil {
+			return nil, err
+		}
+		theHits = append(theHits, hit)
+	}
+	if len(theHits) == 0 {
+		return nil, errors.New("domain is required")
+	}
+	return theHits, nil
+}
+
+func (p *plausibleProtocol) createHit(
+	fhCtx *fasthttp.RequestCtx,
+	request *hits.ParsedRequest,
+	params url.Values,
+	domain string,
+) (*hits.Hit, error) {
+	requestCopy := request.Clone()
+	requestCopy.QueryParams = url.Values{}
+	for key, values := range params {
+		requestCopy.QueryParams[key] = append([]string(nil), values...)
+	}
+	requestCopy.QueryParams.Set(domainParam, domain)
+
+	propertyID, err := p.extractor.PropertyID(&protocol.RequestContext{
+		Parsed:   requestCopy,
+		FastHttp: fhCtx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	eventName := params.Get(nameParam)
+	if eventName == pageviewEventName {
+		eventName = protocol.PageViewEventType
+	}
+
+	// The random client ID of the new hit is a placeholder, the receiver derives the
+	// cookieless one from a secret daily salt
+	hit := hits.New()
+	hit.PropertyID = propertyID
+	hit.EventName = eventName
+	hit.Request = requestCopy
+
+	return hit, nil
+}
+
+// CookielessClientIDs implements protocol.CookielessProtocol, Plausible doesn't use cookies.
+func (p *plausibleProtocol) CookielessClientIDs() {}
+
+const corsMethods = "POST, OPTIONS"
+
+// setCORSHeaders sets the CORS headers of the property of the first domain of the event,
+// or the default ones if the property can't be told.
+func (p *plausibleProtocol) setCORSHeaders(fhCtx *fasthttp.RequestCtx, params url.Values) {
+	domain, _, _ := strings.Cut(params.Get(domainParam), ",")
+	corsParams := url.Values{domainParam: {strings.TrimSpace(domain)}}
+	protocol.SetCORSHeaders(fhCtx, protocol.CORSSettingsFor(
+		&protocol.RequestContext{FastHttp: fhCtx, Parsed: &hits.ParsedRequest{QueryParams: corsParams}},
+		p.extractor, p.psr, p.cors,
+	), corsMethods)
+}
+
+// DefaultCORSSettings implements protocol.CORSSettingsProvider. They are also the CORS
+// settings of preflights, which have no domain.
+func (p *plausibleProtocol) DefaultCORSSettings() properties.CORSSettings {
+	return p.cors
+}
+
+func (p *plausibleProtocol) Endpoints() []protocol.ProtocolEndpoint {
+	return []protocol.ProtocolEndpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Path:    "/api/event",
+		},
+		{
+			Methods:  []string{fasthttp.MethodOptions},
+			Path:     "/api/event",
+			IsCustom: true,
+			CustomHandler: func(ctx *fasthttp.RequestCtx) {
+				protocol.SetCORSHeaders(ctx, p.cors, corsMethods)
+				ctx.SetStatusCode(fasthttp.StatusNoContent)
+			},
+		},
+	}
+}
+
+// PageLocation implements protocol.PageLocationProvider.
+func (p *plausibleProtocol) PageLocation(hit *hits.Hit) string {
+	return hit.MustParsedRequest().QueryParams.Get(urlParam)
+}
+
+// SetPageLocation implements protocol.PageLocationProvider.
+func (p *plausibleProtocol) SetPageLocation(hit *hits.Hit, location string) {
+	hit.MustParsedRequest().QueryParams.Set(urlParam, location)
+}
+
+// EventParams implements protocol.EventParamsProvider, with the custom props of the event.
+func (p *plausibleProtocol) EventParams(hit *hits.Hit) map[string]any {
+	return protocol.PrefixedParams(hit.MustParsedRequest().QueryParams, propsParamPrefix)
+}
+
+func (p *plausibleProtocol) Interfaces() any {
+	return ProtocolInterfaces
+}
+
+func (p *plausibleProtocol) Columns() schema.Columns {
+	return schema.Columns{
+		Event:              eventColumns(newEventPageLocationColumn(p.psr)),
+		Session:            sessionColumns,
+		SessionScopedEvent: sseColumns,
+	}
+}
+
+// PlausibleProtocolOption configures the Plausible protocol.
+type PlausibleProtocolOption func(*plausibleProtocol)
+
+// WithDefaultCORSSettings sets the CORS settings of requests whose property doesn't have
+// any, or can't be told. protocol.DefaultCORSSettings are used otherwise.
+func WithDefaultCORSSettings(cors properties.CORSSettings) PlausibleProtocolOption {
+	return func(p *plausibleProtocol) {
+		p.cors = cors
+	}
+}
+
+// NewPlausibleProtocol creates a protocol accepting events of the Plausible Events API.
+func NewPlausibleProtocol(
+	extractor protocol.PropertyIDExtractor,
+	psr properties.SettingsRegistry,
+	opts ...PlausibleProtocolOption,
+) protocol.Protocol {
+	p := &plausibleProtocol{extractor: extractor, psr: psr, cors: protocol.DefaultCORSSettings}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type fromDomainExtractor struct {
+	psr properties.SettingsRegistry
+}
+
+func (e *fromDomainExtractor) PropertyID(ctx *protocol.RequestContext) (string, error) {
+	domain := ctx.Parsed.QueryParams.Get(domainParam)
+	if domain == "" {
+		return "", errors.New("missing domain")
+	}
+	property, err := e.psr.GetByMeasurementID(domain)
+	if err != nil {
+		return "", err
+	}
+	return property.PropertyID, nil
+}
+
+// NewFromDomainExtractor creates a PropertyIDExtractor that resolves the property using
+// the domain of the event as the measurement ID.
+func NewFromDomainExtractor(psr properties.SettingsRegistry) protocol.PropertyIDExtractor {
+	return &fromDomainExtractor{psr: psr}
+}
+
+// normalizeEvent decodes the body of an Events API request, accepting both the long keys
+// of the API and the one-letter keys of the tracking script.
+func normalizeEvent(body []byte) (url.Values, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var event map[string]any
+	if err := decoder.Decode(&event); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if event == nil {
+		return nil, errors.New("expected a JSON object")
+	}
+	for short, long := range shortKeys {
+		if value, ok := event[short]; ok {
+			if _, exists := event[long]; !exists {
+				event[long] = value
+			}
+		}
+	}
+	if _, ok := event["props"]; !ok {
+		event["props"] = event["meta"]
+	}
+
+	params := url.Values{}
+	for _, key := range []string{nameParam, urlParam, domainParam, referrerParam, interactiveParam} {
+		if value, ok := scalarToString(event[key]); ok {
+			params.Set(key, value)
+		}
+	}
+	if strings.TrimSpace(params.Get(nameParam)) == "" {
+		return nil, errors.New("name is required")
+	}
+	if params.Get(urlParam) == "" {
+		return nil, errors.New("url is required")
+	}
+
+	for key, value := range decodeNestedObject(event["props"]) {
+		if str, ok := scalarToString(value); ok {
+			params.Set(propsParamPrefix+key, str)
+		}
+	}
+	if revenue := decodeNestedObject(event["revenue"]); revenue != nil {
+		amount, _ := scalarToString(revenue["amount"])
+		currency, _ := scalarToString(revenue["currency"])
+		if _, err := strconv.ParseFloat(amount, 64); err != nil || currency == "" {
+			return nil, errors.New("revenue requires a numeric amount and a currency")
+		}
+		params.Set(revenueAmountParam, amount)
+		params.Set(revenueCurrencyParam, strings.ToUpper(currency))
+	}
+	return params, nil
+}
+
+// decodeNestedObject returns the value as an object. Older versions of the tracking script
+// send props and revenue as JSON encoded strings, those are decoded too.
+func decodeNestedObject(value any) map[string]any {
+	switch v := value.(type) {
+	case map[string]any:
+		return v
+	case string:
+		decoder := json.NewDecoder(strings.NewReader(v))
+		decoder.UseNumber()
+		var object map[string]any
+		if err := decoder.Decode(&object); err != nil {
+			return nil
+		}
+		return object
+	default:
+		return nil
+	}
+}
+
+// scalarToString converts a JSON scalar to a string. Nulls, objects and arrays are not
+// accepted as values by Plausible, so they are skipped.
+func scalarToString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		return "", false
+	}
+}
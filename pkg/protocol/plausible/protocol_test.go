@@ -0,0 +1,156 @@
+package plausible
+
+import (
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// nolint:funlen,lll // test code
+func TestHits(t *testing.T) {
+	testCases := []struct {
+		name               string
+		body               string
+		expectedEventNames []string
+		expectedParams     map[string]string
+		expectError        bool
+	}{
+		{
+			name:               "pageview",
+			body:               `{"name":"pageview","url":"https://example.com/blog","domain":"example.com","referrer":"https://google.com/"}`,
+			expectedEventNames: []string{protocol.PageViewEventType},
+			expectedParams: map[string]string{
+				"url":      "https://example.com/blog",
+				"domain":   "example.com",
+				"referrer": "https://google.com/",
+			},
+		},
+		{
+			name:               "custom_event_with_props_and_revenue",
+			body:               `{"name":"Purchase","url":"https://example.com/","domain":"example.com","props":{"plan":"pro","seats":3,"trial":false},"revenue":{"currency":"eur","amount":"19.90"}}`,
+			expectedEventNames: []string{"Purchase"},
+			expectedParams: map[string]string{
+				"props.plan":       "pro",
+				"props.seats":      "3",
+				"props.trial":      "false",
+				"revenue.amount":   "19.90",
+				"revenue.currency": "EUR",
+			},
+		},
+		{
+			name:               "short_keys_of_the_tracking_script",
+			body:               `{"n":"pageview","u":"https://example.com/","d":"example.com","r":null,"p":"{\"author\":\"Jane\"}","i":false}`,
+			expectedEventNames: []string{protocol.PageViewEventType},
+			expectedParams: map[string]string{
+				"url":          "https://example.com/",
+				"domain":       "example.com",
+				"props.author": "Jane",
+				"interactive":  "false",
+			},
+		},
+		{
+			name:               "one_hit_per_domain",
+			body:               `{"name":"pageview","url":"https://example.com/","domain":"example.com, rollup.example.com"}`,
+			expectedEventNames: []string{protocol.PageViewEventType, protocol.PageViewEventType},
+		},
+		{
+			name:        "missing_name_returns_error",
+			body:        `{"url":"https://example.com/","domain":"example.com"}`,
+			expectError: true,
+		},
+		{
+			name:        "missing_url_returns_error",
+			body:        `{"name":"pageview","domain":"example.com"}`,
+			expectError: true,
+		},
+		{
+			name:        "missing_domain_returns_error",
+			body:        `{"name":"pageview","url":"https://example.com/"}`,
+			expectError: true,
+		},
+		{
+			name:        "invalid_revenue_returns_error",
+			body:        `{"name":"Purchase","url":"https://example.com/","domain":"example.com","revenue":{"amount":"a lot"}}`,
+			expectError: true,
+		},
+		{
+			name:        "invalid_json_returns_error",
+			body:        `{"name":`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			plausibleProtocol := NewPlausibleProtocol(
+				&staticPropertyIDExtractor{propertyID: "test_property_id"},
+				testSettingsRegistry(),
+			)
+
+			// when
+			hitsResult, err := plausibleProtocol.Hits(&fasthttp.RequestCtx{}, testRequest(tc.body))
+
+			// then
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, hitsResult, len(tc.expectedEventNames))
+			for index, hit := range hitsResult {
+				assert.Equal(t, tc.expectedEventNames[index], hit.EventName)
+				assert.NotEmpty(t, hit.ClientID)
+				assert.Equal(t, hit.ClientID, hit.AuthoritativeClientID)
+				assert.Equal(t, "test_property_id", hit.PropertyID)
+			}
+			for key, value := range tc.expectedParams {
+				assert.Equal(t, value, hitsResult[0].MustParsedRequest().QueryParams.Get(key), key)
+			}
+		})
+	}
+}
+
+func TestHits_ClientIDIsAPlaceholderForTheReceiver(t *testing.T) {
+	// given
+	const body = `{"name":"pageview","url":"https://example.com/","domain":"example.com"}`
+	plausibleProtocol := NewPlausibleProtocol(&staticPropertyIDExtractor{}, testSettingsRegistry())
+
+	// when
+	first, firstErr := plausibleProtocol.Hits(&fasthttp.RequestCtx{}, testRequest(body))
+	second, secondErr := plausibleProtocol.Hits(&fasthttp.RequestCtx{}, testRequest(body))
+
+	// then
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	assert.Implements(t, (*protocol.CookielessProtocol)(nil), plausibleProtocol)
+	assert.NotEqual(t, first[0].ClientID, second[0].ClientID, "the IP and user agent aren't hashed without a salt")
+}
+
+func TestFromDomainExtractor(t *testing.T) {
+	// given
+	psr := properties.NewStaticSettingsRegistry([]properties.Settings{
+		{PropertyID: "blog", PropertyMeasurementID: "blog.example.com"},
+	})
+	plausibleProtocol := NewPlausibleProtocol(NewFromDomainExtractor(psr), psr)
+
+	// when
+	known, knownErr := plausibleProtocol.Hits(
+		&fasthttp.RequestCtx{},
+		testRequest(`{"name":"pageview","url":"https://blog.example.com/","domain":"blog.example.com"}`),
+	)
+	_, unknownErr := plausibleProtocol.Hits(
+		&fasthttp.RequestCtx{},
+		testRequest(`{"name":"pageview","url":"https://other.example.com/","domain":"other.example.com"}`),
+	)
+
+	// then
+	require.NoError(t, knownErr)
+	require.Len(t, known, 1)
+	assert.Equal(t, "blog", known[0].PropertyID)
+	assert.Error(t, unknownErr)
+}
@@ -0,0 +1,157 @@
+//nolint:dupl,nilnil // unsupported columns share the same shape
+package plausible
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// Names of the events sent by the optional enhancements of the Plausible tracking script.
+const (
+	outboundLinkClickEventName = "Outbound Link: Click"
+	fileDownloadEventName      = "File Download"
+	formSubmissionEventName    = "Form: Submission"
+)
+
+// unsupportedSessionColumn creates a core session column, which the Plausible script has
+// no standard event for. It always writes null.
+func unsupportedSessionColumn(iface schema.Interface, title string) schema.SessionColumn {
+	return columns.NewSimpleSessionColumn(
+		iface.ID,
+		iface.Field,
+		func(_ *schema.Session) (any, schema.D8AColumnWriteError) {
+			return nil, nil
+		},
+		columns.WithSessionColumnDocs(
+			title,
+			"Not supported in the Plausible protocol. The tracking script has no standard event for it, so it is always null.", //nolint:lll // description
+		),
+	)
+}
+
+// sessionTotalPurchasesColumn counts events with revenue, as Plausible has no dedicated
+// purchase event.
+var sessionTotalPurchasesColumn = columns.NewSimpleSessionColumn(
+	columns.CoreInterfaces.SessionTotalPurchases.ID,
+	columns.CoreInterfaces.SessionTotalPurchases.Field,
+	func(session *schema.Session) (any, schema.D8AColumnWriteError) {
+		total := 0
+		for _, event := range session.Events {
+			if event.Values[ProtocolInterfaces.EventParamsRevenueAmount.Field.Name] != nil {
+				total++
+			}
+		}
+		return total, nil
+	},
+	columns.WithSessionColumnDependsOn(
+		schema.DependsOnEntry{
+			Interface: ProtocolInterfaces.EventParamsRevenueAmount.ID,
+		},
+	),
+	columns.WithSessionColumnDocs(
+		"Total Purchases",
+		"The total number of events with revenue in the session.",
+	),
+)
+
+var sessionTotalOutboundClicksColumn = columns.TotalEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionTotalOutboundClicks.ID,
+	columns.CoreInterfaces.SessionTotalOutboundClicks.Field,
+	[]string{outboundLinkClickEventName},
+	columns.WithSessionColumnDocs(
+		"Total Outbound Clicks",
+		fmt.Sprintf("The total number of outbound link clicks (event name: %s) in the session.", outboundLinkClickEventName), //nolint:lll // description
+	),
+)
+
+var sessionUniqueOutboundClicksColumn = columns.UniqueEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionUniqueOutboundClicks.ID,
+	columns.CoreInterfaces.SessionUniqueOutboundClicks.Field,
+	[]string{outboundLinkClickEventName},
+	[]*arrow.Field{
+		ProtocolInterfaces.EventParamsLinkURL.Field,
+	},
+	columns.WithSessionColumnDependsOn(
+		schema.DependsOnEntry{
+			Interface: ProtocolInterfaces.EventParamsLinkURL.ID,
+		},
+	),
+	columns.WithSessionColumnDocs(
+		"Unique Outbound Clicks",
+		fmt.Sprintf("The unique number of outbound link clicks (event name: %s) in the session. Deduplicated by %s.", outboundLinkClickEventName, ProtocolInterfaces.EventParamsLinkURL.Field.Name), //nolint:lll // description
+	),
+)
+
+var sessionTotalFileDownloadsColumn = columns.TotalEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionTotalFileDownloads.ID,
+	columns.CoreInterfaces.SessionTotalFileDownloads.Field,
+	[]string{fileDownloadEventName},
+	columns.WithSessionColumnDocs(
+		"Total File Downloads",
+		fmt.Sprintf("The total number of file downloads (event name: %s) in the session.", fileDownloadEventName),
+	),
+)
+
+var sessionUniqueFileDownloadsColumn = columns.UniqueEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionUniqueFileDownloads.ID,
+	columns.CoreInterfaces.SessionUniqueFileDownloads.Field,
+	[]string{fileDownloadEventName},
+	[]*arrow.Field{
+		ProtocolInterfaces.EventParamsLinkURL.Field,
+	},
+	columns.WithSessionColumnDependsOn(
+		schema.DependsOnEntry{
+			Interface: ProtocolInterfaces.EventParamsLinkURL.ID,
+		},
+	),
+	columns.WithSessionColumnDocs(
+		"Unique File Downloads",
+		fmt.Sprintf("The unique number of file downloads (event name: %s) in the session. Deduplicated by %s.", fileDownloadEventName, ProtocolInterfaces.EventParamsLinkURL.Field.Name), //nolint:lll // description
+	),
+)
+
+var sessionTotalFormInteractionsColumn = columns.TotalEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionTotalFormInteractions.ID,
+	columns.CoreInterfaces.SessionTotalFormInteractions.Field,
+	[]string{formSubmissionEventName},
+	columns.WithSessionColumnDocs(
+		"Total Form Interactions",
+		fmt.Sprintf("The total number of form submissions (event name: %s) in the session.", formSubmissionEventName),
+	),
+)
+
+var sessionUniqueFormInteractionsColumn = columns.UniqueEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionUniqueFormInteractions.ID,
+	columns.CoreInterfaces.SessionUniqueFormInteractions.Field,
+	[]string{formSubmissionEventName},
+	[]*arrow.Field{
+		columns.CoreInterfaces.EventPageLocation.Field,
+	},
+	columns.WithSessionColumnDependsOn(
+		schema.DependsOnEntry{
+			Interface: columns.CoreInterfaces.EventPageLocation.ID,
+		},
+	),
+	columns.WithSessionColumnDocs(
+		"Unique Form Interactions",
+		fmt.Sprintf("The unique number of form submissions (event name: %s) in the session. Deduplicated by %s.", formSubmissionEventName, columns.CoreInterfaces.EventPageLocation.Field.Name), //nolint:lll // description
+	),
+)
+
+var (
+	sessionTotalScrollsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalScrolls, "Total Scrolls",
+	)
+	sessionTotalSiteSearchesColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalSiteSearches, "Total Site Searches",
+	)
+	sessionUniqueSiteSearchesColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionUniqueSiteSearches, "Unique Site Searches",
+	)
+	sessionTotalVideoEngagementsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalVideoEngagements, "Total Video Engagements",
+	)
+)
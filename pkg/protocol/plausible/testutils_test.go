@@ -0,0 +1,49 @@
+package plausible
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type staticPropertyIDExtractor struct {
+	propertyID string
+}
+
+func (e *staticPropertyIDExtractor) PropertyID(_ *protocol.RequestContext) (string, error) {
+	return e.propertyID, nil
+}
+
+func testSettingsRegistry(opts ...properties.TestSettingsOption) properties.SettingsRegistry {
+	return properties.NewTestSettingRegistry(opts...)
+}
+
+func testRequest(body string) *hits.ParsedRequest {
+	headers := http.Header{}
+	headers.Set("User-Agent", "Mozilla/5.0 (X11; Linux x86_64) Firefox/130.0")
+	headers.Set("Accept-Language", "pl-PL,pl;q=0.9")
+	return &hits.ParsedRequest{
+		IP:                 "127.0.0.1",
+		Host:               "stats.example.com",
+		Path:               "/api/event",
+		Method:             fasthttp.MethodPost,
+		ServerReceivedTime: time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+		Headers:            headers,
+		Body:               []byte(body),
+	}
+}
+
+// testHit parses a single event into a hit, for use in column tests.
+func testHit(t *testing.T, body string) *hits.Hit {
+	p := NewPlausibleProtocol(&staticPropertyIDExtractor{propertyID: "test_property_id"}, testSettingsRegistry())
+	theHits, err := p.Hits(&fasthttp.RequestCtx{}, testRequest(body))
+	require.NoError(t, err)
+	require.Len(t, theHits, 1)
+	return theHits[0]
+}
@@ -5,8 +5,10 @@ import (
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
 	"github.com/d8a-tech/d8a/pkg/schema"
 	"github.com/valyala/fasthttp"
 )
@@ -50,3 +52,48 @@ type Protocol interface {
 	Endpoints() []ProtocolEndpoint
 	Hits(*fasthttp.RequestCtx, *hits.ParsedRequest) ([]*hits.Hit, error)
 }
+
+// PageLocationProvider is implemented by protocols whose hits carry the URL of the page
+// they were sent from.
+type PageLocationProvider interface {
+	// PageLocation returns the page URL of the hit as sent by the tracker, or an empty
+	// string if the hit doesn't have one.
+	PageLocation(hit *hits.Hit) string
+	// SetPageLocation replaces the page URL of a hit which has one.
+	SetPageLocation(hit *hits.Hit, location string)
+}
+
+// EventParamsProvider is implemented by protocols whose events carry custom params.
+type EventParamsProvider interface {
+	// EventParams returns the custom params of the event of the hit, by name. Values are
+	// strings, or float64 for params the protocol sends as numbers.
+	EventParams(hit *hits.Hit) map[string]any
+}
+
+// PrefixedParams returns the first values of the params whose names start with the
+// prefix, by name without the prefix.
+func PrefixedParams(params url.Values, prefix string) map[string]any {
+	result := map[string]any{}
+	for key, values := range params {
+		if name, ok := strings.CutPrefix(key, prefix); ok && name != "" && len(values) > 0 {
+			result[name] = values[0]
+		}
+	}
+	return result
+}
+
+// CORSSettingsProvider is implemented by protocols whose endpoints set CORS headers.
+type CORSSettingsProvider interface {
+	// DefaultCORSSettings returns the CORS settings of requests which aren't bound to a
+	// property, like requests for static files.
+	DefaultCORSSettings() properties.CORSSettings
+}
+
+// CookielessProtocol is implemented by protocols whose trackers send no client ID, like
+// Plausible. Their hits get cookieless client IDs, derived by the receiver from a secret
+// salt, whatever the client ID mode of the property.
+type CookielessProtocol interface {
+	// CookielessClientIDs marks the protocol, the client IDs of its hits are placeholders
+	// until the receiver replaces them.
+	CookielessClientIDs()
+}
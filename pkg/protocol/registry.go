@@ -1,5 +1,11 @@
 package protocol
 
+import (
+	"fmt"
+
+	"github.com/d8a-tech/d8a/pkg/properties"
+)
+
 // Registry allows to get a protocol for a given property ID.
 type Registry interface {
 	Get(propertyID string) (Protocol, error)
@@ -13,15 +19,49 @@ type staticProtocolRegistry struct {
 func (r *staticProtocolRegistry) Get(propertyID string) (Protocol, error) {
 	protocol, ok := r.protocols[propertyID]
 	if !ok {
+		if r.defaultProtocol == nil {
+			return nil, fmt.Errorf("no protocol registered for property %q", propertyID)
+		}
 		return r.defaultProtocol, nil
 	}
 	return protocol, nil
 }
 
-// NewStaticRegistry creates a new static protocol registry.
+// NewStaticRegistry creates a new static protocol registry. If defaultProtocol is nil,
+// lookups for properties missing from the map fail.
 func NewStaticRegistry(protocols map[string]Protocol, defaultProtocol Protocol) Registry {
 	return &staticProtocolRegistry{
 		protocols:       protocols,
 		defaultProtocol: defaultProtocol,
 	}
 }
+
+type fromPropertySettingsRegistry struct {
+	psr       properties.SettingsRegistry
+	protocols map[string]Protocol
+}
+
+func (r *fromPropertySettingsRegistry) Get(propertyID string) (Protocol, error) {
+	settings, err := r.psr.GetByPropertyID(propertyID)
+	if err != nil {
+		return nil, err
+	}
+	protocol, ok := r.protocols[settings.ProtocolID]
+	if !ok {
+		return nil, fmt.Errorf("protocol %s of property %q not found", settings.ProtocolID, propertyID)
+	}
+	return protocol, nil
+}
+
+// NewFromPropertySettingsRegistry creates a protocol registry, which resolves the protocol
+// of a property from its current settings.
+func NewFromPropertySettingsRegistry(psr properties.SettingsRegistry, protocols []Protocol) Registry {
+	byID := make(map[string]Protocol, len(protocols))
+	for _, protocol := range protocols {
+		byID[protocol.ID()] = protocol
+	}
+	return &fromPropertySettingsRegistry{
+		psr:       psr,
+		protocols: byID,
+	}
+}
@@ -0,0 +1,27 @@
+package protocol
+
+import (
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/valyala/fasthttp"
+)
+
+// replayedRequestKey is the user value of the request context holding the original request
+// of a replayed one.
+const replayedRequestKey = "d8a.replayed_request"
+
+// SetReplayedRequest marks the request context as a replay of a previously received
+// request, e.g. one read from the raw log.
+func SetReplayedRequest(ctx *fasthttp.RequestCtx, request *hits.ParsedRequest) {
+	ctx.SetUserValue(replayedRequestKey, request)
+}
+
+// ReplayedRequest returns the original request of a replayed request context. Requests in
+// the raw log were verified when they were received, so protocols may skip checks of
+// credentials which aren't stored with them.
+func ReplayedRequest(ctx *fasthttp.RequestCtx) (*hits.ParsedRequest, bool) {
+	if ctx == nil {
+		return nil, false
+	}
+	request, ok := ctx.UserValue(replayedRequestKey).(*hits.ParsedRequest)
+	return request, ok
+}
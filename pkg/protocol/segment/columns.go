@@ -0,0 +1,50 @@
+package segment
+
+import (
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+const (
+	screenViewEventType = "screen_view"
+	identifyEventType   = "identify"
+)
+
+func eventColumns(pageLocationColumn schema.EventColumn) []schema.EventColumn {
+	return []schema.EventColumn{
+		eventIgnoreReferrerColumn,
+		eventDateUTCColumn,
+		eventTimestampUTCColumn,
+		eventPageReferrerColumn,
+		pageLocationColumn,
+		eventPageHostnameColumn,
+		eventPagePathColumn,
+		eventPageTitleColumn,
+		eventTrackingProtocolColumn,
+		eventPlatformColumn,
+		deviceLanguageColumn,
+		deviceScreenResolutionColumn,
+		eventParamsMessageTypeColumn,
+		eventParamsMessageIDColumn,
+		eventParamsOriginalTimestampColumn,
+		eventParamsLibraryNameColumn,
+		eventParamsLibraryVersionColumn,
+		eventPropertiesColumn,
+		eventTraitsColumn,
+	}
+}
+
+var sessionColumns = []schema.SessionColumn{
+	sessionTotalPurchasesColumn,
+	sessionTotalScrollsColumn,
+	sessionTotalOutboundClicksColumn,
+	sessionUniqueOutboundClicksColumn,
+	sessionTotalSiteSearchesColumn,
+	sessionUniqueSiteSearchesColumn,
+	sessionTotalFormInteractionsColumn,
+	sessionUniqueFormInteractionsColumn,
+	sessionTotalVideoEngagementsColumn,
+	sessionTotalFileDownloadsColumn,
+	sessionUniqueFileDownloadsColumn,
+}
+
+var sseColumns = []schema.SessionScopedEventColumn{}
@@ -0,0 +1,108 @@
+package segment
+
+import (
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/columns/columntests"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nolint:funlen,lll // test code
+func TestSegmentEventColumns(t *testing.T) {
+	const pageBody = `{"writeKey":"wk","anonymousId":"anon-1","messageId":"msg-1","originalTimestamp":"2025-03-04T06:06:07.123+01:00",
+		"name":"Home","properties":{"section":"news"},
+		"context":{"locale":"pl-PL","screen":{"width":1920,"height":1080},"library":{"name":"analytics.js","version":"5.2.0"},
+		"page":{"url":"https://example.com/home?ref=abc","referrer":"https://google.com/"}}}`
+	const trackBody = `{"writeKey":"wk","anonymousId":"anon-1","event":"Order Completed",
+		"properties":{"revenue":12.5,"currency":"USD","coupon":null,"product":{"sku":"A-1"},"tags":["x","y"],"gift":true}}`
+	const identifyBody = `{"writeKey":"wk","userId":"user-1","context":{"traits":{"plan":"pro","seats":3}}}`
+	const screenBody = `{"writeKey":"wk","anonymousId":"anon-1","name":"Settings","context":{"app":{"name":"Shop"}}}`
+
+	testCases := []struct {
+		name        string
+		path        string
+		body        string
+		settingsOpt []properties.TestSettingsOption
+		fieldName   string
+		expected    any
+	}{
+		{name: "DateUTC", path: "/v1/page", body: pageBody, fieldName: "date_utc", expected: "2025-03-04"},
+		{name: "TimestampUTC", path: "/v1/page", body: pageBody, fieldName: "timestamp_utc", expected: "2025-03-04T05:06:07Z"},
+		{name: "PageLocation", path: "/v1/page", body: pageBody, fieldName: "page_location", expected: "https://example.com/home?ref=abc"},
+		{
+			name:        "PageLocation_ExcludedParams",
+			path:        "/v1/page",
+			body:        pageBody,
+			settingsOpt: []properties.TestSettingsOption{properties.WithExcludedURLParams([]string{"ref"})},
+			fieldName:   "page_location",
+			expected:    "https://example.com/home",
+		},
+		{name: "PageLocation_FromProperties", path: "/v1/track", body: `{"writeKey":"wk","anonymousId":"a","event":"e","properties":{"url":"https://example.com/p"}}`, fieldName: "page_location", expected: "https://example.com/p"},
+		{name: "PageHostname", path: "/v1/page", body: pageBody, fieldName: "page_hostname", expected: "example.com"},
+		{name: "PagePath", path: "/v1/page", body: pageBody, fieldName: "page_path", expected: "/home"},
+		{name: "PageTitle_FromPageName", path: "/v1/page", body: pageBody, fieldName: "page_title", expected: "Home"},
+		{name: "PageTitle_EmptyForTrack", path: "/v1/track", body: trackBody, fieldName: "page_title", expected: ""},
+		{name: "PageReferrer", path: "/v1/page", body: pageBody, fieldName: "page_referrer", expected: "https://google.com/"},
+		{name: "TrackingProtocol", path: "/v1/page", body: pageBody, fieldName: "tracking_protocol", expected: "segment"},
+		{name: "Platform_Web", path: "/v1/page", body: pageBody, fieldName: "platform", expected: "web"},
+		{name: "Platform_Mobile", path: "/v1/screen", body: screenBody, fieldName: "platform", expected: "mobile"},
+		{name: "Platform_Server", path: "/v1/track", body: trackBody, fieldName: "platform", expected: "server"},
+		{name: "DeviceLanguage", path: "/v1/page", body: pageBody, fieldName: "device_language", expected: "pl-pl"},
+		{name: "DeviceScreenResolution", path: "/v1/page", body: pageBody, fieldName: "device_screen_resolution", expected: "1920x1080"},
+		{name: "DeviceScreenResolution_Missing", path: "/v1/track", body: trackBody, fieldName: "device_screen_resolution", expected: nil},
+		{name: "MessageType", path: "/v1/page", body: pageBody, fieldName: "params_message_type", expected: "page"},
+		{name: "MessageID", path: "/v1/page", body: pageBody, fieldName: "params_message_id", expected: "msg-1"},
+		{name: "OriginalTimestamp", path: "/v1/page", body: pageBody, fieldName: "params_original_timestamp", expected: "2025-03-04T05:06:07Z"},
+		{name: "OriginalTimestamp_Missing", path: "/v1/track", body: trackBody, fieldName: "params_original_timestamp", expected: nil},
+		{name: "LibraryName", path: "/v1/page", body: pageBody, fieldName: "params_library_name", expected: "analytics.js"},
+		{name: "LibraryVersion", path: "/v1/page", body: pageBody, fieldName: "params_library_version", expected: "5.2.0"},
+		{
+			name:      "Properties",
+			path:      "/v1/track",
+			body:      trackBody,
+			fieldName: "properties",
+			expected: []any{
+				map[string]any{"name": "currency", "value_string": "USD", "value_number": nil},
+				map[string]any{"name": "gift", "value_string": "true", "value_number": nil},
+				map[string]any{"name": "product.sku", "value_string": "A-1", "value_number": nil},
+				map[string]any{"name": "revenue", "value_string": nil, "value_number": 12.5},
+				map[string]any{"name": "tags", "value_string": `["x","y"]`, "value_number": nil},
+			},
+		},
+		{
+			name:      "Traits_FromContext",
+			path:      "/v1/identify",
+			body:      identifyBody,
+			fieldName: "traits",
+			expected: []any{
+				map[string]any{"name": "plan", "value_string": "pro", "value_number": nil},
+				map[string]any{"name": "seats", "value_string": nil, "value_number": 3.0},
+			},
+		},
+		{name: "Traits_Empty", path: "/v1/track", body: trackBody, fieldName: "traits", expected: []any{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			proto := NewSegmentProtocol(
+				&staticPropertyIDExtractor{propertyID: "test_property_id"},
+				testSettingsRegistry(tc.settingsOpt...),
+			)
+
+			columntests.ColumnTestCase(
+				t,
+				columntests.TestHits{testHit(t, tc.path, tc.body)},
+				func(t *testing.T, closeErr error, whd *warehouse.MockWarehouseDriver) {
+					require.NoError(t, closeErr)
+					require.NotEmpty(t, whd.WriteCalls, "expected at least one warehouse write call")
+					require.NotEmpty(t, whd.WriteCalls[0].Records, "expected at least one record written")
+					assert.Equal(t, tc.expected, whd.WriteCalls[0].Records[0][tc.fieldName])
+				},
+				proto,
+			)
+		})
+	}
+}
@@ -0,0 +1,222 @@
+package segment
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// firstParam returns the first non-empty value of the given flattened message keys.
+func firstParam(params url.Values, keys ...string) string {
+	for _, key := range keys {
+		if v := params.Get(key); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// eventIgnoreReferrerColumn is always nil, the Segment API has no way to ask for the
+// referrer to be ignored.
+var eventIgnoreReferrerColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventIgnoreReferrer.ID,
+	columns.CoreInterfaces.EventIgnoreReferrer.Field,
+	func(_ *schema.Event) (any, schema.D8AColumnWriteError) {
+		return nil, nil //nolint:nilnil // not supported by the protocol
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Ignore Referrer",
+		"Whether the referrer should be ignored for this hit. Always empty for the Segment protocol.",
+	),
+)
+
+var eventDateUTCColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventDateUTC.ID,
+	columns.CoreInterfaces.EventDateUTC.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		return event.BoundHit.MustParsedRequest().ServerReceivedTime.UTC().Format("2006-01-02"), nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Event Date (UTC)",
+		"The date when the event occurred in the UTC timezone, formatted as YYYY-MM-DD.",
+	),
+)
+
+var eventTimestampUTCColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventTimestampUTC.ID,
+	columns.CoreInterfaces.EventTimestampUTC.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		return event.BoundHit.MustParsedRequest().ServerReceivedTime.UTC().Format(time.RFC3339), nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Event Timestamp (UTC)",
+		"The precise UTC timestamp of when the event occurred, with second-level precision. Taken from the timestamp or originalTimestamp of the message, bounded to the 72 hours before it was received, or the time the hit was received by the server.", // nolint:lll // it's a description
+	),
+)
+
+var eventPageReferrerColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventPageReferrer.ID,
+	columns.CoreInterfaces.EventPageReferrer.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		return firstParam(
+			event.BoundHit.MustParsedRequest().QueryParams,
+			"context.page.referrer",
+			"properties.referrer",
+		), nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Page Referrer",
+		"The URL of the page that referred the user to the current page, taken from context.page.referrer or properties.referrer, set to empty string when not available.", // nolint:lll // it's a description
+	),
+)
+
+var eventPageTitleColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventPageTitle.ID,
+	columns.CoreInterfaces.EventPageTitle.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		params := event.BoundHit.MustParsedRequest().QueryParams
+		if title := firstParam(params, "context.page.title", "properties.title"); title != "" {
+			return title, nil
+		}
+		if params.Get("type") == pageMessageType || params.Get("type") == screenMessageType {
+			return params.Get("name"), nil
+		}
+		return "", nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Page Title",
+		"The title of the page where the event occurred, taken from context.page.title, properties.title or the name of a page call.", // nolint:lll // it's a description
+	),
+)
+
+func newEventPageLocationColumn(psr properties.SettingsRegistry) schema.EventColumn {
+	return columns.NewSimpleEventColumn(
+		columns.CoreInterfaces.EventPageLocation.ID,
+		columns.CoreInterfaces.EventPageLocation.Field,
+		func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+			originalURL := firstParam(
+				event.BoundHit.MustParsedRequest().QueryParams,
+				"context.page.url",
+				"properties.url",
+			)
+			if originalURL == "" {
+				return "", nil
+			}
+
+			settings, err := psr.GetByPropertyID(event.BoundHit.PropertyID)
+			if err != nil {
+				return nil, schema.NewBrokenEventError(fmt.Sprintf("failed to resolve property settings: %s", err))
+			}
+
+			cleanedURL, _, err := columns.StripExcludedParams(originalURL, settings.ExcludedURLParamsSafe())
+			if err != nil {
+				return nil, schema.NewBrokenEventError(fmt.Sprintf("failed to strip excluded params: %s", err))
+			}
+			columns.WriteOriginalPageLocation(event, originalURL)
+			return cleanedURL, nil
+		},
+		columns.WithEventColumnRequired(false),
+		columns.WithEventColumnDocs(
+			"Page Location",
+			"The complete URL of the page where the event occurred, taken from context.page.url or properties.url (e.g., 'https://www.example.com/products/shoes?color=red&size=10'). Tracking parameters (UTM, click IDs) are excluded once extracted into dedicated columns.", // nolint:lll // it's a description
+		),
+	)
+}
+
+var eventPageHostnameColumn = columns.URLElementColumn(
+	columns.CoreInterfaces.EventPageHostname.ID,
+	columns.CoreInterfaces.EventPageHostname.Field,
+	func(_ *schema.Event, u *url.URL) (any, schema.D8AColumnWriteError) {
+		return u.Hostname(), nil
+	},
+	columns.WithEventColumnDocs(
+		"Page Hostname",
+		"The hostname of the page where the event occurred, as specified in the URL (e.g., 'www.example.com', 'shop.example.com').", // nolint:lll // it's a description
+	),
+)
+
+var eventPagePathColumn = columns.URLElementColumn(
+	columns.CoreInterfaces.EventPagePath.ID,
+	columns.CoreInterfaces.EventPagePath.Field,
+	func(_ *schema.Event, u *url.URL) (any, schema.D8AColumnWriteError) {
+		return u.Path, nil
+	},
+	columns.WithEventColumnDocs(
+		"Page Path",
+		"The path of the page where the event occurred, as specified in the URL (e.g., '/products/shoes', '/blog/article-name').", // nolint:lll // it's a description
+	),
+)
+
+var eventTrackingProtocolColumn = columns.ProtocolColumn(func(_ *schema.Event) (any, schema.D8AColumnWriteError) {
+	return "segment", nil
+})
+
+var eventPlatformColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventPlatform.ID,
+	columns.CoreInterfaces.EventPlatform.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		params := event.BoundHit.MustParsedRequest().QueryParams
+		for key := range params {
+			if strings.HasPrefix(key, "context.app.") {
+				return columns.EventPlatformMobile, nil
+			}
+		}
+		if params.Get("type") == screenMessageType {
+			return columns.EventPlatformMobile, nil
+		}
+		if firstParam(params, "context.page.url", "properties.url") != "" {
+			return columns.EventPlatformWeb, nil
+		}
+		return columns.EventPlatformServer, nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Platform",
+		"The platform from which the event was sent: 'mobile' for messages with app context or screen calls, 'web' for messages with a page URL and 'server' otherwise.", // nolint:lll // it's a description
+	),
+)
+
+var deviceLanguageColumn = columns.NewLanguageColumn(
+	columns.CoreInterfaces.DeviceLanguage.ID,
+	columns.CoreInterfaces.DeviceLanguage.Field,
+	func(req *hits.ParsedRequest) (string, bool) {
+		v := req.QueryParams.Get("context.locale")
+		if v != "" {
+			return strings.ToLower(v), true
+		}
+		return "", false
+	},
+	columns.WithEventColumnDocs(
+		"Device Language",
+		"The language setting of the user's device, extracted from context.locale (lowercased) or the Accept-Language header, based on ISO 639 standard for languages and ISO 3166 for country codes (e.g., 'en-us', 'en-gb', 'de-de').", // nolint:lll // it's a description
+	),
+)
+
+var deviceScreenResolutionColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.DeviceScreenResolution.ID,
+	columns.CoreInterfaces.DeviceScreenResolution.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		params := event.BoundHit.MustParsedRequest().QueryParams
+		width, height := params.Get("context.screen.width"), params.Get("context.screen.height")
+		if width == "" || height == "" {
+			return nil, nil //nolint:nilnil // screen size not present
+		}
+		return width + "x" + height, nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Device screen resolution",
+		"The screen resolution of the user's device, built from context.screen.width and context.screen.height (e.g., '1920x1080', '375x667').", // nolint:lll // it's a description
+	),
+)
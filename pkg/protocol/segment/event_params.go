@@ -0,0 +1,169 @@
+package segment
+
+import (
+	"encoding/json"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+var eventParamsMessageTypeColumn = columns.FromQueryParamEventColumn(
+	ProtocolInterfaces.EventParamsMessageType.ID,
+	ProtocolInterfaces.EventParamsMessageType.Field,
+	"type",
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnCast(
+		columns.StrNilIfErrorOrEmpty(columns.CastToString(ProtocolInterfaces.EventParamsMessageType.ID)),
+	),
+	columns.WithEventColumnDocs(
+		"Message Type",
+		"The type of the Segment message (e.g., 'track', 'page', 'screen', 'identify').",
+	),
+)
+
+var eventParamsMessageIDColumn = columns.FromQueryParamEventColumn(
+	ProtocolInterfaces.EventParamsMessageID.ID,
+	ProtocolInterfaces.EventParamsMessageID.Field,
+	"messageId",
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnCast(
+		columns.StrNilIfErrorOrEmpty(columns.CastToString(ProtocolInterfaces.EventParamsMessageID.ID)),
+	),
+	columns.WithEventColumnDocs(
+		"Message ID",
+		"The unique identifier of the message assigned by the tracking library, extracted from messageId.",
+	),
+)
+
+var eventParamsOriginalTimestampColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventParamsOriginalTimestamp.ID,
+	ProtocolInterfaces.EventParamsOriginalTimestamp.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		raw := firstParam(event.BoundHit.MustParsedRequest().QueryParams, "originalTimestamp", "timestamp")
+		if raw == "" {
+			return nil, nil //nolint:nilnil // timestamp not present
+		}
+		parsed, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return nil, nil //nolint:nilnil // unparsable client timestamps are ignored
+		}
+		return parsed.UTC().Format(time.RFC3339), nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Original Timestamp",
+		"The time the message was created on the client, extracted from originalTimestamp or timestamp. The event timestamp uses timestamp, then originalTimestamp, then the server receive time.", // nolint:lll // it's a description
+	),
+)
+
+var eventParamsLibraryNameColumn = columns.FromQueryParamEventColumn(
+	ProtocolInterfaces.EventParamsLibraryName.ID,
+	ProtocolInterfaces.EventParamsLibraryName.Field,
+	"context.library.name",
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnCast(
+		columns.StrNilIfErrorOrEmpty(columns.CastToString(ProtocolInterfaces.EventParamsLibraryName.ID)),
+	),
+	columns.WithEventColumnDocs(
+		"Library Name",
+		"The name of the library which sent the message (e.g., 'analytics.js', 'analytics-node').",
+	),
+)
+
+var eventParamsLibraryVersionColumn = columns.FromQueryParamEventColumn(
+	ProtocolInterfaces.EventParamsLibraryVersion.ID,
+	ProtocolInterfaces.EventParamsLibraryVersion.Field,
+	"context.library.version",
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnCast(
+		columns.StrNilIfErrorOrEmpty(columns.CastToString(ProtocolInterfaces.EventParamsLibraryVersion.ID)),
+	),
+	columns.WithEventColumnDocs(
+		"Library Version",
+		"The version of the library which sent the message.",
+	),
+)
+
+var eventPropertiesColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventProperties.ID,
+	ProtocolInterfaces.EventProperties.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		message, err := decodeObject(event.BoundHit.MustParsedRequest().Body)
+		if err != nil {
+			return []any{}, nil
+		}
+		properties, _ := message["properties"].(map[string]any)
+		return nameValueParams(properties), nil
+	},
+	columns.WithEventColumnDocs(
+		"Properties",
+		"The properties of the message. Nested objects are flattened with dots (e.g., 'product.sku'), numbers are written to value_number, all other values to value_string.", // nolint:lll // it's a description
+	),
+)
+
+var eventTraitsColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventTraits.ID,
+	ProtocolInterfaces.EventTraits.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		message, err := decodeObject(event.BoundHit.MustParsedRequest().Body)
+		if err != nil {
+			return []any{}, nil
+		}
+		traits, ok := message["traits"].(map[string]any)
+		if !ok {
+			messageContext, _ := message["context"].(map[string]any)
+			traits, _ = messageContext["traits"].(map[string]any)
+		}
+		return nameValueParams(traits), nil
+	},
+	columns.WithEventColumnDocs(
+		"Traits",
+		"The user traits sent with an identify call or in context.traits, in the same shape as the properties column.",
+	),
+)
+
+// nameValueParams converts a JSON object to a list of name/value_string/value_number
+// structs, sorted by name.
+func nameValueParams(object map[string]any) []any {
+	params := make([]any, 0, len(object))
+	var add func(prefix string, value any)
+	add = func(prefix string, value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, nested := range v {
+				add(prefix+"."+key, nested)
+			}
+		case nil:
+			return
+		case json.Number:
+			number, err := v.Float64()
+			if err != nil {
+				return
+			}
+			params = append(params, map[string]any{"name": prefix, "value_string": nil, "value_number": number})
+		default:
+			str, ok := scalarToString(v)
+			if !ok {
+				return
+			}
+			params = append(params, map[string]any{"name": prefix, "value_string": str, "value_number": nil})
+		}
+	}
+	for key, value := range object {
+		add(key, value)
+	}
+	slices.SortFunc(params, func(a, b any) int {
+		aMap, aOk := a.(map[string]any)
+		bMap, bOk := b.(map[string]any)
+		if !aOk || !bOk {
+			return 0
+		}
+		aName, _ := aMap["name"].(string)
+		bName, _ := bMap["name"].(string)
+		return strings.Compare(aName, bName)
+	})
+	return params
+}
@@ -0,0 +1,58 @@
+package segment
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// ProtocolInterfaces are the columns specific to the Segment protocol.
+var ProtocolInterfaces = struct {
+	EventParamsMessageType       schema.Interface
+	EventParamsMessageID         schema.Interface
+	EventParamsOriginalTimestamp schema.Interface
+	EventParamsLibraryName       schema.Interface
+	EventParamsLibraryVersion    schema.Interface
+	EventProperties              schema.Interface
+	EventTraits                  schema.Interface
+}{
+	EventParamsMessageType: schema.Interface{
+		ID:    "segment.protocols.d8a.tech/event/params_message_type",
+		Field: &arrow.Field{Name: "params_message_type", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsMessageID: schema.Interface{
+		ID:    "segment.protocols.d8a.tech/event/params_message_id",
+		Field: &arrow.Field{Name: "params_message_id", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsOriginalTimestamp: schema.Interface{
+		ID:    "segment.protocols.d8a.tech/event/params_original_timestamp",
+		Field: &arrow.Field{Name: "params_original_timestamp", Type: arrow.FixedWidthTypes.Timestamp_s, Nullable: true},
+	},
+	EventParamsLibraryName: schema.Interface{
+		ID:    "segment.protocols.d8a.tech/event/params_library_name",
+		Field: &arrow.Field{Name: "params_library_name", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsLibraryVersion: schema.Interface{
+		ID:    "segment.protocols.d8a.tech/event/params_library_version",
+		Field: &arrow.Field{Name: "params_library_version", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventProperties: schema.Interface{
+		ID:    "segment.protocols.d8a.tech/event/properties",
+		Field: repeatedNameValueField("properties"),
+	},
+	EventTraits: schema.Interface{
+		ID:    "segment.protocols.d8a.tech/event/traits",
+		Field: repeatedNameValueField("traits"),
+	},
+}
+
+func repeatedNameValueField(name string) *arrow.Field {
+	return &arrow.Field{
+		Name: name,
+		Type: arrow.ListOf(arrow.StructOf(
+			arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "value_string", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "value_number", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		)),
+		Nullable: true,
+	}
+}
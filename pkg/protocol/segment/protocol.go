@@ -0,0 +1,363 @@
+// Package segment implements the Segment (analytics.js HTTP API) tracking protocol,
+// also spoken by RudderStack.
+package segment
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/schema"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	trackMessageType    = "track"
+	pageMessageType     = "page"
+	screenMessageType   = "screen"
+	identifyMessageType = "identify"
+	batchMessageType    = "batch"
+)
+
+// writeKeyParam is the flattened message key holding the write key of the message.
+const writeKeyParam = "writeKey"
+
+// maxEventAge is how far before the request receive time the timestamp of a message may
+// be, same as timestamp_micros of the GA4 Measurement Protocol.
+const maxEventAge = 72 * time.Hour
+
+// maxClockSkew is how far after the request receive time the timestamp of a message may
+// be, to tolerate senders whose clocks are slightly ahead.
+const maxClockSkew = time.Minute
+
+type segmentProtocol struct {
+	extractor protocol.PropertyIDExtractor
+	psr       properties.SettingsRegistry
+}
+
+func (p *segmentProtocol) ID() string {
+	return "segment"
+}
+
+// Hits turns the JSON body of a request into hits, one per message. Every message is
+// flattened into the query params of its hit (nested keys joined with dots, e.g.
+// context.page.url), so columns can read it like any other tracking parameter. The
+// message itself is kept as the body of the hit.
+func (p *segmentProtocol) Hits(fhCtx *fasthttp.RequestCtx, request *hits.ParsedRequest) ([]*hits.Hit, error) {
+	payload, err := decodeObject(request.Body)
+	if err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+
+	messageType := strings.TrimPrefix(request.Path, "/v1/")
+	if messageType != batchMessageType {
+		if _, ok := payload["type"]; !ok {
+			payload["type"] = messageType
+		}
+		hit, err := p.createHit(fhCtx, request, payload)
+		if err != nil {
+			return nil, err
+		}
+		return []*hits.Hit{hit}, nil
+	}
+
+	batch, ok := payload["batch"].([]any)
+	if !ok || len(batch) == 0 {
+		return nil, errors.New("batch must be a non-empty array of messages")
+	}
+	sharedContext, _ := payload["context"].(map[string]any)
+	theHits := make([]*hits.Hit, 0, len(batch))
+	for idx, item := range batch {
+		message, ok := item.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("batch[%d] is not an object", idx)
+		}
+		if _, ok := message[writeKeyParam]; !ok && payload[writeKeyParam] != nil {
+			message[writeKeyParam] = payload[writeKeyParam]
+		}
+		if sharedContext != nil {
+			messageContext, _ := message["context"].(map[string]any)
+			message["context"] = mergeObjects(sharedContext, messageContext)
+		}
+		hit, err := p.createHit(fhCtx, request, message)
+		if err != nil {
+			return nil, fmt.Errorf("batch[%d]: %w", idx, err)
+		}
+		theHits = append(theHits, hit)
+	}
+	return theHits, nil
+}
+
+func (p *segmentProtocol) createHit(
+	fhCtx *fasthttp.RequestCtx,
+	request *hits.ParsedRequest,
+	message map[string]any,
+) (*hits.Hit, error) {
+	if _, ok := message[writeKeyParam]; !ok {
+		if writeKey := writeKeyFromAuthorization(request.Headers.Get("Authorization")); writeKey != "" {
+			message[writeKeyParam] = writeKey
+		}
+	}
+
+	params := url.Values{}
+	flatten("", message, params)
+
+	eventName, err := deriveEventName(params)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID := params.Get("anonymousId")
+	userID := params.Get("userId")
+	if clientID == "" {
+		clientID = userID
+	}
+	if clientID == "" {
+		return nil, errors.New("either anonymousId or userId is required")
+	}
+
+	eventTime, err := messageTime(params, request.ServerReceivedTime)
+	if err != nil {
+		return nil, err
+	}
+
+	body, err := json.Marshal(message)
+	if err != nil {
+		return nil, err
+	}
+	requestCopy := request.Clone()
+	requestCopy.QueryParams = params
+	requestCopy.Body = body
+	requestCopy.ServerReceivedTime = eventTime
+
+	propertyID, err := p.extractor.PropertyID(&protocol.RequestContext{
+		Parsed:   requestCopy,
+		FastHttp: fhCtx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hit := hits.New()
+	hit.ClientID = hits.ClientID(clientID)
+	hit.AuthoritativeClientID = hit.ClientID
+	hit.PropertyID = propertyID
+	hit.EventName = eventName
+	if userID != "" {
+		hit.UserID = &userID
+	}
+	hit.Request = requestCopy
+
+	return hit, nil
+}
+
+func (p *segmentProtocol) Endpoints() []protocol.ProtocolEndpoint {
+	messageTypes := []string{trackMessageType, pageMessageType, screenMessageType, identifyMessageType, batchMessageType}
+	endpoints := make([]protocol.ProtocolEndpoint, 0, len(messageTypes))
+	for _, messageType := range messageTypes {
+		endpoints = append(endpoints, protocol.ProtocolEndpoint{
+			Methods: []string{fasthttp.MethodPost},
+			Path:    "/v1/" + messageType,
+		})
+	}
+	return endpoints
+}
+
+// PageLocation implements protocol.PageLocationProvider.
+func (p *segmentProtocol) PageLocation(hit *hits.Hit) string {
+	return firstParam(hit.MustParsedRequest().QueryParams, "context.page.url", "properties.url")
+}
+
+// SetPageLocation implements protocol.PageLocationProvider.
+func (p *segmentProtocol) SetPageLocation(hit *hits.Hit, location string) {
+	queryParams := hit.MustParsedRequest().QueryParams
+	for _, key := range []string{"context.page.url", "properties.url"} {
+		if queryParams.Get(key) != "" {
+			queryParams.Set(key, location)
+		}
+	}
+}
+
+// EventParams implements protocol.EventParamsProvider, with the properties of the message,
+// nested ones joined with dots.
+func (p *segmentProtocol) EventParams(hit *hits.Hit) map[string]any {
+	return protocol.PrefixedParams(hit.MustParsedRequest().QueryParams, "properties.")
+}
+
+func (p *segmentProtocol) Interfaces() any {
+	return ProtocolInterfaces
+}
+
+func (p *segmentProtocol) Columns() schema.Columns {
+	return schema.Columns{
+		Event:              eventColumns(newEventPageLocationColumn(p.psr)),
+		Session:            sessionColumns,
+		SessionScopedEvent: sseColumns,
+	}
+}
+
+// NewSegmentProtocol creates a protocol accepting messages of the Segment HTTP tracking API.
+func NewSegmentProtocol(
+	extractor protocol.PropertyIDExtractor,
+	psr properties.SettingsRegistry,
+) protocol.Protocol {
+	return &segmentProtocol{extractor: extractor, psr: psr}
+}
+
+type fromWriteKeyExtractor struct {
+	psr properties.SettingsRegistry
+}
+
+func (e *fromWriteKeyExtractor) PropertyID(ctx *protocol.RequestContext) (string, error) {
+	writeKey := ctx.Parsed.QueryParams.Get(writeKeyParam)
+	if writeKey == "" {
+		return "", errors.New("missing writeKey")
+	}
+	property, err := e.psr.GetByMeasurementID(writeKey)
+	if err != nil {
+		return "", err
+	}
+	return property.PropertyID, nil
+}
+
+// NewFromWriteKeyExtractor creates a PropertyIDExtractor that resolves the property using
+// the write key of the message (sent in the body or as the Basic auth username) as the
+// measurement ID.
+func NewFromWriteKeyExtractor(psr properties.SettingsRegistry) protocol.PropertyIDExtractor {
+	return &fromWriteKeyExtractor{psr: psr}
+}
+
+func deriveEventName(params url.Values) (string, error) {
+	switch messageType := params.Get("type"); messageType {
+	case trackMessageType:
+		event := strings.TrimSpace(params.Get("event"))
+		if event == "" {
+			return "", errors.New("track message requires an event")
+		}
+		return event, nil
+	case pageMessageType:
+		return protocol.PageViewEventType, nil
+	case screenMessageType:
+		return screenViewEventType, nil
+	case identifyMessageType:
+		return identifyEventType, nil
+	default:
+		return "", fmt.Errorf("unsupported message type %q", messageType)
+	}
+}
+
+// messageTime returns the time of the message: its timestamp, its originalTimestamp, or the
+// time the request was received. Batched server-side calls carry the time of each event
+// this way. Timestamps must be within the 72 hours before the request was received.
+func messageTime(params url.Values, receivedAt time.Time) (time.Time, error) {
+	for _, key := range []string{"timestamp", "originalTimestamp"} {
+		raw := params.Get(key)
+		if raw == "" {
+			continue
+		}
+		timestamp, err := time.Parse(time.RFC3339Nano, raw)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("%s must be an ISO 8601 date: %w", key, err)
+		}
+		if timestamp.Before(receivedAt.Add(-maxEventAge)) {
+			return time.Time{}, fmt.Errorf("%s must not be more than %s in the past: %s", key, maxEventAge, raw)
+		}
+		if timestamp.After(receivedAt.Add(maxClockSkew)) {
+			return time.Time{}, fmt.Errorf("%s must not be in the future: %s", key, raw)
+		}
+		return timestamp.UTC(), nil
+	}
+	return receivedAt, nil
+}
+
+// writeKeyFromAuthorization returns the username of a Basic authorization header, which
+// Segment libraries use to send the write key.
+func writeKeyFromAuthorization(header string) string {
+	encoded, ok := strings.CutPrefix(header, "Basic ")
+	if !ok {
+		return ""
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return ""
+	}
+	writeKey, _, _ := strings.Cut(string(decoded), ":")
+	return writeKey
+}
+
+func decodeObject(data []byte) (map[string]any, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil {
+		return nil, err
+	}
+	if object == nil {
+		return nil, errors.New("expected a JSON object")
+	}
+	return object, nil
+}
+
+// mergeObjects returns a deep merge of two objects, values of override win.
+func mergeObjects(base, override map[string]any) map[string]any {
+	merged := make(map[string]any, len(base)+len(override))
+	for key, value := range base {
+		merged[key] = value
+	}
+	for key, value := range override {
+		baseObject, baseIsObject := merged[key].(map[string]any)
+		overrideObject, overrideIsObject := value.(map[string]any)
+		if baseIsObject && overrideIsObject {
+			merged[key] = mergeObjects(baseObject, overrideObject)
+			continue
+		}
+		merged[key] = value
+	}
+	return merged
+}
+
+// flatten writes scalar values of a decoded JSON value to params, joining nested object
+// keys with dots. Arrays are kept as JSON strings, nulls are skipped.
+func flatten(prefix string, value any, params url.Values) {
+	switch v := value.(type) {
+	case map[string]any:
+		for key, nested := range v {
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+			flatten(key, nested, params)
+		}
+	case nil:
+		return
+	default:
+		if scalar, ok := scalarToString(v); ok {
+			params.Set(prefix, scalar)
+		}
+	}
+}
+
+func scalarToString(value any) (string, bool) {
+	switch v := value.(type) {
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(encoded), true
+	}
+}
@@ -0,0 +1,276 @@
+package segment
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+// nolint:funlen,lll // test code
+func TestHits(t *testing.T) {
+	testCases := []struct {
+		name               string
+		path               string
+		body               string
+		authorization      string
+		expectedEventNames []string
+		expectedClientIDs  []hits.ClientID
+		expectedUserID     *string
+		expectedParams     map[string]string
+		expectError        bool
+	}{
+		{
+			name:               "track",
+			path:               "/v1/track",
+			body:               `{"writeKey":"wk","anonymousId":"anon-1","event":"Order Completed","properties":{"revenue":12.5}}`,
+			expectedEventNames: []string{"Order Completed"},
+			expectedClientIDs:  []hits.ClientID{"anon-1"},
+			expectedParams:     map[string]string{"type": "track", "properties.revenue": "12.5"},
+		},
+		{
+			name:               "page",
+			path:               "/v1/page",
+			body:               `{"writeKey":"wk","anonymousId":"anon-1","name":"Home","context":{"page":{"url":"https://example.com/"}}}`,
+			expectedEventNames: []string{protocol.PageViewEventType},
+			expectedClientIDs:  []hits.ClientID{"anon-1"},
+			expectedParams:     map[string]string{"type": "page", "context.page.url": "https://example.com/"},
+		},
+		{
+			name:               "identify_without_anonymous_id_uses_user_id",
+			path:               "/v1/identify",
+			body:               `{"writeKey":"wk","userId":"user-1","traits":{"plan":"pro"}}`,
+			expectedEventNames: []string{identifyEventType},
+			expectedClientIDs:  []hits.ClientID{"user-1"},
+			expectedUserID:     func() *string { v := "user-1"; return &v }(),
+			expectedParams:     map[string]string{"traits.plan": "pro"},
+		},
+		{
+			name: "batch_merges_shared_context_and_write_key",
+			path: "/v1/batch",
+			body: `{"writeKey":"wk","context":{"locale":"en-US","library":{"name":"analytics-node"}},"batch":[
+				{"type":"track","event":"Signed Up","anonymousId":"anon-1","context":{"locale":"pl-PL"}},
+				{"type":"screen","name":"Settings","anonymousId":"anon-2"}
+			]}`,
+			expectedEventNames: []string{"Signed Up", screenViewEventType},
+			expectedClientIDs:  []hits.ClientID{"anon-1", "anon-2"},
+			expectedParams: map[string]string{
+				"writeKey":             "wk",
+				"context.locale":       "pl-PL",
+				"context.library.name": "analytics-node",
+			},
+		},
+		{
+			name:               "write_key_from_basic_auth",
+			path:               "/v1/track",
+			body:               `{"anonymousId":"anon-1","event":"Clicked"}`,
+			authorization:      "Basic " + base64.StdEncoding.EncodeToString([]byte("wk-basic:")),
+			expectedEventNames: []string{"Clicked"},
+			expectedClientIDs:  []hits.ClientID{"anon-1"},
+			expectedParams:     map[string]string{"writeKey": "wk-basic"},
+		},
+		{
+			name:        "track_without_event_returns_error",
+			path:        "/v1/track",
+			body:        `{"writeKey":"wk","anonymousId":"anon-1"}`,
+			expectError: true,
+		},
+		{
+			name:        "missing_identifiers_returns_error",
+			path:        "/v1/track",
+			body:        `{"writeKey":"wk","event":"Clicked"}`,
+			expectError: true,
+		},
+		{
+			name:        "unsupported_message_type_in_batch_returns_error",
+			path:        "/v1/batch",
+			body:        `{"batch":[{"type":"alias","anonymousId":"anon-1","previousId":"x"}]}`,
+			expectError: true,
+		},
+		{
+			name:        "empty_batch_returns_error",
+			path:        "/v1/batch",
+			body:        `{"batch":[]}`,
+			expectError: true,
+		},
+		{
+			name:        "invalid_json_returns_error",
+			path:        "/v1/track",
+			body:        `{"event":`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			segmentProtocol := NewSegmentProtocol(
+				&staticPropertyIDExtractor{propertyID: "test_property_id"},
+				testSettingsRegistry(),
+			)
+			request := testRequest(tc.path, tc.body)
+			if tc.authorization != "" {
+				request.Headers.Set("Authorization", tc.authorization)
+			}
+
+			// when
+			hitsResult, err := segmentProtocol.Hits(&fasthttp.RequestCtx{}, request)
+
+			// then
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, hitsResult, len(tc.expectedEventNames))
+			for index, hit := range hitsResult {
+				assert.Equal(t, tc.expectedEventNames[index], hit.EventName)
+				assert.Equal(t, tc.expectedClientIDs[index], hit.ClientID)
+				assert.Equal(t, hit.ClientID, hit.AuthoritativeClientID)
+				assert.Equal(t, "test_property_id", hit.PropertyID)
+			}
+			if tc.expectedUserID != nil {
+				require.NotNil(t, hitsResult[0].UserID)
+				assert.Equal(t, *tc.expectedUserID, *hitsResult[0].UserID)
+			}
+			for key, value := range tc.expectedParams {
+				assert.Equal(t, value, hitsResult[0].MustParsedRequest().QueryParams.Get(key), key)
+			}
+		})
+	}
+}
+
+func TestHits_EventTime(t *testing.T) {
+	receivedAt := time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC)
+	testCases := []struct {
+		name         string
+		path         string
+		body         string
+		expectedTime []time.Time
+		expectError  bool
+	}{
+		{
+			name:         "receive time without timestamps",
+			path:         "/v1/track",
+			body:         `{"anonymousId":"a","event":"e"}`,
+			expectedTime: []time.Time{receivedAt},
+		},
+		{
+			name:         "timestamp wins over originalTimestamp",
+			path:         "/v1/track",
+			body:         `{"anonymousId":"a","event":"e","timestamp":"2025-03-04T03:00:00Z","originalTimestamp":"2025-03-04T04:00:00Z"}`, //nolint:lll // test data
+			expectedTime: []time.Time{time.Date(2025, 3, 4, 3, 0, 0, 0, time.UTC)},
+		},
+		{
+			name:         "originalTimestamp in another zone",
+			path:         "/v1/track",
+			body:         `{"anonymousId":"a","event":"e","originalTimestamp":"2025-03-04T04:00:00.5+01:00"}`,
+			expectedTime: []time.Time{time.Date(2025, 3, 4, 3, 0, 0, 500000000, time.UTC)},
+		},
+		{
+			name: "batched messages keep their own time",
+			path: "/v1/batch",
+			body: `{"batch":[
+				{"type":"track","event":"e","anonymousId":"a","timestamp":"2025-03-03T05:00:00Z"},
+				{"type":"track","event":"e","anonymousId":"a","timestamp":"2025-03-04T05:00:00Z"}
+			]}`,
+			expectedTime: []time.Time{
+				time.Date(2025, 3, 3, 5, 0, 0, 0, time.UTC),
+				time.Date(2025, 3, 4, 5, 0, 0, 0, time.UTC),
+			},
+		},
+		{
+			name:        "more than 72 hours in the past",
+			path:        "/v1/track",
+			body:        `{"anonymousId":"a","event":"e","timestamp":"2025-03-01T05:06:06Z"}`,
+			expectError: true,
+		},
+		{
+			name:        "in the future",
+			path:        "/v1/track",
+			body:        `{"anonymousId":"a","event":"e","originalTimestamp":"2025-03-04T05:08:00Z"}`,
+			expectError: true,
+		},
+		{
+			name:        "not a date",
+			path:        "/v1/track",
+			body:        `{"anonymousId":"a","event":"e","timestamp":"yesterday"}`,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			segmentProtocol := NewSegmentProtocol(
+				&staticPropertyIDExtractor{propertyID: "test_property_id"},
+				testSettingsRegistry(),
+			)
+
+			// when
+			hitsResult, err := segmentProtocol.Hits(&fasthttp.RequestCtx{}, testRequest(tc.path, tc.body))
+
+			// then
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, hitsResult, len(tc.expectedTime))
+			for idx, hit := range hitsResult {
+				assert.Equal(t, tc.expectedTime[idx], hit.MustParsedRequest().ServerReceivedTime)
+			}
+		})
+	}
+}
+
+func TestFromWriteKeyExtractor(t *testing.T) {
+	// given
+	psr := properties.NewStaticSettingsRegistry([]properties.Settings{
+		{PropertyID: "shop", PropertyMeasurementID: "wk-shop"},
+	})
+	segmentProtocol := NewSegmentProtocol(NewFromWriteKeyExtractor(psr), psr)
+
+	// when
+	known, knownErr := segmentProtocol.Hits(
+		&fasthttp.RequestCtx{},
+		testRequest("/v1/track", `{"writeKey":"wk-shop","anonymousId":"a","event":"e"}`),
+	)
+	_, unknownErr := segmentProtocol.Hits(
+		&fasthttp.RequestCtx{},
+		testRequest("/v1/track", `{"writeKey":"wk-other","anonymousId":"a","event":"e"}`),
+	)
+	_, missingErr := segmentProtocol.Hits(
+		&fasthttp.RequestCtx{},
+		testRequest("/v1/track", `{"anonymousId":"a","event":"e"}`),
+	)
+
+	// then
+	require.NoError(t, knownErr)
+	require.Len(t, known, 1)
+	assert.Equal(t, "shop", known[0].PropertyID)
+	assert.Error(t, unknownErr)
+	assert.Error(t, missingErr)
+}
+
+func TestEndpoints(t *testing.T) {
+	// given
+	segmentProtocol := NewSegmentProtocol(&staticPropertyIDExtractor{}, testSettingsRegistry())
+
+	// when
+	endpoints := segmentProtocol.Endpoints()
+
+	// then
+	paths := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		assert.Equal(t, []string{fasthttp.MethodPost}, endpoint.Methods)
+		paths = append(paths, endpoint.Path)
+	}
+	assert.Equal(t, []string{"/v1/track", "/v1/page", "/v1/screen", "/v1/identify", "/v1/batch"}, paths)
+}
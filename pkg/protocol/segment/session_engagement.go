@@ -0,0 +1,89 @@
+//nolint:dupl,nilnil // unsupported columns share the same shape
+package segment
+
+import (
+	"fmt"
+
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// Event names of the Segment semantic event spec, which map onto core session columns.
+const (
+	orderCompletedEventType       = "Order Completed"
+	productsSearchedEventType     = "Products Searched"
+	videoPlaybackStartedEventType = "Video Playback Started"
+)
+
+// unsupportedSessionColumn creates a core session column, which the Segment protocol has
+// no standard event for. It always writes null.
+func unsupportedSessionColumn(iface schema.Interface, title string) schema.SessionColumn {
+	return columns.NewSimpleSessionColumn(
+		iface.ID,
+		iface.Field,
+		func(_ *schema.Session) (any, schema.D8AColumnWriteError) {
+			return nil, nil
+		},
+		columns.WithSessionColumnDocs(
+			title,
+			"Not supported in the Segment protocol. The semantic event spec has no standard event for it, so it is always null.", //nolint:lll // description
+		),
+	)
+}
+
+var sessionTotalPurchasesColumn = columns.TotalEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionTotalPurchases.ID,
+	columns.CoreInterfaces.SessionTotalPurchases.Field,
+	[]string{orderCompletedEventType},
+	columns.WithSessionColumnDocs(
+		"Total Purchases",
+		fmt.Sprintf("The total number of completed orders (event name: %s) in the session.", orderCompletedEventType),
+	),
+)
+
+var sessionTotalSiteSearchesColumn = columns.TotalEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionTotalSiteSearches.ID,
+	columns.CoreInterfaces.SessionTotalSiteSearches.Field,
+	[]string{productsSearchedEventType},
+	columns.WithSessionColumnDocs(
+		"Total Site Searches",
+		fmt.Sprintf("The total number of searches (event name: %s) in the session.", productsSearchedEventType),
+	),
+)
+
+var sessionTotalVideoEngagementsColumn = columns.TotalEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionTotalVideoEngagements.ID,
+	columns.CoreInterfaces.SessionTotalVideoEngagements.Field,
+	[]string{videoPlaybackStartedEventType},
+	columns.WithSessionColumnDocs(
+		"Total Video Engagements",
+		fmt.Sprintf("The total number of video playbacks (event name: %s) in the session.", videoPlaybackStartedEventType), //nolint:lll // description
+	),
+)
+
+var (
+	sessionUniqueSiteSearchesColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionUniqueSiteSearches, "Unique Site Searches",
+	)
+	sessionTotalScrollsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalScrolls, "Total Scrolls",
+	)
+	sessionTotalOutboundClicksColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalOutboundClicks, "Total Outbound Clicks",
+	)
+	sessionUniqueOutboundClicksColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionUniqueOutboundClicks, "Unique Outbound Clicks",
+	)
+	sessionTotalFormInteractionsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalFormInteractions, "Total Form Interactions",
+	)
+	sessionUniqueFormInteractionsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionUniqueFormInteractions, "Unique Form Interactions",
+	)
+	sessionTotalFileDownloadsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalFileDownloads, "Total File Downloads",
+	)
+	sessionUniqueFileDownloadsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionUniqueFileDownloads, "Unique File Downloads",
+	)
+)
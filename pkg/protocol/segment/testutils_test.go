@@ -0,0 +1,47 @@
+package segment
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type staticPropertyIDExtractor struct {
+	propertyID string
+}
+
+func (e *staticPropertyIDExtractor) PropertyID(_ *protocol.RequestContext) (string, error) {
+	return e.propertyID, nil
+}
+
+func testSettingsRegistry(opts ...properties.TestSettingsOption) properties.SettingsRegistry {
+	return properties.NewTestSettingRegistry(opts...)
+}
+
+func testRequest(path, body string) *hits.ParsedRequest {
+	return &hits.ParsedRequest{
+		IP:                 "127.0.0.1",
+		Host:               "api.example.com",
+		Path:               path,
+		Method:             fasthttp.MethodPost,
+		ServerReceivedTime: time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+		Headers:            http.Header{},
+		Body:               []byte(body),
+	}
+}
+
+// testHit parses a single message sent to the given endpoint into a hit, for use in
+// column tests.
+func testHit(t *testing.T, path, body string) *hits.Hit {
+	p := NewSegmentProtocol(&staticPropertyIDExtractor{propertyID: "test_property_id"}, testSettingsRegistry())
+	theHits, err := p.Hits(&fasthttp.RequestCtx{}, testRequest(path, body))
+	require.NoError(t, err)
+	require.Len(t, theHits, 1)
+	return theHits[0]
+}
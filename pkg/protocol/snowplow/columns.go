@@ -0,0 +1,53 @@
+package snowplow
+
+import (
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+func eventColumns(pageLocationColumn schema.EventColumn) []schema.EventColumn {
+	return []schema.EventColumn{
+		eventIgnoreReferrerColumn,
+		eventDateUTCColumn,
+		eventTimestampUTCColumn,
+		eventPageReferrerColumn,
+		pageLocationColumn,
+		eventPageHostnameColumn,
+		eventPagePathColumn,
+		eventPageTitleColumn,
+		eventTrackingProtocolColumn,
+		eventPlatformColumn,
+		deviceLanguageColumn,
+		deviceScreenResolutionColumn,
+		eventParamsAppIDColumn,
+		eventParamsPlatformColumn,
+		eventParamsTrackerVersionColumn,
+		eventParamsEventIDColumn,
+		eventParamsDeviceCreatedTimestampColumn,
+		eventParamsDomainSessionIDColumn,
+		eventParamsDomainSessionIndexColumn,
+		eventParamsCategoryColumn,
+		eventParamsActionColumn,
+		eventParamsLabelColumn,
+		eventParamsPropertyColumn,
+		eventParamsValueColumn,
+		eventParamsEventSchemaColumn,
+		eventUnstructEventColumn,
+		eventContextsColumn,
+	}
+}
+
+var sessionColumns = []schema.SessionColumn{
+	sessionTotalPurchasesColumn,
+	sessionTotalScrollsColumn,
+	sessionTotalOutboundClicksColumn,
+	sessionUniqueOutboundClicksColumn,
+	sessionTotalSiteSearchesColumn,
+	sessionUniqueSiteSearchesColumn,
+	sessionTotalFormInteractionsColumn,
+	sessionUniqueFormInteractionsColumn,
+	sessionTotalVideoEngagementsColumn,
+	sessionTotalFileDownloadsColumn,
+	sessionUniqueFileDownloadsColumn,
+}
+
+var sseColumns = []schema.SessionScopedEventColumn{}
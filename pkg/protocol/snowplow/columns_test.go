@@ -0,0 +1,118 @@
+package snowplow
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/columns/columntests"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// nolint:funlen,lll // test code
+func TestSnowplowEventColumns(t *testing.T) {
+	pageView := url.Values{
+		"e": {"pv"}, "p": {"web"}, "aid": {"shop"}, "tv": {"js-3.23.0"}, "eid": {"4b6f3c"},
+		"duid": {"duid-1"}, "sid": {"sid-1"}, "vid": {"3"}, "dtm": {"1741064767123"},
+		"url": {"https://example.com/home?ref=abc"}, "page": {"Home"}, "refr": {"https://google.com/"},
+		"lang": {"pl-PL"}, "res": {"1920x1080"},
+		"cx": {encode(`{"schema":"iglu:com.snowplowanalytics.snowplow/contexts/jsonschema/1-0-0","data":[{"schema":"iglu:com.example/user/jsonschema/1-0-0","data":{"plan":"pro","seats":3}}]}`)},
+	}
+	structured := url.Values{
+		"e": {"se"}, "p": {"srv"}, "aid": {"api"}, "uid": {"user-1"},
+		"se_ca": {"video"}, "se_ac": {"play"}, "se_la": {"intro"}, "se_pr": {"hd"}, "se_va": {"12.5"},
+	}
+	screenView := url.Values{
+		"e": {"ue"}, "p": {"mob"}, "aid": {"app"},
+		"ue_px": {encode(screenViewEvent)},
+		"cx":    {encode(clientSessionContext)},
+	}
+
+	testCases := []struct {
+		name        string
+		params      url.Values
+		settingsOpt []properties.TestSettingsOption
+		fieldName   string
+		expected    any
+	}{
+		{name: "DateUTC", params: pageView, fieldName: "date_utc", expected: "2025-03-04"},
+		{name: "TimestampUTC", params: pageView, fieldName: "timestamp_utc", expected: "2025-03-04T05:06:07Z"},
+		{name: "PageLocation", params: pageView, fieldName: "page_location", expected: "https://example.com/home?ref=abc"},
+		{
+			name:        "PageLocation_ExcludedParams",
+			params:      pageView,
+			settingsOpt: []properties.TestSettingsOption{properties.WithExcludedURLParams([]string{"ref"})},
+			fieldName:   "page_location",
+			expected:    "https://example.com/home",
+		},
+		{name: "PageHostname", params: pageView, fieldName: "page_hostname", expected: "example.com"},
+		{name: "PagePath", params: pageView, fieldName: "page_path", expected: "/home"},
+		{name: "PageTitle", params: pageView, fieldName: "page_title", expected: "Home"},
+		{name: "PageReferrer", params: pageView, fieldName: "page_referrer", expected: "https://google.com/"},
+		{name: "TrackingProtocol", params: pageView, fieldName: "tracking_protocol", expected: "snowplow"},
+		{name: "Platform_Web", params: pageView, fieldName: "platform", expected: "web"},
+		{name: "Platform_Mobile", params: screenView, fieldName: "platform", expected: "mobile"},
+		{name: "Platform_Server", params: structured, fieldName: "platform", expected: "server"},
+		{name: "DeviceLanguage", params: pageView, fieldName: "device_language", expected: "pl-pl"},
+		{name: "DeviceScreenResolution", params: pageView, fieldName: "device_screen_resolution", expected: "1920x1080"},
+		{name: "AppID", params: pageView, fieldName: "params_app_id", expected: "shop"},
+		{name: "Platform_Raw", params: screenView, fieldName: "params_platform", expected: "mob"},
+		{name: "TrackerVersion", params: pageView, fieldName: "params_tracker_version", expected: "js-3.23.0"},
+		{name: "EventID", params: pageView, fieldName: "params_event_id", expected: "4b6f3c"},
+		{name: "DeviceCreatedTimestamp", params: pageView, fieldName: "params_device_created_timestamp", expected: "2025-03-04T05:06:07Z"},
+		{name: "DomainSessionID", params: pageView, fieldName: "params_domain_session_id", expected: "sid-1"},
+		{name: "DomainSessionID_FromClientSession", params: screenView, fieldName: "params_domain_session_id", expected: "session-1"},
+		{name: "DomainSessionIndex", params: pageView, fieldName: "params_domain_session_index", expected: int64(3)},
+		{name: "DomainSessionIndex_FromClientSession", params: screenView, fieldName: "params_domain_session_index", expected: int64(4)},
+		{name: "Category", params: structured, fieldName: "params_category", expected: "video"},
+		{name: "Action", params: structured, fieldName: "params_action", expected: "play"},
+		{name: "Label", params: structured, fieldName: "params_label", expected: "intro"},
+		{name: "Property", params: structured, fieldName: "params_property", expected: "hd"},
+		{name: "Value", params: structured, fieldName: "params_value", expected: 12.5},
+		{name: "EventSchema", params: screenView, fieldName: "params_event_schema", expected: "iglu:com.snowplowanalytics.mobile/screen_view/jsonschema/1-0-0"},
+		{name: "EventSchema_Missing", params: pageView, fieldName: "params_event_schema", expected: nil},
+		{
+			name:      "UnstructEvent",
+			params:    screenView,
+			fieldName: "unstruct_event",
+			expected: []any{
+				map[string]any{"name": "id", "value_string": "5e3b", "value_number": nil},
+				map[string]any{"name": "name", "value_string": "Home", "value_number": nil},
+			},
+		},
+		{name: "UnstructEvent_Empty", params: pageView, fieldName: "unstruct_event", expected: []any{}},
+		{
+			name:      "Contexts",
+			params:    pageView,
+			fieldName: "contexts",
+			expected: []any{
+				map[string]any{"schema": "iglu:com.example/user/jsonschema/1-0-0", "name": "plan", "value_string": "pro", "value_number": nil},
+				map[string]any{"schema": "iglu:com.example/user/jsonschema/1-0-0", "name": "seats", "value_string": nil, "value_number": 3.0},
+			},
+		},
+		{name: "Contexts_Empty", params: structured, fieldName: "contexts", expected: []any{}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			proto := NewSnowplowProtocol(
+				&staticPropertyIDExtractor{propertyID: "test_property_id"},
+				testSettingsRegistry(tc.settingsOpt...),
+			)
+
+			columntests.ColumnTestCase(
+				t,
+				columntests.TestHits{testHit(t, tc.params)},
+				func(t *testing.T, closeErr error, whd *warehouse.MockWarehouseDriver) {
+					require.NoError(t, closeErr)
+					require.NotEmpty(t, whd.WriteCalls, "expected at least one warehouse write call")
+					require.NotEmpty(t, whd.WriteCalls[0].Records, "expected at least one record written")
+					assert.Equal(t, tc.expected, whd.WriteCalls[0].Records[0][tc.fieldName])
+				},
+				proto,
+			)
+		})
+	}
+}
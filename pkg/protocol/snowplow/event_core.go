@@ -0,0 +1,184 @@
+package snowplow
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// eventIgnoreReferrerColumn is always nil, the tracker protocol has no way to ask for the
+// referrer to be ignored.
+var eventIgnoreReferrerColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventIgnoreReferrer.ID,
+	columns.CoreInterfaces.EventIgnoreReferrer.Field,
+	func(_ *schema.Event) (any, schema.D8AColumnWriteError) {
+		return nil, nil //nolint:nilnil // not supported by the protocol
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Ignore Referrer",
+		"Whether the referrer should be ignored for this hit. Always empty for the Snowplow protocol.",
+	),
+)
+
+var eventDateUTCColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventDateUTC.ID,
+	columns.CoreInterfaces.EventDateUTC.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		return event.BoundHit.MustParsedRequest().ServerReceivedTime.UTC().Format("2006-01-02"), nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Event Date (UTC)",
+		"The date when the event occurred in the UTC timezone, formatted as YYYY-MM-DD.",
+	),
+)
+
+var eventTimestampUTCColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventTimestampUTC.ID,
+	columns.CoreInterfaces.EventTimestampUTC.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		return event.BoundHit.MustParsedRequest().ServerReceivedTime.UTC().Format(time.RFC3339), nil
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Event Timestamp (UTC)",
+		"The precise UTC timestamp of when the event occurred, with second-level precision. This represents the time recorded when the hit is received by the server.", // nolint:lll // it's a description
+	),
+)
+
+var eventPageReferrerColumn = columns.FromQueryParamEventColumn(
+	columns.CoreInterfaces.EventPageReferrer.ID,
+	columns.CoreInterfaces.EventPageReferrer.Field,
+	"refr",
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Page Referrer",
+		"The URL of the page that referred the user to the current page, extracted from the refr param, set to empty string when not available.", // nolint:lll // it's a description
+	),
+)
+
+var eventPageTitleColumn = columns.FromQueryParamEventColumn(
+	columns.CoreInterfaces.EventPageTitle.ID,
+	columns.CoreInterfaces.EventPageTitle.Field,
+	"page",
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Page Title",
+		"The title of the page where the event occurred, extracted from the page param.",
+	),
+)
+
+func newEventPageLocationColumn(psr properties.SettingsRegistry) schema.EventColumn {
+	return columns.NewSimpleEventColumn(
+		columns.CoreInterfaces.EventPageLocation.ID,
+		columns.CoreInterfaces.EventPageLocation.Field,
+		func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+			originalURL := event.BoundHit.MustParsedRequest().QueryParams.Get("url")
+			if originalURL == "" {
+				return "", nil
+			}
+
+			settings, err := psr.GetByPropertyID(event.BoundHit.PropertyID)
+			if err != nil {
+				return nil, schema.NewBrokenEventError(fmt.Sprintf("failed to resolve property settings: %s", err))
+			}
+
+			cleanedURL, _, err := columns.StripExcludedParams(originalURL, settings.ExcludedURLParamsSafe())
+			if err != nil {
+				return nil, schema.NewBrokenEventError(fmt.Sprintf("failed to strip excluded params: %s", err))
+			}
+			columns.WriteOriginalPageLocation(event, originalURL)
+			return cleanedURL, nil
+		},
+		columns.WithEventColumnRequired(false),
+		columns.WithEventColumnDocs(
+			"Page Location",
+			"The complete URL of the page where the event occurred, extracted from the url param (e.g., 'https://www.example.com/products/shoes?color=red&size=10'). Tracking parameters (UTM, click IDs) are excluded once extracted into dedicated columns.", // nolint:lll // it's a description
+		),
+	)
+}
+
+var eventPageHostnameColumn = columns.URLElementColumn(
+	columns.CoreInterfaces.EventPageHostname.ID,
+	columns.CoreInterfaces.EventPageHostname.Field,
+	func(_ *schema.Event, u *url.URL) (any, schema.D8AColumnWriteError) {
+		return u.Hostname(), nil
+	},
+	columns.WithEventColumnDocs(
+		"Page Hostname",
+		"The hostname of the page where the event occurred, as specified in the URL (e.g., 'www.example.com', 'shop.example.com').", // nolint:lll // it's a description
+	),
+)
+
+var eventPagePathColumn = columns.URLElementColumn(
+	columns.CoreInterfaces.EventPagePath.ID,
+	columns.CoreInterfaces.EventPagePath.Field,
+	func(_ *schema.Event, u *url.URL) (any, schema.D8AColumnWriteError) {
+		return u.Path, nil
+	},
+	columns.WithEventColumnDocs(
+		"Page Path",
+		"The path of the page where the event occurred, as specified in the URL (e.g., '/products/shoes', '/blog/article-name').", // nolint:lll // it's a description
+	),
+)
+
+var eventTrackingProtocolColumn = columns.ProtocolColumn(func(_ *schema.Event) (any, schema.D8AColumnWriteError) {
+	return "snowplow", nil
+})
+
+var eventPlatformColumn = columns.NewSimpleEventColumn(
+	columns.CoreInterfaces.EventPlatform.ID,
+	columns.CoreInterfaces.EventPlatform.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		switch event.BoundHit.MustParsedRequest().QueryParams.Get("p") {
+		case "mob", "app", "tv", "cnsl", "iot":
+			return columns.EventPlatformMobile, nil
+		case "srv":
+			return columns.EventPlatformServer, nil
+		default:
+			return columns.EventPlatformWeb, nil
+		}
+	},
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnDocs(
+		"Platform",
+		"The platform from which the event was sent, mapped from the p param: 'mobile' for mob, app, tv, cnsl and iot, 'server' for srv and 'web' otherwise.", // nolint:lll // it's a description
+	),
+)
+
+var deviceLanguageColumn = columns.NewLanguageColumn(
+	columns.CoreInterfaces.DeviceLanguage.ID,
+	columns.CoreInterfaces.DeviceLanguage.Field,
+	func(req *hits.ParsedRequest) (string, bool) {
+		v := req.QueryParams.Get("lang")
+		if v != "" {
+			return strings.ToLower(v), true
+		}
+		return "", false
+	},
+	columns.WithEventColumnDocs(
+		"Device Language",
+		"The language setting of the user's device, extracted from the lang param (lowercased) or the Accept-Language header, based on ISO 639 standard for languages and ISO 3166 for country codes (e.g., 'en-us', 'en-gb', 'de-de').", // nolint:lll // it's a description
+	),
+)
+
+var deviceScreenResolutionColumn = columns.FromQueryParamEventColumn(
+	columns.CoreInterfaces.DeviceScreenResolution.ID,
+	columns.CoreInterfaces.DeviceScreenResolution.Field,
+	"res",
+	columns.WithEventColumnRequired(false),
+	columns.WithEventColumnCast(
+		columns.StrNilIfErrorOrEmpty(columns.CastToString(columns.CoreInterfaces.DeviceScreenResolution.ID)),
+	),
+	columns.WithEventColumnDocs(
+		"Device screen resolution",
+		"The screen resolution of the user's device, extracted from the res param (e.g., '1920x1080', '375x667').",
+	),
+)
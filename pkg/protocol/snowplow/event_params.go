@@ -0,0 +1,252 @@
+package snowplow
+
+import (
+	"encoding/json"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// stringParamColumn creates a column reading a string tracker param.
+func stringParamColumn(iface schema.Interface, param, title, description string) schema.EventColumn {
+	return columns.FromQueryParamEventColumn(
+		iface.ID,
+		iface.Field,
+		param,
+		columns.WithEventColumnRequired(false),
+		columns.WithEventColumnCast(columns.StrNilIfErrorOrEmpty(columns.CastToString(iface.ID))),
+		columns.WithEventColumnDocs(title, description),
+	)
+}
+
+var (
+	eventParamsAppIDColumn = stringParamColumn(
+		ProtocolInterfaces.EventParamsAppID, "aid",
+		"App ID", "The application ID set in the tracker, extracted from the aid param. Used as the measurement ID of the property.", // nolint:lll // it's a description
+	)
+	eventParamsPlatformColumn = stringParamColumn(
+		ProtocolInterfaces.EventParamsPlatform, "p",
+		"Snowplow Platform", "The platform code sent by the tracker (e.g., 'web', 'mob', 'srv'), extracted from the p param.",
+	)
+	eventParamsTrackerVersionColumn = stringParamColumn(
+		ProtocolInterfaces.EventParamsTrackerVersion, "tv",
+		"Tracker Version", "The name and version of the tracker (e.g., 'js-3.23.0'), extracted from the tv param.",
+	)
+	eventParamsEventIDColumn = stringParamColumn(
+		ProtocolInterfaces.EventParamsEventID, "eid",
+		"Snowplow Event ID", "The UUID assigned to the event by the tracker, extracted from the eid param.",
+	)
+	eventParamsCategoryColumn = stringParamColumn(
+		ProtocolInterfaces.EventParamsCategory, "se_ca",
+		"Category", "The category of a structured event, extracted from the se_ca param.",
+	)
+	eventParamsActionColumn = stringParamColumn(
+		ProtocolInterfaces.EventParamsAction, "se_ac",
+		"Action", "The action of a structured event, extracted from the se_ac param. Also used as the event name.",
+	)
+	eventParamsLabelColumn = stringParamColumn(
+		ProtocolInterfaces.EventParamsLabel, "se_la",
+		"Label", "The label of a structured event, extracted from the se_la param.",
+	)
+	eventParamsPropertyColumn = stringParamColumn(
+		ProtocolInterfaces.EventParamsProperty, "se_pr",
+		"Property", "The property of a structured event, extracted from the se_pr param.",
+	)
+)
+
+var eventParamsValueColumn = columns.FromQueryParamEventColumn(
+	ProtocolInterfaces.EventParamsValue.ID,
+	ProtocolInterfaces.EventParamsValue.Field,
+	"se_va",
+	columns.WithEventColumnCast(columns.CastToFloat64OrNil(ProtocolInterfaces.EventParamsValue.ID)),
+	columns.WithEventColumnDocs(
+		"Value",
+		"The numeric value of a structured event, extracted from the se_va param.",
+	),
+)
+
+var eventParamsDeviceCreatedTimestampColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventParamsDeviceCreatedTimestamp.ID,
+	ProtocolInterfaces.EventParamsDeviceCreatedTimestamp.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		millis, err := strconv.ParseInt(event.BoundHit.MustParsedRequest().QueryParams.Get("dtm"), 10, 64)
+		if err != nil {
+			return nil, nil //nolint:nilnil // missing or unparsable client timestamps are ignored
+		}
+		return time.UnixMilli(millis).UTC().Format(time.RFC3339), nil
+	},
+	columns.WithEventColumnDocs(
+		"Device Created Timestamp",
+		"The time the event was created on the device, extracted from the dtm param. The event timestamp always uses the server receive time.", // nolint:lll // it's a description
+	),
+)
+
+var eventParamsDomainSessionIDColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventParamsDomainSessionID.ID,
+	ProtocolInterfaces.EventParamsDomainSessionID.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		params := event.BoundHit.MustParsedRequest().QueryParams
+		if sid := params.Get("sid"); sid != "" {
+			return sid, nil
+		}
+		if sessionID, ok := clientSessionValue(params, "sessionId").(string); ok && sessionID != "" {
+			return sessionID, nil
+		}
+		return nil, nil //nolint:nilnil // session not present
+	},
+	columns.WithEventColumnDocs(
+		"Client Session ID",
+		"The session ID assigned by the tracker, extracted from the sid param or the sessionId of the client_session context. For real session data calculated on the backend, use the session_id column.", // nolint:lll // it's a description
+	),
+)
+
+var eventParamsDomainSessionIndexColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventParamsDomainSessionIndex.ID,
+	ProtocolInterfaces.EventParamsDomainSessionIndex.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		params := event.BoundHit.MustParsedRequest().QueryParams
+		raw := params.Get("vid")
+		if raw == "" {
+			if number, ok := clientSessionValue(params, "sessionIndex").(json.Number); ok {
+				raw = number.String()
+			}
+		}
+		index, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return nil, nil //nolint:nilnil // session index not present
+		}
+		return index, nil
+	},
+	columns.WithEventColumnDocs(
+		"Client Session Index",
+		"The number of sessions of the user counted by the tracker, extracted from the vid param or the sessionIndex of the client_session context.", // nolint:lll // it's a description
+	),
+)
+
+var eventParamsEventSchemaColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventParamsEventSchema.ID,
+	ProtocolInterfaces.EventParamsEventSchema.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		unstruct, err := unstructEvent(event.BoundHit.MustParsedRequest().QueryParams)
+		if err != nil || unstruct == nil {
+			return nil, nil //nolint:nilnil // not a self-describing event
+		}
+		return unstruct.Schema, nil
+	},
+	columns.WithEventColumnDocs(
+		"Event Schema",
+		"The Iglu schema of a self-describing event (e.g., 'iglu:com.snowplowanalytics.mobile/screen_view/jsonschema/1-0-0'), decoded from the ue_px or ue_pr param.", // nolint:lll // it's a description
+	),
+)
+
+var eventUnstructEventColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventUnstructEvent.ID,
+	ProtocolInterfaces.EventUnstructEvent.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		unstruct, err := unstructEvent(event.BoundHit.MustParsedRequest().QueryParams)
+		if err != nil || unstruct == nil {
+			return []any{}, nil
+		}
+		data, _ := decodeData(unstruct.Data)
+		return nameValueParams(data, nil), nil
+	},
+	columns.WithEventColumnDocs(
+		"Unstruct Event",
+		"The data of a self-describing event, decoded from the ue_px or ue_pr param. Nested objects are flattened with dots (e.g., 'product.sku'), numbers are written to value_number, all other values to value_string.", // nolint:lll // it's a description
+	),
+)
+
+var eventContextsColumn = columns.NewSimpleEventColumn(
+	ProtocolInterfaces.EventContexts.ID,
+	ProtocolInterfaces.EventContexts.Field,
+	func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+		contexts, err := decodeContexts(event.BoundHit.MustParsedRequest().QueryParams)
+		if err != nil {
+			return []any{}, nil
+		}
+		params := make([]any, 0)
+		for _, context := range contexts {
+			data, _ := decodeData(context.Data)
+			params = append(params, nameValueParams(data, map[string]any{"schema": context.Schema})...)
+		}
+		return params, nil
+	},
+	columns.WithEventColumnDocs(
+		"Contexts",
+		"The entities attached to the event, decoded from the cx or co param. Every value of a context is a separate item with the schema of the context, in the same shape as the unstruct_event column.", // nolint:lll // it's a description
+	),
+)
+
+// clientSessionValue returns a value of the client_session context of the event.
+func clientSessionValue(params url.Values, key string) any {
+	contexts, err := decodeContexts(params)
+	if err != nil {
+		return nil
+	}
+	for _, context := range contexts {
+		if !strings.HasPrefix(context.Schema, clientSessionSchemaPrefix) {
+			continue
+		}
+		data, ok := decodeData(context.Data)
+		if !ok {
+			return nil
+		}
+		return data[key]
+	}
+	return nil
+}
+
+// nameValueParams converts a JSON object to a list of name/value_string/value_number
+// structs, sorted by name. The extra fields are added to every struct.
+func nameValueParams(object map[string]any, extra map[string]any) []any {
+	params := make([]any, 0, len(object))
+	newParam := func(name string, valueString, valueNumber any) map[string]any {
+		param := map[string]any{"name": name, "value_string": valueString, "value_number": valueNumber}
+		for key, value := range extra {
+			param[key] = value
+		}
+		return param
+	}
+	var add func(prefix string, value any)
+	add = func(prefix string, value any) {
+		switch v := value.(type) {
+		case map[string]any:
+			for key, nested := range v {
+				add(prefix+"."+key, nested)
+			}
+		case nil:
+			return
+		case json.Number:
+			number, err := v.Float64()
+			if err != nil {
+				return
+			}
+			params = append(params, newParam(prefix, nil, number))
+		default:
+			str, ok := scalarToString(v)
+			if !ok {
+				return
+			}
+			params = append(params, newParam(prefix, str, nil))
+		}
+	}
+	for key, value := range object {
+		add(key, value)
+	}
+	slices.SortFunc(params, func(a, b any) int {
+		aMap, aOk := a.(map[string]any)
+		bMap, bOk := b.(map[string]any)
+		if !aOk || !bOk {
+			return 0
+		}
+		aName, _ := aMap["name"].(string)
+		bName, _ := bMap["name"].(string)
+		return strings.Compare(aName, bName)
+	})
+	return params
+}
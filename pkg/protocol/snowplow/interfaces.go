@@ -0,0 +1,107 @@
+package snowplow
+
+import (
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// ProtocolInterfaces are the columns specific to the Snowplow protocol.
+var ProtocolInterfaces = struct {
+	EventParamsAppID                  schema.Interface
+	EventParamsPlatform               schema.Interface
+	EventParamsTrackerVersion         schema.Interface
+	EventParamsEventID                schema.Interface
+	EventParamsDeviceCreatedTimestamp schema.Interface
+	EventParamsDomainSessionID        schema.Interface
+	EventParamsDomainSessionIndex     schema.Interface
+	EventParamsCategory               schema.Interface
+	EventParamsAction                 schema.Interface
+	EventParamsLabel                  schema.Interface
+	EventParamsProperty               schema.Interface
+	EventParamsValue                  schema.Interface
+	EventParamsEventSchema            schema.Interface
+	EventUnstructEvent                schema.Interface
+	EventContexts                     schema.Interface
+}{
+	EventParamsAppID: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_app_id",
+		Field: &arrow.Field{Name: "params_app_id", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsPlatform: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_platform",
+		Field: &arrow.Field{Name: "params_platform", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsTrackerVersion: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_tracker_version",
+		Field: &arrow.Field{Name: "params_tracker_version", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsEventID: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_event_id",
+		Field: &arrow.Field{Name: "params_event_id", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsDeviceCreatedTimestamp: schema.Interface{
+		ID: "snowplow.protocols.d8a.tech/event/params_device_created_timestamp",
+		Field: &arrow.Field{
+			Name:     "params_device_created_timestamp",
+			Type:     arrow.FixedWidthTypes.Timestamp_s,
+			Nullable: true,
+		},
+	},
+	EventParamsDomainSessionID: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_domain_session_id",
+		Field: &arrow.Field{Name: "params_domain_session_id", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsDomainSessionIndex: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_domain_session_index",
+		Field: &arrow.Field{Name: "params_domain_session_index", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+	},
+	EventParamsCategory: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_category",
+		Field: &arrow.Field{Name: "params_category", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsAction: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_action",
+		Field: &arrow.Field{Name: "params_action", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsLabel: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_label",
+		Field: &arrow.Field{Name: "params_label", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsProperty: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_property",
+		Field: &arrow.Field{Name: "params_property", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventParamsValue: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_value",
+		Field: &arrow.Field{Name: "params_value", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	},
+	EventParamsEventSchema: schema.Interface{
+		ID:    "snowplow.protocols.d8a.tech/event/params_event_schema",
+		Field: &arrow.Field{Name: "params_event_schema", Type: arrow.BinaryTypes.String, Nullable: true},
+	},
+	EventUnstructEvent: schema.Interface{
+		ID: "snowplow.protocols.d8a.tech/event/unstruct_event",
+		Field: &arrow.Field{
+			Name: "unstruct_event",
+			Type: arrow.ListOf(arrow.StructOf(
+				arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "value_string", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "value_number", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			)),
+			Nullable: true,
+		},
+	},
+	EventContexts: schema.Interface{
+		ID: "snowplow.protocols.d8a.tech/event/contexts",
+		Field: &arrow.Field{
+			Name: "contexts",
+			Type: arrow.ListOf(arrow.StructOf(
+				arrow.Field{Name: "schema", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "value_string", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "value_number", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			)),
+			Nullable: true,
+		},
+	},
+}
@@ -0,0 +1,306 @@
+// Package snowplow implements the Snowplow tracker protocol (tp2), as sent by the Snowplow
+// JavaScript, mobile and server trackers.
+package snowplow
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/schema"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	// postPath is the path of the tp2 endpoint, receiving batches of events as JSON.
+	postPath = "/com.snowplowanalytics.snowplow/tp2"
+	// pixelPath is the path of the pixel endpoint, receiving a single event as query params.
+	pixelPath = "/i"
+)
+
+// Values of the e param, naming the type of the event.
+const (
+	pageViewEventType    = "pv"
+	pagePingEventType    = "pp"
+	structuredEventType  = "se"
+	unstructEventType    = "ue"
+	transactionEventType = "tr"
+	transactionItemType  = "ti"
+)
+
+// Event names of the event types, which have no name in the event itself.
+const (
+	pagePingEventName    = "page_ping"
+	transactionEventName = "transaction"
+	transactionItemName  = "transaction_item"
+)
+
+type snowplowProtocol struct {
+	extractor protocol.PropertyIDExtractor
+	psr       properties.SettingsRegistry
+	cors      properties.CORSSettings
+}
+
+func (p *snowplowProtocol) ID() string {
+	return "snowplow"
+}
+
+// Hits creates a hit for every event of a request. Events of the tp2 endpoint are read
+// from the data array of the payload_data JSON, the event of the pixel endpoint from
+// the query params. Either way the tracker params of the event become the query params
+// of the hit.
+func (p *snowplowProtocol) Hits(fhCtx *fasthttp.RequestCtx, request *hits.ParsedRequest) ([]*hits.Hit, error) {
+	if request.Path == pixelPath {
+		p.setCORSHeaders(fhCtx, request.QueryParams)
+		hit, err := p.createHit(fhCtx, request, request.QueryParams)
+		if err != nil {
+			return nil, err
+		}
+		return []*hits.Hit{hit}, nil
+	}
+
+	events, err := decodePayloadData(request.Body)
+	if err != nil {
+		p.setCORSHeaders(fhCtx, nil)
+		return nil, err
+	}
+	p.setCORSHeaders(fhCtx, events[0])
+	theHits := make([]*hits.Hit, 0, len(events))
+	for idx, params := range events {
+		hit, err := p.createHit(fhCtx, request, params)
+		if err != nil {
+			return nil, fmt.Errorf("data[%d]: %w", idx, err)
+		}
+		theHits = append(theHits, hit)
+	}
+	return theHits, nil
+}
+
+func (p *snowplowProtocol) createHit(
+	fhCtx *fasthttp.RequestCtx,
+	request *hits.ParsedRequest,
+	params url.Values,
+) (*hits.Hit, error) {
+	requestCopy := request.Clone()
+	requestCopy.QueryParams = url.Values{}
+	for key, values := range params {
+		requestCopy.QueryParams[key] = append([]string(nil), values...)
+	}
+
+	eventName, err := deriveEventName(requestCopy.QueryParams)
+	if err != nil {
+		return nil, err
+	}
+
+	clientID := clientIDFromParams(requestCopy.QueryParams)
+	if clientID == "" {
+		return nil, errors.New("one of duid, client_session context or uid is required")
+	}
+
+	propertyID, err := p.extractor.PropertyID(&protocol.RequestContext{
+		Parsed:   requestCopy,
+		FastHttp: fhCtx,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	hit := hits.New()
+	hit.ClientID = hits.ClientID(clientID)
+	hit.AuthoritativeClientID = hit.ClientID
+	hit.PropertyID = propertyID
+	hit.EventName = eventName
+	if userID := requestCopy.QueryParams.Get("uid"); userID != "" {
+		hit.UserID = &userID
+	}
+	hit.Request = requestCopy
+
+	return hit, nil
+}
+
+const corsMethods = "POST, GET, OPTIONS"
+
+// setCORSHeaders sets the CORS headers of the property of the given event, or the default
+// ones if the property can't be told.
+func (p *snowplowProtocol) setCORSHeaders(fhCtx *fasthttp.RequestCtx, params url.Values) {
+	protocol.SetCORSHeaders(fhCtx, protocol.CORSSettingsFor(
+		&protocol.RequestContext{FastHttp: fhCtx, Parsed: &hits.ParsedRequest{QueryParams: params}},
+		p.extractor, p.psr, p.cors,
+	), corsMethods)
+}
+
+// DefaultCORSSettings implements protocol.CORSSettingsProvider. They are also the CORS
+// settings of preflights, which have no app ID.
+func (p *snowplowProtocol) DefaultCORSSettings() properties.CORSSettings {
+	return p.cors
+}
+
+func (p *snowplowProtocol) Endpoints() []protocol.ProtocolEndpoint {
+	return []protocol.ProtocolEndpoint{
+		{
+			Methods: []string{fasthttp.MethodPost},
+			Path:    postPath,
+		},
+		{
+			Methods:  []string{fasthttp.MethodOptions},
+			Path:     postPath,
+			IsCustom: true,
+			CustomHandler: func(ctx *fasthttp.RequestCtx) {
+				protocol.SetCORSHeaders(ctx, p.cors, corsMethods)
+				ctx.SetStatusCode(fasthttp.StatusNoContent)
+			},
+		},
+		{
+			Methods: []string{fasthttp.MethodGet},
+			Path:    pixelPath,
+		},
+	}
+}
+
+// PageLocation implements protocol.PageLocationProvider.
+func (p *snowplowProtocol) PageLocation(hit *hits.Hit) string {
+	return hit.MustParsedRequest().QueryParams.Get("url")
+}
+
+// SetPageLocation implements protocol.PageLocationProvider.
+func (p *snowplowProtocol) SetPageLocation(hit *hits.Hit, location string) {
+	hit.MustParsedRequest().QueryParams.Set("url", location)
+}
+
+func (p *snowplowProtocol) Interfaces() any {
+	return ProtocolInterfaces
+}
+
+func (p *snowplowProtocol) Columns() schema.Columns {
+	return schema.Columns{
+		Event:              eventColumns(newEventPageLocationColumn(p.psr)),
+		Session:            sessionColumns,
+		SessionScopedEvent: sseColumns,
+	}
+}
+
+// SnowplowProtocolOption configures the Snowplow protocol.
+type SnowplowProtocolOption func(*snowplowProtocol)
+
+// WithDefaultCORSSettings sets the CORS settings of requests whose property doesn't have
+// any, or can't be told. protocol.DefaultCORSSettings are used otherwise.
+func WithDefaultCORSSettings(cors properties.CORSSettings) SnowplowProtocolOption {
+	return func(p *snowplowProtocol) {
+		p.cors = cors
+	}
+}
+
+// NewSnowplowProtocol creates a protocol accepting events of the Snowplow tracker protocol.
+func NewSnowplowProtocol(
+	extractor protocol.PropertyIDExtractor,
+	psr properties.SettingsRegistry,
+	opts ...SnowplowProtocolOption,
+) protocol.Protocol {
+	p := &snowplowProtocol{extractor: extractor, psr: psr, cors: protocol.DefaultCORSSettings}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+type fromAppIDExtractor struct {
+	psr properties.SettingsRegistry
+}
+
+func (e *fromAppIDExtractor) PropertyID(ctx *protocol.RequestContext) (string, error) {
+	appID := ctx.Parsed.QueryParams.Get("aid")
+	if appID == "" {
+		return "", errors.New("missing aid")
+	}
+	property, err := e.psr.GetByMeasurementID(appID)
+	if err != nil {
+		return "", err
+	}
+	return property.PropertyID, nil
+}
+
+// NewFromAppIDExtractor creates a PropertyIDExtractor that resolves the property using the
+// app ID (the aid param) as the measurement ID.
+func NewFromAppIDExtractor(psr properties.SettingsRegistry) protocol.PropertyIDExtractor {
+	return &fromAppIDExtractor{psr: psr}
+}
+
+func deriveEventName(params url.Values) (string, error) {
+	switch eventType := params.Get("e"); eventType {
+	case pageViewEventType:
+		return protocol.PageViewEventType, nil
+	case pagePingEventType:
+		return pagePingEventName, nil
+	case structuredEventType:
+		action := params.Get("se_ac")
+		if action == "" {
+			return "", errors.New("structured event requires se_ac")
+		}
+		return action, nil
+	case unstructEventType:
+		event, err := unstructEvent(params)
+		if err != nil {
+			return "", err
+		}
+		if event == nil {
+			return "", errors.New("self-describing event requires ue_pr or ue_px")
+		}
+		_, name := schemaVendorAndName(event.Schema)
+		if name == "" {
+			return "", fmt.Errorf("invalid self-describing event schema %q", event.Schema)
+		}
+		return name, nil
+	case transactionEventType:
+		return transactionEventName, nil
+	case transactionItemType:
+		return transactionItemName, nil
+	case "":
+		return "", errors.New("e is required")
+	default:
+		return "", fmt.Errorf("unsupported event type %q", eventType)
+	}
+}
+
+// clientIDFromParams returns the domain user ID of web trackers, the user ID of the
+// client_session context sent by mobile trackers or, for server trackers, the user ID.
+func clientIDFromParams(params url.Values) string {
+	if duid := params.Get("duid"); duid != "" {
+		return duid
+	}
+	if userID, ok := clientSessionValue(params, "userId").(string); ok && userID != "" {
+		return userID
+	}
+	return params.Get("uid")
+}
+
+// decodePayloadData reads the events of a tp2 POST body. Tracker params are strings, but
+// other scalars are accepted too.
+func decodePayloadData(body []byte) ([]url.Values, error) {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var payload struct {
+		Data []map[string]any `json:"data"`
+	}
+	if err := decoder.Decode(&payload); err != nil {
+		return nil, fmt.Errorf("invalid JSON body: %w", err)
+	}
+	if len(payload.Data) == 0 {
+		return nil, errors.New("data must be a non-empty array of events")
+	}
+	events := make([]url.Values, 0, len(payload.Data))
+	for _, event := range payload.Data {
+		params := url.Values{}
+		for key, value := range event {
+			if str, ok := scalarToString(value); ok {
+				params.Set(key, str)
+			}
+		}
+		events = append(events, params)
+	}
+	return events, nil
+}
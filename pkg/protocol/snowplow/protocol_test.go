@@ -0,0 +1,176 @@
+package snowplow
+
+import (
+	"net/url"
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+const (
+	screenViewEvent      = `{"schema":"iglu:com.snowplowanalytics.snowplow/unstruct_event/jsonschema/1-0-0","data":{"schema":"iglu:com.snowplowanalytics.mobile/screen_view/jsonschema/1-0-0","data":{"name":"Home","id":"5e3b"}}}`                                          // nolint:lll // test data
+	clientSessionContext = `{"schema":"iglu:com.snowplowanalytics.snowplow/contexts/jsonschema/1-0-0","data":[{"schema":"iglu:com.snowplowanalytics.snowplow/client_session/jsonschema/1-0-2","data":{"userId":"device-user-1","sessionId":"session-1","sessionIndex":4}}]}` // nolint:lll // test data
+)
+
+// nolint:funlen,lll // test code
+func TestHits(t *testing.T) {
+	testCases := []struct {
+		name               string
+		request            *hits.ParsedRequest
+		expectedEventNames []string
+		expectedClientIDs  []hits.ClientID
+		expectedUserID     *string
+		expectError        bool
+	}{
+		{
+			name:               "pixel_page_view",
+			request:            testPixelRequest(url.Values{"e": {"pv"}, "aid": {"web"}, "duid": {"duid-1"}, "url": {"https://example.com/"}}),
+			expectedEventNames: []string{protocol.PageViewEventType},
+			expectedClientIDs:  []hits.ClientID{"duid-1"},
+		},
+		{
+			name: "post_batch",
+			request: testPostRequest(`{"schema":"iglu:com.snowplowanalytics.snowplow/payload_data/jsonschema/1-0-4","data":[
+				{"e":"se","aid":"web","duid":"duid-1","uid":"user-1","se_ca":"video","se_ac":"play"},
+				{"e":"pp","aid":"web","duid":"duid-1"},
+				{"e":"tr","aid":"web","duid":"duid-1","tr_id":"order-1","tr_tt":"10"}
+			]}`),
+			expectedEventNames: []string{"play", pagePingEventName, transactionEventName},
+			expectedClientIDs:  []hits.ClientID{"duid-1", "duid-1", "duid-1"},
+			expectedUserID:     func() *string { v := "user-1"; return &v }(),
+		},
+		{
+			name:               "mobile_self_describing_event_with_client_session",
+			request:            testPostRequest(`{"data":[{"e":"ue","p":"mob","aid":"app","ue_px":"` + encode(screenViewEvent) + `","cx":"` + encode(clientSessionContext) + `"}]}`),
+			expectedEventNames: []string{"screen_view"},
+			expectedClientIDs:  []hits.ClientID{"device-user-1"},
+		},
+		{
+			name:               "plain_json_self_describing_event",
+			request:            testPixelRequest(url.Values{"e": {"ue"}, "aid": {"web"}, "duid": {"duid-1"}, "ue_pr": {screenViewEvent}}),
+			expectedEventNames: []string{"screen_view"},
+			expectedClientIDs:  []hits.ClientID{"duid-1"},
+		},
+		{
+			name:               "server_event_falls_back_to_user_id",
+			request:            testPostRequest(`{"data":[{"e":"se","p":"srv","aid":"api","uid":"user-1","se_ca":"billing","se_ac":"renewed"}]}`),
+			expectedEventNames: []string{"renewed"},
+			expectedClientIDs:  []hits.ClientID{"user-1"},
+			expectedUserID:     func() *string { v := "user-1"; return &v }(),
+		},
+		{
+			name:        "missing_identifiers_returns_error",
+			request:     testPixelRequest(url.Values{"e": {"pv"}, "aid": {"web"}}),
+			expectError: true,
+		},
+		{
+			name:        "missing_event_type_returns_error",
+			request:     testPixelRequest(url.Values{"aid": {"web"}, "duid": {"duid-1"}}),
+			expectError: true,
+		},
+		{
+			name:        "self_describing_event_without_payload_returns_error",
+			request:     testPixelRequest(url.Values{"e": {"ue"}, "aid": {"web"}, "duid": {"duid-1"}}),
+			expectError: true,
+		},
+		{
+			name:        "structured_event_without_action_returns_error",
+			request:     testPixelRequest(url.Values{"e": {"se"}, "aid": {"web"}, "duid": {"duid-1"}, "se_ca": {"video"}}),
+			expectError: true,
+		},
+		{
+			name:        "empty_batch_returns_error",
+			request:     testPostRequest(`{"data":[]}`),
+			expectError: true,
+		},
+		{
+			name:        "invalid_json_returns_error",
+			request:     testPostRequest(`{"data":`),
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			snowplowProtocol := NewSnowplowProtocol(
+				&staticPropertyIDExtractor{propertyID: "test_property_id"},
+				testSettingsRegistry(),
+			)
+
+			// when
+			hitsResult, err := snowplowProtocol.Hits(&fasthttp.RequestCtx{}, tc.request)
+
+			// then
+			if tc.expectError {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, hitsResult, len(tc.expectedEventNames))
+			for index, hit := range hitsResult {
+				assert.Equal(t, tc.expectedEventNames[index], hit.EventName)
+				assert.Equal(t, tc.expectedClientIDs[index], hit.ClientID)
+				assert.Equal(t, hit.ClientID, hit.AuthoritativeClientID)
+				assert.Equal(t, "test_property_id", hit.PropertyID)
+			}
+			if tc.expectedUserID != nil {
+				require.NotNil(t, hitsResult[0].UserID)
+				assert.Equal(t, *tc.expectedUserID, *hitsResult[0].UserID)
+			}
+		})
+	}
+}
+
+func TestFromAppIDExtractor(t *testing.T) {
+	// given
+	psr := properties.NewStaticSettingsRegistry([]properties.Settings{
+		{PropertyID: "mobile", PropertyMeasurementID: "shop-ios"},
+	})
+	snowplowProtocol := NewSnowplowProtocol(NewFromAppIDExtractor(psr), psr)
+
+	// when
+	known, knownErr := snowplowProtocol.Hits(
+		&fasthttp.RequestCtx{},
+		testPixelRequest(url.Values{"e": {"pv"}, "aid": {"shop-ios"}, "duid": {"d"}}),
+	)
+	_, unknownErr := snowplowProtocol.Hits(
+		&fasthttp.RequestCtx{},
+		testPixelRequest(url.Values{"e": {"pv"}, "aid": {"other"}, "duid": {"d"}}),
+	)
+	_, missingErr := snowplowProtocol.Hits(
+		&fasthttp.RequestCtx{},
+		testPixelRequest(url.Values{"e": {"pv"}, "duid": {"d"}}),
+	)
+
+	// then
+	require.NoError(t, knownErr)
+	require.Len(t, known, 1)
+	assert.Equal(t, "mobile", known[0].PropertyID)
+	assert.Error(t, unknownErr)
+	assert.Error(t, missingErr)
+}
+
+func TestEndpoints(t *testing.T) {
+	// given
+	snowplowProtocol := NewSnowplowProtocol(&staticPropertyIDExtractor{}, testSettingsRegistry())
+
+	// when
+	endpoints := snowplowProtocol.Endpoints()
+
+	// then
+	routes := make([]string, 0, len(endpoints))
+	for _, endpoint := range endpoints {
+		routes = append(routes, endpoint.Methods[0]+" "+endpoint.Path)
+	}
+	assert.Equal(t, []string{
+		"POST /com.snowplowanalytics.snowplow/tp2",
+		"OPTIONS /com.snowplowanalytics.snowplow/tp2",
+		"GET /i",
+	}, routes)
+}
@@ -0,0 +1,112 @@
+package snowplow
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// clientSessionSchemaPrefix is the schema of the context, which mobile trackers use to
+// send the device user ID and the session.
+const clientSessionSchemaPrefix = "iglu:com.snowplowanalytics.snowplow/client_session/"
+
+// selfDescribingJSON is the envelope of Snowplow events and contexts, naming the Iglu
+// schema its data conforms to.
+type selfDescribingJSON struct {
+	Schema string          `json:"schema"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// unstructEvent returns the self-describing event of a ue event, sent either as JSON in
+// ue_pr or base64 encoded in ue_px. It returns nil if neither is present.
+func unstructEvent(params url.Values) (*selfDescribingJSON, error) {
+	raw, err := paramJSON(params, "ue_pr", "ue_px")
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	var envelope selfDescribingJSON
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, err
+	}
+	var event selfDescribingJSON
+	if err := json.Unmarshal(envelope.Data, &event); err != nil {
+		return nil, err
+	}
+	return &event, nil
+}
+
+// decodeContexts returns the contexts of an event, sent either as JSON in co or base64
+// encoded in cx.
+func decodeContexts(params url.Values) ([]selfDescribingJSON, error) {
+	raw, err := paramJSON(params, "co", "cx")
+	if err != nil || raw == nil {
+		return nil, err
+	}
+	var envelope struct {
+		Data []selfDescribingJSON `json:"data"`
+	}
+	if err := json.Unmarshal(raw, &envelope); err != nil {
+		return nil, err
+	}
+	return envelope.Data, nil
+}
+
+// paramJSON returns the JSON of the plain param or, if it's missing, the decoded base64
+// param. Trackers use the URL-safe alphabet, often without padding.
+func paramJSON(params url.Values, plainKey, encodedKey string) ([]byte, error) {
+	if plain := params.Get(plainKey); plain != "" {
+		return []byte(plain), nil
+	}
+	encoded := params.Get(encodedKey)
+	if encoded == "" {
+		return nil, nil
+	}
+	encoded = strings.TrimRight(encoded, "=")
+	if decoded, err := base64.RawURLEncoding.DecodeString(encoded); err == nil {
+		return decoded, nil
+	}
+	return base64.RawStdEncoding.DecodeString(encoded)
+}
+
+// schemaVendorAndName splits an Iglu URI like
+// iglu:com.snowplowanalytics.mobile/screen_view/jsonschema/1-0-0 into its vendor and name.
+func schemaVendorAndName(schemaURI string) (vendor, name string) {
+	parts := strings.Split(strings.TrimPrefix(schemaURI, "iglu:"), "/")
+	if len(parts) < 2 {
+		return "", ""
+	}
+	return parts[0], parts[1]
+}
+
+// decodeData decodes the data of a self-describing JSON, keeping numbers as json.Number.
+func decodeData(data json.RawMessage) (map[string]any, bool) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	var object map[string]any
+	if err := decoder.Decode(&object); err != nil || object == nil {
+		return nil, false
+	}
+	return object, true
+}
+
+func scalarToString(value any) (string, bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", false
+	case string:
+		return v, true
+	case json.Number:
+		return v.String(), true
+	case bool:
+		return strconv.FormatBool(v), true
+	default:
+		encoded, err := json.Marshal(v)
+		if err != nil {
+			return "", false
+		}
+		return string(encoded), true
+	}
+}
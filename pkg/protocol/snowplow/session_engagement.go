@@ -0,0 +1,91 @@
+//nolint:dupl,nilnil // unsupported columns share the same shape
+package snowplow
+
+import (
+	"fmt"
+
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/schema"
+)
+
+// Names of the built-in self-describing events, which map onto core session columns.
+const (
+	siteSearchEventName  = "site_search"
+	focusFormEventName   = "focus_form"
+	changeFormEventName  = "change_form"
+	submitFormEventName  = "submit_form"
+	formEventNamesString = focusFormEventName + ", " + changeFormEventName + ", " + submitFormEventName
+)
+
+// unsupportedSessionColumn creates a core session column, which the Snowplow trackers have
+// no built-in event for. It always writes null.
+func unsupportedSessionColumn(iface schema.Interface, title string) schema.SessionColumn {
+	return columns.NewSimpleSessionColumn(
+		iface.ID,
+		iface.Field,
+		func(_ *schema.Session) (any, schema.D8AColumnWriteError) {
+			return nil, nil
+		},
+		columns.WithSessionColumnDocs(
+			title,
+			"Not supported in the Snowplow protocol. The trackers have no built-in event for it, so it is always null.",
+		),
+	)
+}
+
+var sessionTotalPurchasesColumn = columns.TotalEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionTotalPurchases.ID,
+	columns.CoreInterfaces.SessionTotalPurchases.Field,
+	[]string{transactionEventName},
+	columns.WithSessionColumnDocs(
+		"Total Purchases",
+		fmt.Sprintf("The total number of transactions (event name: %s) in the session.", transactionEventName),
+	),
+)
+
+var sessionTotalSiteSearchesColumn = columns.TotalEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionTotalSiteSearches.ID,
+	columns.CoreInterfaces.SessionTotalSiteSearches.Field,
+	[]string{siteSearchEventName},
+	columns.WithSessionColumnDocs(
+		"Total Site Searches",
+		fmt.Sprintf("The total number of site searches (event name: %s) in the session.", siteSearchEventName),
+	),
+)
+
+var sessionTotalFormInteractionsColumn = columns.TotalEventsOfGivenNameColumn(
+	columns.CoreInterfaces.SessionTotalFormInteractions.ID,
+	columns.CoreInterfaces.SessionTotalFormInteractions.Field,
+	[]string{focusFormEventName, changeFormEventName, submitFormEventName},
+	columns.WithSessionColumnDocs(
+		"Total Form Interactions",
+		fmt.Sprintf("The total number of form interactions (event names: %s) in the session.", formEventNamesString),
+	),
+)
+
+var (
+	sessionUniqueSiteSearchesColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionUniqueSiteSearches, "Unique Site Searches",
+	)
+	sessionUniqueFormInteractionsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionUniqueFormInteractions, "Unique Form Interactions",
+	)
+	sessionTotalScrollsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalScrolls, "Total Scrolls",
+	)
+	sessionTotalOutboundClicksColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalOutboundClicks, "Total Outbound Clicks",
+	)
+	sessionUniqueOutboundClicksColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionUniqueOutboundClicks, "Unique Outbound Clicks",
+	)
+	sessionTotalVideoEngagementsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalVideoEngagements, "Total Video Engagements",
+	)
+	sessionTotalFileDownloadsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionTotalFileDownloads, "Total File Downloads",
+	)
+	sessionUniqueFileDownloadsColumn = unsupportedSessionColumn(
+		columns.CoreInterfaces.SessionUniqueFileDownloads, "Unique File Downloads",
+	)
+)
@@ -0,0 +1,64 @@
+package snowplow
+
+import (
+	"encoding/base64"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type staticPropertyIDExtractor struct {
+	propertyID string
+}
+
+func (e *staticPropertyIDExtractor) PropertyID(_ *protocol.RequestContext) (string, error) {
+	return e.propertyID, nil
+}
+
+func testSettingsRegistry(opts ...properties.TestSettingsOption) properties.SettingsRegistry {
+	return properties.NewTestSettingRegistry(opts...)
+}
+
+// testPostRequest creates a tp2 request with the given payload_data body.
+func testPostRequest(body string) *hits.ParsedRequest {
+	return &hits.ParsedRequest{
+		IP:                 "127.0.0.1",
+		Host:               "collector.example.com",
+		Path:               postPath,
+		Method:             fasthttp.MethodPost,
+		ServerReceivedTime: time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+		QueryParams:        url.Values{},
+		Headers:            http.Header{},
+		Body:               []byte(body),
+	}
+}
+
+// testPixelRequest creates a pixel request with the given query params.
+func testPixelRequest(params url.Values) *hits.ParsedRequest {
+	request := testPostRequest("")
+	request.Path = pixelPath
+	request.Method = fasthttp.MethodGet
+	request.QueryParams = params
+	return request
+}
+
+// encode returns the JSON base64 encoded the way the trackers do it.
+func encode(json string) string {
+	return base64.RawURLEncoding.EncodeToString([]byte(json))
+}
+
+// testHit parses a single pixel event into a hit, for use in column tests.
+func testHit(t *testing.T, params url.Values) *hits.Hit {
+	p := NewSnowplowProtocol(&staticPropertyIDExtractor{propertyID: "test_property_id"}, testSettingsRegistry())
+	theHits, err := p.Hits(&fasthttp.RequestCtx{}, testPixelRequest(params))
+	require.NoError(t, err)
+	require.Len(t, theHits, 1)
+	return theHits[0]
+}
@@ -12,7 +12,7 @@ func GetConflictCheckRequests(
 	settings *properties.Settings,
 ) []*IdentifierConflictRequest {
 	requests := make([]*IdentifierConflictRequest, 0)
-	if settings.SessionJoinBySessionStamp {
+	if joinsBySessionStamp(hit, settings) {
 		stamp, ok := GetIsolatedSessionStamp(hit)
 		if !ok {
 			logrus.Errorf("missing isolated session stamp metadata for hit %s, skipping conflict check", hit.ID)
@@ -58,6 +58,9 @@ func GetRemoveHitRelatedMetadataRequests(
 	sessionStampToHit := make(map[string]*hits.Hit)
 	if settings.SessionJoinBySessionStamp {
 		for _, hit := range protoSession {
+			if !joinsBySessionStamp(hit, settings) {
+				continue
+			}
 			sessionStamp, ok := GetIsolatedSessionStamp(hit)
 			if !ok {
 				logrus.Errorf("missing isolated session stamp metadata for hit %s, skipping metadata removal", hit.ID)
@@ -1,6 +1,10 @@
 package protosessions
 
-import "github.com/d8a-tech/d8a/pkg/hits"
+import (
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/privacysignals"
+	"github.com/d8a-tech/d8a/pkg/properties"
+)
 
 const (
 	MetaOriginalAuthoritativeClientIDKey = "original_authoritative_client_id"
@@ -39,6 +43,13 @@ func GetIsolatedClientID(hit *hits.Hit) hits.ClientID {
 	return hit.AuthoritativeClientID // fallback
 }
 
+// joinsBySessionStamp tells whether the hit is joined with others by its session stamp.
+// Hits stripped of identifiers aren't, their stamps would join every visitor with the
+// same user agent.
+func joinsBySessionStamp(hit *hits.Hit, settings *properties.Settings) bool {
+	return settings.SessionJoinBySessionStamp && !privacysignals.IdentifiersStripped(hit)
+}
+
 func SetIsolatedSessionStamp(hit *hits.Hit, stamp string) {
 	hit.Metadata[MetaIsolatedSessionStampKey] = stamp
 }
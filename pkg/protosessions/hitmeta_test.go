@@ -4,6 +4,8 @@ import (
 	"testing"
 
 	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/privacysignals"
+	"github.com/d8a-tech/d8a/pkg/properties"
 	"github.com/stretchr/testify/assert"
 )
 
@@ -94,3 +96,47 @@ func TestSetIsolatedUserIDStamp_GetIsolatedUserIDStamp(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConflictCheckRequests_StrippedHitsAreNotJoinedBySessionStamp(t *testing.T) {
+	t.Parallel()
+
+	tests := []struct {
+		name     string
+		stripped bool
+		want     []string
+	}{
+		{
+			name: "joins_by_session_stamp",
+			want: []string{"session_stamp"},
+		},
+		{
+			name:     "skips_session_stamp_of_stripped_hit",
+			stripped: true,
+			want:     []string{},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			t.Parallel()
+
+			// given
+			hit := hits.New()
+			SetIsolatedSessionStamp(hit, "stamp123")
+			if tt.stripped {
+				hit.Metadata[privacysignals.IdentifiersStrippedMetadataKey] = "true"
+			}
+			settings := &properties.Settings{SessionJoinBySessionStamp: true}
+
+			// when
+			requests := GetConflictCheckRequests(hit, settings)
+
+			// then
+			names := []string{}
+			for _, request := range requests {
+				names = append(names, request.IdentifierType)
+			}
+			assert.Equal(t, tt.want, names)
+		})
+	}
+}
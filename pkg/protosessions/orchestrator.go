@@ -386,7 +386,7 @@ func (o *Orchestrator) precomputeIsolatedSessionAndUserStamps(
 			return NewErrorCausingTaskRetry(err)
 		}
 		guard := o.identifierIsolationGuardFactory.New(settings)
-		if settings.SessionJoinBySessionStamp {
+		if joinsBySessionStamp(hit, settings) {
 			SetIsolatedSessionStamp(hit, guard.IsolatedSessionStamp(hit))
 		}
 		if settings.SessionJoinByUserID && hit.UserID != nil {
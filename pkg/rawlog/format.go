@@ -0,0 +1,178 @@
+// Package rawlog keeps a copy of every tracking request received by the receiver, as it
+// was received and with its original IP, only without ingestion credentials. Requests are
+// appended to a crash-safe spool and sealed into compressed, time-partitioned segment
+// files, uploaded to the local filesystem or object storage.
+package rawlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/fxamacker/cbor/v2"
+)
+
+// Format encodes requests as records of a segment and decodes them back.
+type Format interface {
+	// Extension returns the file extension of uncompressed segments, e.g. "cbor".
+	Extension() string
+	// Marshal encodes a single request as a record, records are concatenated in a segment.
+	Marshal(request *hits.ParsedRequest) ([]byte, error)
+	// NewDecoder returns a decoder reading consecutive records from r.
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Decoder reads records of a segment one by one.
+type Decoder interface {
+	// Decode reads the next request, returning io.EOF after the last one.
+	Decode() (*hits.ParsedRequest, error)
+}
+
+// NewFormat returns the format with the given name, either "cbor" or "ndjson".
+func NewFormat(name string) (Format, error) {
+	switch name {
+	case "cbor":
+		return NewCBORFormat(), nil
+	case "ndjson":
+		return NewNDJSONFormat(), nil
+	default:
+		return nil, fmt.Errorf("unsupported raw log format %q, expected cbor or ndjson", name)
+	}
+}
+
+type cborFormat struct {
+	encMode cbor.EncMode
+}
+
+// NewCBORFormat creates a format storing requests as a CBOR sequence (RFC 8742), using
+// the CBOR tags of hits.ParsedRequest. Timestamps keep nanosecond precision.
+func NewCBORFormat() Format {
+	encMode, err := cbor.EncOptions{Time: cbor.TimeRFC3339Nano}.EncMode()
+	if err != nil {
+		// The options are static, this can't happen
+		panic(err)
+	}
+	return &cborFormat{encMode: encMode}
+}
+
+func (f *cborFormat) Extension() string {
+	return "cbor"
+}
+
+func (f *cborFormat) Marshal(request *hits.ParsedRequest) ([]byte, error) {
+	return f.encMode.Marshal(request)
+}
+
+func (f *cborFormat) NewDecoder(r io.Reader) Decoder {
+	return &cborDecoder{decoder: cbor.NewDecoder(r)}
+}
+
+type cborDecoder struct {
+	decoder *cbor.Decoder
+}
+
+func (d *cborDecoder) Decode() (*hits.ParsedRequest, error) {
+	var request hits.ParsedRequest
+	if err := d.decoder.Decode(&request); err != nil {
+		return nil, err
+	}
+	return &request, nil
+}
+
+// ndjsonRecord is the JSON representation of a request. The body is base64 encoded, as
+// it's not guaranteed to be valid UTF-8.
+type ndjsonRecord struct {
+	IP                 string      `json:"ip"`
+	Host               string      `json:"host"`
+	ServerReceivedTime time.Time   `json:"server_received_time"`
+	Method             string      `json:"method"`
+	Path               string      `json:"path"`
+	QueryParams        url.Values  `json:"query_params"`
+	Headers            http.Header `json:"headers"`
+	Body               []byte      `json:"body"`
+}
+
+type ndjsonFormat struct{}
+
+// NewNDJSONFormat creates a format storing requests as newline delimited JSON, easy to
+// inspect with standard tools at the cost of a larger size.
+func NewNDJSONFormat() Format {
+	return &ndjsonFormat{}
+}
+
+func (f *ndjsonFormat) Extension() string {
+	return "ndjson"
+}
+
+func (f *ndjsonFormat) Marshal(request *hits.ParsedRequest) ([]byte, error) {
+	encoded, err := json.Marshal(ndjsonRecord{
+		IP:                 request.IP,
+		Host:               request.Host,
+		ServerReceivedTime: request.ServerReceivedTime,
+		Method:             request.Method,
+		Path:               request.Path,
+		QueryParams:        request.QueryParams,
+		Headers:            request.Headers,
+		Body:               request.Body,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return append(encoded, '\n'), nil
+}
+
+func (f *ndjsonFormat) NewDecoder(r io.Reader) Decoder {
+	return &ndjsonDecoder{decoder: json.NewDecoder(bufio.NewReader(r))}
+}
+
+type ndjsonDecoder struct {
+	decoder *json.Decoder
+}
+
+func (d *ndjsonDecoder) Decode() (*hits.ParsedRequest, error) {
+	var record ndjsonRecord
+	if err := d.decoder.Decode(&record); err != nil {
+		return nil, err
+	}
+	return &hits.ParsedRequest{
+		IP:                 record.IP,
+		Host:               record.Host,
+		ServerReceivedTime: record.ServerReceivedTime,
+		Method:             record.Method,
+		Path:               record.Path,
+		QueryParams:        record.QueryParams,
+		Headers:            record.Headers,
+		Body:               record.Body,
+	}, nil
+}
+
+// ReadSegment decodes a gzip compressed segment, calling fn for every request in the
+// order they were received.
+func ReadSegment(r io.Reader, format Format, fn func(*hits.ParsedRequest) error) error {
+	gz, err := gzip.NewReader(r)
+	if err != nil {
+		return fmt.Errorf("opening gzip reader: %w", err)
+	}
+	defer func() { _ = gz.Close() }()
+
+	decoder := format.NewDecoder(gz)
+	for {
+		request, err := decoder.Decode()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("decoding record: %w", err)
+		}
+		if err := fn(request); err != nil {
+			return err
+		}
+	}
+}
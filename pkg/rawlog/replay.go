@@ -0,0 +1,63 @@
+package rawlog
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+)
+
+// Read calls fn for every request received in [from, to), segment by segment in the order
+// they were sealed. Segments are sealed after the requests they hold were received, so the
+// ones sealed up to sealLag after to are read as well.
+func Read(
+	ctx context.Context,
+	source Source,
+	from, to time.Time,
+	sealLag time.Duration,
+	fn func(*hits.ParsedRequest) error,
+) error {
+	segments, err := source.Segments(ctx, from, to.Add(sealLag))
+	if err != nil {
+		return fmt.Errorf("listing segments: %w", err)
+	}
+	for _, segment := range segments {
+		if err := readSegment(ctx, source, segment, from, to, fn); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func readSegment(
+	ctx context.Context,
+	source Source,
+	segment Segment,
+	from, to time.Time,
+	fn func(*hits.ParsedRequest) error,
+) error {
+	format, err := FormatOf(segment.Key)
+	if err != nil {
+		return fmt.Errorf("segment %q: %w", segment.Key, err)
+	}
+	r, err := source.Open(ctx, segment.Key)
+	if err != nil {
+		return fmt.Errorf("opening segment %q: %w", segment.Key, err)
+	}
+	defer func() { _ = r.Close() }()
+
+	err = ReadSegment(r, format, func(request *hits.ParsedRequest) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if request.ServerReceivedTime.Before(from) || !request.ServerReceivedTime.Before(to) {
+			return nil
+		}
+		return fn(request)
+	})
+	if err != nil {
+		return fmt.Errorf("reading segment %q: %w", segment.Key, err)
+	}
+	return nil
+}
@@ -0,0 +1,39 @@
+package rawlog
+
+import (
+	"context"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Pruner deletes segments sealed before the given time, returning how many were deleted.
+type Pruner interface {
+	Prune(ctx context.Context, before time.Time) (int, error)
+}
+
+// RunRetention prunes segments older than retention right away and then every interval,
+// until the context is canceled.
+func RunRetention(ctx context.Context, pruner Pruner, retention, interval time.Duration) {
+	prune := func() {
+		deleted, err := pruner.Prune(ctx, time.Now().Add(-retention))
+		if err != nil {
+			logrus.WithError(err).Error("failed to prune raw log segments")
+		}
+		if deleted > 0 {
+			logrus.Infof("pruned %d raw log segments older than %s", deleted, retention)
+		}
+	}
+
+	prune()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			prune()
+		}
+	}
+}
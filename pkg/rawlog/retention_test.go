@@ -0,0 +1,69 @@
+package rawlog
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+)
+
+func TestFilesystemPrunerDeletesExpiredSegments(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	oldSegment := filepath.Join(dir, "y=2026", "m=01", "d=01", "1767225600_a.cbor.gz")
+	newSegment := filepath.Join(dir, "y=2026", "m=03", "d=07", "1772879400_b.cbor.gz")
+	otherFile := filepath.Join(dir, "y=2026", "m=01", "d=02", "README")
+	for _, path := range []string{oldSegment, newSegment, otherFile} {
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0o600))
+	}
+
+	// when
+	deleted, err := NewFilesystemPruner(dir).Prune(context.Background(), testSealTime.Add(-24*time.Hour))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	assert.NoFileExists(t, oldSegment)
+	assert.NoDirExists(t, filepath.Join(dir, "y=2026", "m=01", "d=01"))
+	assert.FileExists(t, newSegment)
+	assert.FileExists(t, otherFile)
+}
+
+func TestFilesystemPrunerMissingDirectory(t *testing.T) {
+	// when
+	deleted, err := NewFilesystemPruner(filepath.Join(t.TempDir(), "missing")).Prune(context.Background(), time.Now())
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 0, deleted)
+}
+
+func TestBlobPrunerDeletesExpiredSegments(t *testing.T) {
+	// given
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	t.Cleanup(func() { _ = bucket.Close() })
+	keys := []string{
+		"rawlog/y=2026/m=01/d=01/1767225600_a.ndjson.gz",
+		"rawlog/y=2026/m=03/d=07/1772879400_b.ndjson.gz",
+		"other/y=2026/m=01/d=01/1767225600_c.ndjson.gz",
+	}
+	for _, key := range keys {
+		require.NoError(t, bucket.WriteAll(ctx, key, []byte("x"), nil))
+	}
+
+	// when
+	deleted, err := NewBlobPruner(bucket, "rawlog").Prune(ctx, testSealTime.Add(-24*time.Hour))
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, 1, deleted)
+	remaining := listSegments(t, bucket)
+	assert.ElementsMatch(t, keys[1:], remaining)
+}
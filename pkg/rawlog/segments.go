@@ -0,0 +1,245 @@
+package rawlog
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gocloud.dev/blob"
+)
+
+// Segment is a sealed segment file.
+type Segment struct {
+	Key      string
+	SealTime time.Time
+}
+
+// Source lists and opens the segments of a raw log.
+type Source interface {
+	// Segments returns the segments sealed in [from, to), ordered by seal time.
+	Segments(ctx context.Context, from, to time.Time) ([]Segment, error)
+	// Open returns a reader of the compressed segment.
+	Open(ctx context.Context, key string) (io.ReadCloser, error)
+}
+
+// FormatOf returns the format of a segment, based on its extension.
+func FormatOf(key string) (Format, error) {
+	name := strings.TrimSuffix(path.Base(filepath.ToSlash(key)), segmentExtension)
+	return NewFormat(strings.TrimPrefix(path.Ext(name), "."))
+}
+
+// segmentSealTime reads the seal time from the name of a segment. Objects not named like
+// segments are reported as not ok, they're neither replayed nor deleted.
+func segmentSealTime(key string) (time.Time, bool) {
+	name := path.Base(filepath.ToSlash(key))
+	if !strings.HasSuffix(name, segmentExtension) {
+		return time.Time{}, false
+	}
+	unixPart, _, found := strings.Cut(name, "_")
+	if !found {
+		return time.Time{}, false
+	}
+	seconds, err := strconv.ParseInt(unixPart, 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(seconds, 0), true
+}
+
+func sortSegments(segments []Segment) {
+	sort.Slice(segments, func(i, j int) bool {
+		if !segments[i].SealTime.Equal(segments[j].SealTime) {
+			return segments[i].SealTime.Before(segments[j].SealTime)
+		}
+		return segments[i].Key < segments[j].Key
+	})
+}
+
+type filesystemSegments struct {
+	dir string
+}
+
+// NewFilesystemSource creates a Source reading segments stored in dir, the destination
+// directory of files.NewFilesystemUploader joined with the segment prefix.
+func NewFilesystemSource(dir string) Source {
+	return &filesystemSegments{dir: dir}
+}
+
+// NewFilesystemPruner creates a Pruner deleting segments stored in dir, the destination
+// directory of files.NewFilesystemUploader joined with the segment prefix.
+func NewFilesystemPruner(dir string) Pruner {
+	return &filesystemSegments{dir: dir}
+}
+
+// walk calls fn for every segment, dirs receives the directories below p.dir, parents
+// before their children.
+func (p *filesystemSegments) walk(
+	ctx context.Context,
+	fn func(segment Segment) error,
+	dirs func(dir string),
+) error {
+	err := filepath.WalkDir(p.dir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		if entry.IsDir() {
+			if filePath != p.dir && dirs != nil {
+				dirs(filePath)
+			}
+			return nil
+		}
+		sealTime, ok := segmentSealTime(filePath)
+		if !ok {
+			return nil
+		}
+		relPath, err := filepath.Rel(p.dir, filePath)
+		if err != nil {
+			return err
+		}
+		return fn(Segment{Key: filepath.ToSlash(relPath), SealTime: sealTime})
+	})
+	if err != nil {
+		return fmt.Errorf("walking segments in %q: %w", p.dir, err)
+	}
+	return nil
+}
+
+func (p *filesystemSegments) Segments(ctx context.Context, from, to time.Time) ([]Segment, error) {
+	var segments []Segment
+	err := p.walk(ctx, func(segment Segment) error {
+		if !segment.SealTime.Before(from) && segment.SealTime.Before(to) {
+			segments = append(segments, segment)
+		}
+		return nil
+	}, nil)
+	if err != nil {
+		return nil, err
+	}
+	sortSegments(segments)
+	return segments, nil
+}
+
+func (p *filesystemSegments) Open(_ context.Context, key string) (io.ReadCloser, error) {
+	return os.Open(filepath.Join(p.dir, filepath.FromSlash(key))) //nolint:gosec // key comes from the listing
+}
+
+func (p *filesystemSegments) Prune(ctx context.Context, before time.Time) (int, error) {
+	deleted := 0
+	var emptyCandidates []string
+	err := p.walk(ctx, func(segment Segment) error {
+		if !segment.SealTime.Before(before) {
+			return nil
+		}
+		filePath := filepath.Join(p.dir, filepath.FromSlash(segment.Key))
+		if err := os.Remove(filePath); err != nil {
+			return fmt.Errorf("removing segment %q: %w", filePath, err)
+		}
+		deleted++
+		return nil
+	}, func(dir string) {
+		emptyCandidates = append(emptyCandidates, dir)
+	})
+	if err != nil {
+		return deleted, err
+	}
+	// Partition directories left without segments are removed deepest first, removing
+	// a directory which still has files fails and is ignored.
+	for i := len(emptyCandidates) - 1; i >= 0; i-- {
+		_ = os.Remove(emptyCandidates[i])
+	}
+	return deleted, nil
+}
+
+type blobSegments struct {
+	bucket *blob.Bucket
+	prefix string
+}
+
+func newBlobSegments(bucket *blob.Bucket, prefix string) *blobSegments {
+	if prefix != "" && !strings.HasSuffix(prefix, "/") {
+		prefix += "/"
+	}
+	return &blobSegments{bucket: bucket, prefix: prefix}
+}
+
+// NewBlobSource creates a Source reading segments stored in the bucket under prefix.
+func NewBlobSource(bucket *blob.Bucket, prefix string) Source {
+	return newBlobSegments(bucket, prefix)
+}
+
+// NewBlobPruner creates a Pruner deleting segments stored in the bucket under prefix.
+func NewBlobPruner(bucket *blob.Bucket, prefix string) Pruner {
+	return newBlobSegments(bucket, prefix)
+}
+
+func (p *blobSegments) walk(ctx context.Context, fn func(segment Segment) error) error {
+	iter := p.bucket.List(&blob.ListOptions{Prefix: p.prefix})
+	for {
+		obj, err := iter.Next(ctx)
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("listing segments under %q: %w", p.prefix, err)
+		}
+		if obj.IsDir {
+			continue
+		}
+		sealTime, ok := segmentSealTime(obj.Key)
+		if !ok {
+			continue
+		}
+		if err := fn(Segment{Key: obj.Key, SealTime: sealTime}); err != nil {
+			return err
+		}
+	}
+}
+
+func (p *blobSegments) Segments(ctx context.Context, from, to time.Time) ([]Segment, error) {
+	var segments []Segment
+	err := p.walk(ctx, func(segment Segment) error {
+		if !segment.SealTime.Before(from) && segment.SealTime.Before(to) {
+			segments = append(segments, segment)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sortSegments(segments)
+	return segments, nil
+}
+
+func (p *blobSegments) Open(ctx context.Context, key string) (io.ReadCloser, error) {
+	return p.bucket.NewReader(ctx, key, nil)
+}
+
+func (p *blobSegments) Prune(ctx context.Context, before time.Time) (int, error) {
+	deleted := 0
+	err := p.walk(ctx, func(segment Segment) error {
+		if !segment.SealTime.Before(before) {
+			return nil
+		}
+		if err := p.bucket.Delete(ctx, segment.Key); err != nil {
+			return fmt.Errorf("deleting segment %q: %w", segment.Key, err)
+		}
+		deleted++
+		return nil
+	})
+	return deleted, err
+}
@@ -0,0 +1,176 @@
+package rawlog
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob/memblob"
+)
+
+func TestSegmentSealTime(t *testing.T) {
+	testCases := []struct {
+		name       string
+		key        string
+		expectedOK bool
+	}{
+		{name: "segment", key: "rawlog/y=2026/m=03/d=07/1772879400_abc.cbor.gz", expectedOK: true},
+		{name: "not_compressed", key: "rawlog/y=2026/m=03/d=07/1772879400_abc.cbor", expectedOK: false},
+		{name: "no_seal_time", key: "rawlog/y=2026/m=03/d=07/notes.gz", expectedOK: false},
+		{name: "temp_upload", key: "upload-123.tmp", expectedOK: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			sealTime, ok := segmentSealTime(tc.key)
+
+			// then
+			assert.Equal(t, tc.expectedOK, ok)
+			if tc.expectedOK {
+				assert.Equal(t, testSealTime.Unix(), sealTime.Unix())
+			}
+		})
+	}
+}
+
+func TestFormatOf(t *testing.T) {
+	testCases := []struct {
+		name        string
+		key         string
+		expectedExt string
+		expectError bool
+	}{
+		{name: "cbor", key: "rawlog/y=2026/m=03/d=07/1772879400_abc.cbor.gz", expectedExt: "cbor"},
+		{name: "ndjson", key: "1772879400_abc.ndjson.gz", expectedExt: "ndjson"},
+		{name: "unknown", key: "1772879400_abc.csv.gz", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			format, err := FormatOf(tc.key)
+
+			// then
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedExt, format.Extension())
+		})
+	}
+}
+
+// testSegment returns a compressed segment holding requests received at the given times.
+func testSegment(t *testing.T, format Format, receivedAt ...time.Time) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	for i, at := range receivedAt {
+		request := testRequest("10.0.0." + string(rune('1'+i)))
+		request.ServerReceivedTime = at
+		record, err := format.Marshal(request)
+		require.NoError(t, err)
+		_, err = gz.Write(record)
+		require.NoError(t, err)
+	}
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestFilesystemSourceListsSegmentsInRange(t *testing.T) {
+	// given
+	dir := t.TempDir()
+	keys := []string{
+		"y=2026/m=03/d=07/1772879400_b.cbor.gz",
+		"y=2026/m=01/d=01/1767225600_a.cbor.gz",
+		"y=2026/m=03/d=08/1772965800_c.cbor.gz",
+		"y=2026/m=01/d=02/README",
+	}
+	for _, key := range keys {
+		path := filepath.Join(dir, filepath.FromSlash(key))
+		require.NoError(t, os.MkdirAll(filepath.Dir(path), 0o750))
+		require.NoError(t, os.WriteFile(path, []byte("x"), 0o600))
+	}
+
+	// when
+	segments, err := NewFilesystemSource(dir).Segments(
+		context.Background(), time.Unix(1767225600, 0), time.Unix(1772965800, 0),
+	)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, segments, 2)
+	assert.Equal(t, keys[1], segments[0].Key)
+	assert.Equal(t, keys[0], segments[1].Key)
+}
+
+func TestRead(t *testing.T) {
+	// given
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	t.Cleanup(func() { _ = bucket.Close() })
+	from := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	to := from.Add(time.Hour)
+	segments := map[string][]byte{
+		// Sealed after from, holding a request received before it
+		"rawlog/y=2026/m=03/d=07/1772877660_a.cbor.gz": testSegment(t, NewCBORFormat(),
+			from.Add(-time.Minute), from.Add(time.Minute)),
+		"rawlog/y=2026/m=03/d=07/1772879400_b.ndjson.gz": testSegment(t, NewNDJSONFormat(),
+			from.Add(30*time.Minute)),
+		// Sealed after to, holding a request received before it
+		"rawlog/y=2026/m=03/d=07/1772881260_c.cbor.gz": testSegment(t, NewCBORFormat(),
+			to.Add(-time.Second), to),
+		// Sealed too late to hold requests from the range
+		"rawlog/y=2026/m=03/d=07/1772888400_d.cbor.gz": testSegment(t, NewCBORFormat(),
+			to.Add(-time.Second)),
+	}
+	for key, content := range segments {
+		require.NoError(t, bucket.WriteAll(ctx, key, content, nil))
+	}
+
+	// when
+	var received []time.Time
+	err := Read(ctx, NewBlobSource(bucket, "rawlog"), from, to, 15*time.Minute,
+		func(request *hits.ParsedRequest) error {
+			received = append(received, request.ServerReceivedTime.UTC())
+			return nil
+		})
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, []time.Time{
+		from.Add(time.Minute),
+		from.Add(30 * time.Minute),
+		to.Add(-time.Second),
+	}, received)
+}
+
+func TestReadStopsOnError(t *testing.T) {
+	// given
+	ctx := context.Background()
+	bucket := memblob.OpenBucket(nil)
+	t.Cleanup(func() { _ = bucket.Close() })
+	require.NoError(t, bucket.WriteAll(ctx, "rawlog/1772879400_a.cbor.gz",
+		testSegment(t, NewCBORFormat(), testSealTime.Add(-time.Second), testSealTime.Add(-time.Second)), nil))
+
+	// when
+	calls := 0
+	err := Read(ctx, NewBlobSource(bucket, "rawlog"), time.Time{}, testSealTime, time.Minute,
+		func(*hits.ParsedRequest) error {
+			calls++
+			return assert.AnError
+		})
+
+	// then
+	assert.ErrorIs(t, err, assert.AnError)
+	assert.Equal(t, 1, calls)
+}
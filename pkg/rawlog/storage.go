@@ -0,0 +1,158 @@
+package rawlog
+
+import (
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/spools"
+	"github.com/d8a-tech/d8a/pkg/warehouse/files"
+	"github.com/google/uuid"
+)
+
+// spoolKey is the single key raw requests are appended under.
+const spoolKey = "rawlog"
+
+// segmentExtension is appended to the format extension, all segments are gzip compressed.
+const segmentExtension = ".gz"
+
+// DefaultPrefix is the default key prefix of segments.
+const DefaultPrefix = "rawlog"
+
+// StorageOption configures a Storage.
+type StorageOption func(*Storage)
+
+// WithFormat sets the format of segments, CBOR by default.
+func WithFormat(format Format) StorageOption {
+	return func(s *Storage) {
+		s.format = format
+	}
+}
+
+// WithPrefix sets the key prefix of segments.
+func WithPrefix(prefix string) StorageOption {
+	return func(s *Storage) {
+		s.prefix = prefix
+	}
+}
+
+// WithCompressionLevel sets the gzip compression level of segments.
+func WithCompressionLevel(level int) StorageOption {
+	return func(s *Storage) {
+		s.compressionLevel = level
+	}
+}
+
+// WithNowFunc sets the clock used for naming segments, used in tests.
+func WithNowFunc(now func() time.Time) StorageOption {
+	return func(s *Storage) {
+		s.nowFunc = now
+	}
+}
+
+// Storage appends raw requests to a spool. Every sealed spool file (see
+// spools.WithMaxActiveSize and spools.WithFlushInterval) is uploaded as one gzip
+// compressed segment, under <prefix>/y=<year>/m=<month>/d=<day>/<unix seal time>_<uuid>.
+type Storage struct {
+	spool            spools.Spool
+	uploader         files.StreamUploader
+	format           Format
+	prefix           string
+	compressionLevel int
+	nowFunc          func() time.Time
+}
+
+// NewStorage creates a Storage using the spool created by the factory. Closing the
+// factory flushes the remaining requests.
+func NewStorage(
+	spoolFactory spools.Factory,
+	uploader files.StreamUploader,
+	opts ...StorageOption,
+) (*Storage, error) {
+	s := &Storage{
+		uploader:         uploader,
+		format:           NewCBORFormat(),
+		prefix:           DefaultPrefix,
+		compressionLevel: gzip.DefaultCompression,
+		nowFunc:          time.Now,
+	}
+	for _, opt := range opts {
+		opt(s)
+	}
+	if _, err := gzip.NewWriterLevel(io.Discard, s.compressionLevel); err != nil {
+		return nil, fmt.Errorf("invalid compression level: %w", err)
+	}
+
+	spool, err := spoolFactory.Create(s.flush)
+	if err != nil {
+		return nil, fmt.Errorf("creating spool: %w", err)
+	}
+	s.spool = spool
+	return s, nil
+}
+
+// Store implements receiver.RawLogStorage.
+func (s *Storage) Store(request *hits.ParsedRequest) error {
+	record, err := s.format.Marshal(request)
+	if err != nil {
+		return fmt.Errorf("encoding raw request: %w", err)
+	}
+	return s.spool.Append(spoolKey, record)
+}
+
+//nolint:contextcheck // flush handler signature has no context; use non-canceled context per invocation.
+func (s *Storage) flush(_ string, next func() ([][]byte, error)) error {
+	remoteKey := s.segmentKey(s.nowFunc().UTC())
+	upload, err := s.uploader.Begin(context.Background(), remoteKey)
+	if err != nil {
+		return fmt.Errorf("beginning upload for key %s: %w", remoteKey, err)
+	}
+
+	abortWith := func(cause error) error {
+		if abortErr := upload.Abort(); abortErr != nil {
+			return errors.Join(cause, fmt.Errorf("aborting upload: %w", abortErr))
+		}
+		return cause
+	}
+
+	gz, err := gzip.NewWriterLevel(upload.Writer(), s.compressionLevel)
+	if err != nil {
+		return abortWith(fmt.Errorf("creating gzip writer: %w", err))
+	}
+	for {
+		frames, err := next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return abortWith(fmt.Errorf("reading spool frames: %w", err))
+		}
+		for _, frame := range frames {
+			if _, err := gz.Write(frame); err != nil {
+				return abortWith(fmt.Errorf("writing segment: %w", err))
+			}
+		}
+	}
+	if err := gz.Close(); err != nil {
+		return abortWith(fmt.Errorf("closing gzip writer: %w", err))
+	}
+	if err := upload.Commit(); err != nil {
+		return abortWith(fmt.Errorf("committing upload: %w", err))
+	}
+	return nil
+}
+
+func (s *Storage) segmentKey(sealTime time.Time) string {
+	return path.Join(
+		s.prefix,
+		fmt.Sprintf("y=%d", sealTime.Year()),
+		fmt.Sprintf("m=%02d", sealTime.Month()),
+		fmt.Sprintf("d=%02d", sealTime.Day()),
+		fmt.Sprintf("%d_%s.%s%s", sealTime.Unix(), uuid.NewString(), s.format.Extension(), segmentExtension),
+	)
+}
@@ -0,0 +1,171 @@
+package rawlog
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/spools"
+	"github.com/d8a-tech/d8a/pkg/warehouse/files"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gocloud.dev/blob"
+	"gocloud.dev/blob/memblob"
+)
+
+var testSealTime = time.Date(2026, 3, 7, 10, 30, 0, 0, time.UTC)
+
+func testRequest(ip string) *hits.ParsedRequest {
+	return &hits.ParsedRequest{
+		IP:                 ip,
+		Host:               "d8a.example.com",
+		ServerReceivedTime: time.Date(2026, 3, 7, 10, 29, 59, 123456789, time.UTC),
+		QueryParams:        url.Values{"v": {"2"}, "tid": {"G-2VEWJC5YPE"}, "en": {"page_view"}},
+		Body:               []byte{0xff, 0x00, 'e', 'n', '=', 'x'},
+		Path:               "/g/collect",
+		Method:             http.MethodPost,
+		Headers:            http.Header{"User-Agent": {"Mozilla/5.0"}, "Accept-Language": {"pl-PL"}},
+	}
+}
+
+func listSegments(t *testing.T, bucket *blob.Bucket) []string {
+	t.Helper()
+	var keys []string
+	iter := bucket.List(nil)
+	for {
+		obj, err := iter.Next(context.Background())
+		if errors.Is(err, io.EOF) {
+			return keys
+		}
+		require.NoError(t, err)
+		keys = append(keys, obj.Key)
+	}
+}
+
+func TestStorageWritesSegments(t *testing.T) {
+	testCases := []struct {
+		name           string
+		format         Format
+		expectedSuffix string
+	}{
+		{name: "cbor", format: NewCBORFormat(), expectedSuffix: ".cbor.gz"},
+		{name: "ndjson", format: NewNDJSONFormat(), expectedSuffix: ".ndjson.gz"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			bucket := memblob.OpenBucket(nil)
+			t.Cleanup(func() { _ = bucket.Close() })
+			factory, err := spools.NewFileFactory(afero.NewMemMapFs(), "/spool", spools.WithFlushOnClose(true))
+			require.NoError(t, err)
+			storage, err := NewStorage(
+				factory,
+				files.NewBlobUploader(bucket),
+				WithFormat(tc.format),
+				WithNowFunc(func() time.Time { return testSealTime }),
+			)
+			require.NoError(t, err)
+
+			// when
+			require.NoError(t, storage.Store(testRequest("10.0.0.1")))
+			require.NoError(t, storage.Store(testRequest("10.0.0.2")))
+			require.NoError(t, factory.Close())
+
+			// then
+			keys := listSegments(t, bucket)
+			require.Len(t, keys, 1)
+			assert.True(t, strings.HasPrefix(keys[0], "rawlog/y=2026/m=03/d=07/1772879400_"), keys[0])
+			assert.True(t, strings.HasSuffix(keys[0], tc.expectedSuffix), keys[0])
+
+			segment, err := bucket.ReadAll(context.Background(), keys[0])
+			require.NoError(t, err)
+			var decoded []*hits.ParsedRequest
+			require.NoError(t, ReadSegment(bytes.NewReader(segment), tc.format, func(r *hits.ParsedRequest) error {
+				decoded = append(decoded, r)
+				return nil
+			}))
+			require.Len(t, decoded, 2)
+			for i, ip := range []string{"10.0.0.1", "10.0.0.2"} {
+				expected := testRequest(ip)
+				assert.Equal(t, expected.IP, decoded[i].IP)
+				assert.Equal(t, expected.Host, decoded[i].Host)
+				assert.True(t, expected.ServerReceivedTime.Equal(decoded[i].ServerReceivedTime))
+				assert.Equal(t, expected.QueryParams, decoded[i].QueryParams)
+				assert.Equal(t, expected.Body, decoded[i].Body)
+				assert.Equal(t, expected.Path, decoded[i].Path)
+				assert.Equal(t, expected.Method, decoded[i].Method)
+				assert.Equal(t, expected.Headers, decoded[i].Headers)
+			}
+		})
+	}
+}
+
+func TestStorageRotatesBySize(t *testing.T) {
+	// given
+	destDir := t.TempDir()
+	uploader, err := files.NewFilesystemUploader(destDir)
+	require.NoError(t, err)
+	factory, err := spools.NewFileFactory(
+		afero.NewMemMapFs(),
+		"/spool",
+		spools.WithMaxActiveSize(1),
+		spools.WithFlushOnClose(true),
+	)
+	require.NoError(t, err)
+	storage, err := NewStorage(factory, uploader)
+	require.NoError(t, err)
+
+	// when
+	for _, ip := range []string{"10.0.0.1", "10.0.0.2", "10.0.0.3"} {
+		require.NoError(t, storage.Store(testRequest(ip)))
+	}
+	require.NoError(t, factory.Close())
+
+	// then
+	var segments []string
+	require.NoError(t, filepath.WalkDir(destDir, func(filePath string, entry fs.DirEntry, err error) error {
+		if err == nil && !entry.IsDir() {
+			segments = append(segments, filePath)
+		}
+		return err
+	}))
+	assert.Len(t, segments, 3)
+}
+
+func TestNewFormat(t *testing.T) {
+	testCases := []struct {
+		name        string
+		expectedExt string
+		expectError bool
+	}{
+		{name: "cbor", expectedExt: "cbor"},
+		{name: "ndjson", expectedExt: "ndjson"},
+		{name: "csv", expectError: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			format, err := NewFormat(tc.name)
+
+			// then
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedExt, format.Extension())
+		})
+	}
+}
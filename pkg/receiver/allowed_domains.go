@@ -0,0 +1,109 @@
+package receiver
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+
+	"github.com/d8a-tech/d8a/pkg/botdetection"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+)
+
+// DomainViolationMetadataKey is the hit metadata key holding where a hit from a domain
+// outside the allowed domains was found, e.g. "origin evil.example", set for properties
+// which only report the violations.
+const DomainViolationMetadataKey = "domain_violation"
+
+type hitURLSource struct {
+	name string
+	url  string
+}
+
+// HitFromAllowedDomain checks that the page hostname, Origin and Referer of hits are in the
+// allowed domains of the property. Missing ones aren't checked, but hits missing all of
+// them violate the allowed domains too, unless they were sent to an authenticated endpoint,
+// like the ones of server-side callers. For properties in report-only mode, violating hits
+// are kept with the DomainViolationMetadataKey and the traffic filter name set.
+func HitFromAllowedDomain(settings properties.SettingsRegistry) HitValidatingRule {
+	return NewSimpleHitValidatingRule(func(p protocol.Protocol, hit *hits.Hit) error {
+		propertySettings, err := settings.GetByPropertyID(hit.PropertyID)
+		if err != nil {
+			return err
+		}
+		if len(propertySettings.AllowedDomains) == 0 {
+			return nil
+		}
+
+		request := hit.MustParsedRequest()
+		sources := []hitURLSource{
+			{name: "origin", url: request.Headers.Get("Origin")},
+			{name: "referer", url: request.Headers.Get("Referer")},
+		}
+		if provider, ok := p.(protocol.PageLocationProvider); ok {
+			sources = append(sources, hitURLSource{name: "page", url: provider.PageLocation(hit)})
+		}
+
+		checked := false
+		for _, source := range sources {
+			if source.url == "" {
+				continue
+			}
+			checked = true
+			hostname := urlHostname(source.url)
+			if domainAllowed(hostname, propertySettings.AllowedDomains) {
+				continue
+			}
+			if !propertySettings.AllowedDomainsReportOnly {
+				return newClientError(fmt.Sprintf("%s domain %q is not allowed for the property", source.name, hostname))
+			}
+			reportDomainViolation(hit, source.name+" "+hostname, "domain not allowed: "+hostname)
+			return nil
+		}
+		if checked || requestAuthenticated(request) {
+			return nil
+		}
+		if !propertySettings.AllowedDomainsReportOnly {
+			return newClientError("hits without an origin, referer or page location are not allowed for the property")
+		}
+		reportDomainViolation(hit, "missing", "domain missing")
+		return nil
+	})
+}
+
+func reportDomainViolation(hit *hits.Hit, violation, trafficFilterName string) {
+	hit.Metadata[DomainViolationMetadataKey] = violation
+	if _, ok := hit.Metadata[botdetection.TrafficFilterNameMetadataKey]; !ok {
+		hit.Metadata[botdetection.TrafficFilterNameMetadataKey] = trafficFilterName
+	}
+}
+
+func urlHostname(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(u.Hostname())
+}
+
+// domainAllowed tells whether the hostname is one of the allowed domains, "*.example.com"
+// matching any subdomain of example.com but not example.com itself.
+func domainAllowed(hostname string, allowed []string) bool {
+	if hostname == "" {
+		return false
+	}
+	for _, domain := range allowed {
+		domain = strings.ToLower(domain)
+		if suffix, ok := strings.CutPrefix(domain, "*"); ok {
+			if strings.HasSuffix(hostname, suffix) {
+				return true
+			}
+			continue
+		}
+		if hostname == domain {
+			return true
+		}
+	}
+	return false
+}
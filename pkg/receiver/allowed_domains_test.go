@@ -0,0 +1,153 @@
+package receiver
+
+import (
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/botdetection"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type pageLocationProtocol struct {
+	mockProtocol
+}
+
+func (p *pageLocationProtocol) PageLocation(hit *hits.Hit) string {
+	return hit.MustParsedRequest().QueryParams.Get("dl")
+}
+
+func (p *pageLocationProtocol) SetPageLocation(hit *hits.Hit, location string) {
+	hit.MustParsedRequest().QueryParams.Set("dl", location)
+}
+
+func TestHitFromAllowedDomain(t *testing.T) {
+	testCases := []struct {
+		name             string
+		allowedDomains   []string
+		reportOnly       bool
+		origin           string
+		referer          string
+		pageLocation     string
+		authenticated    bool
+		expectError      bool
+		expectedMetadata map[string]string
+	}{
+		{
+			name:             "no allowed domains accepts anything",
+			origin:           "https://evil.example",
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name:             "exact domain",
+			allowedDomains:   []string{"shop.example.com"},
+			origin:           "https://shop.example.com",
+			referer:          "https://SHOP.example.com/cart",
+			pageLocation:     "https://shop.example.com:8443/cart?step=2",
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name:             "wildcard matches subdomains",
+			allowedDomains:   []string{"*.example.com"},
+			origin:           "https://eu.shop.example.com",
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name:           "wildcard does not match the apex domain",
+			allowedDomains: []string{"*.example.com"},
+			origin:         "https://example.com",
+			expectError:    true,
+		},
+		{
+			name:           "wildcard does not match lookalike domains",
+			allowedDomains: []string{"*.example.com"},
+			origin:         "https://evilexample.com",
+			expectError:    true,
+		},
+		{
+			name:           "page location from another domain",
+			allowedDomains: []string{"shop.example.com"},
+			origin:         "https://shop.example.com",
+			pageLocation:   "https://copycat.example/",
+			expectError:    true,
+		},
+		{
+			name:             "missing sources are not checked",
+			allowedDomains:   []string{"shop.example.com"},
+			referer:          "https://shop.example.com/cart",
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name:           "all sources missing",
+			allowedDomains: []string{"shop.example.com"},
+			expectError:    true,
+		},
+		{
+			name:             "all sources missing from an authenticated request",
+			allowedDomains:   []string{"shop.example.com"},
+			authenticated:    true,
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name:           "report only keeps the hit with all sources missing",
+			allowedDomains: []string{"shop.example.com"},
+			reportOnly:     true,
+			expectedMetadata: map[string]string{
+				DomainViolationMetadataKey:                "missing",
+				botdetection.TrafficFilterNameMetadataKey: "domain missing",
+			},
+		},
+		{
+			name:           "report only keeps the hit",
+			allowedDomains: []string{"shop.example.com"},
+			reportOnly:     true,
+			referer:        "https://copycat.example/page",
+			expectedMetadata: map[string]string{
+				DomainViolationMetadataKey:                "referer copycat.example",
+				botdetection.TrafficFilterNameMetadataKey: "domain not allowed: copycat.example",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+				"test_property_id": {
+					PropertyID:               "test_property_id",
+					AllowedDomains:           tc.allowedDomains,
+					AllowedDomainsReportOnly: tc.reportOnly,
+				},
+			}}
+			hit := hits.New()
+			hit.PropertyID = "test_property_id"
+			if tc.origin != "" {
+				hit.Request.Headers.Set("Origin", tc.origin)
+			}
+			if tc.referer != "" {
+				hit.Request.Headers.Set("Referer", tc.referer)
+			}
+			if tc.pageLocation != "" {
+				hit.Request.QueryParams.Set("dl", tc.pageLocation)
+			}
+			if tc.authenticated {
+				hit.Request.Headers.Set(PropertyIDHeader, "test_property_id")
+			}
+			var p protocol.Protocol = &pageLocationProtocol{mockProtocol{id: "test_protocol"}}
+
+			// when
+			err := HitFromAllowedDomain(settings).Validate(p, hit)
+
+			// then
+			if tc.expectError {
+				var clientErr safeClientError
+				assert.ErrorAs(t, err, &clientErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMetadata, hit.Metadata)
+		})
+	}
+}
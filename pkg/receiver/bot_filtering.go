@@ -0,0 +1,43 @@
+package receiver
+
+import (
+	"errors"
+
+	"github.com/d8a-tech/d8a/pkg/botdetection"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+)
+
+// ErrHitDropped is returned by hit processing rules for hits which should not be stored.
+// The request is still accepted, so that the sender can't tell its hits were dropped.
+var ErrHitDropped = errors.New("hit dropped")
+
+// BotFiltering returns a hit processing rule classifying hits as bot traffic, per the
+// bot filter mode of the property. Tagged hits get the botdetection metadata, read by the
+// is_bot and traffic_filter_name columns. It needs to run before IP masking, for IP ranges
+// to match. The raw log keeps the original IP, so replayed hits get the same verdict.
+func BotFiltering(settings properties.SettingsRegistry, detector botdetection.Detector) HitProcessingRule {
+	return NewSimpleHitProcessingRule(func(_ protocol.Protocol, hit *hits.Hit) error {
+		propertySettings, err := settings.GetByPropertyID(hit.PropertyID)
+		if err != nil {
+			return err
+		}
+
+		mode := propertySettings.BotFilterMode
+		if mode == "" || mode == properties.BotFilterAllow {
+			return nil
+		}
+
+		name, isBot := detector.Detect(hit.MustParsedRequest())
+		if isBot && mode == properties.BotFilterDrop {
+			return ErrHitDropped
+		}
+		hit.Metadata[botdetection.IsBotMetadataKey] = "false"
+		if isBot {
+			hit.Metadata[botdetection.IsBotMetadataKey] = "true"
+			hit.Metadata[botdetection.TrafficFilterNameMetadataKey] = "bot: " + name
+		}
+		return nil
+	})
+}
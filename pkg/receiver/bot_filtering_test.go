@@ -0,0 +1,184 @@
+package receiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/botdetection"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type userAgentBotDetector struct{}
+
+func (userAgentBotDetector) Detect(request *hits.ParsedRequest) (string, bool) {
+	if request.Headers.Get("User-Agent") == "crawler" {
+		return "Crawler", true
+	}
+	return "", false
+}
+
+func TestBotFiltering(t *testing.T) {
+	testCases := []struct {
+		name             string
+		mode             properties.BotFilterMode
+		userAgent        string
+		expectedErr      error
+		expectedMetadata map[string]string
+	}{
+		{
+			name:             "allow does not classify bots",
+			mode:             properties.BotFilterAllow,
+			userAgent:        "crawler",
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name:             "unset mode allows bots",
+			userAgent:        "crawler",
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name:      "tag marks bots",
+			mode:      properties.BotFilterTag,
+			userAgent: "crawler",
+			expectedMetadata: map[string]string{
+				botdetection.IsBotMetadataKey:             "true",
+				botdetection.TrafficFilterNameMetadataKey: "bot: Crawler",
+			},
+		},
+		{
+			name:      "tag marks humans",
+			mode:      properties.BotFilterTag,
+			userAgent: "browser",
+			expectedMetadata: map[string]string{
+				botdetection.IsBotMetadataKey: "false",
+			},
+		},
+		{
+			name:        "drop drops bots",
+			mode:        properties.BotFilterDrop,
+			userAgent:   "crawler",
+			expectedErr: ErrHitDropped,
+		},
+		{
+			name:      "drop keeps humans",
+			mode:      properties.BotFilterDrop,
+			userAgent: "browser",
+			expectedMetadata: map[string]string{
+				botdetection.IsBotMetadataKey: "false",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+				"test_property_id": {PropertyID: "test_property_id", BotFilterMode: tc.mode},
+			}}
+			hit := hits.New()
+			hit.PropertyID = "test_property_id"
+			hit.Request.Headers.Set("User-Agent", tc.userAgent)
+
+			// when
+			err := BotFiltering(settings, userAgentBotDetector{}).Process(nil, hit)
+
+			// then
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMetadata, hit.Metadata)
+		})
+	}
+}
+
+func TestHandleRequest_DropsBotHitsSilently(t *testing.T) {
+	// given
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"test_property_id": {
+			PropertyID:     "test_property_id",
+			ProtocolID:     "test_protocol",
+			BotFilterMode:  properties.BotFilterDrop,
+			IPMaskingLevel: 1,
+		},
+	}}
+	storage := &mockStorage{}
+	rawLogStorage := &capturingRawLogStorage{}
+	p := &mockProtocol{id: "test_protocol"}
+	server := NewServer(
+		storage,
+		rawLogStorage,
+		HitValidatingRuleSet(1024*128, settings),
+		[]protocol.Protocol{p},
+		8080,
+		WithTrustAllProxies(),
+		WithHitProcessingRule(NewMultipleHitProcessingRule(
+			BotFiltering(settings, userAgentBotDetector{}),
+			IPMasking(settings),
+		)),
+	)
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetHost("example.com")
+	ctx.Request.Header.Set("X-Real-IP", "192.168.1.1")
+	ctx.Request.Header.Set("User-Agent", "crawler")
+	ctx.URI().SetPath("/collect")
+
+	// when
+	server.handleRequest(context.Background(), ctx, p)
+
+	// then
+	assert.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	assert.Empty(t, storage.hits)
+	require.Len(t, rawLogStorage.requests, 1)
+	assert.Equal(t, "192.168.1.1", rawLogStorage.requests[0].IP)
+}
+
+func TestReplayer_DropsBotHitsOfIPRangesNarrowerThanTheMask(t *testing.T) {
+	// given
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"test_property_id": {
+			PropertyID:     "test_property_id",
+			ProtocolID:     "test_protocol",
+			BotFilterMode:  properties.BotFilterDrop,
+			IPMaskingLevel: 1,
+		},
+	}}
+	detector, err := botdetection.NewIPRangeDetector([]string{"192.168.1.120/30"})
+	require.NoError(t, err)
+	storage := &mockStorage{}
+	rawLogStorage := &capturingRawLogStorage{}
+	p := &mockProtocol{id: "test_protocol"}
+	server := NewServer(
+		storage,
+		rawLogStorage,
+		HitValidatingRuleSet(1024*128, settings),
+		[]protocol.Protocol{p},
+		8080,
+		WithTrustAllProxies(),
+		WithHitProcessingRule(NewMultipleHitProcessingRule(
+			BotFiltering(settings, detector),
+			IPMasking(settings),
+		)),
+	)
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetHost("example.com")
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.Header.Set("X-Real-IP", "192.168.1.123")
+	ctx.URI().SetPath("/collect")
+	server.handleRequest(context.Background(), ctx, p)
+	require.Empty(t, storage.hits)
+	require.Len(t, rawLogStorage.requests, 1)
+
+	// when
+	replayErr := server.Replayer(context.Background()).Replay(rawLogStorage.requests[0])
+
+	// then
+	require.NoError(t, replayErr)
+	assert.Empty(t, storage.hits)
+}
@@ -0,0 +1,186 @@
+package receiver
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/storage"
+)
+
+// cookielessSaltKeyPrefix prefixes the keys of the salts of every day, followed by the
+// day in YYYY-MM-DD format.
+const cookielessSaltKeyPrefix = "receiver/cookieless_salt/"
+
+// cookielessSaltDaysKey holds the comma separated days with a stored salt, in order, for
+// the salts of days past the retention to be deleted.
+var cookielessSaltDaysKey = []byte("receiver/cookieless_salt_days")
+
+const cookielessSaltBytes = 32
+
+// DailySalt is the secret salt of cookieless client IDs, rotated every UTC day. The salt of
+// the previous day is deleted on rotation, so that client IDs of past days can't be
+// computed again. A retention keeps the salts of past days for replayed requests to get
+// the client IDs they got when they were received, at the cost of these client IDs being
+// computable, and linkable to visitors, for as long.
+type DailySalt struct {
+	kv        storage.KV
+	retention time.Duration
+	mu        sync.Mutex
+	loaded    bool
+	days      []string
+	salts     map[string][]byte
+}
+
+// NewDailySalt creates a daily salt kept in the given KV storage, keeping the salts of past
+// days for the given retention. A zero retention keeps only the salt of the latest day.
+func NewDailySalt(kv storage.KV, retention time.Duration) *DailySalt {
+	return &DailySalt{kv: kv, retention: retention, salts: map[string][]byte{}}
+}
+
+// Salt returns the salt of the day of the given time, creating it if there's none. Salts
+// of days past the retention of the latest one are never stored, they'd be deleted anyway.
+func (s *DailySalt) Salt(at time.Time) ([]byte, error) {
+	day := at.UTC().Format(time.DateOnly)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.loaded {
+		if err := s.load(); err != nil {
+			return nil, err
+		}
+		s.loaded = true
+	}
+	if salt, ok := s.salts[day]; ok {
+		return salt, nil
+	}
+	value, err := s.kv.Get(cookielessSaltKey(day))
+	if err != nil {
+		return nil, fmt.Errorf("loading cookieless salt: %w", err)
+	}
+	if value != nil {
+		s.salts[day] = value
+		return value, nil
+	}
+
+	salt := make([]byte, cookielessSaltBytes)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, fmt.Errorf("generating cookieless salt: %w", err)
+	}
+	if s.expired(day, s.latestDay(day)) {
+		return salt, nil
+	}
+	s.salts[day] = salt
+	if _, err := s.kv.Set(cookielessSaltKey(day), salt); err != nil {
+		return nil, fmt.Errorf("storing cookieless salt: %w", err)
+	}
+	s.days = append(s.days, day)
+	// Dates formatted as YYYY-MM-DD are ordered lexicographically
+	sort.Strings(s.days)
+	if err := s.prune(); err != nil {
+		return nil, err
+	}
+	return salt, nil
+}
+
+func (s *DailySalt) load() error {
+	value, err := s.kv.Get(cookielessSaltDaysKey)
+	if err != nil {
+		return fmt.Errorf("loading cookieless salt days: %w", err)
+	}
+	if len(value) > 0 {
+		s.days = strings.Split(string(value), ",")
+	}
+	return nil
+}
+
+func (s *DailySalt) latestDay(day string) string {
+	if len(s.days) > 0 && s.days[len(s.days)-1] > day {
+		return s.days[len(s.days)-1]
+	}
+	return day
+}
+
+func (s *DailySalt) expired(day, latestDay string) bool {
+	if s.retention <= 0 {
+		return day < latestDay
+	}
+	latest, err := time.Parse(time.DateOnly, latestDay)
+	if err != nil {
+		return false
+	}
+	return day < latest.Add(-s.retention).Format(time.DateOnly)
+}
+
+// prune deletes the salts of days past the retention and stores the remaining days.
+func (s *DailySalt) prune() error {
+	latestDay := s.latestDay("")
+	kept := s.days[:0]
+	for _, day := range s.days {
+		if !s.expired(day, latestDay) {
+			kept = append(kept, day)
+			continue
+		}
+		if err := s.kv.Delete(cookielessSaltKey(day)); err != nil {
+			return fmt.Errorf("deleting cookieless salt: %w", err)
+		}
+		delete(s.salts, day)
+	}
+	s.days = kept
+	if _, err := s.kv.Set(cookielessSaltDaysKey, []byte(strings.Join(s.days, ","))); err != nil {
+		return fmt.Errorf("storing cookieless salt days: %w", err)
+	}
+	return nil
+}
+
+func cookielessSaltKey(day string) []byte {
+	return []byte(cookielessSaltKeyPrefix + day)
+}
+
+// CookielessClientID returns a hit processing rule replacing the client ID of hits of
+// properties in the cookieless client ID mode, and of protocol.CookielessProtocol
+// protocols, with a hash of the salt of the day, the IP, the user agent and the property
+// ID. A visitor keeps the same client ID for a day, which can't be linked to the client
+// IDs of other days once the salt is rotated. It needs to run before IP masking, for
+// visitors behind the same masked IP to be told apart.
+func CookielessClientID(settings properties.SettingsRegistry, salt *DailySalt) HitProcessingRule {
+	return NewSimpleHitProcessingRule(func(p protocol.Protocol, hit *hits.Hit) error {
+		propertySettings, err := settings.GetByPropertyID(hit.PropertyID)
+		if err != nil {
+			return err
+		}
+		_, alwaysCookieless := p.(protocol.CookielessProtocol)
+		if !alwaysCookieless && propertySettings.ClientIDMode != properties.ClientIDModeCookieless {
+			return nil
+		}
+
+		request := hit.MustParsedRequest()
+		daySalt, err := salt.Salt(request.ServerReceivedTime)
+		if err != nil {
+			return err
+		}
+		hash := sha256.New()
+		for _, part := range [][]byte{
+			daySalt,
+			[]byte(request.IP),
+			[]byte(request.Headers.Get("User-Agent")),
+			[]byte(hit.PropertyID),
+		} {
+			hash.Write(part)
+			hash.Write([]byte{'|'})
+		}
+		clientID := hits.ClientID(hex.EncodeToString(hash.Sum(nil)))
+		hit.ClientID = clientID
+		hit.AuthoritativeClientID = clientID
+		return nil
+	})
+}
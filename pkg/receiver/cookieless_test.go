@@ -0,0 +1,143 @@
+package receiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDailySalt(t *testing.T) {
+	// given
+	kv := storage.NewInMemoryKV()
+	day := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	salt := NewDailySalt(kv, 48*time.Hour)
+
+	// when
+	first, err := salt.Salt(day)
+	require.NoError(t, err)
+	sameDay, err := salt.Salt(day.Add(13 * time.Hour))
+	require.NoError(t, err)
+	nextDay, err := salt.Salt(day.Add(14 * time.Hour))
+	require.NoError(t, err)
+	previousDay, err := salt.Salt(day)
+	require.NoError(t, err)
+	reloaded, err := NewDailySalt(kv, 48*time.Hour).Salt(day)
+	require.NoError(t, err)
+
+	// then
+	assert.Len(t, first, cookielessSaltBytes)
+	assert.Equal(t, first, sameDay)
+	assert.NotEqual(t, first, nextDay)
+	assert.Equal(t, first, previousDay, "salts of past days are kept for replays")
+	assert.Equal(t, first, reloaded)
+	days, err := kv.Get(cookielessSaltDaysKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-07,2026-03-08", string(days))
+}
+
+func TestDailySalt_DeletesSaltsPastTheRetention(t *testing.T) {
+	// given
+	kv := storage.NewInMemoryKV()
+	day := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	salt := NewDailySalt(kv, 24*time.Hour)
+	first, err := salt.Salt(day)
+	require.NoError(t, err)
+
+	// when
+	_, err = salt.Salt(day.Add(48 * time.Hour))
+	require.NoError(t, err)
+	expired, err := NewDailySalt(kv, 24*time.Hour).Salt(day)
+	require.NoError(t, err)
+
+	// then
+	stored, err := kv.Get(cookielessSaltKey("2026-03-07"))
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+	assert.NotEqual(t, first, expired, "the salt of a day past the retention is destroyed")
+	days, err := kv.Get(cookielessSaltDaysKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-09", string(days))
+}
+
+func TestDailySalt_WithoutRetentionDeletesThePreviousSaltOnRotation(t *testing.T) {
+	// given
+	kv := storage.NewInMemoryKV()
+	day := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	salt := NewDailySalt(kv, 0)
+	first, err := salt.Salt(day)
+	require.NoError(t, err)
+
+	// when
+	_, err = salt.Salt(day.Add(24 * time.Hour))
+	require.NoError(t, err)
+	previousDay, err := salt.Salt(day)
+	require.NoError(t, err)
+
+	// then
+	stored, err := kv.Get(cookielessSaltKey("2026-03-07"))
+	require.NoError(t, err)
+	assert.Nil(t, stored)
+	assert.NotEqual(t, first, previousDay, "the salt of the previous day is destroyed on rotation")
+	days, err := kv.Get(cookielessSaltDaysKey)
+	require.NoError(t, err)
+	assert.Equal(t, "2026-03-08", string(days))
+}
+
+func TestCookielessClientID(t *testing.T) {
+	day := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	newHit := func(propertyID, ip, userAgent string, receivedAt time.Time) *hits.Hit {
+		hit := hits.New()
+		hit.PropertyID = propertyID
+		hit.ClientID = "cookie_client_id"
+		hit.AuthoritativeClientID = hit.ClientID
+		hit.Request.IP = ip
+		hit.Request.Headers.Set("User-Agent", userAgent)
+		hit.Request.ServerReceivedTime = receivedAt
+		return hit
+	}
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"cookieless": {PropertyID: "cookieless", ClientIDMode: properties.ClientIDModeCookieless},
+		"other":      {PropertyID: "other", ClientIDMode: properties.ClientIDModeCookieless},
+		"cookies":    {PropertyID: "cookies", ClientIDMode: properties.ClientIDModeProtocol},
+	}}
+	rule := CookielessClientID(settings, NewDailySalt(storage.NewInMemoryKV(), 0))
+	process := func(hit *hits.Hit) hits.ClientID {
+		require.NoError(t, rule.Process(nil, hit))
+		assert.Equal(t, hit.ClientID, hit.AuthoritativeClientID)
+		return hit.ClientID
+	}
+
+	// when
+	visitor := process(newHit("cookieless", "10.0.0.1", "Firefox", day))
+	sameVisitorLater := process(newHit("cookieless", "10.0.0.1", "Firefox", day.Add(time.Hour)))
+	otherUserAgent := process(newHit("cookieless", "10.0.0.1", "Chrome", day))
+	otherIP := process(newHit("cookieless", "10.0.0.2", "Firefox", day))
+	otherProperty := process(newHit("other", "10.0.0.1", "Firefox", day))
+	sameVisitorNextDay := process(newHit("cookieless", "10.0.0.1", "Firefox", day.Add(24*time.Hour)))
+	cookies := process(newHit("cookies", "10.0.0.1", "Firefox", day))
+	cookielessProtocolHit := newHit("cookies", "10.0.0.1", "Firefox", day)
+	require.NoError(t, rule.Process(&cookielessProtocolStub{}, cookielessProtocolHit))
+
+	// then
+	assert.Len(t, string(visitor), 64)
+	assert.Equal(t, visitor, sameVisitorLater)
+	assert.NotEqual(t, visitor, otherUserAgent)
+	assert.NotEqual(t, visitor, otherIP)
+	assert.NotEqual(t, visitor, otherProperty)
+	assert.NotEqual(t, visitor, sameVisitorNextDay)
+	assert.Equal(t, hits.ClientID("cookie_client_id"), cookies)
+	assert.Len(t, string(cookielessProtocolHit.ClientID), 64, "cookieless protocols ignore the client ID mode")
+	assert.Equal(t, cookielessProtocolHit.ClientID, cookielessProtocolHit.AuthoritativeClientID)
+}
+
+type cookielessProtocolStub struct {
+	protocol.Protocol
+}
+
+func (p *cookielessProtocolStub) CookielessClientIDs() {}
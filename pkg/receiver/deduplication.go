@@ -0,0 +1,218 @@
+package receiver
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/storage"
+	"github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+const deduplicationSweepInterval = time.Minute
+
+var duplicateHitsCounter metric.Int64Counter
+
+func init() {
+	duplicateHitsCounter, _ = otel.GetMeterProvider().Meter("receiver").Int64Counter(
+		"receiver.deduplication.suppressed",
+		metric.WithDescription("Duplicate hits dropped by the receiver, by property and fingerprint"),
+	)
+}
+
+// deduplicationKeyPrefix prefixes the keys of the hits seen by DeduplicatingStorage in its
+// KV storage.
+const deduplicationKeyPrefix = "receiver/dedup/"
+
+// keysLister is implemented by KV storages able to list their keys, for the expired keys
+// of DeduplicatingStorage to be swept.
+type keysLister interface {
+	Keys(prefix []byte, opts ...storage.KeysOptionsFunc) ([][]byte, error)
+}
+
+// DeduplicatingStorage is a storage dropping hits which are duplicates of a hit pushed
+// within the deduplication window of their property, before pushing the others to the
+// child storage. Hits are recognized by the event ID param of the property or by a hash of
+// their client ID, event name, params and body, and their position among the hits of the
+// request with the same ones. Seen hits are kept in the KV storage until their window
+// passes, so that they're remembered across restarts. Windows are measured by the
+// ServerReceivedTime of the hits, so replayed requests are deduplicated as they were
+// originally. Hits of a push failing in the child storage are forgotten, for retries of the
+// request not to be dropped.
+type DeduplicatingStorage struct {
+	child     Storage
+	settings  properties.SettingsRegistry
+	kv        storage.KV
+	mu        sync.Mutex
+	lastSweep time.Time
+}
+
+// NewDeduplicatingStorage creates a new DeduplicatingStorage instance, keeping the seen
+// hits in the given KV storage.
+func NewDeduplicatingStorage(
+	child Storage,
+	settings properties.SettingsRegistry,
+	kv storage.KV,
+) *DeduplicatingStorage {
+	return &DeduplicatingStorage{
+		child:    child,
+		settings: settings,
+		kv:       kv,
+	}
+}
+
+// Push implements Storage.
+func (s *DeduplicatingStorage) Push(theHits []*hits.Hit) error {
+	kept := make([]*hits.Hit, 0, len(theHits))
+	var seen []string
+	occurrences := map[string]int{}
+	for _, hit := range theHits {
+		propertySettings, err := s.settings.GetByPropertyID(hit.PropertyID)
+		if err != nil || propertySettings.Deduplication.Window <= 0 {
+			kept = append(kept, hit)
+			continue
+		}
+		key, fingerprint := hitFingerprint(hit, propertySettings.Deduplication.EventIDParam)
+		if fingerprint == payloadFingerprint {
+			// Hits of batched requests may share the payload, the n-th of them is a
+			// duplicate of the n-th one of a request sent again only
+			occurrence := occurrences[key]
+			occurrences[key]++
+			key += "/" + strconv.Itoa(occurrence)
+		}
+		remembered, err := s.remember(key, hit.MustParsedRequest().ServerReceivedTime, propertySettings.Deduplication.Window)
+		if err != nil {
+			s.forget(seen)
+			return err
+		}
+		if !remembered {
+			logrus.WithFields(logrus.Fields{
+				"property_id": hit.PropertyID, "event_name": hit.EventName, "fingerprint": fingerprint,
+			}).Debug("dropping duplicate hit")
+			duplicateHitsCounter.Add(context.Background(), 1, metric.WithAttributes(
+				attribute.String("property_id", hit.PropertyID),
+				attribute.String("fingerprint", fingerprint),
+			))
+			continue
+		}
+		seen = append(seen, key)
+		kept = append(kept, hit)
+	}
+
+	if err := s.child.Push(kept); err != nil {
+		s.forget(seen)
+		return err
+	}
+	return nil
+}
+
+const (
+	eventIDFingerprint = "event_id"
+	payloadFingerprint = "payload"
+)
+
+// hitFingerprint returns the key identifying the hit within its property, and whether it
+// comes from the event ID or from the payload. The payload fingerprint includes the client
+// ID, so that visitors sending the same payload, like Plausible page views of the same
+// URL, aren't duplicates of each other.
+func hitFingerprint(hit *hits.Hit, eventIDParam string) (key, fingerprint string) {
+	request := hit.MustParsedRequest()
+	if eventIDParam != "" {
+		if eventID := request.QueryParams.Get(eventIDParam); eventID != "" {
+			digest := sha256.Sum256([]byte(eventID))
+			return deduplicationKeyPrefix + hit.PropertyID + "/event_id/" + hex.EncodeToString(digest[:]),
+				eventIDFingerprint
+		}
+	}
+	digest := sha256.New()
+	digest.Write([]byte(hit.AuthoritativeClientID))
+	digest.Write([]byte("\n"))
+	digest.Write([]byte(hit.EventName))
+	digest.Write([]byte("\n"))
+	digest.Write([]byte(request.QueryParams.Encode()))
+	digest.Write([]byte("\n"))
+	digest.Write(request.Body)
+	return deduplicationKeyPrefix + hit.PropertyID + "/payload/" + hex.EncodeToString(digest.Sum(nil)),
+		payloadFingerprint
+}
+
+// remember records the key as seen at the given time, returning false if it was already
+// seen within the window.
+func (s *DeduplicatingStorage) remember(key string, now time.Time, window time.Duration) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if now.Sub(s.lastSweep) >= deduplicationSweepInterval {
+		s.sweep(now)
+		s.lastSweep = now
+	}
+
+	value, err := s.kv.Get([]byte(key))
+	if err != nil {
+		return false, fmt.Errorf("reading deduplication key: %w", err)
+	}
+	if expiresAt, ok := parseExpiry(value); ok && now.Before(expiresAt) {
+		return false, nil
+	}
+	expiresAt := strconv.FormatInt(now.Add(window).UnixNano(), 10)
+	if _, err := s.kv.Set([]byte(key), []byte(expiresAt)); err != nil {
+		return false, fmt.Errorf("storing deduplication key: %w", err)
+	}
+	return true, nil
+}
+
+func (s *DeduplicatingStorage) forget(keys []string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, key := range keys {
+		if err := s.kv.Delete([]byte(key)); err != nil {
+			logrus.Errorf("failed to delete deduplication key: %v", err)
+		}
+	}
+}
+
+// sweep removes keys whose window has passed, if the KV storage can list them.
+func (s *DeduplicatingStorage) sweep(now time.Time) {
+	lister, ok := s.kv.(keysLister)
+	if !ok {
+		return
+	}
+	keys, err := lister.Keys([]byte(deduplicationKeyPrefix))
+	if err != nil {
+		logrus.Errorf("failed to list deduplication keys: %v", err)
+		return
+	}
+	for _, key := range keys {
+		value, err := s.kv.Get(key)
+		if err != nil {
+			continue
+		}
+		if expiresAt, ok := parseExpiry(value); ok && now.Before(expiresAt) {
+			continue
+		}
+		if err := s.kv.Delete(key); err != nil {
+			logrus.Errorf("failed to delete deduplication key: %v", err)
+		}
+	}
+}
+
+func parseExpiry(value []byte) (time.Time, bool) {
+	if value == nil {
+		return time.Time{}, false
+	}
+	unixNano, err := strconv.ParseInt(string(value), 10, 64)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return time.Unix(0, unixNano), true
+}
@@ -0,0 +1,190 @@
+package receiver
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/storage"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func deduplicationTestHit(propertyID string, receivedAt time.Time, query, body string) *hits.Hit {
+	hit := hits.New()
+	hit.PropertyID = propertyID
+	hit.AuthoritativeClientID = "client-1"
+	params, _ := url.ParseQuery(query)
+	hit.Request = &hits.ParsedRequest{
+		ServerReceivedTime: receivedAt,
+		QueryParams:        params,
+		Body:               []byte(body),
+	}
+	return hit
+}
+
+func withAuthoritativeClientID(hit *hits.Hit, clientID hits.ClientID) *hits.Hit {
+	hit.AuthoritativeClientID = clientID
+	return hit
+}
+
+func TestDeduplicatingStorage(t *testing.T) {
+	start := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"shop": {PropertyID: "shop", Deduplication: properties.DeduplicationSettings{
+			Window:       time.Minute,
+			EventIDParam: "ep.event_id",
+		}},
+		"blog": {PropertyID: "blog"},
+	}}
+
+	testCases := []struct {
+		name     string
+		pushes   [][]*hits.Hit
+		expected []int
+	}{
+		{
+			name: "same payload within the window is dropped",
+			pushes: [][]*hits.Hit{
+				{deduplicationTestHit("shop", start, "en=purchase&cid=1", "body")},
+				{deduplicationTestHit("shop", start.Add(30*time.Second), "cid=1&en=purchase", "body")},
+			},
+			expected: []int{1, 0},
+		},
+		{
+			name: "same payload after the window is kept",
+			pushes: [][]*hits.Hit{
+				{deduplicationTestHit("shop", start, "en=purchase&cid=1", "body")},
+				{deduplicationTestHit("shop", start.Add(time.Minute), "en=purchase&cid=1", "body")},
+			},
+			expected: []int{1, 1},
+		},
+		{
+			name: "different body is kept",
+			pushes: [][]*hits.Hit{
+				{deduplicationTestHit("shop", start, "en=purchase&cid=1", "body")},
+				{deduplicationTestHit("shop", start, "en=purchase&cid=1", "other body")},
+			},
+			expected: []int{1, 1},
+		},
+		{
+			name: "same payload of another visitor is kept",
+			pushes: [][]*hits.Hit{
+				{deduplicationTestHit("shop", start, "", `{"name":"pageview","url":"https://shop.example.com/"}`)},
+				{withAuthoritativeClientID(
+					deduplicationTestHit("shop", start, "", `{"name":"pageview","url":"https://shop.example.com/"}`),
+					"client-2",
+				)},
+			},
+			expected: []int{1, 1},
+		},
+		{
+			name: "same event ID with different payloads is dropped",
+			pushes: [][]*hits.Hit{
+				{deduplicationTestHit("shop", start, "en=purchase&ep.event_id=e-1&_s=1", "")},
+				{deduplicationTestHit("shop", start, "en=purchase&ep.event_id=e-1&_s=2", "")},
+			},
+			expected: []int{1, 0},
+		},
+		{
+			name: "hits of a request sharing the payload are kept",
+			pushes: [][]*hits.Hit{
+				{
+					deduplicationTestHit("shop", start, "en=page_view&cid=1", "batch"),
+					deduplicationTestHit("shop", start, "en=page_view&cid=1", "batch"),
+					deduplicationTestHit("shop", start, "en=scroll&cid=1", "batch"),
+				},
+			},
+			expected: []int{3},
+		},
+		{
+			name: "hits of a request sent again are dropped",
+			pushes: [][]*hits.Hit{
+				{
+					deduplicationTestHit("shop", start, "en=page_view&cid=1", "batch"),
+					deduplicationTestHit("shop", start, "en=page_view&cid=1", "batch"),
+				},
+				{
+					deduplicationTestHit("shop", start, "en=page_view&cid=1", "batch"),
+					deduplicationTestHit("shop", start, "en=page_view&cid=1", "batch"),
+					deduplicationTestHit("shop", start, "en=page_view&cid=1", "batch"),
+				},
+			},
+			expected: []int{2, 1},
+		},
+		{
+			name: "properties without a window keep duplicates",
+			pushes: [][]*hits.Hit{
+				{deduplicationTestHit("blog", start, "en=purchase&cid=1", "body")},
+				{deduplicationTestHit("blog", start, "en=purchase&cid=1", "body")},
+			},
+			expected: []int{1, 1},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			child := &mockStorage{}
+			deduplicating := NewDeduplicatingStorage(child, settings, storage.NewInMemoryKV())
+
+			for i, push := range tc.pushes {
+				// when
+				err := deduplicating.Push(push)
+
+				// then
+				require.NoError(t, err)
+				assert.Len(t, child.hits, tc.expected[i], "push #%d", i)
+			}
+		})
+	}
+}
+
+func TestDeduplicatingStorage_ForgetsHitsOfFailedPush(t *testing.T) {
+	// given
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"shop": {PropertyID: "shop", Deduplication: properties.DeduplicationSettings{Window: time.Minute}},
+	}}
+	child := &mockStorage{err: assert.AnError}
+	deduplicating := NewDeduplicatingStorage(child, settings, storage.NewInMemoryKV())
+	receivedAt := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	require.Error(t, deduplicating.Push([]*hits.Hit{deduplicationTestHit("shop", receivedAt, "en=purchase", "")}))
+	child.err = nil
+
+	// when
+	err := deduplicating.Push([]*hits.Hit{deduplicationTestHit("shop", receivedAt, "en=purchase", "")})
+
+	// then
+	require.NoError(t, err)
+	assert.Len(t, child.hits, 1)
+}
+
+func TestDeduplicatingStorage_RemembersHitsInTheKV(t *testing.T) {
+	// given
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"shop": {PropertyID: "shop", Deduplication: properties.DeduplicationSettings{Window: time.Minute}},
+	}}
+	kv := storage.NewInMemoryKV()
+	receivedAt := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	require.NoError(t, NewDeduplicatingStorage(&mockStorage{}, settings, kv).Push(
+		[]*hits.Hit{deduplicationTestHit("shop", receivedAt, "en=purchase", "")},
+	))
+	child := &mockStorage{}
+	restarted := NewDeduplicatingStorage(child, settings, kv)
+
+	// when
+	duplicateErr := restarted.Push([]*hits.Hit{deduplicationTestHit("shop", receivedAt, "en=purchase", "")})
+	duplicates := len(child.hits)
+	laterErr := restarted.Push([]*hits.Hit{deduplicationTestHit("shop", receivedAt.Add(2*time.Minute), "en=purchase", "")})
+
+	// then
+	require.NoError(t, duplicateErr)
+	require.NoError(t, laterErr)
+	assert.Zero(t, duplicates)
+	assert.Len(t, child.hits, 1)
+	keys, err := kv.(keysLister).Keys([]byte(deduplicationKeyPrefix))
+	require.NoError(t, err)
+	assert.Len(t, keys, 1, "keys of passed windows are swept")
+}
@@ -0,0 +1,222 @@
+package receiver
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/valyala/fasthttp"
+)
+
+// AuthenticatedPathPrefix prefixes the paths of the authenticated variant of the tracking
+// endpoints, e.g. /s2s/g/collect, which server-to-server callers send hits to.
+const AuthenticatedPathPrefix = "/s2s"
+
+// Headers of requests to authenticated endpoints.
+const (
+	// PropertyIDHeader is the ID of the property the credentials are of. All hits of the
+	// request must belong to it.
+	PropertyIDHeader = "X-D8A-Property-ID"
+	// APIKeyHeader is one of the ingestion API keys of the property.
+	APIKeyHeader = "X-D8A-API-Key"
+	// SignatureHeader is "sha256=" followed by the hex encoded HMAC-SHA256 of the request,
+	// see Sign, with one of the ingestion HMAC secrets of the property.
+	SignatureHeader = "X-D8A-Signature"
+	// SignatureTimestampHeader is when the request was signed, in RFC 3339 format. It's
+	// required with the SignatureHeader, signatures too far from the time the request is
+	// received are rejected, so they can't be replayed later.
+	SignatureTimestampHeader = "X-D8A-Signature-Timestamp"
+	// ClientIPHeader overrides the IP of the request, with the IP of the visitor.
+	ClientIPHeader = "X-D8A-Client-IP"
+	// TimestampHeader overrides the time the request was received, in RFC 3339 format.
+	TimestampHeader = "X-D8A-Timestamp"
+)
+
+const signaturePrefix = "sha256="
+
+// signatureMaxClockSkew is how far the SignatureTimestampHeader may be from the time the
+// request is received.
+const signatureMaxClockSkew = 5 * time.Minute
+
+// timestampMaxAge is how far before the time the request is received the TimestampHeader
+// may be, same as timestamp_micros of the GA4 Measurement Protocol.
+const timestampMaxAge = 72 * time.Hour
+
+// timestampMaxClockSkew is how far after the time the request is received the
+// TimestampHeader may be, to tolerate callers whose clocks are slightly ahead.
+const timestampMaxClockSkew = time.Minute
+
+// authenticatedRequestKey is the user value of the request context telling that the
+// request was sent to an authenticated endpoint.
+const authenticatedRequestKey = "d8a.authenticated_request"
+
+// ErrUnauthorized is returned for requests to authenticated endpoints without valid
+// credentials of the property of their hits.
+var ErrUnauthorized = errors.New("unauthorized")
+
+// WithIngestionAuth registers the authenticated variant of every tracking endpoint, under
+// AuthenticatedPathPrefix. Requests to it are verified against the ingestion credentials of
+// the property before hits are created, and may override the IP and the time they were
+// received with the ClientIPHeader and TimestampHeader.
+func WithIngestionAuth(settings properties.SettingsRegistry) ServerOption {
+	return func(s *Server) {
+		s.ingestionAuth = settings
+	}
+}
+
+// authenticate verifies the credentials of a request to an authenticated endpoint and
+// applies its overrides, returning the ID of the property the credentials are of. The
+// credentials are removed from the request, so they're neither stored in hits nor in the
+// raw log, and so is the path prefix, so that protocols see the path of the endpoint.
+// Requests in the raw log are therefore replayed through the open endpoint, with the IP and
+// the time they were received with.
+func (s *Server) authenticate(ctx *fasthttp.RequestCtx, request *hits.ParsedRequest) (string, error) {
+	propertyID := request.Headers.Get(PropertyIDHeader)
+	if propertyID == "" {
+		return "", fmt.Errorf("%w: missing %s header", ErrUnauthorized, PropertyIDHeader)
+	}
+	settings, err := s.ingestionAuth.GetByPropertyID(propertyID)
+	if err != nil {
+		return "", fmt.Errorf("%w: %w", ErrUnauthorized, err)
+	}
+	if err := verifyCredentials(ctx, request, settings.IngestionAuth); err != nil {
+		return "", fmt.Errorf("%w: property %q: %w", ErrUnauthorized, propertyID, err)
+	}
+	request.Headers.Del(APIKeyHeader)
+	request.Headers.Del(SignatureHeader)
+	request.Headers.Del(SignatureTimestampHeader)
+	request.Path = strings.TrimPrefix(request.Path, AuthenticatedPathPrefix)
+
+	if clientIP := request.Headers.Get(ClientIPHeader); clientIP != "" {
+		if net.ParseIP(clientIP) == nil {
+			return "", newClientError(fmt.Sprintf("%s must be an IP address: %q", ClientIPHeader, clientIP))
+		}
+		request.IP = clientIP
+	}
+	if timestamp := request.Headers.Get(TimestampHeader); timestamp != "" {
+		receivedTime, err := time.Parse(time.RFC3339Nano, timestamp)
+		if err != nil {
+			return "", newClientError(fmt.Sprintf("%s must be an RFC 3339 time: %q", TimestampHeader, timestamp))
+		}
+		if receivedTime.Before(request.ServerReceivedTime.Add(-timestampMaxAge)) ||
+			receivedTime.After(request.ServerReceivedTime.Add(timestampMaxClockSkew)) {
+			return "", newClientError(fmt.Sprintf(
+				"%s must not be more than %s in the past nor in the future: %q", TimestampHeader, timestampMaxAge, timestamp,
+			))
+		}
+		request.ServerReceivedTime = receivedTime
+	}
+	return propertyID, nil
+}
+
+// requestAuthenticated tells whether the request was verified by an authenticated endpoint.
+// Their requests keep the PropertyIDHeader, which is removed from requests to open ones, so
+// it tells replayed requests in the raw log apart too.
+func requestAuthenticated(request *hits.ParsedRequest) bool {
+	return request.Headers.Get(PropertyIDHeader) != ""
+}
+
+func verifyCredentials(
+	ctx *fasthttp.RequestCtx,
+	request *hits.ParsedRequest,
+	auth properties.IngestionAuthSettings,
+) error {
+	if !auth.Enabled() {
+		return errors.New("authenticated ingestion is not enabled")
+	}
+	if apiKey := request.Headers.Get(APIKeyHeader); apiKey != "" {
+		for _, key := range auth.APIKeys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(apiKey)) == 1 {
+				return nil
+			}
+		}
+		return errors.New("invalid API key")
+	}
+	signature := request.Headers.Get(SignatureHeader)
+	if signature == "" {
+		return fmt.Errorf("missing %s or %s header", APIKeyHeader, SignatureHeader)
+	}
+	hexDigest, ok := strings.CutPrefix(signature, signaturePrefix)
+	digest, err := hex.DecodeString(hexDigest)
+	if !ok || err != nil {
+		return fmt.Errorf("%s must be %s followed by a hex encoded digest", SignatureHeader, signaturePrefix)
+	}
+	if err := verifySignatureTimestamp(request); err != nil {
+		return err
+	}
+	signed := &SignedRequest{
+		Method:             request.Method,
+		Path:               request.Path,
+		PropertyID:         request.Headers.Get(PropertyIDHeader),
+		ClientIP:           request.Headers.Get(ClientIPHeader),
+		Timestamp:          request.Headers.Get(TimestampHeader),
+		SignatureTimestamp: request.Headers.Get(SignatureTimestampHeader),
+		QueryString:        ctx.URI().QueryString(),
+		Body:               request.Body,
+	}
+	for _, secret := range auth.HMACSecrets {
+		if hmac.Equal(digest, Sign(secret, signed)) {
+			return nil
+		}
+	}
+	return errors.New("invalid signature")
+}
+
+func verifySignatureTimestamp(request *hits.ParsedRequest) error {
+	value := request.Headers.Get(SignatureTimestampHeader)
+	if value == "" {
+		return fmt.Errorf("missing %s header", SignatureTimestampHeader)
+	}
+	signedAt, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return fmt.Errorf("%s must be an RFC 3339 time: %q", SignatureTimestampHeader, value)
+	}
+	if skew := request.ServerReceivedTime.Sub(signedAt).Abs(); skew > signatureMaxClockSkew {
+		return fmt.Errorf("%s must be within %s of the time the request is received: %q",
+			SignatureTimestampHeader, signatureMaxClockSkew, value)
+	}
+	return nil
+}
+
+// SignedRequest is the part of a request to an authenticated endpoint covered by its
+// signature. Headers which aren't set are empty.
+type SignedRequest struct {
+	Method             string
+	Path               string
+	PropertyID         string
+	ClientIP           string
+	Timestamp          string
+	SignatureTimestamp string
+	QueryString        []byte
+	Body               []byte
+}
+
+// Sign returns the HMAC-SHA256 of a request, as expected in the SignatureHeader. It's
+// computed over the method, the path, the PropertyIDHeader, ClientIPHeader, TimestampHeader
+// and SignatureTimestampHeader, and the raw query string, each followed by a newline, and
+// then the body.
+func Sign(secret string, request *SignedRequest) []byte {
+	mac := hmac.New(sha256.New, []byte(secret))
+	for _, part := range []string{
+		request.Method,
+		request.Path,
+		request.PropertyID,
+		request.ClientIP,
+		request.Timestamp,
+		request.SignatureTimestamp,
+		string(request.QueryString),
+	} {
+		mac.Write([]byte(part))
+		mac.Write([]byte("\n"))
+	}
+	mac.Write(request.Body)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,313 @@
+package receiver
+
+import (
+	"context"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+var ingestionAuthTestSettings = settingsRegistryStub{
+	settingsByPropertyID: map[string]*properties.Settings{
+		"test_property_id": {
+			PropertyID: "test_property_id",
+			ProtocolID: "test_protocol",
+			IngestionAuth: properties.IngestionAuthSettings{
+				APIKeys:     []string{"key"},
+				HMACSecrets: []string{"secret"},
+			},
+		},
+		"other_property_id": {
+			PropertyID:    "other_property_id",
+			ProtocolID:    "test_protocol",
+			IngestionAuth: properties.IngestionAuthSettings{APIKeys: []string{"other-key"}},
+		},
+		"open_property_id": {PropertyID: "open_property_id", ProtocolID: "test_protocol"},
+	},
+}
+
+// signedHeaders returns the headers of a request to /s2s/collect?v=2 signed with the
+// secret, with the given headers added to the signed ones.
+func signedHeaders(secret, body string, signedAt time.Time, headers map[string]string) map[string]string {
+	result := map[string]string{
+		PropertyIDHeader:         "test_property_id",
+		SignatureTimestampHeader: signedAt.Format(time.RFC3339),
+	}
+	for key, value := range headers {
+		result[key] = value
+	}
+	digest := Sign(secret, &SignedRequest{
+		Method:             fasthttp.MethodPost,
+		Path:               "/s2s/collect",
+		PropertyID:         result[PropertyIDHeader],
+		ClientIP:           result[ClientIPHeader],
+		Timestamp:          result[TimestampHeader],
+		SignatureTimestamp: result[SignatureTimestampHeader],
+		QueryString:        []byte("v=2"),
+		Body:               []byte(body),
+	})
+	result[SignatureHeader] = signaturePrefix + hex.EncodeToString(digest)
+	return result
+}
+
+// nolint:funlen // test code
+func TestIngestionAuth(t *testing.T) {
+	now := time.Now()
+	testCases := []struct {
+		name           string
+		path           string
+		headers        map[string]string
+		expectedStatus int
+	}{
+		{
+			name:           "open endpoint doesn't require credentials",
+			path:           "/collect",
+			expectedStatus: fasthttp.StatusNoContent,
+		},
+		{
+			name: "valid API key",
+			path: "/s2s/collect",
+			headers: map[string]string{
+				PropertyIDHeader: "test_property_id",
+				APIKeyHeader:     "key",
+			},
+			expectedStatus: fasthttp.StatusNoContent,
+		},
+		{
+			name:           "valid signature",
+			path:           "/s2s/collect",
+			headers:        signedHeaders("secret", "body", now, nil),
+			expectedStatus: fasthttp.StatusNoContent,
+		},
+		{
+			name: "valid signature of overrides",
+			path: "/s2s/collect",
+			headers: signedHeaders("secret", "body", now, map[string]string{
+				ClientIPHeader:  "203.0.113.7",
+				TimestampHeader: now.Add(-time.Hour).Format(time.RFC3339),
+			}),
+			expectedStatus: fasthttp.StatusNoContent,
+		},
+		{
+			name: "override not covered by the signature",
+			path: "/s2s/collect",
+			headers: func() map[string]string {
+				headers := signedHeaders("secret", "body", now, nil)
+				headers[ClientIPHeader] = "203.0.113.7"
+				return headers
+			}(),
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name: "signature without a signature timestamp",
+			path: "/s2s/collect",
+			headers: func() map[string]string {
+				headers := signedHeaders("secret", "body", now, nil)
+				delete(headers, SignatureTimestampHeader)
+				return headers
+			}(),
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name:           "replayed signature",
+			path:           "/s2s/collect",
+			headers:        signedHeaders("secret", "body", now.Add(-10*time.Minute), nil),
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name:           "missing credentials",
+			path:           "/s2s/collect",
+			headers:        map[string]string{PropertyIDHeader: "test_property_id"},
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name:           "missing property ID",
+			path:           "/s2s/collect",
+			headers:        map[string]string{APIKeyHeader: "key"},
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name: "invalid API key",
+			path: "/s2s/collect",
+			headers: map[string]string{
+				PropertyIDHeader: "test_property_id",
+				APIKeyHeader:     "other-key",
+			},
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name:           "signature of another body",
+			path:           "/s2s/collect",
+			headers:        signedHeaders("secret", "other body", now, nil),
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name: "malformed signature",
+			path: "/s2s/collect",
+			headers: map[string]string{
+				PropertyIDHeader: "test_property_id",
+				SignatureHeader:  "md5=abc",
+			},
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name: "hits of another property",
+			path: "/s2s/collect",
+			headers: map[string]string{
+				PropertyIDHeader: "other_property_id",
+				APIKeyHeader:     "other-key",
+			},
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name: "property without credentials",
+			path: "/s2s/collect",
+			headers: map[string]string{
+				PropertyIDHeader: "open_property_id",
+				APIKeyHeader:     "key",
+			},
+			expectedStatus: fasthttp.StatusUnauthorized,
+		},
+		{
+			name: "invalid client IP override",
+			path: "/s2s/collect",
+			headers: map[string]string{
+				PropertyIDHeader: "test_property_id",
+				APIKeyHeader:     "key",
+				ClientIPHeader:   "localhost",
+			},
+			expectedStatus: fasthttp.StatusBadRequest,
+		},
+		{
+			name: "timestamp override too far in the past",
+			path: "/s2s/collect",
+			headers: map[string]string{
+				PropertyIDHeader: "test_property_id",
+				APIKeyHeader:     "key",
+				TimestampHeader:  now.Add(-73 * time.Hour).Format(time.RFC3339),
+			},
+			expectedStatus: fasthttp.StatusBadRequest,
+		},
+		{
+			name: "timestamp override in the future",
+			path: "/s2s/collect",
+			headers: map[string]string{
+				PropertyIDHeader: "test_property_id",
+				APIKeyHeader:     "key",
+				TimestampHeader:  now.Add(time.Hour).Format(time.RFC3339),
+			},
+			expectedStatus: fasthttp.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			storage := &mockStorage{}
+			server := NewServer(
+				storage,
+				&capturingRawLogStorage{},
+				HitValidatingRuleSet(1024*128, ingestionAuthTestSettings),
+				[]protocol.Protocol{&mockProtocol{id: "test_protocol"}},
+				8080,
+				WithIngestionAuth(ingestionAuthTestSettings),
+			)
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+			ctx.Request.SetRequestURI(tc.path + "?v=2")
+			ctx.Request.Header.SetHost("example.com")
+			ctx.Request.Header.Set("User-Agent", "test-agent")
+			ctx.Request.SetBodyString("body")
+			for key, value := range tc.headers {
+				ctx.Request.Header.Set(key, value)
+			}
+
+			// when
+			server.setupRouter(context.Background()).Handler(ctx)
+
+			// then
+			assert.Equal(t, tc.expectedStatus, ctx.Response.StatusCode(), string(ctx.Response.Body()))
+			if tc.expectedStatus == fasthttp.StatusNoContent {
+				assert.Len(t, storage.hits, 1)
+			} else {
+				assert.Empty(t, storage.hits)
+			}
+		})
+	}
+}
+
+func TestIngestionAuth_TrustedOverrides(t *testing.T) {
+	// given
+	storage := &mockStorage{}
+	rawLogStorage := &capturingRawLogStorage{}
+	server := NewServer(
+		storage,
+		rawLogStorage,
+		HitValidatingRuleSet(1024*128, ingestionAuthTestSettings),
+		[]protocol.Protocol{&mockProtocol{id: "test_protocol"}},
+		8080,
+		WithIngestionAuth(ingestionAuthTestSettings),
+	)
+	hitTime := time.Now().Add(-time.Hour).Truncate(time.Second).UTC()
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.SetRequestURI("/s2s/collect")
+	ctx.Request.Header.SetHost("example.com")
+	ctx.Request.Header.Set("User-Agent", "test-agent")
+	ctx.Request.Header.Set(PropertyIDHeader, "test_property_id")
+	ctx.Request.Header.Set(APIKeyHeader, "key")
+	ctx.Request.Header.Set(ClientIPHeader, "203.0.113.7")
+	ctx.Request.Header.Set(TimestampHeader, hitTime.Format(time.RFC3339))
+
+	// when
+	server.setupRouter(context.Background()).Handler(ctx)
+
+	// then
+	require.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	require.Len(t, storage.hits, 1)
+	require.Len(t, rawLogStorage.requests, 1)
+	for _, request := range []*hits.ParsedRequest{storage.hits[0].MustParsedRequest(), rawLogStorage.requests[0]} {
+		assert.Equal(t, "203.0.113.7", request.IP)
+		assert.Equal(t, hitTime, request.ServerReceivedTime)
+		assert.Equal(t, "/collect", request.Path)
+		assert.Empty(t, request.Headers.Get(APIKeyHeader))
+		assert.True(t, requestAuthenticated(request))
+	}
+}
+
+func TestIngestionAuth_OpenEndpointRemovesPropertyIDHeader(t *testing.T) {
+	// given
+	storage := &mockStorage{}
+	rawLogStorage := &capturingRawLogStorage{}
+	server := NewServer(
+		storage,
+		rawLogStorage,
+		HitValidatingRuleSet(1024*128, ingestionAuthTestSettings),
+		[]protocol.Protocol{&mockProtocol{id: "test_protocol"}},
+		8080,
+		WithIngestionAuth(ingestionAuthTestSettings),
+	)
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+	ctx.Request.SetRequestURI("/collect")
+	ctx.Request.Header.SetHost("example.com")
+	ctx.Request.Header.Set(PropertyIDHeader, "test_property_id")
+
+	// when
+	server.setupRouter(context.Background()).Handler(ctx)
+
+	// then
+	require.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	require.Len(t, storage.hits, 1)
+	require.Len(t, rawLogStorage.requests, 1)
+	for _, request := range []*hits.ParsedRequest{storage.hits[0].MustParsedRequest(), rawLogStorage.requests[0]} {
+		assert.False(t, requestAuthenticated(request))
+	}
+}
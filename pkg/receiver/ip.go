@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"net"
 
+	"github.com/d8a-tech/d8a/pkg/hits"
 	"github.com/valyala/fasthttp"
 )
 
@@ -100,6 +101,26 @@ func WithTrustedProxies(cidrs ...string) ServerOption {
 	}
 }
 
+// WithProxyOnlyHeaders sets request headers only trusted proxies may set, like geolocation
+// headers of a CDN. They're removed from requests whose immediate connection doesn't come
+// from a trusted proxy, before the request reaches protocols, hits and the raw log.
+func WithProxyOnlyHeaders(headers ...string) ServerOption {
+	return func(s *Server) {
+		s.proxyOnlyHeaders = append(s.proxyOnlyHeaders, headers...)
+	}
+}
+
+// removeProxyOnlyHeaders removes the proxy-only headers from the request, unless it came
+// through a trusted proxy.
+func (s *Server) removeProxyOnlyHeaders(ctx *fasthttp.RequestCtx, request *hits.ParsedRequest) {
+	if len(s.proxyOnlyHeaders) == 0 || s.proxyTrust.IsTrustedProxy(ctx.RemoteIP()) {
+		return
+	}
+	for _, header := range s.proxyOnlyHeaders {
+		request.Headers.Del(header)
+	}
+}
+
 // realIP returns the client's real IP address (IPv4 or IPv6).
 //
 // When the immediate connection originates from a trusted proxy, the following
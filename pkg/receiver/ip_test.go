@@ -2,8 +2,10 @@ package receiver
 
 import (
 	"net"
+	"net/http"
 	"testing"
 
+	"github.com/d8a-tech/d8a/pkg/hits"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/valyala/fasthttp"
@@ -559,3 +561,38 @@ func TestRealIP_TrustedProxyThenMasking(t *testing.T) {
 	assert.Equal(t, "203.0.113.99", ip)
 	assert.Equal(t, "203.0.113.0", maskedIP)
 }
+
+func TestRemoveProxyOnlyHeaders(t *testing.T) {
+	tests := []struct {
+		name            string
+		server          *Server
+		expectedCountry string
+	}{
+		{
+			name:            "trusted proxy keeps headers",
+			server:          trustAllServer(),
+			expectedCountry: "PL",
+		},
+		{
+			name:   "untrusted connection loses headers",
+			server: trustNoneServer(),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			WithProxyOnlyHeaders("CF-IPCountry")(tt.server)
+			request := &hits.ParsedRequest{Headers: http.Header{}}
+			request.Headers.Set("CF-IPCountry", "PL")
+			request.Headers.Set("User-Agent", "test-agent")
+
+			// when
+			tt.server.removeProxyOnlyHeaders(&fasthttp.RequestCtx{}, request)
+
+			// then
+			assert.Equal(t, tt.expectedCountry, request.Headers.Get("CF-IPCountry"))
+			assert.Equal(t, "test-agent", request.Headers.Get("User-Agent"))
+		})
+	}
+}
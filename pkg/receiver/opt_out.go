@@ -0,0 +1,87 @@
+package receiver
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/privacysignals"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+)
+
+// ErrHitOptedOut is returned by OptOutEnforcement for dropped hits of visitors who opted
+// out. Requests whose every hit opted out aren't stored in the raw log either.
+var ErrHitOptedOut = fmt.Errorf("%w: visitor opted out", ErrHitDropped)
+
+// OptOutEnforcement returns a hit processing rule enforcing the opt-out mode of the
+// property on hits with privacy signals, like Sec-GPC or denied GA4 analytics storage.
+// Hits are dropped, stripped of identifiers or just marked with the privacysignals
+// metadata, read by the privacy_opt_out column. It needs to run after the rules relying
+// on identifiers, like rate limiting, and before IP masking.
+func OptOutEnforcement(settings properties.SettingsRegistry) HitProcessingRule {
+	return NewSimpleHitProcessingRule(func(p protocol.Protocol, hit *hits.Hit) error {
+		propertySettings, err := settings.GetByPropertyID(hit.PropertyID)
+		if err != nil {
+			return err
+		}
+
+		mode := propertySettings.OptOutMode
+		if mode == "" || mode == properties.OptOutIgnore {
+			return nil
+		}
+
+		optedOut := len(privacysignals.Signals(hit.MustParsedRequest())) > 0
+		hit.Metadata[privacysignals.OptOutMetadataKey] = "false"
+		if !optedOut {
+			return nil
+		}
+		hit.Metadata[privacysignals.OptOutMetadataKey] = "true"
+
+		switch mode {
+		case properties.OptOutDrop:
+			return ErrHitOptedOut
+		case properties.OptOutStrip:
+			stripIdentifiers(p, hit)
+		}
+		return nil
+	})
+}
+
+// stripIdentifiers removes what links the hit to the visitor. The client ID is replaced
+// with the random ID of the hit, so that every stripped hit is a session of its own.
+func stripIdentifiers(p protocol.Protocol, hit *hits.Hit) {
+	hit.ClientID = hits.ClientID(hit.ID)
+	hit.AuthoritativeClientID = hit.ClientID
+	hit.UserID = nil
+	request := hit.MustParsedRequest()
+	request.IP = maskIPByPrivacyLevel(request.IP, 4)
+	if provider, ok := p.(protocol.PageLocationProvider); ok {
+		if location := provider.PageLocation(hit); location != "" {
+			provider.SetPageLocation(hit, withoutClickIDs(location))
+		}
+	}
+	hit.Metadata[privacysignals.IdentifiersStrippedMetadataKey] = "true"
+}
+
+// withoutClickIDs removes the ad click identifiers from the query of a URL, keeping the
+// URL as it is if it has none.
+func withoutClickIDs(location string) string {
+	parsed, err := url.Parse(location)
+	if err != nil {
+		return location
+	}
+	query := parsed.Query()
+	stripped := false
+	for _, param := range privacysignals.ClickIDParams {
+		if query.Has(param) {
+			query.Del(param)
+			stripped = true
+		}
+	}
+	if !stripped {
+		return location
+	}
+	parsed.RawQuery = query.Encode()
+	return parsed.String()
+}
@@ -0,0 +1,207 @@
+package receiver
+
+import (
+	"context"
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/privacysignals"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+func TestOptOutEnforcement(t *testing.T) {
+	testCases := []struct {
+		name             string
+		mode             properties.OptOutMode
+		gpc              bool
+		expectedErr      error
+		expectedMetadata map[string]string
+		expectStripped   bool
+	}{
+		{
+			name:             "ignore does not read signals",
+			mode:             properties.OptOutIgnore,
+			gpc:              true,
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name:             "unset mode ignores signals",
+			gpc:              true,
+			expectedMetadata: map[string]string{},
+		},
+		{
+			name: "flag marks opted-out visitors",
+			mode: properties.OptOutFlag,
+			gpc:  true,
+			expectedMetadata: map[string]string{
+				privacysignals.OptOutMetadataKey: "true",
+			},
+		},
+		{
+			name: "flag marks other visitors",
+			mode: properties.OptOutFlag,
+			expectedMetadata: map[string]string{
+				privacysignals.OptOutMetadataKey: "false",
+			},
+		},
+		{
+			name: "strip removes identifiers of opted-out visitors",
+			mode: properties.OptOutStrip,
+			gpc:  true,
+			expectedMetadata: map[string]string{
+				privacysignals.OptOutMetadataKey:              "true",
+				privacysignals.IdentifiersStrippedMetadataKey: "true",
+			},
+			expectStripped: true,
+		},
+		{
+			name: "strip keeps identifiers of other visitors",
+			mode: properties.OptOutStrip,
+			expectedMetadata: map[string]string{
+				privacysignals.OptOutMetadataKey: "false",
+			},
+		},
+		{
+			name:        "drop drops opted-out visitors",
+			mode:        properties.OptOutDrop,
+			gpc:         true,
+			expectedErr: ErrHitOptedOut,
+		},
+		{
+			name: "drop keeps other visitors",
+			mode: properties.OptOutDrop,
+			expectedMetadata: map[string]string{
+				privacysignals.OptOutMetadataKey: "false",
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+				"test_property_id": {PropertyID: "test_property_id", OptOutMode: tc.mode},
+			}}
+			hit := hits.New()
+			hit.PropertyID = "test_property_id"
+			hit.ClientID = "client-1"
+			hit.AuthoritativeClientID = "client-1"
+			userID := "user-1"
+			hit.UserID = &userID
+			hit.Request.IP = "203.0.113.7"
+			hit.Request.QueryParams.Set("dl", "https://shop.example.com/?gclid=abc&fbclid=def&page=2")
+			if tc.gpc {
+				hit.Request.Headers.Set("Sec-GPC", "1")
+			}
+			p := &pageLocationProtocol{mockProtocol{id: "test_protocol"}}
+
+			// when
+			err := OptOutEnforcement(settings).Process(p, hit)
+
+			// then
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMetadata, hit.Metadata)
+			if !tc.expectStripped {
+				assert.Equal(t, hits.ClientID("client-1"), hit.ClientID)
+				assert.Equal(t, &userID, hit.UserID)
+				assert.Equal(t, "203.0.113.7", hit.Request.IP)
+				return
+			}
+			assert.Equal(t, hits.ClientID(hit.ID), hit.ClientID)
+			assert.Equal(t, hits.ClientID(hit.ID), hit.AuthoritativeClientID)
+			assert.Nil(t, hit.UserID)
+			assert.Equal(t, "0.0.0.0", hit.Request.IP)
+			assert.Equal(t, "https://shop.example.com/?page=2", p.PageLocation(hit))
+		})
+	}
+}
+
+func TestWithoutClickIDs(t *testing.T) {
+	testCases := []struct {
+		name     string
+		location string
+		expected string
+	}{
+		{
+			name:     "removes click IDs",
+			location: "https://shop.example.com/product?msclkid=1&id=7#reviews",
+			expected: "https://shop.example.com/product?id=7#reviews",
+		},
+		{
+			name:     "keeps URL without click IDs as it is",
+			location: "https://shop.example.com/product?z=1&a=2",
+			expected: "https://shop.example.com/product?z=1&a=2",
+		},
+		{
+			name:     "keeps unparsable URL",
+			location: "://broken",
+			expected: "://broken",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			result := withoutClickIDs(tc.location)
+
+			// then
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestHandleRequest_DoesNotStoreRawLogOfOptedOutVisitors(t *testing.T) {
+	testCases := []struct {
+		name            string
+		gpc             bool
+		expectedRawLogs int
+	}{
+		{name: "opted-out visitor", gpc: true},
+		{name: "other visitor", expectedRawLogs: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+				"test_property_id": {
+					PropertyID: "test_property_id",
+					ProtocolID: "test_protocol",
+					OptOutMode: properties.OptOutDrop,
+				},
+			}}
+			rawLogStorage := &capturingRawLogStorage{}
+			p := &mockProtocol{id: "test_protocol"}
+			server := NewServer(
+				&mockStorage{},
+				rawLogStorage,
+				HitValidatingRuleSet(1024*128, settings),
+				[]protocol.Protocol{p},
+				8080,
+				WithHitProcessingRule(OptOutEnforcement(settings)),
+			)
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetMethod(fasthttp.MethodPost)
+			ctx.Request.Header.SetHost("example.com")
+			ctx.URI().SetPath("/collect")
+			if tc.gpc {
+				ctx.Request.Header.Set("Sec-GPC", "1")
+			}
+
+			// when
+			server.handleRequest(context.Background(), ctx, p)
+
+			// then
+			assert.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+			assert.Len(t, rawLogStorage.requests, tc.expectedRawLogs)
+		})
+	}
+}
@@ -0,0 +1,147 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/botdetection"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+	"golang.org/x/time/rate"
+)
+
+// RateLimitedMetadataKey is the hit metadata key holding the rate limit a hit exceeded,
+// set for properties which tag hits over the limit instead of rejecting them.
+const RateLimitedMetadataKey = "rate_limited"
+
+// ErrRateLimited is returned by hit processing rules for hits over a rate limit. The
+// request is responded to with 429 Too Many Requests.
+var ErrRateLimited = errors.New("rate limit exceeded")
+
+const rateLimiterSweepInterval = time.Minute
+
+var rateLimitDecisionCounter metric.Int64Counter
+
+func init() {
+	rateLimitDecisionCounter, _ = otel.GetMeterProvider().Meter("receiver").Int64Counter(
+		"receiver.rate_limit.decisions",
+		metric.WithDescription("Rate limit decisions for hits, by limit and decision"),
+	)
+}
+
+type rateLimiterEntry struct {
+	limit    properties.RateLimit
+	limiter  *rate.Limiter
+	lastSeen time.Time
+}
+
+type rateLimiter struct {
+	settings  properties.SettingsRegistry
+	mu        sync.Mutex
+	entries   map[string]*rateLimiterEntry
+	lastSweep time.Time
+}
+
+// RateLimiting returns a hit processing rule enforcing the token bucket rate limits of the
+// property, per client IP, per client ID and per property. Buckets are refilled by the
+// ServerReceivedTime of the hits. Buckets are kept in memory of the process, so replayed
+// requests can't be limited as they were originally, leave the rule out of the ones used
+// for them, see WithReplayHitProcessingRule.
+// Hits over a limit fail with ErrRateLimited or, if the property only tags them, get the
+// RateLimitedMetadataKey and the traffic filter name. It needs to run before IP masking,
+// for clients behind the same masked IP to be limited separately.
+func RateLimiting(settings properties.SettingsRegistry) HitProcessingRule {
+	l := &rateLimiter{
+		settings: settings,
+		entries:  map[string]*rateLimiterEntry{},
+	}
+	return NewSimpleHitProcessingRule(l.process)
+}
+
+func (l *rateLimiter) process(_ protocol.Protocol, hit *hits.Hit) error {
+	propertySettings, err := l.settings.GetByPropertyID(hit.PropertyID)
+	if err != nil {
+		return err
+	}
+	limits := propertySettings.RateLimits
+	request := hit.MustParsedRequest()
+
+	for _, check := range []struct {
+		name  string
+		key   string
+		limit properties.RateLimit
+	}{
+		{name: "per_ip", key: request.IP, limit: limits.PerIP},
+		{name: "per_client_id", key: string(hit.ClientID), limit: limits.PerClientID},
+		{name: "per_property", limit: limits.PerProperty},
+	} {
+		if !check.limit.Enabled() {
+			continue
+		}
+		key := check.name + "\x00" + hit.PropertyID + "\x00" + check.key
+		if l.allow(key, check.limit, request.ServerReceivedTime) {
+			recordRateLimitDecision(check.name, "allowed")
+			continue
+		}
+		if !limits.TagOnly {
+			recordRateLimitDecision(check.name, "rejected")
+			return fmt.Errorf("%w: %s for property %s", ErrRateLimited, check.name, hit.PropertyID)
+		}
+		recordRateLimitDecision(check.name, "tagged")
+		hit.Metadata[RateLimitedMetadataKey] = check.name
+		if _, ok := hit.Metadata[botdetection.TrafficFilterNameMetadataKey]; !ok {
+			hit.Metadata[botdetection.TrafficFilterNameMetadataKey] = "rate limit: " + check.name
+		}
+		return nil
+	}
+	return nil
+}
+
+func (l *rateLimiter) allow(key string, limit properties.RateLimit, now time.Time) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if now.Sub(l.lastSweep) >= rateLimiterSweepInterval {
+		l.sweep(now)
+		l.lastSweep = now
+	}
+
+	entry, ok := l.entries[key]
+	// Settings of the property may have changed since the bucket was created
+	if !ok || entry.limit != limit {
+		entry = &rateLimiterEntry{
+			limit:   limit,
+			limiter: rate.NewLimiter(rate.Limit(limit.Rate), limit.Burst),
+		}
+		l.entries[key] = entry
+	}
+	if now.After(entry.lastSeen) {
+		entry.lastSeen = now
+	}
+	return entry.limiter.AllowN(now, 1)
+}
+
+// sweep removes buckets which have refilled completely, they're no different from new ones.
+func (l *rateLimiter) sweep(now time.Time) {
+	for key, entry := range l.entries {
+		refill := time.Duration(float64(entry.limit.Burst) / entry.limit.Rate * float64(time.Second))
+		if now.Sub(entry.lastSeen) > refill {
+			delete(l.entries, key)
+		}
+	}
+}
+
+func recordRateLimitDecision(limit, decision string) {
+	rateLimitDecisionCounter.Add(context.Background(), 1,
+		metric.WithAttributes(
+			attribute.String("limit", limit),
+			attribute.String("decision", decision),
+		))
+}
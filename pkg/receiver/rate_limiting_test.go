@@ -0,0 +1,207 @@
+package receiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/botdetection"
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+type rateLimitedHit struct {
+	ip       string
+	clientID string
+	offset   time.Duration
+}
+
+func TestRateLimiting(t *testing.T) {
+	start := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	testCases := []struct {
+		name           string
+		limits         properties.RateLimitSettings
+		hits           []rateLimitedHit
+		expectedErrors []bool
+		expectedTags   []string
+	}{
+		{
+			name:           "no limits",
+			hits:           []rateLimitedHit{{ip: "10.0.0.1"}, {ip: "10.0.0.1"}, {ip: "10.0.0.1"}},
+			expectedErrors: []bool{false, false, false},
+		},
+		{
+			name:   "per IP limit rejects burst from a single IP",
+			limits: properties.RateLimitSettings{PerIP: properties.RateLimit{Rate: 1, Burst: 2}},
+			hits: []rateLimitedHit{
+				{ip: "10.0.0.1"}, {ip: "10.0.0.1"}, {ip: "10.0.0.1"}, {ip: "10.0.0.2"},
+			},
+			expectedErrors: []bool{false, false, true, false},
+		},
+		{
+			name:   "bucket refills with received time",
+			limits: properties.RateLimitSettings{PerIP: properties.RateLimit{Rate: 1, Burst: 1}},
+			hits: []rateLimitedHit{
+				{ip: "10.0.0.1"}, {ip: "10.0.0.1", offset: 500 * time.Millisecond}, {ip: "10.0.0.1", offset: time.Second},
+			},
+			expectedErrors: []bool{false, true, false},
+		},
+		{
+			name:   "per client ID limit",
+			limits: properties.RateLimitSettings{PerClientID: properties.RateLimit{Rate: 1, Burst: 1}},
+			hits: []rateLimitedHit{
+				{ip: "10.0.0.1", clientID: "a"}, {ip: "10.0.0.2", clientID: "a"}, {ip: "10.0.0.1", clientID: "b"},
+			},
+			expectedErrors: []bool{false, true, false},
+		},
+		{
+			name:           "per property limit",
+			limits:         properties.RateLimitSettings{PerProperty: properties.RateLimit{Rate: 1, Burst: 2}},
+			hits:           []rateLimitedHit{{ip: "10.0.0.1"}, {ip: "10.0.0.2"}, {ip: "10.0.0.3"}},
+			expectedErrors: []bool{false, false, true},
+		},
+		{
+			name: "tag only keeps hits over the limit",
+			limits: properties.RateLimitSettings{
+				PerIP:   properties.RateLimit{Rate: 1, Burst: 1},
+				TagOnly: true,
+			},
+			hits:           []rateLimitedHit{{ip: "10.0.0.1"}, {ip: "10.0.0.1"}},
+			expectedErrors: []bool{false, false},
+			expectedTags:   []string{"", "per_ip"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+				"test_property_id": {PropertyID: "test_property_id", RateLimits: tc.limits},
+			}}
+			rule := RateLimiting(settings)
+
+			for i, h := range tc.hits {
+				hit := hits.New()
+				hit.PropertyID = "test_property_id"
+				hit.ClientID = hits.ClientID(h.clientID)
+				hit.Request.IP = h.ip
+				hit.Request.ServerReceivedTime = start.Add(h.offset)
+
+				// when
+				err := rule.Process(nil, hit)
+
+				// then
+				if tc.expectedErrors[i] {
+					assert.ErrorIs(t, err, ErrRateLimited, "hit %d", i)
+				} else {
+					assert.NoError(t, err, "hit %d", i)
+				}
+				if tc.expectedTags != nil {
+					assert.Equal(t, tc.expectedTags[i], hit.Metadata[RateLimitedMetadataKey], "hit %d", i)
+					if tc.expectedTags[i] != "" {
+						assert.Equal(t, "rate limit: "+tc.expectedTags[i],
+							hit.Metadata[botdetection.TrafficFilterNameMetadataKey])
+					}
+				}
+			}
+		})
+	}
+}
+
+func TestRateLimiting_SweepsRefilledBuckets(t *testing.T) {
+	// given
+	start := time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC)
+	limiter := &rateLimiter{entries: map[string]*rateLimiterEntry{}}
+	limit := properties.RateLimit{Rate: 1, Burst: 10}
+	limiter.allow("idle", limit, start)
+	limiter.allow("active", limit, start.Add(55*time.Second))
+
+	// when
+	limiter.allow("active", limit, start.Add(rateLimiterSweepInterval))
+
+	// then
+	assert.NotContains(t, limiter.entries, "idle")
+	assert.Contains(t, limiter.entries, "active")
+}
+
+func TestHandleRequest_RateLimitedRequestGets429(t *testing.T) {
+	// given
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"test_property_id": {
+			PropertyID: "test_property_id",
+			ProtocolID: "test_protocol",
+			RateLimits: properties.RateLimitSettings{PerIP: properties.RateLimit{Rate: 1, Burst: 1}},
+		},
+	}}
+	storage := &mockStorage{}
+	p := &mockProtocol{id: "test_protocol"}
+	server := NewServer(
+		storage,
+		&capturingRawLogStorage{},
+		HitValidatingRuleSet(1024*128, settings),
+		[]protocol.Protocol{p},
+		8080,
+		WithTrustAllProxies(),
+		WithHitProcessingRule(RateLimiting(settings)),
+	)
+	newCtx := func() *fasthttp.RequestCtx {
+		ctx := &fasthttp.RequestCtx{}
+		ctx.Request.Header.SetHost("example.com")
+		ctx.Request.Header.Set("X-Real-IP", "192.168.1.1")
+		ctx.URI().SetPath("/collect")
+		return ctx
+	}
+	first, second := newCtx(), newCtx()
+
+	// when
+	server.handleRequest(context.Background(), first, p)
+	server.handleRequest(context.Background(), second, p)
+
+	// then
+	assert.Equal(t, fasthttp.StatusNoContent, first.Response.StatusCode())
+	assert.Equal(t, fasthttp.StatusTooManyRequests, second.Response.StatusCode())
+	assert.Equal(t, "1", string(second.Response.Header.Peek("Retry-After")))
+	require.Len(t, storage.hits, 1)
+}
+
+func TestReplayer_SkipsRateLimitsWithReplayHitProcessingRule(t *testing.T) {
+	// given
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"test_property_id": {
+			PropertyID: "test_property_id",
+			ProtocolID: "test_protocol",
+			RateLimits: properties.RateLimitSettings{PerIP: properties.RateLimit{Rate: 1, Burst: 1}},
+		},
+	}}
+	storage := &mockStorage{}
+	server := NewServer(
+		storage,
+		NewDummyRawLogStorage(),
+		HitValidatingRuleSet(1024*128, settings),
+		[]protocol.Protocol{&mockProtocol{id: "test_protocol"}},
+		8080,
+		WithHitProcessingRule(RateLimiting(settings)),
+		WithReplayHitProcessingRule(NoopHitProcessingRule),
+	)
+	replayer := server.Replayer(context.Background())
+	request := &hits.ParsedRequest{
+		IP:                 "192.168.1.1",
+		Host:               "example.com",
+		Method:             fasthttp.MethodPost,
+		Path:               "/collect",
+		ServerReceivedTime: time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC),
+	}
+
+	// when
+	firstErr := replayer.Replay(request)
+	secondErr := replayer.Replay(request)
+
+	// then
+	require.NoError(t, firstErr)
+	require.NoError(t, secondErr)
+	assert.Len(t, storage.hits, 1)
+}
@@ -0,0 +1,63 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/valyala/fasthttp"
+)
+
+// ErrReplayRejected is returned for replayed requests the receiver responds to with a client
+// error, e.g. requests which were invalid when they were received in the first place.
+var ErrReplayRejected = errors.New("replayed request rejected")
+
+// Replayer processes previously received requests, e.g. read from the raw log, the same way
+// as requests received over HTTP: they're routed to the protocol of their endpoint and go
+// through the processing and validation rules into the storage. The IP and the
+// ServerReceivedTime of the original request are kept and the request isn't stored in the
+// raw log again.
+type Replayer struct {
+	handler fasthttp.RequestHandler
+}
+
+// Replayer creates a Replayer using the endpoints of the server.
+func (s *Server) Replayer(ctx context.Context) *Replayer {
+	return &Replayer{handler: s.setupRouter(ctx).Handler}
+}
+
+// Replay processes a single request. It returns an error wrapping ErrReplayRejected if the
+// request is rejected, any other error means hits could not be stored.
+func (r *Replayer) Replay(request *hits.ParsedRequest) error {
+	fctx := &fasthttp.RequestCtx{}
+	for key, values := range request.Headers {
+		for _, value := range values {
+			fctx.Request.Header.Add(key, value)
+		}
+	}
+	fctx.Request.Header.SetMethod(request.Method)
+	uri := request.Path
+	if len(request.QueryParams) > 0 {
+		uri += "?" + request.QueryParams.Encode()
+	}
+	fctx.Request.SetRequestURI(uri)
+	fctx.Request.Header.SetHost(request.Host)
+	fctx.Request.SetBody(request.Body)
+	protocol.SetReplayedRequest(fctx, request)
+
+	r.handler(fctx)
+
+	statusCode := fctx.Response.StatusCode()
+	switch {
+	case statusCode < fasthttp.StatusBadRequest:
+		return nil
+	case statusCode < fasthttp.StatusInternalServerError:
+		return fmt.Errorf("%w: %s %s: status %d: %s",
+			ErrReplayRejected, request.Method, request.Path, statusCode, fctx.Response.Body())
+	default:
+		return fmt.Errorf("replaying %s %s: status %d: %s",
+			request.Method, request.Path, statusCode, fctx.Response.Body())
+	}
+}
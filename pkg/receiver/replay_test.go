@@ -0,0 +1,167 @@
+package receiver
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+var replayTestSettings = settingsRegistryStub{
+	settingsByPropertyID: map[string]*properties.Settings{
+		"test_property_id": {PropertyID: "test_property_id", ProtocolID: "test_protocol"},
+	},
+}
+
+func TestReplayer(t *testing.T) {
+	receivedAt := time.Date(2026, 3, 7, 10, 29, 59, 0, time.UTC)
+	testCases := []struct {
+		name          string
+		request       *hits.ParsedRequest
+		storageErr    error
+		expectedError error
+		expectError   bool
+	}{
+		{
+			name: "replays request",
+			request: &hits.ParsedRequest{
+				IP:                 "10.0.0.1",
+				Host:               "example.com",
+				Method:             fasthttp.MethodPost,
+				Path:               "/collect",
+				ServerReceivedTime: receivedAt,
+				QueryParams:        url.Values{"param1": {"value1"}},
+				Headers:            http.Header{"User-Agent": {"test-agent"}},
+				Body:               []byte("body"),
+			},
+		},
+		{
+			name: "unknown endpoint",
+			request: &hits.ParsedRequest{
+				IP:                 "10.0.0.1",
+				Method:             fasthttp.MethodPost,
+				Path:               "/unknown",
+				ServerReceivedTime: receivedAt,
+			},
+			expectedError: ErrReplayRejected,
+		},
+		{
+			name: "storage error",
+			request: &hits.ParsedRequest{
+				IP:                 "10.0.0.1",
+				Host:               "example.com",
+				Headers:            http.Header{"User-Agent": {"test-agent"}},
+				Method:             fasthttp.MethodPost,
+				Path:               "/collect",
+				ServerReceivedTime: receivedAt,
+			},
+			storageErr:  assert.AnError,
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			storage := &mockStorage{err: tc.storageErr}
+			rawLogStorage := &capturingRawLogStorage{}
+			server := NewServer(
+				storage,
+				rawLogStorage,
+				HitValidatingRuleSet(1024*128, replayTestSettings),
+				[]protocol.Protocol{&mockProtocol{id: "test_protocol"}},
+				8080,
+				WithTrustAllProxies(),
+			)
+
+			// when
+			err := server.Replayer(context.Background()).Replay(tc.request)
+
+			// then
+			assert.Empty(t, rawLogStorage.requests)
+			if tc.expectedError != nil {
+				assert.ErrorIs(t, err, tc.expectedError)
+				return
+			}
+			if tc.expectError {
+				require.Error(t, err)
+				assert.NotErrorIs(t, err, ErrReplayRejected)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, storage.hits, 1)
+			request := storage.hits[0].MustParsedRequest()
+			assert.Equal(t, "10.0.0.1", request.IP)
+			assert.Equal(t, receivedAt, request.ServerReceivedTime)
+			assert.Equal(t, "example.com", request.Host)
+			assert.Equal(t, []string{"value1"}, request.QueryParams["param1"])
+			assert.Equal(t, []string{"test-agent"}, request.Headers["User-Agent"])
+			assert.Equal(t, []byte("body"), request.Body)
+			assert.Equal(t, "test_protocol", storage.hits[0].Metadata[HitProtocolMetadataKey])
+		})
+	}
+}
+
+func TestHandleRequest_RawLogKeepsOriginalRequest(t *testing.T) {
+	// given
+	rawLogStorage := &capturingRawLogStorage{}
+	p := &mockProtocol{id: "test_protocol", rewriteQuery: true}
+	server := NewServer(
+		&mockStorage{},
+		rawLogStorage,
+		HitValidatingRuleSet(1024*128, replayTestSettings),
+		[]protocol.Protocol{p},
+		8080,
+	)
+	ctx := &fasthttp.RequestCtx{}
+	ctx.Request.Header.SetHost("example.com")
+	ctx.URI().SetPath("/collect")
+	ctx.URI().SetQueryString("api_secret=s3cret")
+
+	// when
+	server.handleRequest(context.Background(), ctx, p)
+
+	// then
+	assert.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
+	require.Len(t, rawLogStorage.requests, 1)
+	assert.Equal(t, url.Values{"api_secret": {"s3cret"}}, rawLogStorage.requests[0].QueryParams)
+}
+
+func TestReplayer_MasksOriginalIP(t *testing.T) {
+	// given
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"test_property_id": {PropertyID: "test_property_id", ProtocolID: "test_protocol", IPMaskingLevel: 1},
+	}}
+	storage := &mockStorage{}
+	server := NewServer(
+		storage,
+		NewDummyRawLogStorage(),
+		HitValidatingRuleSet(1024*128, settings),
+		[]protocol.Protocol{&mockProtocol{id: "test_protocol"}},
+		8080,
+		WithHitProcessingRule(IPMasking(settings)),
+	)
+
+	// when
+	err := server.Replayer(context.Background()).Replay(&hits.ParsedRequest{
+		IP:                 "192.168.1.123",
+		Host:               "example.com",
+		Method:             fasthttp.MethodPost,
+		Path:               "/collect",
+		ServerReceivedTime: time.Date(2026, 3, 7, 10, 29, 59, 0, time.UTC),
+		Headers:            http.Header{"User-Agent": {"test-agent"}},
+	})
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, storage.hits, 1)
+	assert.Equal(t, "192.168.1.0", storage.hits[0].MustParsedRequest().IP)
+}
@@ -12,10 +12,12 @@ import (
 
 	"github.com/d8a-tech/d8a/pkg/hits"
 	"github.com/d8a-tech/d8a/pkg/monitoring"
+	"github.com/d8a-tech/d8a/pkg/properties"
 	"github.com/d8a-tech/d8a/pkg/protocol"
 	"github.com/fasthttp/router"
 	"github.com/sirupsen/logrus"
 	"github.com/valyala/fasthttp"
+	"github.com/valyala/fasthttp/fasthttpadaptor"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/metric"
@@ -118,13 +120,18 @@ type Server struct {
 	storage            Storage
 	rawLogStorage      RawLogStorage
 	hitProcessingRules HitProcessingRule
+	replayHitRules     HitProcessingRule
 	validationRules    HitValidatingRule
+	replayValidation   HitValidatingRule
 	host               string
 	port               int
 	proxyTrust         ProxyTrust
+	proxyOnlyHeaders   []string
 	readTimeout        time.Duration
 	writeTimeout       time.Duration
 	maxConcurrency     int
+	ingestionAuth      properties.SettingsRegistry
+	metricsHandler     http.Handler
 }
 
 func WithHost(host string) ServerOption {
@@ -163,6 +170,32 @@ func WithHitProcessingRule(rule HitProcessingRule) ServerOption {
 	}
 }
 
+// WithReplayHitProcessingRule sets the hit processing rule of replayed requests, e.g. one
+// leaving out rate limits, which were enforced when the requests were received. Replayed
+// requests use the rule of received requests by default.
+func WithReplayHitProcessingRule(rule HitProcessingRule) ServerOption {
+	return func(s *Server) {
+		s.replayHitRules = rule
+	}
+}
+
+// WithReplayHitValidatingRule sets the hit validating rule of replayed requests, e.g. one
+// leaving out rules recording metrics, which were recorded when the requests were received.
+// Replayed requests use the rule of received requests by default.
+func WithReplayHitValidatingRule(rule HitValidatingRule) ServerOption {
+	return func(s *Server) {
+		s.replayValidation = rule
+	}
+}
+
+// WithMetricsHandler serves the handler, e.g. exposing metrics to Prometheus scrapes, on
+// the /metrics path of the server.
+func WithMetricsHandler(handler http.Handler) ServerOption {
+	return func(s *Server) {
+		s.metricsHandler = handler
+	}
+}
+
 type ServerOption func(*Server)
 
 // NewServer creates a new Server instance with the provided dependencies
@@ -203,6 +236,17 @@ func (s *Server) handleRequest(
 	var err error
 
 	hits, err := s.createHits(ctx, selectedProtocol)
+	if errors.Is(err, ErrRateLimited) {
+		logrus.WithError(err).Debug("rejecting request over rate limit")
+		ctx.Error("Too Many Requests", fasthttp.StatusTooManyRequests)
+		ctx.Response.Header.Set("Retry-After", "1")
+		return
+	}
+	if errors.Is(err, ErrUnauthorized) {
+		logrus.WithError(err).Warn("rejecting unauthenticated request")
+		ctx.Error("Unauthorized", fasthttp.StatusUnauthorized)
+		return
+	}
 	if err != nil {
 		logrus.WithError(err).Warn("failed to create hits from request")
 		var clientErr safeClientError
@@ -272,31 +316,77 @@ func (s *Server) createHits(ctx *fasthttp.RequestCtx, p protocol.Protocol) ([]*h
 		Headers:            headers,
 		Body:               bodyCopy,
 	}
+	replayedRequest, isReplayed := protocol.ReplayedRequest(ctx)
+	authenticatedPropertyID := ""
+	if authenticated, _ := ctx.UserValue(authenticatedRequestKey).(bool); authenticated {
+		propertyID, err := s.authenticate(ctx, request)
+		if err != nil {
+			return nil, err
+		}
+		authenticatedPropertyID = propertyID
+	}
+	// Replayed requests keep the IP and the time of the original request, proxy-only headers
+	// were removed when it was received
+	if isReplayed {
+		request.IP = replayedRequest.IP
+		request.ServerReceivedTime = replayedRequest.ServerReceivedTime
+	} else {
+		s.removeProxyOnlyHeaders(ctx, request)
+		// The header tells authenticated requests apart, see requestAuthenticated
+		if authenticatedPropertyID == "" {
+			request.Headers.Del(PropertyIDHeader)
+		}
+	}
 
 	hits, err := p.Hits(ctx, request)
 	if err != nil {
 		return nil, err
 	}
 
+	rules, validationRules := s.hitProcessingRules, s.validationRules
+	if isReplayed && s.replayHitRules != nil {
+		rules = s.replayHitRules
+	}
+	if isReplayed && s.replayValidation != nil {
+		validationRules = s.replayValidation
+	}
+
+	hitCount, optedOutCount := len(hits), 0
+	keptHits := hits[:0]
 	for _, hit := range hits {
-		if err := s.hitProcessingRules.Process(p, hit); err != nil {
+		if authenticatedPropertyID != "" && hit.PropertyID != authenticatedPropertyID {
+			return nil, fmt.Errorf("%w: hit of property %q sent with credentials of property %q",
+				ErrUnauthorized, hit.PropertyID, authenticatedPropertyID)
+		}
+		err := rules.Process(p, hit)
+		if errors.Is(err, ErrHitOptedOut) {
+			optedOutCount++
+		}
+		if errors.Is(err, ErrHitDropped) {
+			continue
+		}
+		if err != nil {
 			return nil, err
 		}
-		if err := s.validationRules.Validate(p, hit); err != nil {
+		if err := validationRules.Validate(p, hit); err != nil {
 			return nil, err
 		}
+		keptHits = append(keptHits, hit)
 	}
 
-	rawLogRequest := request
-	if len(hits) > 0 {
-		rawLogRequest = hits[0].Request
+	// Requests of visitors who opted out aren't kept for replays either
+	if isReplayed || (hitCount > 0 && optedOutCount == hitCount) {
+		return keptHits, nil
 	}
 
-	if err := s.rawLogStorage.Store(rawLogRequest); err != nil {
+	// Protocols and processing rules work on copies of the request in hits, the raw log keeps
+	// the request as it was received, with its original IP. IP masking and the other rules
+	// are applied again when it's replayed.
+	if err := s.rawLogStorage.Store(request); err != nil {
 		logrus.Errorf("failed to store raw log: %v", err)
 	}
 
-	return hits, nil
+	return keptHits, nil
 }
 
 // Run starts the HTTP server and blocks until the context is cancelled or an error occurs
@@ -384,6 +474,17 @@ func (s *Server) setupRouter(ctx context.Context) *router.Router {
 					}()
 					s.handleRequest(ctx, fctx, protocol)
 				})
+				if s.ingestionAuth == nil {
+					continue
+				}
+				r.Handle(method, AuthenticatedPathPrefix+endpoint.Path, func(fctx *fasthttp.RequestCtx) {
+					start := time.Now()
+					defer func() {
+						recordRequestMetrics(ctx, fctx.Response.StatusCode(), start)
+					}()
+					fctx.SetUserValue(authenticatedRequestKey, true)
+					s.handleRequest(ctx, fctx, protocol)
+				})
 			}
 		}
 	}
@@ -393,5 +494,10 @@ func (s *Server) setupRouter(ctx context.Context) *router.Router {
 			fctx.SetBodyString("OK")
 		})
 	}
+	if s.metricsHandler != nil {
+		metricsHandler := fasthttpadaptor.NewFastHTTPHandler(s.metricsHandler)
+		r.GET("/metrics", metricsHandler)
+		r.HEAD("/metrics", metricsHandler)
+	}
 	return r
 }
@@ -3,6 +3,7 @@ package receiver
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	"github.com/d8a-tech/d8a/pkg/currency"
 	"github.com/d8a-tech/d8a/pkg/hits"
@@ -10,6 +11,9 @@ import (
 	"github.com/d8a-tech/d8a/pkg/protocol"
 	"github.com/d8a-tech/d8a/pkg/protocol/d8a"
 	"github.com/d8a-tech/d8a/pkg/protocol/ga4"
+	"github.com/d8a-tech/d8a/pkg/protocol/matomo"
+	"github.com/d8a-tech/d8a/pkg/protocol/plausible"
+	"github.com/d8a-tech/d8a/pkg/protocol/snowplow"
 	"github.com/stretchr/testify/assert"
 	"github.com/valyala/fasthttp"
 )
@@ -39,6 +43,10 @@ func TestCORS_GA4_Collect_Preflight_MirrorsOriginAndCredentials(t *testing.T) {
 		currency.NewDummyConverter(1),
 		properties.NewTestSettingRegistry(),
 		ga4.WithPropertyIDExtractor(&staticPropertyIDExtractorCORS{propertyID: "test_property_id"}),
+		ga4.WithDefaultCORSSettings(properties.CORSSettings{
+			AllowedOrigins:   []string{"https://example.test"},
+			AllowCredentials: true,
+		}),
 	)
 	server := NewServer(
 		storage,
@@ -70,6 +78,40 @@ func TestCORS_GA4_Collect_Preflight_MirrorsOriginAndCredentials(t *testing.T) {
 	assert.Equal(t, "content-type,x-requested-with", string(resp.Header.Peek("Access-Control-Allow-Headers")))
 }
 
+func TestCORS_GA4_Collect_Preflight_DefaultsToWildcardWithoutCredentials(t *testing.T) {
+	// given
+	storage := &captureStorageCORS{}
+	settingsRegistry := buildSettingsRegistry("ga4", "test_property_id", "Test")
+	ga4Protocol := ga4.NewGA4Protocol(
+		currency.NewDummyConverter(1),
+		properties.NewTestSettingRegistry(),
+		ga4.WithPropertyIDExtractor(&staticPropertyIDExtractorCORS{propertyID: "test_property_id"}),
+	)
+	server := NewServer(
+		storage,
+		NewDummyRawLogStorage(),
+		HitValidatingRuleSet(1024*128, settingsRegistry),
+		[]protocol.Protocol{ga4Protocol},
+		9999,
+	)
+	r := newInmemReceiver(t, server)
+
+	req := fasthttp.AcquireRequest()
+	t.Cleanup(func() { fasthttp.ReleaseRequest(req) })
+	req.SetRequestURI("http://localhost/g/collect")
+	req.Header.SetMethod(fasthttp.MethodOptions)
+	req.Header.Set("Origin", "https://example.test")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+
+	// when
+	resp := r.do(req)
+
+	// then
+	assert.Equal(t, fasthttp.StatusNoContent, resp.StatusCode())
+	assert.Equal(t, "*", string(resp.Header.Peek("Access-Control-Allow-Origin")))
+	assert.Empty(t, string(resp.Header.Peek("Access-Control-Allow-Credentials")))
+}
+
 func TestCORS_GA4_Collect_GET_FallbacksToWildcardWithoutOrigin(t *testing.T) {
 	// given
 	storage := &captureStorageCORS{}
@@ -145,6 +187,10 @@ func TestCORS_D8A_Collect_Preflight_MirrorsOriginAndCredentials(t *testing.T) {
 		currency.NewDummyConverter(1),
 		properties.NewTestSettingRegistry(),
 		ga4.WithPropertyIDExtractor(&staticPropertyIDExtractorCORS{propertyID: "test_property_id"}),
+		ga4.WithDefaultCORSSettings(properties.CORSSettings{
+			AllowedOrigins:   []string{"https://example.test"},
+			AllowCredentials: true,
+		}),
 	)
 	server := NewServer(
 		storage,
@@ -204,3 +250,293 @@ func TestCORS_D8A_Static_Preflight_UsesWildcard(t *testing.T) {
 	assert.Equal(t, "*", string(resp.Header.Peek("Access-Control-Allow-Origin")))
 	assert.Empty(t, string(resp.Header.Peek("Access-Control-Allow-Credentials")))
 }
+
+func TestCORS_PropertySettings(t *testing.T) {
+	cors := &properties.CORSSettings{
+		AllowedOrigins: []string{"https://shop.example.com", "https://*.example.org"},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAge:         time.Hour,
+	}
+	testCases := []struct {
+		name                string
+		protocol            func(psr properties.SettingsRegistry) protocol.Protocol
+		path                string
+		origin              string
+		expectedAllowOrigin string
+	}{
+		{
+			name: "ga4 allowed origin",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return ga4.NewGA4Protocol(currency.NewDummyConverter(1), psr,
+					ga4.WithPropertyIDExtractor(&staticPropertyIDExtractorCORS{propertyID: "test_property_id"}))
+			},
+			path:                "/g/collect?tid=G-TEST",
+			origin:              "https://shop.example.com",
+			expectedAllowOrigin: "https://shop.example.com",
+		},
+		{
+			name: "ga4 wildcard subdomain",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return ga4.NewGA4Protocol(currency.NewDummyConverter(1), psr,
+					ga4.WithPropertyIDExtractor(&staticPropertyIDExtractorCORS{propertyID: "test_property_id"}))
+			},
+			path:                "/g/collect?tid=G-TEST",
+			origin:              "https://blog.example.org",
+			expectedAllowOrigin: "https://blog.example.org",
+		},
+		{
+			name: "ga4 origin not allowed",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return ga4.NewGA4Protocol(currency.NewDummyConverter(1), psr,
+					ga4.WithPropertyIDExtractor(&staticPropertyIDExtractorCORS{propertyID: "test_property_id"}))
+			},
+			path:   "/g/collect?tid=G-TEST",
+			origin: "https://copycat.example",
+		},
+		{
+			name: "d8a origin not allowed",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return d8a.NewD8AProtocol(currency.NewDummyConverter(1), psr,
+					ga4.WithPropertyIDExtractor(&staticPropertyIDExtractorCORS{propertyID: "test_property_id"}))
+			},
+			path:   "/d/c?tid=G-TEST",
+			origin: "https://copycat.example",
+		},
+		{
+			name: "matomo allowed origin",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return matomo.NewMatomoProtocol(&staticPropertyIDExtractorCORS{propertyID: "test_property_id"}, psr)
+			},
+			path:                "/matomo.php?idsite=1",
+			origin:              "https://shop.example.com",
+			expectedAllowOrigin: "https://shop.example.com",
+		},
+		{
+			name: "matomo origin not allowed",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return matomo.NewMatomoProtocol(&staticPropertyIDExtractorCORS{propertyID: "test_property_id"}, psr)
+			},
+			path:   "/matomo.php?idsite=1",
+			origin: "https://copycat.example",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			settingsRegistry := properties.NewStaticSettingsRegistry([]properties.Settings{{
+				PropertyID: "test_property_id",
+				CORS:       cors,
+			}})
+			p := tc.protocol(settingsRegistry)
+			server := NewServer(
+				&captureStorageCORS{},
+				NewDummyRawLogStorage(),
+				HitValidatingRuleSet(1024*128, settingsRegistry),
+				[]protocol.Protocol{p},
+				9999,
+			)
+			r := newInmemReceiver(t, server)
+
+			req := fasthttp.AcquireRequest()
+			t.Cleanup(func() { fasthttp.ReleaseRequest(req) })
+			req.SetRequestURI("http://localhost" + tc.path)
+			req.Header.SetMethod(fasthttp.MethodOptions)
+			req.Header.Set("Origin", tc.origin)
+			req.Header.Set("Access-Control-Request-Method", "POST")
+			req.Header.Set("Access-Control-Request-Headers", "content-type,x-custom")
+
+			// when
+			resp := r.do(req)
+
+			// then
+			assert.Equal(t, fasthttp.StatusNoContent, resp.StatusCode())
+			assert.Equal(t, tc.expectedAllowOrigin, string(resp.Header.Peek("Access-Control-Allow-Origin")))
+			assert.Empty(t, string(resp.Header.Peek("Access-Control-Allow-Credentials")))
+			assert.Contains(t, string(resp.Header.Peek("Vary")), "Origin")
+			assert.Equal(t, "Content-Type", string(resp.Header.Peek("Access-Control-Allow-Headers")))
+			assert.Equal(t, "3600", string(resp.Header.Peek("Access-Control-Max-Age")))
+		})
+	}
+}
+
+func TestCORS_PropertySettings_FromEvent(t *testing.T) {
+	cors := &properties.CORSSettings{
+		AllowedOrigins:   []string{"https://shop.example.com"},
+		AllowCredentials: true,
+	}
+	testCases := []struct {
+		name                     string
+		protocol                 func(psr properties.SettingsRegistry) protocol.Protocol
+		protocolID               string
+		measurementID            string
+		method                   string
+		path                     string
+		body                     string
+		origin                   string
+		expectedAllowOrigin      string
+		expectedAllowCredentials string
+	}{
+		{
+			name: "snowplow tp2 allowed origin",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return snowplow.NewSnowplowProtocol(snowplow.NewFromAppIDExtractor(psr), psr)
+			},
+			protocolID:               "snowplow",
+			measurementID:            "web",
+			method:                   fasthttp.MethodPost,
+			path:                     "/com.snowplowanalytics.snowplow/tp2",
+			body:                     `{"data":[{"e":"pv","aid":"web","duid":"d1","url":"https://shop.example.com/"}]}`,
+			origin:                   "https://shop.example.com",
+			expectedAllowOrigin:      "https://shop.example.com",
+			expectedAllowCredentials: "true",
+		},
+		{
+			name: "snowplow tp2 origin not allowed",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return snowplow.NewSnowplowProtocol(snowplow.NewFromAppIDExtractor(psr), psr)
+			},
+			protocolID:    "snowplow",
+			measurementID: "web",
+			method:        fasthttp.MethodPost,
+			path:          "/com.snowplowanalytics.snowplow/tp2",
+			body:          `{"data":[{"e":"pv","aid":"web","duid":"d1","url":"https://shop.example.com/"}]}`,
+			origin:        "https://copycat.example",
+		},
+		{
+			name: "snowplow pixel origin not allowed",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return snowplow.NewSnowplowProtocol(snowplow.NewFromAppIDExtractor(psr), psr)
+			},
+			protocolID:    "snowplow",
+			measurementID: "web",
+			method:        fasthttp.MethodGet,
+			path:          "/i?e=pv&aid=web&duid=d1",
+			origin:        "https://copycat.example",
+		},
+		{
+			name: "plausible allowed origin",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return plausible.NewPlausibleProtocol(plausible.NewFromDomainExtractor(psr), psr)
+			},
+			protocolID:               "plausible",
+			measurementID:            "shop.example.com",
+			method:                   fasthttp.MethodPost,
+			path:                     "/api/event",
+			body:                     `{"name":"pageview","url":"https://shop.example.com/","domain":"shop.example.com"}`,
+			origin:                   "https://shop.example.com",
+			expectedAllowOrigin:      "https://shop.example.com",
+			expectedAllowCredentials: "true",
+		},
+		{
+			name: "plausible origin not allowed",
+			protocol: func(psr properties.SettingsRegistry) protocol.Protocol {
+				return plausible.NewPlausibleProtocol(plausible.NewFromDomainExtractor(psr), psr)
+			},
+			protocolID:    "plausible",
+			measurementID: "shop.example.com",
+			method:        fasthttp.MethodPost,
+			path:          "/api/event",
+			body:          `{"name":"pageview","url":"https://shop.example.com/","domain":"shop.example.com"}`,
+			origin:        "https://copycat.example",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			settingsRegistry := properties.NewStaticSettingsRegistry([]properties.Settings{{
+				PropertyID:            "test_property_id",
+				PropertyMeasurementID: tc.measurementID,
+				ProtocolID:            tc.protocolID,
+				CORS:                  cors,
+			}})
+			p := tc.protocol(settingsRegistry)
+			server := NewServer(
+				&captureStorageCORS{},
+				NewDummyRawLogStorage(),
+				HitValidatingRuleSet(1024*128, settingsRegistry),
+				[]protocol.Protocol{p},
+				9999,
+			)
+			r := newInmemReceiver(t, server)
+
+			req := fasthttp.AcquireRequest()
+			t.Cleanup(func() { fasthttp.ReleaseRequest(req) })
+			req.SetRequestURI("http://localhost" + tc.path)
+			req.Header.SetMethod(tc.method)
+			req.Header.Set("Origin", tc.origin)
+			req.SetBodyString(tc.body)
+
+			// when
+			resp := r.do(req)
+
+			// then
+			assert.Equal(t, fasthttp.StatusNoContent, resp.StatusCode(), string(resp.Body()))
+			assert.Equal(t, tc.expectedAllowOrigin, string(resp.Header.Peek("Access-Control-Allow-Origin")))
+			assert.Equal(t, tc.expectedAllowCredentials, string(resp.Header.Peek("Access-Control-Allow-Credentials")))
+			assert.Contains(t, string(resp.Header.Peek("Vary")), "Origin")
+		})
+	}
+}
+
+func TestCORS_Preflight_OriginNotAllowedByDefaults(t *testing.T) {
+	defaults := properties.CORSSettings{
+		AllowedOrigins:   []string{"https://shop.example.com"},
+		AllowCredentials: true,
+	}
+	testCases := []struct {
+		name     string
+		protocol protocol.Protocol
+		path     string
+	}{
+		{
+			name: "snowplow",
+			protocol: snowplow.NewSnowplowProtocol(
+				&staticPropertyIDExtractorCORS{propertyID: "test_property_id"},
+				properties.NewTestSettingRegistry(),
+				snowplow.WithDefaultCORSSettings(defaults),
+			),
+			path: "/com.snowplowanalytics.snowplow/tp2",
+		},
+		{
+			name: "plausible",
+			protocol: plausible.NewPlausibleProtocol(
+				&staticPropertyIDExtractorCORS{propertyID: "test_property_id"},
+				properties.NewTestSettingRegistry(),
+				plausible.WithDefaultCORSSettings(defaults),
+			),
+			path: "/api/event",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			settingsRegistry := buildSettingsRegistry(tc.protocol.ID(), "test_property_id", "Test")
+			server := NewServer(
+				&captureStorageCORS{},
+				NewDummyRawLogStorage(),
+				HitValidatingRuleSet(1024*128, settingsRegistry),
+				[]protocol.Protocol{tc.protocol},
+				9999,
+			)
+			r := newInmemReceiver(t, server)
+
+			req := fasthttp.AcquireRequest()
+			t.Cleanup(func() { fasthttp.ReleaseRequest(req) })
+			req.SetRequestURI("http://localhost" + tc.path)
+			req.Header.SetMethod(fasthttp.MethodOptions)
+			req.Header.Set("Origin", "https://copycat.example")
+			req.Header.Set("Access-Control-Request-Method", "POST")
+
+			// when
+			resp := r.do(req)
+
+			// then
+			assert.Equal(t, fasthttp.StatusNoContent, resp.StatusCode())
+			assert.Empty(t, string(resp.Header.Peek("Access-Control-Allow-Origin")))
+			assert.Empty(t, string(resp.Header.Peek("Access-Control-Allow-Credentials")))
+		})
+	}
+}
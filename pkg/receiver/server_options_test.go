@@ -1,12 +1,15 @@
 package receiver
 
 import (
+	"context"
+	"net/http"
 	"testing"
 	"time"
 
 	"github.com/d8a-tech/d8a/pkg/hits"
 	"github.com/d8a-tech/d8a/pkg/protocol"
 	"github.com/stretchr/testify/assert"
+	"github.com/valyala/fasthttp"
 )
 
 type noopValidatingRule struct{}
@@ -97,3 +100,42 @@ func TestNewServer_OptionOverrides(t *testing.T) {
 		})
 	}
 }
+
+func TestWithMetricsHandler(t *testing.T) {
+	tests := []struct {
+		name       string
+		opts       []ServerOption
+		wantStatus int
+	}{
+		{
+			name: "serves metrics",
+			opts: []ServerOption{WithMetricsHandler(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+				_, _ = w.Write([]byte("receiver_requests_total 1\n"))
+			}))},
+			wantStatus: fasthttp.StatusOK,
+		},
+		{
+			name:       "no metrics without handler",
+			wantStatus: fasthttp.StatusNotFound,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			s := NewServer(&mockStorage{}, NewDummyRawLogStorage(), noopValidatingRule{}, nil, 8080, tt.opts...)
+			ctx := &fasthttp.RequestCtx{}
+			ctx.Request.Header.SetMethod(fasthttp.MethodGet)
+			ctx.Request.SetRequestURI("/metrics")
+
+			// when
+			s.setupRouter(context.Background()).Handler(ctx)
+
+			// then
+			assert.Equal(t, tt.wantStatus, ctx.Response.StatusCode())
+			if tt.wantStatus == fasthttp.StatusOK {
+				assert.Equal(t, "receiver_requests_total 1\n", string(ctx.Response.Body()))
+			}
+		})
+	}
+}
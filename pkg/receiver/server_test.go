@@ -3,6 +3,7 @@ package receiver
 import (
 	"context"
 	"fmt"
+	"net/url"
 	"testing"
 
 	"github.com/d8a-tech/d8a/pkg/hits"
@@ -30,6 +31,9 @@ type mockProtocol struct {
 	columns schema.Columns
 	err     error
 	hits    []*hits.Hit
+	// rewriteQuery makes hits carry different query params than the request, like
+	// protocols translating request bodies do
+	rewriteQuery bool
 }
 
 func (m *mockProtocol) ID() string {
@@ -64,6 +68,9 @@ func (m *mockProtocol) Hits(_ *fasthttp.RequestCtx, request *hits.ParsedRequest)
 	theHit.PropertyID = "test_property_id"
 	theHit.EventName = "page_view"
 	theHit.Request = request.Clone()
+	if m.rewriteQuery {
+		theHit.Request.QueryParams = url.Values{"rewritten": {"1"}}
+	}
 	return []*hits.Hit{theHit}, m.err
 }
 
@@ -327,7 +334,7 @@ func TestHandleRequest_SettingsErrorDoesNotLeakDetails(t *testing.T) {
 	assert.Equal(t, "Bad Request", string(ctx.Response.Body()))
 }
 
-func TestHandleRequest_MasksIPBeforeStorageAndRawLog(t *testing.T) {
+func TestHandleRequest_MasksIPBeforeStorageKeepingOriginalInRawLog(t *testing.T) {
 	// given
 	storage := &mockStorage{}
 	rawLogStorage := &capturingRawLogStorage{}
@@ -365,7 +372,7 @@ func TestHandleRequest_MasksIPBeforeStorageAndRawLog(t *testing.T) {
 	assert.Len(t, storage.hits, 1)
 	assert.Equal(t, "192.168.1.0", storage.hits[0].MustParsedRequest().IP)
 	assert.Len(t, rawLogStorage.requests, 1)
-	assert.Equal(t, "192.168.1.0", rawLogStorage.requests[0].IP)
+	assert.Equal(t, "192.168.1.123", rawLogStorage.requests[0].IP)
 }
 
 func TestHandleRequest_IPMaskingRegistryErrorReturnsBadRequest(t *testing.T) {
@@ -448,7 +455,7 @@ func TestHandleRequest_StoresRawLogOnceAfterAllHitsValidate(t *testing.T) {
 	assert.Equal(t, fasthttp.StatusNoContent, ctx.Response.StatusCode())
 	assert.Len(t, storage.hits, 2)
 	assert.Len(t, rawLogStorage.requests, 1)
-	assert.Equal(t, "192.168.1.0", rawLogStorage.requests[0].IP)
+	assert.Equal(t, "192.168.1.123", rawLogStorage.requests[0].IP)
 }
 
 func TestHandleRequest_DoesNotStoreRawLogWhenLaterHitValidationFails(t *testing.T) {
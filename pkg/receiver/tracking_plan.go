@@ -0,0 +1,29 @@
+package receiver
+
+import (
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/trackingplan"
+)
+
+// TrackingPlanViolations returns a hit validating rule counting the violations of the
+// tracking plan of the property by the hits, see trackingplan.RecordViolations. It never
+// rejects hits, strict plans are enforced by the tracking plan violations column. Leave it
+// out of the rule of replayed requests, see WithReplayHitValidatingRule, so that their
+// violations aren't counted again.
+func TrackingPlanViolations(settings properties.SettingsRegistry) HitValidatingRule {
+	return NewSimpleHitValidatingRule(func(p protocol.Protocol, hit *hits.Hit) error {
+		propertySettings, err := settings.GetByPropertyID(hit.PropertyID)
+		if err != nil || propertySettings.TrackingPlan == nil {
+			return nil
+		}
+		var params map[string]any
+		if provider, ok := p.(protocol.EventParamsProvider); ok {
+			params = provider.EventParams(hit)
+		}
+		violations := trackingplan.Validate(propertySettings.TrackingPlan, hit.EventName, params)
+		trackingplan.RecordViolations(hit.PropertyID, violations)
+		return nil
+	})
+}
@@ -0,0 +1,31 @@
+package receiver
+
+import (
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTrackingPlanViolations_NeverRejectsHits(t *testing.T) {
+	// given
+	settings := settingsRegistryStub{settingsByPropertyID: map[string]*properties.Settings{
+		"test_property_id": {
+			PropertyID: "test_property_id",
+			TrackingPlan: &properties.TrackingPlan{
+				Strict: true,
+				Events: map[string]properties.TrackingPlanEvent{"page_view": {}},
+			},
+		},
+	}}
+	hit := hits.New()
+	hit.PropertyID = "test_property_id"
+	hit.EventName = "unplanned_event"
+
+	// when
+	err := TrackingPlanViolations(settings).Validate(&mockProtocol{id: "test_protocol"}, hit)
+
+	// then
+	assert.NoError(t, err)
+}
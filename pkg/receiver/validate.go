@@ -165,6 +165,7 @@ func HitValidatingRuleSet(
 		HitBodyNotNil,
 		TotalHitSizeDoesNotExceed(maxHitSizeBytes),
 		PropertyProtocolMatchesTheEndpointProtocol(settings),
+		HitFromAllowedDomain(settings),
 		EventNameNotEmpty,
 	)
 }
@@ -1,6 +1,8 @@
 package schema
 
 import (
+	"fmt"
+
 	"github.com/apache/arrow-go/v18/arrow"
 )
 
@@ -60,16 +62,7 @@ func (m *eventsWithEmbeddedSessionColumnsLayout) Tables(
 	eventColumns := columns.Event
 	sessionColumns := columns.Session
 	// First, include fields from session-scoped event columns as event-level fields
-	ssecExtraFields := make([]arrow.Field, len(columns.SessionScopedEvent))
-	for i, ssec := range columns.SessionScopedEvent {
-		f := ssec.Implements().Field
-		ssecExtraFields[i] = arrow.Field{
-			Name:     f.Name,
-			Type:     f.Type,
-			Nullable: f.Nullable,
-			Metadata: f.Metadata,
-		}
-	}
+	ssecExtraFields := sessionScopedEventFields(columns)
 
 	// Then, include prefixed session fields
 	prefixedSessionExtraFields := make([]arrow.Field, len(sessionColumns))
@@ -129,6 +122,149 @@ func (m *eventsWithEmbeddedSessionColumnsLayout) ToRows(
 	}, nil
 }
 
+// sessionIDFieldName is the name of the field joining the events with their session
+const sessionIDFieldName = "session_id"
+
+// sessionFirstEventFieldNames are event fields the sessions table takes from the first
+// event of the session, so it's keyed and partitioned like the events table: the default
+// ClickHouse ORDER BY and PARTITION BY and the BigQuery and PostgreSQL partition fields
+// reference them.
+var sessionFirstEventFieldNames = []string{"property_id", "date_utc"}
+
+type eventsWithSeparateSessionsTableLayout struct {
+	eventsTableName   string
+	sessionsTableName string
+}
+
+// NewSeparateSessionsTableLayout creates two tables: the events table with event and
+// session-scoped event columns, and the sessions table with one row per session holding
+// the session columns. Both tables carry the session_id field to join them, the sessions
+// table also carries the property_id and date_utc of the first event of the session.
+func NewSeparateSessionsTableLayout(
+	eventsTableName string,
+	sessionsTableName string,
+) Layout {
+	return &eventsWithSeparateSessionsTableLayout{
+		eventsTableName:   eventsTableName,
+		sessionsTableName: sessionsTableName,
+	}
+}
+
+func (m *eventsWithSeparateSessionsTableLayout) Tables(
+	columns Columns,
+) []WithMeta {
+	sessionIDField := arrow.Field{Name: sessionIDFieldName, Type: arrow.BinaryTypes.String}
+	sessionHasID := false
+	for _, sessionColumn := range columns.Session {
+		if f := sessionColumn.Implements().Field; f.Name == sessionIDFieldName {
+			sessionIDField = *f
+			sessionHasID = true
+		}
+	}
+
+	eventExtraFields := sessionScopedEventFields(columns)
+	if !hasField(columns.Event, sessionIDFieldName) && !hasField(columns.SessionScopedEvent, sessionIDFieldName) {
+		eventExtraFields = append([]arrow.Field{sessionIDField}, eventExtraFields...)
+	}
+	sessionExtraFields := []arrow.Field{}
+	if !sessionHasID {
+		sessionExtraFields = append(sessionExtraFields, sessionIDField)
+	}
+	for _, name := range sessionFirstEventFieldNames {
+		if hasField(columns.Session, name) {
+			continue
+		}
+		for _, eventColumn := range columns.Event {
+			if f := eventColumn.Implements().Field; f.Name == name {
+				sessionExtraFields = append(sessionExtraFields, *f)
+			}
+		}
+	}
+
+	return []WithMeta{
+		{
+			Schema: WithExtraFields(columns.Event, eventExtraFields...),
+			Table:  m.eventsTableName,
+			Scope:  ScopeEvent,
+		},
+		{
+			Schema: WithExtraFields(columns.Session, sessionExtraFields...),
+			Table:  m.sessionsTableName,
+			Scope:  ScopeSession,
+		},
+	}
+}
+
+func (m *eventsWithSeparateSessionsTableLayout) ToRows(
+	_ Columns,
+	sessions ...*Session,
+) ([]TableRows, error) {
+	totalEvents := 0
+	for _, session := range sessions {
+		totalEvents += len(session.Events)
+	}
+	eventRows := make([]map[string]any, 0, totalEvents)
+	sessionRows := make([]map[string]any, 0, len(sessions))
+
+	for _, session := range sessions {
+		sessionID, ok := session.Values[sessionIDFieldName]
+		if !ok {
+			return nil, fmt.Errorf("session of property %s has no %s value", session.PropertyID, sessionIDFieldName)
+		}
+
+		for _, event := range session.Events {
+			eventValuesCopy := make(map[string]any, len(event.Values)+1)
+			for k, v := range event.Values {
+				eventValuesCopy[k] = v
+			}
+			eventValuesCopy[sessionIDFieldName] = sessionID
+			eventRows = append(eventRows, eventValuesCopy)
+		}
+
+		sessionValuesCopy := make(map[string]any, len(session.Values)+len(sessionFirstEventFieldNames))
+		if len(session.Events) > 0 {
+			for _, name := range sessionFirstEventFieldNames {
+				if v, ok := session.Events[0].Values[name]; ok {
+					sessionValuesCopy[name] = v
+				}
+			}
+		}
+		for k, v := range session.Values {
+			sessionValuesCopy[k] = v
+		}
+		sessionRows = append(sessionRows, sessionValuesCopy)
+	}
+	return []TableRows{
+		{Table: m.eventsTableName, Rows: eventRows},
+		{Table: m.sessionsTableName, Rows: sessionRows},
+	}, nil
+}
+
+// sessionScopedEventFields returns copies of the fields of session-scoped event columns,
+// which are written as event-level fields.
+func sessionScopedEventFields(columns Columns) []arrow.Field {
+	fields := make([]arrow.Field, len(columns.SessionScopedEvent))
+	for i, ssec := range columns.SessionScopedEvent {
+		f := ssec.Implements().Field
+		fields[i] = arrow.Field{
+			Name:     f.Name,
+			Type:     f.Type,
+			Nullable: f.Nullable,
+			Metadata: f.Metadata,
+		}
+	}
+	return fields
+}
+
+func hasField[T Column](columns []T, name string) bool {
+	for _, column := range columns {
+		if column.Implements().Field.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 type staticLayoutRegistry struct {
 	layouts       map[string]Layout
 	defaultLayout Layout
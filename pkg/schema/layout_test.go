@@ -606,3 +606,184 @@ func TestEventsWithEmbeddedSessionColumnsTableLayout_IdempotentCalls(t *testing.
 	assert.Equal(t, "test_event", row2["id"])
 	assert.Equal(t, "test_event", row3["id"])
 }
+
+func TestSeparateSessionsTableLayout_Tables(t *testing.T) {
+	tests := []struct {
+		name                  string
+		eventColumns          []EventColumn
+		sessionColumns        []SessionColumn
+		expectedEventFields   []string
+		expectedSessionFields []string
+	}{
+		{
+			name: "session id taken from the session columns",
+			eventColumns: []EventColumn{
+				&mockEventColumn{
+					id:    "id",
+					field: &arrow.Field{Name: "id", Type: arrow.BinaryTypes.String},
+				},
+			},
+			sessionColumns: []SessionColumn{
+				&mockSessionColumn{
+					id:    "session_id",
+					field: &arrow.Field{Name: "session_id", Type: arrow.BinaryTypes.String},
+				},
+				&mockSessionColumn{
+					id:    "user_id",
+					field: &arrow.Field{Name: "user_id", Type: arrow.PrimitiveTypes.Int64},
+				},
+			},
+			expectedEventFields:   []string{"id", "session_id"},
+			expectedSessionFields: []string{"session_id", "user_id"},
+		},
+		{
+			name: "session id added when no session column provides it",
+			eventColumns: []EventColumn{
+				&mockEventColumn{
+					id:    "id",
+					field: &arrow.Field{Name: "id", Type: arrow.BinaryTypes.String},
+				},
+			},
+			sessionColumns: []SessionColumn{
+				&mockSessionColumn{
+					id:    "user_id",
+					field: &arrow.Field{Name: "user_id", Type: arrow.PrimitiveTypes.Int64},
+				},
+			},
+			expectedEventFields:   []string{"id", "session_id"},
+			expectedSessionFields: []string{"user_id", "session_id"},
+		},
+		{
+			name: "event column named session_id isn't duplicated",
+			eventColumns: []EventColumn{
+				&mockEventColumn{
+					id:    "session_id",
+					field: &arrow.Field{Name: "session_id", Type: arrow.BinaryTypes.String},
+				},
+			},
+			sessionColumns: []SessionColumn{
+				&mockSessionColumn{
+					id:    "session_id",
+					field: &arrow.Field{Name: "session_id", Type: arrow.BinaryTypes.String},
+				},
+			},
+			expectedEventFields:   []string{"session_id"},
+			expectedSessionFields: []string{"session_id"},
+		},
+		{
+			name: "property id and date taken from the event columns",
+			eventColumns: []EventColumn{
+				&mockEventColumn{
+					id:    "property_id",
+					field: &arrow.Field{Name: "property_id", Type: arrow.BinaryTypes.String},
+				},
+				&mockEventColumn{
+					id:    "date_utc",
+					field: &arrow.Field{Name: "date_utc", Type: arrow.FixedWidthTypes.Date32},
+				},
+			},
+			sessionColumns: []SessionColumn{
+				&mockSessionColumn{
+					id:    "session_id",
+					field: &arrow.Field{Name: "session_id", Type: arrow.BinaryTypes.String},
+				},
+			},
+			expectedEventFields:   []string{"property_id", "date_utc", "session_id"},
+			expectedSessionFields: []string{"session_id", "property_id", "date_utc"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// given
+			layout := NewSeparateSessionsTableLayout("events", "sessions")
+			sources := Columns{
+				Event:   tt.eventColumns,
+				Session: tt.sessionColumns,
+			}
+
+			// when
+			result := layout.Tables(sources)
+
+			// then
+			require.Len(t, result, 2)
+			assert.Equal(t, "events", result[0].Table)
+			assert.Equal(t, ScopeEvent, result[0].Scope)
+			assert.Equal(t, tt.expectedEventFields, fieldNames(result[0].Schema))
+			assert.Equal(t, "sessions", result[1].Table)
+			assert.Equal(t, ScopeSession, result[1].Scope)
+			assert.Equal(t, tt.expectedSessionFields, fieldNames(result[1].Schema))
+		})
+	}
+}
+
+func TestSeparateSessionsTableLayout_ToRows(t *testing.T) {
+	// given
+	layout := NewSeparateSessionsTableLayout("events", "sessions")
+	sessions := []*Session{
+		{
+			PropertyID: "prop1",
+			Events: []*Event{
+				{BoundHit: &hits.Hit{ID: "hit1"}, Values: map[string]any{
+					"id": "hit1", "property_id": "prop1", "date_utc": "2026-01-01",
+				}},
+				{BoundHit: &hits.Hit{ID: "hit2"}, Values: map[string]any{
+					"id": "hit2", "property_id": "prop1", "date_utc": "2026-01-02",
+				}},
+			},
+			Values: map[string]any{"session_id": "hit1", "user_id": int64(1)},
+		},
+		{
+			PropertyID: "prop1",
+			Events: []*Event{
+				{BoundHit: &hits.Hit{ID: "hit3"}, Values: map[string]any{
+					"id": "hit3", "property_id": "prop1", "date_utc": "2026-01-03",
+				}},
+			},
+			Values: map[string]any{"session_id": "hit3", "user_id": int64(2)},
+		},
+	}
+
+	// when
+	result, err := layout.ToRows(Columns{}, sessions...)
+
+	// then
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+	assert.Equal(t, TableRows{Table: "events", Rows: []map[string]any{
+		{"id": "hit1", "property_id": "prop1", "date_utc": "2026-01-01", "session_id": "hit1"},
+		{"id": "hit2", "property_id": "prop1", "date_utc": "2026-01-02", "session_id": "hit1"},
+		{"id": "hit3", "property_id": "prop1", "date_utc": "2026-01-03", "session_id": "hit3"},
+	}}, result[0])
+	assert.Equal(t, TableRows{Table: "sessions", Rows: []map[string]any{
+		{"session_id": "hit1", "user_id": int64(1), "property_id": "prop1", "date_utc": "2026-01-01"},
+		{"session_id": "hit3", "user_id": int64(2), "property_id": "prop1", "date_utc": "2026-01-03"},
+	}}, result[1])
+	assert.NotContains(t, sessions[0].Events[0].Values, "session_id", "event values shouldn't be modified")
+}
+
+func TestSeparateSessionsTableLayout_ToRowsWithoutSessionID(t *testing.T) {
+	// given
+	layout := NewSeparateSessionsTableLayout("events", "sessions")
+	session := &Session{
+		PropertyID: "prop1",
+		Events: []*Event{
+			{BoundHit: &hits.Hit{ID: "hit1"}, Values: map[string]any{"id": "hit1"}},
+		},
+		Values: map[string]any{"user_id": int64(1)},
+	}
+
+	// when
+	_, err := layout.ToRows(Columns{}, session)
+
+	// then
+	assert.ErrorContains(t, err, "no session_id value")
+}
+
+func fieldNames(schema *arrow.Schema) []string {
+	names := make([]string, len(schema.Fields()))
+	for i, field := range schema.Fields() {
+		names[i] = field.Name
+	}
+	return names
+}
@@ -3,7 +3,9 @@ package sessions
 import (
 	"context"
 	"fmt"
+	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/apache/arrow-go/v18/arrow"
@@ -24,6 +26,8 @@ type sessionWriterImpl struct {
 	concurrency  int
 
 	cacheTTL time.Duration
+	// cacheGeneration is a part of every cache key, bumping it invalidates all cached entries
+	cacheGeneration atomic.Uint64
 
 	layoutRegistry schema.LayoutRegistry
 	layoutsCache   *ristretto.Cache[string, schema.Layout]
@@ -121,7 +125,9 @@ func (m *sessionWriterImpl) getSchemaForWriting(propertyID, table string) (*arro
 }
 
 func (m *sessionWriterImpl) getColumns(propertyID string) (schema.Columns, error) {
-	columns, err := getCached(m.columnsCache, &m.columnsLock, m.columnsRegistry.Get, propertyID, m.cacheTTL)
+	columns, err := getCached(
+		m.columnsCache, &m.columnsLock, m.columnsRegistry.Get, m.cacheKey(propertyID), propertyID, m.cacheTTL,
+	)
 	if err != nil {
 		return schema.Columns{}, err
 	}
@@ -129,13 +135,25 @@ func (m *sessionWriterImpl) getColumns(propertyID string) (schema.Columns, error
 }
 
 func (m *sessionWriterImpl) getLayout(propertyID string) (schema.Layout, error) {
-	layout, err := getCached(m.layoutsCache, &m.layoutsLock, m.layoutRegistry.Get, propertyID, m.cacheTTL)
+	layout, err := getCached(
+		m.layoutsCache, &m.layoutsLock, m.layoutRegistry.Get, m.cacheKey(propertyID), propertyID, m.cacheTTL,
+	)
 	if err != nil {
 		return nil, err
 	}
 	return layout, nil
 }
 
+func (m *sessionWriterImpl) cacheKey(propertyID string) string {
+	return strconv.FormatUint(m.cacheGeneration.Load(), 10) + "/" + propertyID
+}
+
+// InvalidateCaches makes the writer fetch layouts and columns from the registries again,
+// for example after property settings were reloaded.
+func (m *sessionWriterImpl) InvalidateCaches() {
+	m.cacheGeneration.Add(1)
+}
+
 // Write method writes the sessions to the warehouse. It first determines where the data
 // should be written, then executes the writes in parallel for every table. It waits for
 // all the writes to complete and returns an error if any of the writes fail.
@@ -384,11 +402,12 @@ func getCached[T any](
 	cache *ristretto.Cache[string, T],
 	lock *sync.Mutex,
 	getter func(string) (T, error),
+	cacheKey string,
 	propertyID string,
 	cacheTTL time.Duration,
 ) (T, error) {
 	// First check without lock
-	item, ok := cache.Get(propertyID)
+	item, ok := cache.Get(cacheKey)
 	if ok {
 		return item, nil
 	}
@@ -396,7 +415,7 @@ func getCached[T any](
 	// Acquire lock and double-check
 	lock.Lock()
 	defer lock.Unlock()
-	item, ok = cache.Get(propertyID)
+	item, ok = cache.Get(cacheKey)
 	if ok {
 		return item, nil
 	}
@@ -409,6 +428,6 @@ func getCached[T any](
 	}
 
 	// Cache the result
-	cache.SetWithTTL(propertyID, item, 1, cacheTTL)
+	cache.SetWithTTL(cacheKey, item, 1, cacheTTL)
 	return item, nil
 }
@@ -229,3 +229,61 @@ func TestWriter_ResolvesWarehousePerWriteCall(t *testing.T) {
 	assert.Equal(t, 2, registry.GetCallCount())
 	assert.Len(t, mockDriver.WriteCalls, 2)
 }
+
+type countingColumnsRegistry struct {
+	mu       sync.Mutex
+	getCalls int
+}
+
+func (r *countingColumnsRegistry) Get(_ string) (schema.Columns, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.getCalls++
+	return schema.NewColumns(
+		[]schema.SessionColumn{},
+		[]schema.EventColumn{},
+		[]schema.SessionScopedEventColumn{},
+	), nil
+}
+
+func (r *countingColumnsRegistry) GetCallCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.getCalls
+}
+
+func TestWriter_InvalidateCachesRefetchesColumns(t *testing.T) {
+	// given
+	ctx, cancel := context.WithTimeout(context.Background(), 1*time.Second)
+	defer cancel()
+	columnsRegistry := &countingColumnsRegistry{}
+	writer := NewSessionWriter(
+		ctx,
+		warehouse.NewStaticDriverRegistry(warehouse.NewMockWarehouseDriver()),
+		columnsRegistry,
+		schema.NewStaticLayoutRegistry(
+			map[string]schema.Layout{},
+			schema.NewEmbeddedSessionColumnsLayout(
+				"events",
+				"session_",
+			),
+		),
+		splitter.NewStaticRegistry(splitter.NewNoop()),
+	)
+	impl, ok := writer.(*sessionWriterImpl)
+	assert.True(t, ok)
+	assert.NoError(t, writer.Write(testSession("1", "1", "1")))
+	impl.columnsCache.Wait()
+	callsAfterFirstWrite := columnsRegistry.GetCallCount()
+	assert.NoError(t, writer.Write(testSession("1", "2", "2")))
+	callsAfterCachedWrite := columnsRegistry.GetCallCount()
+
+	// when
+	impl.InvalidateCaches()
+	err := writer.Write(testSession("1", "3", "3"))
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, callsAfterFirstWrite, callsAfterCachedWrite)
+	assert.Greater(t, columnsRegistry.GetCallCount(), callsAfterCachedWrite)
+}
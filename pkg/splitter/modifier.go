@@ -2,6 +2,8 @@
 package splitter
 
 import (
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/d8a-tech/d8a/pkg/properties"
@@ -200,11 +202,19 @@ type cachingRegistry struct {
 	underlying Registry
 	cache      *ristretto.Cache[string, SessionModifier]
 	config     cachingRegistryConfig
+	generation atomic.Uint64
+}
+
+// InvalidateCaches makes the registry build session modifiers from scratch on next lookups,
+// for example after property settings were reloaded.
+func (r *cachingRegistry) InvalidateCaches() {
+	r.generation.Add(1)
 }
 
 func (r *cachingRegistry) SessionModifier(propertyID string) (SessionModifier, error) {
+	cacheKey := strconv.FormatUint(r.generation.Load(), 10) + "/" + propertyID
 	// Check cache
-	if modifier, found := r.cache.Get(propertyID); found {
+	if modifier, found := r.cache.Get(cacheKey); found {
 		return modifier, nil
 	}
 
@@ -215,7 +225,7 @@ func (r *cachingRegistry) SessionModifier(propertyID string) (SessionModifier, e
 	}
 
 	// Store in cache
-	r.cache.SetWithTTL(propertyID, modifier, 1, r.config.ttl)
+	r.cache.SetWithTTL(cacheKey, modifier, 1, r.config.ttl)
 
 	return modifier, nil
 }
@@ -693,3 +693,36 @@ func TestMultiModifierEmptySession(t *testing.T) {
 	assert.Len(t, actual, 1)
 	assert.Len(t, actual[0].Events, 0)
 }
+
+type countingRegistry struct {
+	calls int
+}
+
+func (r *countingRegistry) SessionModifier(_ string) (SessionModifier, error) {
+	r.calls++
+	return NewNoop(), nil
+}
+
+func TestCachingRegistryInvalidateCaches(t *testing.T) {
+	// given
+	underlying := &countingRegistry{}
+	registry, err := NewCachingRegistry(underlying)
+	assert.NoError(t, err)
+	caching, ok := registry.(*cachingRegistry)
+	assert.True(t, ok)
+	_, err = registry.SessionModifier("p1")
+	assert.NoError(t, err)
+	caching.cache.Wait()
+	_, err = registry.SessionModifier("p1")
+	assert.NoError(t, err)
+	callsBeforeInvalidation := underlying.calls
+
+	// when
+	caching.InvalidateCaches()
+	_, err = registry.SessionModifier("p1")
+
+	// then
+	assert.NoError(t, err)
+	assert.Equal(t, 1, callsBeforeInvalidation)
+	assert.Equal(t, 2, underlying.calls)
+}
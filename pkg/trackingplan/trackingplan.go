@@ -0,0 +1,205 @@
+// Package trackingplan validates events against the tracking plan of their property,
+// reporting what doesn't conform to it in a column and metrics.
+package trackingplan
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/d8a-tech/d8a/pkg/columns"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/schema"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// ViolationKind tells how an event doesn't conform to the tracking plan.
+type ViolationKind string
+
+const (
+	// UnknownEvent is an event whose name isn't in the plan.
+	UnknownEvent ViolationKind = "unknown_event"
+	// MissingParam is a required param the event doesn't have.
+	MissingParam ViolationKind = "missing_param"
+	// UnexpectedParam is a param which isn't declared for the event.
+	UnexpectedParam ViolationKind = "unexpected_param"
+	// InvalidParamType is a param whose value isn't of the declared type.
+	InvalidParamType ViolationKind = "invalid_param_type"
+)
+
+// Violation is a way in which an event doesn't conform to the tracking plan.
+type Violation struct {
+	Kind  ViolationKind
+	Event string
+	// Param is the name of the param the violation is about, empty for unknown events.
+	Param string
+	// Type is the declared type of the param, for invalid param types.
+	Type properties.TrackingPlanParamType
+}
+
+func (v Violation) String() string {
+	switch v.Kind {
+	case UnknownEvent:
+		return fmt.Sprintf("unknown event %q", v.Event)
+	case MissingParam:
+		return fmt.Sprintf("missing required param %q", v.Param)
+	case UnexpectedParam:
+		return fmt.Sprintf("unexpected param %q", v.Param)
+	case InvalidParamType:
+		return fmt.Sprintf("param %q is not of type %s", v.Param, v.Type)
+	}
+	return string(v.Kind)
+}
+
+// Validate returns the violations of the tracking plan by an event with the given name and
+// params, sorted by param. Params are nil for protocols which don't provide them, only the
+// event name is validated then.
+func Validate(plan *properties.TrackingPlan, eventName string, params map[string]any) []Violation {
+	event, known := plan.Events[eventName]
+	if !known {
+		return []Violation{{Kind: UnknownEvent, Event: eventName}}
+	}
+	if params == nil {
+		return nil
+	}
+
+	var violations []Violation
+	for name, param := range event.Params {
+		value, present := params[name]
+		switch {
+		case !present && param.Required:
+			violations = append(violations, Violation{Kind: MissingParam, Event: eventName, Param: name})
+		case present && !valueHasType(value, param.Type):
+			violations = append(violations, Violation{
+				Kind: InvalidParamType, Event: eventName, Param: name, Type: param.Type,
+			})
+		}
+	}
+	for name := range params {
+		if _, declared := event.Params[name]; !declared {
+			violations = append(violations, Violation{Kind: UnexpectedParam, Event: eventName, Param: name})
+		}
+	}
+	sort.Slice(violations, func(i, j int) bool {
+		if violations[i].Param != violations[j].Param {
+			return violations[i].Param < violations[j].Param
+		}
+		return violations[i].Kind < violations[j].Kind
+	})
+	return violations
+}
+
+func valueHasType(value any, paramType properties.TrackingPlanParamType) bool {
+	switch paramType {
+	case properties.TrackingPlanParamNumber, properties.TrackingPlanParamInteger:
+		number, ok := value.(float64)
+		if str, isString := value.(string); isString {
+			parsed, err := strconv.ParseFloat(strings.TrimSpace(str), 64)
+			number, ok = parsed, err == nil
+		}
+		if !ok || math.IsNaN(number) || math.IsInf(number, 0) {
+			return false
+		}
+		return paramType == properties.TrackingPlanParamNumber || number == math.Trunc(number)
+	case properties.TrackingPlanParamBoolean:
+		if _, ok := value.(bool); ok {
+			return true
+		}
+		str, ok := value.(string)
+		return ok && (str == "true" || str == "false")
+	default:
+		return true
+	}
+}
+
+// Labels of the tracking_plan.violations metric standing for names which aren't in the
+// plan, any sender could otherwise grow the number of series.
+const (
+	unknownEventLabel    = "(unknown)"
+	undeclaredParamLabel = "(undeclared)"
+)
+
+var violationsCounter metric.Int64Counter
+
+func init() {
+	violationsCounter, _ = otel.GetMeterProvider().Meter("trackingplan").Int64Counter(
+		"tracking_plan.violations",
+		metric.WithDescription("Violations of tracking plans by events, by property, event, param and violation"),
+	)
+}
+
+// RecordViolations counts the violations of the tracking plan by an event of the property
+// with the tracking_plan.violations metric. Event names which aren't in the plan are
+// counted as "(unknown)" and params which aren't declared as "(undeclared)".
+func RecordViolations(propertyID string, violations []Violation) {
+	for _, violation := range violations {
+		event, param := violation.labels()
+		violationsCounter.Add(context.Background(), 1, metric.WithAttributes(
+			attribute.String("property_id", propertyID),
+			attribute.String("event", event),
+			attribute.String("param", param),
+			attribute.String("violation", string(violation.Kind)),
+		))
+	}
+}
+
+// labels returns the event and param metric labels of the violation.
+func (v Violation) labels() (event, param string) {
+	switch v.Kind {
+	case UnknownEvent:
+		return unknownEventLabel, v.Param
+	case UnexpectedParam:
+		return v.Event, undeclaredParamLabel
+	}
+	return v.Event, v.Param
+}
+
+// ViolationsColumn returns the column listing the violations of the tracking plan of the
+// property by the event, empty for conforming events and nil for properties without a
+// plan. Events of properties with a strict plan are marked broken on any violation, so
+// they aren't written. Params are read from protocols implementing
+// protocol.EventParamsProvider. Violations are counted by the receiver, see
+// RecordViolations, columns may be written again for the same event.
+func ViolationsColumn(psr properties.SettingsRegistry, protocols protocol.Registry) schema.EventColumn {
+	return columns.NewSimpleEventColumn(
+		columns.CoreInterfaces.EventTrackingPlanViolations.ID,
+		columns.CoreInterfaces.EventTrackingPlanViolations.Field,
+		func(event *schema.Event) (any, schema.D8AColumnWriteError) {
+			hit := event.BoundHit
+			settings, err := psr.GetByPropertyID(hit.PropertyID)
+			if err != nil || settings.TrackingPlan == nil {
+				return nil, nil // nolint:nilnil // nil is valid
+			}
+
+			var params map[string]any
+			if p, err := protocols.Get(hit.PropertyID); err == nil {
+				if provider, ok := p.(protocol.EventParamsProvider); ok {
+					params = provider.EventParams(hit)
+				}
+			}
+
+			violations := Validate(settings.TrackingPlan, hit.EventName, params)
+			messages := make([]any, 0, len(violations))
+			for _, violation := range violations {
+				messages = append(messages, violation.String())
+			}
+			if settings.TrackingPlan.Strict && len(violations) > 0 {
+				return nil, schema.NewBrokenEventError(fmt.Sprintf(
+					"event %q violates the tracking plan: %v", hit.EventName, messages,
+				))
+			}
+			return messages, nil
+		},
+		columns.WithEventColumnRequired(false),
+		columns.WithEventColumnDocs(
+			"Tracking Plan Violations",
+			"How the event doesn't conform to the tracking plan of the property: unknown event names, missing required params, undeclared params and params of the wrong type. Empty for conforming events, null for properties without a tracking plan.", // nolint:lll // it's a description
+		),
+	)
+}
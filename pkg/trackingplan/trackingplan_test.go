@@ -0,0 +1,217 @@
+package trackingplan
+
+import (
+	"testing"
+
+	"github.com/d8a-tech/d8a/pkg/hits"
+	"github.com/d8a-tech/d8a/pkg/properties"
+	"github.com/d8a-tech/d8a/pkg/protocol"
+	"github.com/d8a-tech/d8a/pkg/schema"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/valyala/fasthttp"
+)
+
+var testPlan = &properties.TrackingPlan{Events: map[string]properties.TrackingPlanEvent{
+	"page_view": {},
+	"purchase": {Params: map[string]properties.TrackingPlanParam{
+		"transaction_id": {Type: properties.TrackingPlanParamString, Required: true},
+		"value":          {Type: properties.TrackingPlanParamNumber, Required: true},
+		"quantity":       {Type: properties.TrackingPlanParamInteger},
+		"first_order":    {Type: properties.TrackingPlanParamBoolean},
+		"coupon":         {},
+	}},
+}}
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name      string
+		eventName string
+		params    map[string]any
+		expected  []string
+	}{
+		{
+			name:      "conforming event",
+			eventName: "purchase",
+			params: map[string]any{
+				"transaction_id": "T-1",
+				"value":          12.5,
+				"quantity":       "3",
+				"first_order":    "true",
+				"coupon":         "SUMMER",
+			},
+		},
+		{
+			name:      "unknown event",
+			eventName: "purchse",
+			params:    map[string]any{},
+			expected:  []string{`unknown event "purchse"`},
+		},
+		{
+			name:      "event without params",
+			eventName: "page_view",
+			params:    map[string]any{},
+		},
+		{
+			name:      "params aren't validated without a provider",
+			eventName: "purchase",
+		},
+		{
+			name:      "missing required params",
+			eventName: "purchase",
+			params:    map[string]any{"transaction_id": "T-1"},
+			expected:  []string{`missing required param "value"`},
+		},
+		{
+			name:      "unexpected param",
+			eventName: "page_view",
+			params:    map[string]any{"debug": "1"},
+			expected:  []string{`unexpected param "debug"`},
+		},
+		{
+			name:      "invalid param types",
+			eventName: "purchase",
+			params: map[string]any{
+				"transaction_id": "T-1",
+				"value":          "twelve",
+				"quantity":       2.5,
+				"first_order":    "yes",
+			},
+			expected: []string{
+				`param "first_order" is not of type boolean`,
+				`param "quantity" is not of type integer`,
+				`param "value" is not of type number`,
+			},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			violations := Validate(testPlan, tc.eventName, tc.params)
+
+			// then
+			messages := make([]string, 0, len(violations))
+			for _, violation := range violations {
+				messages = append(messages, violation.String())
+			}
+			assert.ElementsMatch(t, tc.expected, messages)
+		})
+	}
+}
+
+type paramsProtocol struct {
+	params map[string]any
+}
+
+func (p *paramsProtocol) ID() string                             { return "test_protocol" }
+func (p *paramsProtocol) Columns() schema.Columns                { return schema.Columns{} }
+func (p *paramsProtocol) Interfaces() any                        { return nil }
+func (p *paramsProtocol) Endpoints() []protocol.ProtocolEndpoint { return nil }
+func (p *paramsProtocol) Hits(*fasthttp.RequestCtx, *hits.ParsedRequest) ([]*hits.Hit, error) {
+	return nil, nil
+}
+func (p *paramsProtocol) EventParams(*hits.Hit) map[string]any { return p.params }
+
+func TestViolationLabels(t *testing.T) {
+	testCases := []struct {
+		name          string
+		violation     Violation
+		expectedEvent string
+		expectedParam string
+	}{
+		{
+			name:          "unknown event",
+			violation:     Violation{Kind: UnknownEvent, Event: "random_event_123"},
+			expectedEvent: "(unknown)",
+		},
+		{
+			name:          "undeclared param",
+			violation:     Violation{Kind: UnexpectedParam, Event: "purchase", Param: "random_param_123"},
+			expectedEvent: "purchase",
+			expectedParam: "(undeclared)",
+		},
+		{
+			name:          "missing param",
+			violation:     Violation{Kind: MissingParam, Event: "purchase", Param: "value"},
+			expectedEvent: "purchase",
+			expectedParam: "value",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			event, param := tc.violation.labels()
+
+			// then
+			assert.Equal(t, tc.expectedEvent, event)
+			assert.Equal(t, tc.expectedParam, param)
+		})
+	}
+}
+
+func TestViolationsColumn(t *testing.T) {
+	testCases := []struct {
+		name        string
+		plan        *properties.TrackingPlan
+		eventName   string
+		expected    any
+		expectError bool
+	}{
+		{
+			name:      "no tracking plan",
+			eventName: "purchse",
+			expected:  nil,
+		},
+		{
+			name:      "conforming event",
+			plan:      testPlan,
+			eventName: "page_view",
+			expected:  []any{},
+		},
+		{
+			name:      "violations are reported",
+			plan:      testPlan,
+			eventName: "purchse",
+			expected:  []any{`unknown event "purchse"`},
+		},
+		{
+			name:        "strict plan marks violating events broken",
+			plan:        &properties.TrackingPlan{Events: testPlan.Events, Strict: true},
+			eventName:   "purchse",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			psr := properties.NewStaticSettingsRegistry([]properties.Settings{{
+				PropertyID:            "test_property_id",
+				PropertyMeasurementID: "G-TEST",
+				TrackingPlan:          tc.plan,
+			}})
+			protocols := protocol.NewStaticRegistry(
+				map[string]protocol.Protocol{},
+				&paramsProtocol{params: map[string]any{}},
+			)
+			hit := hits.New()
+			hit.PropertyID = "test_property_id"
+			hit.EventName = tc.eventName
+			event := schema.NewEvent(hit)
+
+			// when
+			err := ViolationsColumn(psr, protocols).Write(event)
+
+			// then
+			if tc.expectError {
+				require.Error(t, err)
+				assert.False(t, err.IsRetryable())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, event.Values["tracking_plan_violations"])
+		})
+	}
+}
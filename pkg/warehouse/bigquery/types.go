@@ -2,6 +2,7 @@ package bigquery
 
 import (
 	"fmt"
+	"sort"
 	"time"
 
 	"cloud.google.com/go/bigquery"
@@ -271,6 +272,107 @@ func (m *bigQueryArrayTypeMapper) WarehouseToArrow(
 	}, nil
 }
 
+// bigQueryMapTypeMapper maps maps of strings to strings or float64s to repeated records of
+// a key and a STRING or FLOAT64 value, as BigQuery has no map type.
+type bigQueryMapTypeMapper struct{}
+
+func (m *bigQueryMapTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificBigQueryType, error) {
+	var valueType bigquery.FieldType
+	var formatFunc func(i any, m arrow.Metadata) (any, error)
+	switch {
+	case warehouse.IsStringMap(arrowType.ArrowDataType):
+		valueType, formatFunc = bigquery.StringFieldType, formatBigQueryStringMap
+	case warehouse.IsFloat64Map(arrowType.ArrowDataType):
+		valueType, formatFunc = bigquery.FloatFieldType, formatBigQueryFloat64Map
+	default:
+		return SpecificBigQueryType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+
+	return SpecificBigQueryType{
+		FieldType: bigquery.RecordFieldType,
+		Required:  false,
+		Repeated:  true,
+		Schema: &bigquery.Schema{
+			{Name: "key", Type: bigquery.StringFieldType, Required: true},
+			{Name: "value", Type: valueType},
+		},
+		FormatFunc: func(_ SpecificBigQueryType) func(i any, m arrow.Metadata) (any, error) {
+			return formatFunc
+		},
+	}, nil
+}
+
+func (m *bigQueryMapTypeMapper) WarehouseToArrow(
+	warehouseType SpecificBigQueryType,
+) (warehouse.ArrowType, error) {
+	if warehouseType.FieldType != bigquery.RecordFieldType || !warehouseType.Repeated ||
+		warehouseType.Schema == nil {
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+	}
+	itemType, ok := bigQueryMapItemType(*warehouseType.Schema)
+	if !ok {
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+	}
+	// Repeated fields are never required in BigQuery, so maps are read back as nullable
+	return warehouse.ArrowType{
+		ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, itemType),
+		Nullable:      true,
+	}, nil
+}
+
+// bigQueryMapItemType returns the Arrow type of the map values when the record schema is
+// the one written for maps: a required STRING key and a STRING or FLOAT64 value.
+func bigQueryMapItemType(schema bigquery.Schema) (arrow.DataType, bool) {
+	if len(schema) != 2 {
+		return nil, false
+	}
+	key, value := schema[0], schema[1]
+	if key.Name != "key" || key.Type != bigquery.StringFieldType || !key.Required || key.Repeated ||
+		value.Name != "value" || value.Repeated {
+		return nil, false
+	}
+	switch value.Type {
+	case bigquery.StringFieldType:
+		return arrow.BinaryTypes.String, true
+	case bigquery.FloatFieldType:
+		return arrow.PrimitiveTypes.Float64, true
+	default:
+		return nil, false
+	}
+}
+
+// formatBigQueryStringMap formats a map of strings as records sorted by key.
+func formatBigQueryStringMap(i any, _ arrow.Metadata) (any, error) {
+	values, err := warehouse.ToStringMap(i)
+	if err != nil {
+		return nil, err
+	}
+	return bigQueryMapRecords(values), nil
+}
+
+// formatBigQueryFloat64Map formats a map of float64s as records sorted by key.
+func formatBigQueryFloat64Map(i any, _ arrow.Metadata) (any, error) {
+	values, err := warehouse.ToFloat64Map(i)
+	if err != nil {
+		return nil, err
+	}
+	return bigQueryMapRecords(values), nil
+}
+
+func bigQueryMapRecords[V string | float64](values map[string]V) []any {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	records := make([]any, 0, len(keys))
+	for _, key := range keys {
+		records = append(records, map[string]any{"key": key, "value": values[key]})
+	}
+	return records
+}
+
 type bigQueryNestedTypeMapper struct {
 	SubMapper warehouse.FieldTypeMapper[SpecificBigQueryType]
 }
@@ -281,15 +383,8 @@ func (m *bigQueryNestedTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowTyp
 		return SpecificBigQueryType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
 	}
 
-	// If this is a direct Struct (not inside an array), reject it
-	if getParentType(arrowType) != "array" {
-		return SpecificBigQueryType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
-	}
-
-	if err := m.validateStructFields(structType); err != nil {
-		return SpecificBigQueryType{}, err
-	}
-
+	// Unlike the other warehouses, BigQuery supports RECORD columns at any level, including
+	// records and repeated fields nested in records, as used by the GA4 export schema.
 	fieldTypes, schema, err := m.buildFieldTypesAndSchema(structType)
 	if err != nil {
 		return SpecificBigQueryType{}, err
@@ -297,22 +392,13 @@ func (m *bigQueryNestedTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowTyp
 
 	return SpecificBigQueryType{
 		FieldType:  bigquery.RecordFieldType,
-		Required:   false,
+		Required:   !arrowType.Nullable,
 		Repeated:   false,
 		Schema:     &schema,
 		FormatFunc: m.createFormatFunc(structType, fieldTypes),
 	}, nil
 }
 
-func (m *bigQueryNestedTypeMapper) validateStructFields(structType *arrow.StructType) error {
-	for _, field := range structType.Fields() {
-		if err := m.validatePrimitiveField(field.Type); err != nil {
-			return err
-		}
-	}
-	return nil
-}
-
 func (m *bigQueryNestedTypeMapper) buildFieldTypesAndSchema(
 	structType *arrow.StructType,
 ) ([]SpecificBigQueryType, bigquery.Schema, error) {
@@ -373,6 +459,9 @@ func (m *bigQueryNestedTypeMapper) createFormatFunc(
 ) func(SpecificBigQueryType) func(i any, m arrow.Metadata) (any, error) {
 	return func(_ SpecificBigQueryType) func(i any, metadata arrow.Metadata) (any, error) {
 		return func(i any, metadata arrow.Metadata) (any, error) {
+			if i == nil {
+				return nil, nil
+			}
 			record, ok := i.(map[string]any)
 			if !ok {
 				return nil, fmt.Errorf("expected map[string]any for nested type, got %T", i)
@@ -391,21 +480,6 @@ func (m *bigQueryNestedTypeMapper) createFormatFunc(
 	}
 }
 
-// validatePrimitiveField ensures that the field type is primitive (no nested Lists or Structs)
-func (m *bigQueryNestedTypeMapper) validatePrimitiveField(dataType arrow.DataType) error {
-	switch dataType.(type) {
-	case *arrow.ListType:
-		// Lists inside Struct are not allowed (multi-level nesting)
-		return warehouse.NewUnsupportedMappingErr(dataType, MapperName)
-	case *arrow.StructType:
-		// Structs inside Struct are not allowed (multi-level nesting)
-		return warehouse.NewUnsupportedMappingErr(dataType, MapperName)
-	default:
-		// Primitive types are allowed
-		return nil
-	}
-}
-
 func (m *bigQueryNestedTypeMapper) WarehouseToArrow(
 	warehouseType SpecificBigQueryType,
 ) (warehouse.ArrowType, error) {
@@ -707,12 +781,14 @@ func NewFieldTypeMapper() warehouse.FieldTypeMapper[SpecificBigQueryType] {
 		return comprehensiveMapper
 	})
 
+	// Map mapper comes first, so that its repeated records aren't read back as lists
+	mapMapper := &bigQueryMapTypeMapper{}
 	arrayMapper := &bigQueryArrayTypeMapper{SubMapper: deferredMapper}
 	nestedMapper := &bigQueryNestedTypeMapper{SubMapper: deferredMapper}
 	nullableMapper := &bigQueryNullableTypeMapper{SubMapper: deferredMapper}
 
-	allMappers := make([]warehouse.FieldTypeMapper[SpecificBigQueryType], 0, 3+len(baseMappers))
-	allMappers = append(allMappers, arrayMapper, nestedMapper, nullableMapper)
+	allMappers := make([]warehouse.FieldTypeMapper[SpecificBigQueryType], 0, 4+len(baseMappers))
+	allMappers = append(allMappers, mapMapper, arrayMapper, nestedMapper, nullableMapper)
 	allMappers = append(allMappers, baseMappers...)
 
 	comprehensiveMapper = warehouse.NewTypeMapper(allMappers)
@@ -289,12 +289,8 @@ func getTestCases() []TypeMappingTestCase {
 		},
 		{
 			BaseTestCase: BaseTestCase{
-				name:        "nested struct with string fields",
-				expectError: true,
-				errorType: warehouse.NewUnsupportedMappingErr(arrow.StructOf(
-					arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
-					arrow.Field{Name: "description", Type: arrow.BinaryTypes.String, Nullable: true},
-				), MapperName),
+				name:        "struct with string fields",
+				expectError: false,
 			},
 			arrowType: warehouse.ArrowType{
 				ArrowDataType: arrow.StructOf(
@@ -302,17 +298,16 @@ func getTestCases() []TypeMappingTestCase {
 					arrow.Field{Name: "description", Type: arrow.BinaryTypes.String, Nullable: true},
 				),
 			},
-			// Remove expected BQ type since this should fail
+			expectedBQType: SpecificBigQueryType{
+				FieldType: bigquery.RecordFieldType,
+				Required:  true,
+				Repeated:  false,
+			},
 		},
 		{
 			BaseTestCase: BaseTestCase{
-				name:        "nested struct with mixed types",
-				expectError: true,
-				errorType: warehouse.NewUnsupportedMappingErr(arrow.StructOf(
-					arrow.Field{Name: "id", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
-					arrow.Field{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
-					arrow.Field{Name: "active", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
-				), MapperName),
+				name:        "nullable struct with mixed types",
+				expectError: false,
 			},
 			arrowType: warehouse.ArrowType{
 				ArrowDataType: arrow.StructOf(
@@ -320,8 +315,33 @@ func getTestCases() []TypeMappingTestCase {
 					arrow.Field{Name: "score", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
 					arrow.Field{Name: "active", Type: arrow.FixedWidthTypes.Boolean, Nullable: true},
 				),
+				Nullable: true,
+			},
+			expectedBQType: SpecificBigQueryType{
+				FieldType: bigquery.RecordFieldType,
+				Required:  false,
+				Repeated:  false,
+			},
+		},
+		{
+			BaseTestCase: BaseTestCase{
+				name:        "list of struct with nested struct",
+				expectError: false,
+			},
+			arrowType: warehouse.ArrowType{
+				ArrowDataType: arrow.ListOf(arrow.StructOf(
+					arrow.Field{Name: "key", Type: arrow.BinaryTypes.String, Nullable: true},
+					arrow.Field{Name: "value", Type: arrow.StructOf(
+						arrow.Field{Name: "string_value", Type: arrow.BinaryTypes.String, Nullable: true},
+						arrow.Field{Name: "int_value", Type: arrow.PrimitiveTypes.Int64, Nullable: true},
+					), Nullable: true},
+				)),
+			},
+			expectedBQType: SpecificBigQueryType{
+				FieldType: bigquery.RecordFieldType,
+				Required:  false,
+				Repeated:  true,
 			},
-			// Remove expected BQ type since this should fail
 		},
 		{
 			BaseTestCase: BaseTestCase{
@@ -458,6 +478,45 @@ func getTestCases() []TypeMappingTestCase {
 			arrowType:       warehouse.ArrowType{ArrowDataType: arrow.BinaryTypes.String},
 			useNestedMapper: true,
 		},
+		{
+			BaseTestCase: BaseTestCase{
+				name:        "map of strings",
+				expectError: false,
+			},
+			arrowType: warehouse.ArrowType{
+				ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String),
+				Nullable:      true,
+			},
+			expectedBQType: SpecificBigQueryType{
+				FieldType: bigquery.RecordFieldType,
+				Required:  false,
+				Repeated:  true,
+			},
+		},
+		{
+			BaseTestCase: BaseTestCase{
+				name:        "map of float64s",
+				expectError: false,
+			},
+			arrowType: warehouse.ArrowType{
+				ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64),
+				Nullable:      true,
+			},
+			expectedBQType: SpecificBigQueryType{
+				FieldType: bigquery.RecordFieldType,
+				Required:  false,
+				Repeated:  true,
+			},
+		},
+		{
+			BaseTestCase: BaseTestCase{
+				name:        "map of int64",
+				expectError: true,
+			},
+			arrowType: warehouse.ArrowType{
+				ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int64),
+			},
+		},
 		{
 			BaseTestCase: BaseTestCase{
 				name:        "non-nullable type to nullable mapper",
@@ -787,6 +846,51 @@ func TestFormatFunction(t *testing.T) {
 			input:    nil,
 			expected: []any{},
 		},
+		{
+			name: "map of strings keeps numeric looking values as strings",
+			bqType: func() SpecificBigQueryType {
+				bt, err := NewFieldTypeMapper().ArrowToWarehouse(warehouse.ArrowType{
+					ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String),
+					Nullable:      true,
+				})
+				require.NoError(t, err)
+				return bt
+			}(),
+			input: map[string]any{"zip": "00123", "currency": "USD"},
+			expected: []any{
+				map[string]any{"key": "currency", "value": "USD"},
+				map[string]any{"key": "zip", "value": "00123"},
+			},
+		},
+		{
+			name: "map of float64s",
+			bqType: func() SpecificBigQueryType {
+				bt, err := NewFieldTypeMapper().ArrowToWarehouse(warehouse.ArrowType{
+					ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64),
+					Nullable:      true,
+				})
+				require.NoError(t, err)
+				return bt
+			}(),
+			input: map[string]any{"score": float64(42), "ratio": 0.5},
+			expected: []any{
+				map[string]any{"key": "ratio", "value": 0.5},
+				map[string]any{"key": "score", "value": float64(42)},
+			},
+		},
+		{
+			name: "map nil input coerces to empty array",
+			bqType: func() SpecificBigQueryType {
+				bt, err := NewFieldTypeMapper().ArrowToWarehouse(warehouse.ArrowType{
+					ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String),
+					Nullable:      true,
+				})
+				require.NoError(t, err)
+				return bt
+			}(),
+			input:    nil,
+			expected: []any{},
+		},
 	}
 
 	for _, tc := range testCases {
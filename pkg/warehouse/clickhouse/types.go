@@ -226,6 +226,38 @@ func (m *clickhouseDate32TypeMapper) WarehouseToArrow(
 
 // === COMPLEX TYPE MAPPERS ===
 
+// clickhouseMapTypeMapper maps maps of strings to strings or float64s to Map(String, String)
+// or Map(String, Float64)
+type clickhouseMapTypeMapper struct{}
+
+func (m *clickhouseMapTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificClickhouseType, error) {
+	switch {
+	case warehouse.IsStringMap(arrowType.ArrowDataType):
+		return clickhouseStringMap, nil
+	case warehouse.IsFloat64Map(arrowType.ArrowDataType):
+		return clickhouseFloat64Map, nil
+	default:
+		return SpecificClickhouseType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, ClickhouseMapperName)
+	}
+}
+
+func (m *clickhouseMapTypeMapper) WarehouseToArrow(
+	warehouseType SpecificClickhouseType,
+) (warehouse.ArrowType, error) {
+	switch warehouseType.TypeAsString {
+	case clickhouseStringMap.TypeAsString:
+		return warehouse.ArrowType{
+			ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String),
+		}, nil
+	case clickhouseFloat64Map.TypeAsString:
+		return warehouse.ArrowType{
+			ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64),
+		}, nil
+	default:
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, ClickhouseMapperName)
+	}
+}
+
 type clickhouseArrayTypeMapper struct {
 	SubMapper warehouse.FieldTypeMapper[SpecificClickhouseType]
 }
@@ -818,6 +850,24 @@ var clickhouseDate32 = SpecificClickhouseType{
 	},
 }
 
+var clickhouseStringMap = SpecificClickhouseType{
+	TypeAsString:         "Map(String, String)",
+	DefaultValue:         map[string]string{},
+	DefaultSQLExpression: "map()",
+	FormatFunc: func(i any, _ arrow.Metadata) (any, error) {
+		return warehouse.ToStringMap(i)
+	},
+}
+
+var clickhouseFloat64Map = SpecificClickhouseType{
+	TypeAsString:         "Map(String, Float64)",
+	DefaultValue:         map[string]float64{},
+	DefaultSQLExpression: "map()",
+	FormatFunc: func(i any, _ arrow.Metadata) (any, error) {
+		return warehouse.ToFloat64Map(i)
+	},
+}
+
 func anyType(asString string) SpecificClickhouseType {
 	return SpecificClickhouseType{
 		TypeAsString: asString,
@@ -859,6 +909,7 @@ func NewFieldTypeMapper() warehouse.FieldTypeMapper[SpecificClickhouseType] {
 		&clickhouseTimestampTypeMapper{},
 		&clickhouseBoolTypeMapper{},
 		&clickhouseDate32TypeMapper{},
+		&clickhouseMapTypeMapper{},
 	}
 
 	// deferred mapper for circular dependency resolution
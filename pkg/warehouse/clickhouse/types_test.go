@@ -429,6 +429,35 @@ func getTestCases() []TypeMappingTestCase {
 			},
 			expectedCHType: "Nested(`name` String, `score` Float64, `birth_date` Date32)",
 		},
+		{
+			BaseTestCase: BaseTestCase{
+				name:        "map of strings",
+				expectError: false,
+			},
+			arrowType: warehouse.ArrowType{
+				ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String),
+			},
+			expectedCHType: "Map(String, String)",
+		},
+		{
+			BaseTestCase: BaseTestCase{
+				name:        "map of float64s",
+				expectError: false,
+			},
+			arrowType: warehouse.ArrowType{
+				ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64),
+			},
+			expectedCHType: "Map(String, Float64)",
+		},
+		{
+			BaseTestCase: BaseTestCase{
+				name:        "map of int64",
+				expectError: true,
+			},
+			arrowType: warehouse.ArrowType{
+				ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Int64),
+			},
+		},
 	}
 }
 
@@ -510,6 +539,65 @@ func TestArrayFormatNilAsEmpty(t *testing.T) {
 	}
 }
 
+func TestMapFormat(t *testing.T) {
+	testCases := []struct {
+		name           string
+		itemType       arrow.DataType
+		value          any
+		expectedCHType string
+		expected       any
+	}{
+		{
+			name:           "map of strings",
+			itemType:       arrow.BinaryTypes.String,
+			value:          map[string]any{"currency": "USD", "zip": "00123", "empty": nil},
+			expectedCHType: "Map(String, String)",
+			expected:       map[string]string{"currency": "USD", "zip": "00123"},
+		},
+		{
+			name:           "map of strings nil as empty",
+			itemType:       arrow.BinaryTypes.String,
+			value:          nil,
+			expectedCHType: "Map(String, String)",
+			expected:       map[string]string{},
+		},
+		{
+			name:           "map of float64s",
+			itemType:       arrow.PrimitiveTypes.Float64,
+			value:          map[string]any{"score": float64(42), "ratio": 0.5, "empty": nil},
+			expectedCHType: "Map(String, Float64)",
+			expected:       map[string]float64{"score": 42, "ratio": 0.5},
+		},
+		{
+			name:           "map of float64s nil as empty",
+			itemType:       arrow.PrimitiveTypes.Float64,
+			value:          nil,
+			expectedCHType: "Map(String, Float64)",
+			expected:       map[string]float64{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			chType, err := NewFieldTypeMapper().ArrowToWarehouse(warehouse.ArrowType{
+				ArrowDataType: arrow.MapOf(arrow.BinaryTypes.String, tc.itemType),
+				Nullable:      true,
+			})
+			require.NoError(t, err)
+
+			// when
+			formatted, err := chType.Format(tc.value, arrow.Metadata{})
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedCHType, chType.TypeAsString)
+			assert.Equal(t, "map()", chType.DefaultSQLExpression)
+			assert.Equal(t, tc.expected, formatted)
+		})
+	}
+}
+
 func TestWarehouseToArrow(t *testing.T) {
 	testCases := getTestCases()
 
@@ -0,0 +1,304 @@
+//go:build cgo && duckdb_arrow
+
+// Package duckdb provides implementation of an embedded DuckDB data warehouse
+package duckdb
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/duckdb/duckdb-go/v2"
+	"github.com/sirupsen/logrus"
+)
+
+const defaultSchema = "main"
+
+type duckDBDriver struct {
+	connector       *duckdb.Connector
+	db              *sql.DB
+	schema          string
+	queryMapper     *duckDBQueryMapper
+	fieldTypeMapper warehouse.FieldTypeMapper[SpecificDuckDBType]
+	queryTimeout    time.Duration
+	typeComparer    *warehouse.TypeComparer
+}
+
+// Option configures the DuckDB table driver.
+type Option func(*duckDBDriver)
+
+// WithQueryTimeout sets the timeout of DDL and metadata queries.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(d *duckDBDriver) {
+		d.queryTimeout = timeout
+	}
+}
+
+// NewDuckDBTableDriver creates a new DuckDB table driver storing the tables in the database
+// file at the given path, which is created if it doesn't exist. An empty path keeps the
+// database in memory. The file can't be opened by another process while the driver is open.
+func NewDuckDBTableDriver(path string, opts ...Option) (warehouse.Driver, error) {
+	if path != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0o750); err != nil {
+			return nil, fmt.Errorf("creating DuckDB database directory: %w", err)
+		}
+	}
+	connector, err := duckdb.NewConnector(path, nil)
+	if err != nil {
+		return nil, fmt.Errorf("opening DuckDB database: %w", err)
+	}
+
+	d := &duckDBDriver{
+		connector:       connector,
+		db:              sql.OpenDB(connector),
+		schema:          defaultSchema,
+		queryMapper:     newDuckDBQueryMapper(),
+		fieldTypeMapper: NewFieldTypeMapper(),
+		queryTimeout:    30 * time.Second,
+		typeComparer:    warehouse.NewTypeComparer(),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+func (d *duckDBDriver) rawTableName(table string) string {
+	return fmt.Sprintf("%s.%s", d.schema, table)
+}
+
+func (d *duckDBDriver) CreateTable(table string, schema *arrow.Schema) error {
+	query, err := warehouse.CreateTableQuery(d.queryMapper, quoteFullTableName(d.schema, table), schema)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.queryTimeout)
+	defer cancel()
+	if _, err := d.db.ExecContext(ctx, query); err != nil {
+		if isAlreadyExistsErr(err) {
+			return warehouse.NewTableAlreadyExistsError(d.rawTableName(table))
+		}
+		return err
+	}
+	return nil
+}
+
+// AddColumn implements warehouse.Driver. DuckDB can't add a column with a NOT NULL
+// constraint, so non-nullable columns are added with a default for the existing rows
+// and constrained afterwards.
+func (d *duckDBDriver) AddColumn(table string, field *arrow.Field) error {
+	fieldType, err := d.queryMapper.fieldType(field)
+	if err != nil {
+		return fmt.Errorf("error converting field type: %w", err)
+	}
+
+	fullTableName := quoteFullTableName(d.schema, table)
+	queries := []string{
+		fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", fullTableName, quoteIdentifier(field.Name), fieldType.TypeAsString),
+	}
+	if !fieldType.Nullable {
+		queries = []string{
+			fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s DEFAULT %s",
+				fullTableName, quoteIdentifier(field.Name), fieldType.TypeAsString, fieldType.DefaultSQLExpression),
+			fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s SET NOT NULL", fullTableName, quoteIdentifier(field.Name)),
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.queryTimeout)
+	defer cancel()
+	tx, err := d.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("error starting transaction: %w", err)
+	}
+	for _, query := range queries {
+		if _, err := tx.ExecContext(ctx, query); err != nil {
+			if rollbackErr := tx.Rollback(); rollbackErr != nil {
+				logrus.Error("failed to roll back adding column: ", rollbackErr)
+			}
+			if isAlreadyExistsErr(err) {
+				return warehouse.NewColumnAlreadyExistsError(d.rawTableName(table), field.Name)
+			}
+			return fmt.Errorf("error adding column: %w", err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("error adding column: %w", err)
+	}
+	return nil
+}
+
+// columns retrieves all columns from the specified table as Arrow fields
+func (d *duckDBDriver) columns(ctx context.Context, table string) ([]*arrow.Field, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT column_name, data_type, is_nullable
+		FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = ?
+		ORDER BY ordinal_position
+	`
+
+	rows, err := d.db.QueryContext(ctx, query, d.schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if err := rows.Close(); err != nil {
+			logrus.Error("failed to close database rows: ", err)
+		}
+	}()
+
+	var fields []*arrow.Field
+	for rows.Next() {
+		var columnName, columnType, isNullable string
+		if err := rows.Scan(&columnName, &columnType, &isNullable); err != nil {
+			return nil, err
+		}
+
+		arrowType, err := d.fieldTypeMapper.WarehouseToArrow(SpecificDuckDBType{
+			TypeAsString: columnType,
+			Nullable:     isNullable == "YES",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, &arrow.Field{
+			Name:     columnName,
+			Type:     arrowType.ArrowDataType,
+			Nullable: arrowType.Nullable,
+			Metadata: arrowType.Metadata,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// If no rows were returned, the table doesn't exist
+	if len(fields) == 0 {
+		return nil, warehouse.NewTableNotFoundError(d.rawTableName(table))
+	}
+	return fields, nil
+}
+
+func (d *duckDBDriver) MissingColumns(table string, schema *arrow.Schema) ([]*arrow.Field, error) {
+	fields, err := d.columns(context.Background(), table)
+	if err != nil {
+		return nil, err
+	}
+
+	existingFields := make(map[string]*arrow.Field, len(fields))
+	for _, field := range fields {
+		existingFields[field.Name] = field
+	}
+
+	return warehouse.FindMissingColumns(d.rawTableName(table), existingFields, schema, d)
+}
+
+// Write implements warehouse.Driver. Rows are collected in an Arrow record up front, so a row
+// which doesn't match the schema fails the write before anything is inserted, then inserted in
+// bulk through an Arrow view of the record. Columns of the table missing from the schema are
+// left NULL.
+func (d *duckDBDriver) Write(ctx context.Context, table string, schema *arrow.Schema, rows []map[string]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	fields := schema.Fields()
+	columns := make([]string, len(fields))
+	columnTypes := make([]SpecificDuckDBType, len(fields))
+	for i := range fields {
+		columnType, err := d.queryMapper.fieldType(&fields[i])
+		if err != nil {
+			return fmt.Errorf("error mapping type for column %s: %w", fields[i].Name, err)
+		}
+		columns[i] = quoteIdentifier(fields[i].Name)
+		columnTypes[i] = columnType
+	}
+
+	record, err := buildRecord(schema, columnTypes, rows)
+	if err != nil {
+		return err
+	}
+	defer record.Release()
+
+	conn, err := d.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("error getting connection: %w", err)
+	}
+	defer func() {
+		if err := conn.Close(); err != nil {
+			logrus.Error("failed to close DuckDB connection: ", err)
+		}
+	}()
+
+	query := fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+		quoteFullTableName(d.schema, table), strings.Join(columns, ", "), strings.Join(columns, ", "),
+		quoteIdentifier(writeViewName))
+	return conn.Raw(func(driverConn any) error {
+		return insertRecord(ctx, driverConn.(driver.Conn), record, query)
+	})
+}
+
+// writeViewName is the name of the temporary view exposing the written record to the
+// INSERT query, views are local to the connection so concurrent writes don't clash.
+const writeViewName = "d8a_write_rows"
+
+func insertRecord(ctx context.Context, driverConn driver.Conn, record arrow.RecordBatch, query string) error {
+	arrowConn, err := duckdb.NewArrowFromConn(driverConn)
+	if err != nil {
+		return fmt.Errorf("error creating Arrow connection: %w", err)
+	}
+	reader, err := array.NewRecordReader(record.Schema(), []arrow.RecordBatch{record})
+	if err != nil {
+		return fmt.Errorf("error creating record reader: %w", err)
+	}
+	defer reader.Release()
+
+	release, err := arrowConn.RegisterView(reader, writeViewName)
+	if err != nil {
+		return fmt.Errorf("error registering Arrow view: %w", err)
+	}
+	defer release()
+
+	execer, ok := driverConn.(driver.ExecerContext)
+	if !ok {
+		return errors.New("DuckDB connection doesn't support executing queries")
+	}
+	if _, err := execer.ExecContext(ctx, query, nil); err != nil {
+		return fmt.Errorf("error inserting rows: %w", err)
+	}
+	if _, err := execer.ExecContext(ctx, "DROP VIEW IF EXISTS "+quoteIdentifier(writeViewName), nil); err != nil {
+		return fmt.Errorf("error dropping Arrow view: %w", err)
+	}
+	return nil
+}
+
+// Close implements warehouse.Driver.
+func (d *duckDBDriver) Close() error {
+	return errors.Join(d.db.Close(), d.connector.Close())
+}
+
+// AreFieldsCompatible implements warehouse.FieldCompatibilityChecker
+func (d *duckDBDriver) AreFieldsCompatible(existing, input *arrow.Field) (bool, error) {
+	if existing.Nullable != input.Nullable {
+		return false, fmt.Errorf("nullability differs - existing: %t, input: %t", existing.Nullable, input.Nullable)
+	}
+
+	result := d.typeComparer.Compare(existing.Type, input.Type, existing.Name)
+	if !result.Equal {
+		return false, errors.New(result.ErrorMessage)
+	}
+	return true, nil
+}
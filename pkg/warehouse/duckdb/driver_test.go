@@ -0,0 +1,115 @@
+//go:build cgo && duckdb_arrow
+
+package duckdb
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/d8a-tech/d8a/pkg/warehouse/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestDriver(t *testing.T) *duckDBDriver {
+	t.Helper()
+	driver, err := NewDuckDBTableDriver(filepath.Join(t.TempDir(), "warehouse", "d8a.duckdb"))
+	require.NoError(t, err)
+	t.Cleanup(func() { assert.NoError(t, driver.Close()) })
+	return driver.(*duckDBDriver)
+}
+
+func TestDuckDBDriver(t *testing.T) {
+	t.Run("missing_columns", func(t *testing.T) {
+		testutils.TestMissingColumns(t, newTestDriver(t), "missing_columns")
+	})
+	t.Run("basic_writes", func(t *testing.T) {
+		testutils.TestBasicWrites(t, newTestDriver(t), "basic_writes")
+	})
+	t.Run("complex_writes", func(t *testing.T) {
+		testutils.TestComplexWrites(t, newTestDriver(t), "complex_writes")
+	})
+	t.Run("add_column", func(t *testing.T) {
+		testutils.TestAddColumn(t, newTestDriver(t), "add_column")
+	})
+	t.Run("create_table", func(t *testing.T) {
+		testutils.TestCreateTable(t, newTestDriver(t), "create_table")
+	})
+}
+
+func TestDuckDBDriver_WrittenRowsAreQueryable(t *testing.T) {
+	// given
+	driver := newTestDriver(t)
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "timestamp", Type: arrow.FixedWidthTypes.Timestamp_s},
+		{Name: "params", Type: arrow.ListOf(arrow.StructOf(
+			arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "value", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		)), Nullable: true},
+	}, nil)
+	require.NoError(t, driver.CreateTable("events", schema))
+
+	// when
+	err := driver.Write(context.Background(), "events", schema, []map[string]any{
+		{
+			"name":      "purchase",
+			"timestamp": time.Date(2026, 3, 7, 10, 29, 59, 0, time.UTC),
+			"params":    []any{map[string]any{"name": "value", "value": 42.5}},
+		},
+		{"name": "page_view", "timestamp": int64(1772879400), "params": nil},
+	})
+
+	// then
+	require.NoError(t, err)
+	var count int
+	var total float64
+	require.NoError(t, driver.db.QueryRow(
+		`SELECT count(*), sum(list_sum([p.value FOR p IN coalesce(params, [])])) FROM events`,
+	).Scan(&count, &total))
+	assert.Equal(t, 2, count)
+	assert.Equal(t, 42.5, total)
+}
+
+func TestDuckDBDriver_AddNonNullableColumnToTableWithRows(t *testing.T) {
+	// given
+	driver := newTestDriver(t)
+	schema := arrow.NewSchema([]arrow.Field{{Name: "name", Type: arrow.BinaryTypes.String}}, nil)
+	require.NoError(t, driver.CreateTable("events", schema))
+	require.NoError(t, driver.Write(context.Background(), "events", schema, []map[string]any{{"name": "page_view"}}))
+	field := &arrow.Field{Name: "count", Type: arrow.PrimitiveTypes.Int64}
+
+	// when
+	err := driver.AddColumn("events", field)
+
+	// then
+	require.NoError(t, err)
+	missing, err := driver.MissingColumns("events", arrow.NewSchema([]arrow.Field{schema.Field(0), *field}, nil))
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestDuckDBDriver_WriteRejectsMismatchedRowsAtomically(t *testing.T) {
+	// given
+	driver := newTestDriver(t)
+	schema := arrow.NewSchema([]arrow.Field{{Name: "name", Type: arrow.BinaryTypes.String}}, nil)
+	require.NoError(t, driver.CreateTable("events", schema))
+
+	// when
+	err := driver.Write(context.Background(), "events", schema, []map[string]any{
+		{"name": "page_view"},
+		{"name": nil},
+	})
+
+	// then
+	require.Error(t, err)
+	var count int
+	require.NoError(t, driver.db.QueryRow(`SELECT count(*) FROM events`).Scan(&count))
+	assert.Zero(t, count)
+}
+
+var _ warehouse.FieldCompatibilityChecker = &duckDBDriver{}
@@ -0,0 +1,55 @@
+//go:build cgo && duckdb_arrow
+
+package duckdb
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+)
+
+type duckDBQueryMapper struct {
+	fieldTypeMapper warehouse.FieldTypeMapper[SpecificDuckDBType]
+}
+
+// NewDuckDBQueryMapper creates a new DuckDB query mapper.
+func NewDuckDBQueryMapper() warehouse.QueryMapper {
+	return newDuckDBQueryMapper()
+}
+
+func newDuckDBQueryMapper() *duckDBQueryMapper {
+	return &duckDBQueryMapper{fieldTypeMapper: NewFieldTypeMapper()}
+}
+
+func (q *duckDBQueryMapper) TablePredicate(table string) string {
+	return fmt.Sprintf("TABLE %s", table)
+}
+
+// ColumnName implements warehouse.QueryMapper.
+func (q *duckDBQueryMapper) ColumnName(name string) string {
+	return quoteIdentifier(name)
+}
+
+func (q *duckDBQueryMapper) TableSuffix(_ string) string {
+	return ""
+}
+
+func (q *duckDBQueryMapper) Field(field *arrow.Field) (string, error) {
+	fieldType, err := q.fieldType(field)
+	if err != nil {
+		return "", err
+	}
+	if fieldType.Nullable {
+		return fieldType.TypeAsString, nil
+	}
+	return fieldType.TypeAsString + " NOT NULL", nil
+}
+
+func (q *duckDBQueryMapper) fieldType(field *arrow.Field) (SpecificDuckDBType, error) {
+	return q.fieldTypeMapper.ArrowToWarehouse(warehouse.ArrowType{
+		ArrowDataType: field.Type,
+		Nullable:      field.Nullable,
+		Metadata:      field.Metadata,
+	})
+}
@@ -0,0 +1,41 @@
+//go:build cgo && duckdb_arrow
+
+package duckdb
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/d8a-tech/d8a/pkg/warehouse/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateQuery(t *testing.T) {
+	// given
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.BinaryTypes.String},
+		{Name: "timestamp", Type: arrow.FixedWidthTypes.Timestamp_s, Nullable: true},
+		{Name: "tags", Type: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+	}, nil)
+
+	// when
+	query, err := warehouse.CreateTableQuery(NewDuckDBQueryMapper(), `"main"."events"`, schema)
+
+	// then
+	require.NoError(t, err)
+	assert.Equal(t, `CREATE TABLE "main"."events" (
+  "id" VARCHAR NOT NULL,
+  "timestamp" TIMESTAMP,
+  "tags" VARCHAR[]
+)`, query)
+}
+
+func TestQueryMapperArrowTypes(t *testing.T) {
+	testutils.TestSupportedArrowTypes(t, NewDuckDBQueryMapper())
+}
+
+func TestQueryMapperTypeErrors(t *testing.T) {
+	testutils.TestQueryMapperTypeErrors(t, NewDuckDBQueryMapper())
+}
@@ -0,0 +1,146 @@
+//go:build cgo && duckdb_arrow
+
+package duckdb
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+)
+
+// recordType returns the Arrow type a column is sent to DuckDB with. Timestamps drop their
+// time zone, DuckDB reads zoned Arrow timestamps as TIMESTAMP WITH TIME ZONE while the
+// columns are plain TIMESTAMPs holding UTC.
+func recordType(dataType arrow.DataType) arrow.DataType {
+	switch t := dataType.(type) {
+	case *arrow.TimestampType:
+		return &arrow.TimestampType{Unit: t.Unit}
+	case *arrow.ListType:
+		elem := t.ElemField()
+		elem.Type = recordType(elem.Type)
+		return arrow.ListOfField(elem)
+	case *arrow.StructType:
+		fields := make([]arrow.Field, len(t.Fields()))
+		for i, field := range t.Fields() {
+			field.Type = recordType(field.Type)
+			fields[i] = field
+		}
+		return arrow.StructOf(fields...)
+	default:
+		return dataType
+	}
+}
+
+func recordSchema(schema *arrow.Schema) *arrow.Schema {
+	fields := make([]arrow.Field, len(schema.Fields()))
+	for i, field := range schema.Fields() {
+		field.Type = recordType(field.Type)
+		fields[i] = field
+	}
+	return arrow.NewSchema(fields, nil)
+}
+
+// buildRecord formats the rows with the DuckDB types of the columns and collects them
+// in an Arrow record, so a row which doesn't match the schema fails the write before
+// anything reaches the table.
+func buildRecord(
+	schema *arrow.Schema, columnTypes []SpecificDuckDBType, rows []map[string]any,
+) (arrow.RecordBatch, error) {
+	builder := array.NewRecordBuilder(memory.DefaultAllocator, recordSchema(schema))
+	defer builder.Release()
+
+	fields := schema.Fields()
+	for _, row := range rows {
+		for i := range fields {
+			value, exists := row[fields[i].Name]
+			if !exists {
+				return nil, fmt.Errorf("missing value for column %s", fields[i].Name)
+			}
+			if value == nil && !columnTypes[i].Nullable {
+				return nil, fmt.Errorf("null value for non-nullable column %s", fields[i].Name)
+			}
+			formattedValue, err := columnTypes[i].Format(value, fields[i].Metadata)
+			if err != nil {
+				return nil, fmt.Errorf("error formatting value for column %s: %w", fields[i].Name, err)
+			}
+			if err := appendValue(builder.Field(i), formattedValue); err != nil {
+				return nil, fmt.Errorf("error appending value for column %s: %w", fields[i].Name, err)
+			}
+		}
+	}
+	return builder.NewRecordBatch(), nil
+}
+
+// appendValue appends a value formatted by a SpecificDuckDBType to the Arrow builder.
+func appendValue(builder array.Builder, value any) error {
+	if value == nil {
+		builder.AppendNull()
+		return nil
+	}
+
+	switch b := builder.(type) {
+	case *array.StringBuilder:
+		return appendTyped(value, b.Append)
+	case *array.Int64Builder:
+		return appendTyped(value, b.Append)
+	case *array.Int32Builder:
+		return appendTyped(value, b.Append)
+	case *array.Float64Builder:
+		return appendTyped(value, b.Append)
+	case *array.Float32Builder:
+		return appendTyped(value, b.Append)
+	case *array.BooleanBuilder:
+		return appendTyped(value, b.Append)
+	case *array.TimestampBuilder:
+		return appendTyped(value, func(t time.Time) {
+			b.Append(arrow.Timestamp(t.Unix()))
+		})
+	case *array.Date32Builder:
+		return appendTyped(value, func(t time.Time) {
+			b.Append(arrow.Date32FromTime(t))
+		})
+	case *array.ListBuilder:
+		items, ok := value.([]any)
+		if !ok {
+			return fmt.Errorf("expected []any for list, got %T", value)
+		}
+		b.Append(true)
+		for idx, item := range items {
+			if err := appendValue(b.ValueBuilder(), item); err != nil {
+				return fmt.Errorf("error appending list element at index %d: %w", idx, err)
+			}
+		}
+		return nil
+	case *array.StructBuilder:
+		record, ok := value.(map[string]any)
+		if !ok {
+			return fmt.Errorf("expected map[string]any for struct, got %T", value)
+		}
+		b.Append(true)
+		structType, ok := b.Type().(*arrow.StructType)
+		if !ok {
+			return fmt.Errorf("unexpected struct builder type %s", b.Type())
+		}
+		for idx, field := range structType.Fields() {
+			if err := appendValue(b.FieldBuilder(idx), record[field.Name]); err != nil {
+				return fmt.Errorf("error appending field %s: %w", field.Name, err)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("unsupported Arrow type %s", builder.Type())
+	}
+}
+
+func appendTyped[T any](value any, appendFunc func(T)) error {
+	v, ok := value.(T)
+	if !ok {
+		var zero T
+		return fmt.Errorf("expected %T, got %T", zero, value)
+	}
+	appendFunc(v)
+	return nil
+}
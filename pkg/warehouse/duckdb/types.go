@@ -0,0 +1,479 @@
+//go:build cgo && duckdb_arrow
+
+package duckdb
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+)
+
+// MapperName is the name of the DuckDB type mapper
+const MapperName = "duckdb"
+
+const metadataParentType = "DuckDB.ParentType"
+
+// SpecificDuckDBType represents a DuckDB data type with its string representation and formatting function.
+// Nullability isn't part of DuckDB type names, it's a column constraint.
+type SpecificDuckDBType struct {
+	TypeAsString         string
+	Nullable             bool
+	DefaultSQLExpression string // SQL expression filling existing rows when a NOT NULL column is added
+	FormatFunc           func(i any, m arrow.Metadata) (any, error)
+}
+
+// Format formats a value according to the DuckDB type's formatting function
+func (t SpecificDuckDBType) Format(i any, m arrow.Metadata) (any, error) {
+	return t.FormatFunc(i, m)
+}
+
+// === PRIMITIVE TYPE MAPPERS ===
+
+// duckDBPrimitiveTypeMapper maps an Arrow type to a DuckDB type and back. DuckDB names
+// are matched exactly, aliases lists additional names DuckDB can report the type with.
+type duckDBPrimitiveTypeMapper struct {
+	arrowType arrow.DataType
+	duckType  SpecificDuckDBType
+	aliases   []string
+}
+
+func (m *duckDBPrimitiveTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificDuckDBType, error) {
+	if !arrow.TypeEqual(arrowType.ArrowDataType, m.arrowType) {
+		return SpecificDuckDBType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+	return m.duckType, nil
+}
+
+func (m *duckDBPrimitiveTypeMapper) WarehouseToArrow(warehouseType SpecificDuckDBType) (warehouse.ArrowType, error) {
+	if warehouseType.Nullable {
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+	}
+	if warehouseType.TypeAsString != m.duckType.TypeAsString {
+		found := false
+		for _, alias := range m.aliases {
+			if warehouseType.TypeAsString == alias {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+		}
+	}
+	return warehouse.ArrowType{ArrowDataType: m.arrowType}, nil
+}
+
+// === COMPLEX TYPE MAPPERS ===
+
+type duckDBListTypeMapper struct {
+	SubMapper warehouse.FieldTypeMapper[SpecificDuckDBType]
+}
+
+func (m *duckDBListTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificDuckDBType, error) {
+	listType, ok := arrowType.ArrowDataType.(*arrow.ListType)
+	if !ok {
+		return SpecificDuckDBType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+
+	elementType, err := m.SubMapper.ArrowToWarehouse(warehouse.ArrowType{
+		ArrowDataType: listType.Elem(),
+		Nullable:      listType.ElemField().Nullable,
+		Metadata:      arrow.NewMetadata([]string{metadataParentType}, []string{"array"}),
+	})
+	if err != nil {
+		return SpecificDuckDBType{}, err
+	}
+
+	return SpecificDuckDBType{
+		TypeAsString:         elementType.TypeAsString + "[]",
+		DefaultSQLExpression: "[]",
+		FormatFunc: func(i any, metadata arrow.Metadata) (any, error) {
+			if i == nil {
+				return []any{}, nil
+			}
+
+			slice, ok := i.([]any)
+			if !ok {
+				return nil, fmt.Errorf("expected []any for array, got %T", i)
+			}
+
+			result := make([]any, len(slice))
+			for idx, elem := range slice {
+				formatted, err := elementType.Format(elem, metadata)
+				if err != nil {
+					return nil, fmt.Errorf("error formatting array element at index %d: %w", idx, err)
+				}
+				result[idx] = formatted
+			}
+			return result, nil
+		},
+	}, nil
+}
+
+func (m *duckDBListTypeMapper) WarehouseToArrow(warehouseType SpecificDuckDBType) (warehouse.ArrowType, error) {
+	if warehouseType.Nullable || !strings.HasSuffix(warehouseType.TypeAsString, "[]") {
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+	}
+
+	// Elements of DuckDB lists are always nullable
+	elementType, err := m.SubMapper.WarehouseToArrow(SpecificDuckDBType{
+		TypeAsString: strings.TrimSuffix(warehouseType.TypeAsString, "[]"),
+		Nullable:     true,
+	})
+	if err != nil {
+		return warehouse.ArrowType{}, err
+	}
+
+	return warehouse.ArrowType{ArrowDataType: arrow.ListOf(elementType.ArrowDataType)}, nil
+}
+
+type duckDBStructTypeMapper struct {
+	SubMapper warehouse.FieldTypeMapper[SpecificDuckDBType]
+}
+
+func (m *duckDBStructTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificDuckDBType, error) {
+	structType, ok := arrowType.ArrowDataType.(*arrow.StructType)
+	if !ok {
+		return SpecificDuckDBType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+
+	// Structs are only supported as elements of lists, the same as in the other warehouses
+	parentType, _ := warehouse.GetArrowMetadataValue(arrowType.Metadata, metadataParentType)
+	if parentType != "array" {
+		return SpecificDuckDBType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+
+	fieldDefs := make([]string, 0, len(structType.Fields()))
+	fieldTypes := make([]SpecificDuckDBType, 0, len(structType.Fields()))
+	for _, field := range structType.Fields() {
+		switch field.Type.(type) {
+		case *arrow.ListType, *arrow.StructType:
+			// Only one level of nesting is supported
+			return SpecificDuckDBType{}, warehouse.NewUnsupportedMappingErr(field.Type, MapperName)
+		}
+		// DuckDB can't constrain fields of structs, they're always nullable
+		if !field.Nullable {
+			return SpecificDuckDBType{}, warehouse.NewUnsupportedMappingErr(field.Type, MapperName)
+		}
+
+		fieldType, err := m.SubMapper.ArrowToWarehouse(warehouse.ArrowType{
+			ArrowDataType: field.Type,
+			Nullable:      true,
+			Metadata:      warehouse.MergeArrowMetadata(field.Metadata, metadataParentType, "struct"),
+		})
+		if err != nil {
+			return SpecificDuckDBType{}, fmt.Errorf("error mapping field %s: %w", field.Name, err)
+		}
+		fieldDefs = append(fieldDefs, fmt.Sprintf("%s %s", quoteIdentifier(field.Name), fieldType.TypeAsString))
+		fieldTypes = append(fieldTypes, fieldType)
+	}
+
+	return SpecificDuckDBType{
+		TypeAsString: fmt.Sprintf("STRUCT(%s)", strings.Join(fieldDefs, ", ")),
+		FormatFunc: func(i any, metadata arrow.Metadata) (any, error) {
+			record, ok := i.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("expected map[string]any for struct, got %T", i)
+			}
+			// Every field of the struct is appended to the Arrow record
+			result := make(map[string]any, len(structType.Fields()))
+			for idx, field := range structType.Fields() {
+				formatted, err := fieldTypes[idx].Format(record[field.Name], metadata)
+				if err != nil {
+					return nil, fmt.Errorf("error formatting field %s: %w", field.Name, err)
+				}
+				result[field.Name] = formatted
+			}
+			return result, nil
+		},
+	}, nil
+}
+
+func (m *duckDBStructTypeMapper) WarehouseToArrow(warehouseType SpecificDuckDBType) (warehouse.ArrowType, error) {
+	if warehouseType.Nullable ||
+		!strings.HasPrefix(warehouseType.TypeAsString, "STRUCT(") ||
+		!strings.HasSuffix(warehouseType.TypeAsString, ")") {
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+	}
+
+	fieldDefs, err := splitStructFields(warehouseType.TypeAsString[len("STRUCT(") : len(warehouseType.TypeAsString)-1])
+	if err != nil {
+		return warehouse.ArrowType{}, err
+	}
+
+	fields := make([]arrow.Field, 0, len(fieldDefs))
+	for _, fieldDef := range fieldDefs {
+		name, typeAsString, err := parseStructField(fieldDef)
+		if err != nil {
+			return warehouse.ArrowType{}, err
+		}
+		fieldType, err := m.SubMapper.WarehouseToArrow(SpecificDuckDBType{TypeAsString: typeAsString, Nullable: true})
+		if err != nil {
+			return warehouse.ArrowType{}, fmt.Errorf("error converting field %s: %w", name, err)
+		}
+		fields = append(fields, arrow.Field{Name: name, Type: fieldType.ArrowDataType, Nullable: true})
+	}
+
+	return warehouse.ArrowType{ArrowDataType: arrow.StructOf(fields...)}, nil
+}
+
+// splitStructFields splits the fields of a DuckDB STRUCT type on the commas which aren't
+// nested in parentheses or quoted names.
+func splitStructFields(fieldsAsString string) ([]string, error) {
+	var fields []string
+	depth, start, quoted := 0, 0, false
+	for i := 0; i < len(fieldsAsString); i++ {
+		switch c := fieldsAsString[i]; {
+		case c == '"':
+			quoted = !quoted
+		case quoted:
+		case c == '(':
+			depth++
+		case c == ')':
+			depth--
+		case c == ',' && depth == 0:
+			fields = append(fields, strings.TrimSpace(fieldsAsString[start:i]))
+			start = i + 1
+		}
+	}
+	if depth != 0 || quoted {
+		return nil, fmt.Errorf("invalid STRUCT fields: %s", fieldsAsString)
+	}
+	return append(fields, strings.TrimSpace(fieldsAsString[start:])), nil
+}
+
+// parseStructField parses a field of a DuckDB STRUCT type, e.g. `"value" VARCHAR`.
+func parseStructField(fieldDef string) (name, typeAsString string, err error) {
+	if !strings.HasPrefix(fieldDef, `"`) {
+		parts := strings.SplitN(fieldDef, " ", 2)
+		if len(parts) != 2 {
+			return "", "", fmt.Errorf("invalid STRUCT field: %s", fieldDef)
+		}
+		return parts[0], strings.TrimSpace(parts[1]), nil
+	}
+	for i := 1; i < len(fieldDef); i++ {
+		if fieldDef[i] != '"' {
+			continue
+		}
+		// Quotes in names are escaped by doubling them
+		if i+1 < len(fieldDef) && fieldDef[i+1] == '"' {
+			i++
+			continue
+		}
+		return strings.ReplaceAll(fieldDef[1:i], `""`, `"`), strings.TrimSpace(fieldDef[i+1:]), nil
+	}
+	return "", "", fmt.Errorf("invalid STRUCT field: %s", fieldDef)
+}
+
+type duckDBNullableTypeMapper struct {
+	SubMapper warehouse.FieldTypeMapper[SpecificDuckDBType]
+}
+
+func (m *duckDBNullableTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificDuckDBType, error) {
+	if !arrowType.Nullable {
+		return SpecificDuckDBType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+
+	newInstance := arrowType.Copy()
+	newInstance.Nullable = false
+	innerType, err := m.SubMapper.ArrowToWarehouse(newInstance)
+	if err != nil {
+		return SpecificDuckDBType{}, err
+	}
+
+	return SpecificDuckDBType{
+		TypeAsString: innerType.TypeAsString,
+		Nullable:     true,
+		FormatFunc: func(i any, metadata arrow.Metadata) (any, error) {
+			if i == nil {
+				return nil, nil
+			}
+			return innerType.Format(i, metadata)
+		},
+	}, nil
+}
+
+func (m *duckDBNullableTypeMapper) WarehouseToArrow(warehouseType SpecificDuckDBType) (warehouse.ArrowType, error) {
+	if !warehouseType.Nullable {
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+	}
+
+	innerType := warehouseType
+	innerType.Nullable = false
+	innerArrowType, err := m.SubMapper.WarehouseToArrow(innerType)
+	if err != nil {
+		return warehouse.ArrowType{}, err
+	}
+
+	innerArrowType.Nullable = true
+	return innerArrowType, nil
+}
+
+// === TYPE INSTANCES ===
+
+func formatString(i any, _ arrow.Metadata) (any, error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected string, got %T", i)
+	}
+	return v, nil
+}
+
+func formatBool(i any, _ arrow.Metadata) (any, error) {
+	v, ok := i.(bool)
+	if !ok {
+		return nil, fmt.Errorf("expected bool, got %T", i)
+	}
+	return v, nil
+}
+
+func formatInt64(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case int64:
+		return v, nil
+	case int32:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	default:
+		return nil, fmt.Errorf("expected int64-compatible type, got %T", i)
+	}
+}
+
+func formatInt32(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case int32:
+		return v, nil
+	case int64:
+		if v > int64(1<<31-1) || v < int64(-1<<31) {
+			return nil, fmt.Errorf("int64 value %d overflows int32 range", v)
+		}
+		return int32(v), nil
+	case int:
+		if v > int(1<<31-1) || v < int(-1<<31) {
+			return nil, fmt.Errorf("int value %d overflows int32 range", v)
+		}
+		return int32(v), nil
+	default:
+		return nil, fmt.Errorf("expected int32-compatible type, got %T", i)
+	}
+}
+
+func formatFloat64(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	default:
+		return nil, fmt.Errorf("expected float64-compatible type, got %T", i)
+	}
+}
+
+func formatFloat32(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case float32:
+		return v, nil
+	case float64:
+		if v > float64(3.4028235e+38) || v < float64(-3.4028235e+38) {
+			return nil, fmt.Errorf("float64 value %g overflows float32 range", v)
+		}
+		return float32(v), nil
+	default:
+		return nil, fmt.Errorf("expected float32-compatible type, got %T", i)
+	}
+}
+
+// formatTimestamp truncates timestamps to seconds, the precision of the Arrow type.
+func formatTimestamp(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case int32:
+		return time.Unix(int64(v), 0).UTC(), nil
+	case int64:
+		return time.Unix(v, 0).UTC(), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ISO string format: %w", err)
+		}
+		return t.UTC().Truncate(time.Second), nil
+	case time.Time:
+		return v.UTC().Truncate(time.Second), nil
+	default:
+		return nil, fmt.Errorf("expected int32, int64, ISO string or time.Time, got %T", i)
+	}
+}
+
+func formatDate(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case time.Time:
+		return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC), nil
+	case string:
+		t, err := time.Parse("2006-01-02", v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("expected time.Time or date string, got %T", i)
+	}
+}
+
+var duckDBString = SpecificDuckDBType{TypeAsString: "VARCHAR", DefaultSQLExpression: "''", FormatFunc: formatString}
+
+var duckDBInt64 = SpecificDuckDBType{TypeAsString: "BIGINT", DefaultSQLExpression: "0", FormatFunc: formatInt64}
+
+var duckDBInt32 = SpecificDuckDBType{TypeAsString: "INTEGER", DefaultSQLExpression: "0", FormatFunc: formatInt32}
+
+var duckDBFloat64 = SpecificDuckDBType{TypeAsString: "DOUBLE", DefaultSQLExpression: "0", FormatFunc: formatFloat64}
+
+var duckDBFloat32 = SpecificDuckDBType{TypeAsString: "FLOAT", DefaultSQLExpression: "0", FormatFunc: formatFloat32}
+
+var duckDBBool = SpecificDuckDBType{TypeAsString: "BOOLEAN", DefaultSQLExpression: "false", FormatFunc: formatBool}
+
+var duckDBTimestamp = SpecificDuckDBType{
+	TypeAsString:         "TIMESTAMP",
+	DefaultSQLExpression: "'1970-01-01 00:00:00'",
+	FormatFunc:           formatTimestamp,
+}
+
+var duckDBDate = SpecificDuckDBType{TypeAsString: "DATE", DefaultSQLExpression: "'1970-01-01'", FormatFunc: formatDate}
+
+// NewFieldTypeMapper creates a mapper that supports DuckDB types
+func NewFieldTypeMapper() warehouse.FieldTypeMapper[SpecificDuckDBType] {
+	baseMappers := []warehouse.FieldTypeMapper[SpecificDuckDBType]{
+		&duckDBPrimitiveTypeMapper{arrowType: arrow.BinaryTypes.String, duckType: duckDBString},
+		&duckDBPrimitiveTypeMapper{arrowType: arrow.PrimitiveTypes.Int64, duckType: duckDBInt64},
+		&duckDBPrimitiveTypeMapper{arrowType: arrow.PrimitiveTypes.Int32, duckType: duckDBInt32},
+		&duckDBPrimitiveTypeMapper{arrowType: arrow.PrimitiveTypes.Float64, duckType: duckDBFloat64},
+		&duckDBPrimitiveTypeMapper{arrowType: arrow.PrimitiveTypes.Float32, duckType: duckDBFloat32},
+		&duckDBPrimitiveTypeMapper{arrowType: arrow.FixedWidthTypes.Boolean, duckType: duckDBBool},
+		&duckDBPrimitiveTypeMapper{
+			arrowType: arrow.FixedWidthTypes.Timestamp_s,
+			duckType:  duckDBTimestamp,
+			aliases:   []string{"TIMESTAMP_S"},
+		},
+		&duckDBPrimitiveTypeMapper{arrowType: arrow.FixedWidthTypes.Date32, duckType: duckDBDate},
+	}
+
+	// deferred mapper for circular dependency resolution
+	var comprehensiveMapper warehouse.FieldTypeMapper[SpecificDuckDBType]
+	deferredMapper := warehouse.NewDeferredMapper(func() warehouse.FieldTypeMapper[SpecificDuckDBType] {
+		return comprehensiveMapper
+	})
+
+	allMappers := make([]warehouse.FieldTypeMapper[SpecificDuckDBType], 0, 3+len(baseMappers))
+	allMappers = append(allMappers,
+		&duckDBNullableTypeMapper{SubMapper: deferredMapper},
+		&duckDBListTypeMapper{SubMapper: deferredMapper},
+		&duckDBStructTypeMapper{SubMapper: deferredMapper},
+	)
+	allMappers = append(allMappers, baseMappers...)
+
+	comprehensiveMapper = warehouse.NewTypeMapper(allMappers)
+
+	return comprehensiveMapper
+}
@@ -0,0 +1,165 @@
+//go:build cgo && duckdb_arrow
+
+package duckdb
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldTypeMapper_RoundTrip(t *testing.T) {
+	testCases := []struct {
+		name           string
+		arrowType      warehouse.ArrowType
+		expectedDuckDB string
+	}{
+		{
+			name:           "string",
+			arrowType:      warehouse.ArrowType{ArrowDataType: arrow.BinaryTypes.String},
+			expectedDuckDB: "VARCHAR",
+		},
+		{
+			name:           "nullable int64",
+			arrowType:      warehouse.ArrowType{ArrowDataType: arrow.PrimitiveTypes.Int64, Nullable: true},
+			expectedDuckDB: "BIGINT",
+		},
+		{
+			name:           "timestamp",
+			arrowType:      warehouse.ArrowType{ArrowDataType: arrow.FixedWidthTypes.Timestamp_s, Nullable: true},
+			expectedDuckDB: "TIMESTAMP",
+		},
+		{
+			name:           "date",
+			arrowType:      warehouse.ArrowType{ArrowDataType: arrow.FixedWidthTypes.Date32},
+			expectedDuckDB: "DATE",
+		},
+		{
+			name:           "list of float32",
+			arrowType:      warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.PrimitiveTypes.Float32), Nullable: true},
+			expectedDuckDB: "FLOAT[]",
+		},
+		{
+			name: "list of struct",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.StructOf(
+				arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "value \"number\"", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+				arrow.Field{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+			)), Nullable: true},
+			expectedDuckDB: `STRUCT("name" VARCHAR, "value ""number""" DOUBLE, "day" DATE)[]`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			mapper := NewFieldTypeMapper()
+
+			// when
+			duckType, err := mapper.ArrowToWarehouse(tc.arrowType)
+			require.NoError(t, err)
+			arrowType, err := mapper.WarehouseToArrow(duckType)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedDuckDB, duckType.TypeAsString)
+			assert.Equal(t, tc.arrowType.Nullable, duckType.Nullable)
+			assert.True(t, arrow.TypeEqual(tc.arrowType.ArrowDataType, arrowType.ArrowDataType),
+				"expected %s, got %s", tc.arrowType.ArrowDataType, arrowType.ArrowDataType)
+			assert.Equal(t, tc.arrowType.Nullable, arrowType.Nullable)
+		})
+	}
+}
+
+func TestFieldTypeMapper_RejectsNonNullableStructFields(t *testing.T) {
+	// given
+	mapper := NewFieldTypeMapper()
+
+	// when
+	_, err := mapper.ArrowToWarehouse(warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.StructOf(
+		arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: false},
+	))})
+
+	// then
+	var unsupportedErr *warehouse.ErrUnsupportedMapping
+	assert.ErrorAs(t, err, &unsupportedErr)
+}
+
+func TestFieldTypeMapper_Format(t *testing.T) {
+	testCases := []struct {
+		name        string
+		arrowType   warehouse.ArrowType
+		input       any
+		expected    any
+		expectError bool
+	}{
+		{
+			name:      "timestamp is truncated to seconds in UTC",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.FixedWidthTypes.Timestamp_s},
+			input:     time.Date(2026, 3, 7, 11, 29, 59, 500, time.FixedZone("CET", 3600)),
+			expected:  time.Date(2026, 3, 7, 10, 29, 59, 0, time.UTC),
+		},
+		{
+			name:      "date from string",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.FixedWidthTypes.Date32},
+			input:     "2026-03-07",
+			expected:  time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC),
+		},
+		{
+			name:      "int32 from int64",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.PrimitiveTypes.Int32},
+			input:     int64(42),
+			expected:  int32(42),
+		},
+		{
+			name:        "int32 overflow",
+			arrowType:   warehouse.ArrowType{ArrowDataType: arrow.PrimitiveTypes.Int32},
+			input:       int64(1 << 40),
+			expectError: true,
+		},
+		{
+			name:        "string from int",
+			arrowType:   warehouse.ArrowType{ArrowDataType: arrow.BinaryTypes.String},
+			input:       42,
+			expectError: true,
+		},
+		{
+			name: "struct with missing fields",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.StructOf(
+				arrow.Field{Name: "key", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "value", Type: arrow.BinaryTypes.String, Nullable: true},
+			))},
+			input:    []any{map[string]any{"key": "campaign"}},
+			expected: []any{map[string]any{"key": "campaign", "value": nil}},
+		},
+		{
+			name:      "null list of non-nullable column",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.BinaryTypes.String)},
+			input:     nil,
+			expected:  []any{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			duckType, err := NewFieldTypeMapper().ArrowToWarehouse(tc.arrowType)
+			require.NoError(t, err)
+
+			// when
+			result, err := duckType.Format(tc.input, arrow.Metadata{})
+
+			// then
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
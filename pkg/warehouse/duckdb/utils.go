@@ -0,0 +1,30 @@
+//go:build cgo && duckdb_arrow
+
+package duckdb
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/duckdb/duckdb-go/v2"
+)
+
+// quoteIdentifier wraps a DuckDB identifier in double quotes, escaping any embedded
+// double quotes by doubling them.
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// quoteFullTableName quotes a schema.table reference for use in DDL/DML.
+func quoteFullTableName(schema, table string) string {
+	return fmt.Sprintf("%s.%s", quoteIdentifier(schema), quoteIdentifier(table))
+}
+
+func isAlreadyExistsErr(err error) bool {
+	var duckErr *duckdb.Error
+	if errors.As(err, &duckErr) {
+		return duckErr.Type == duckdb.ErrorTypeCatalog && strings.Contains(duckErr.Msg, "already exists")
+	}
+	return false
+}
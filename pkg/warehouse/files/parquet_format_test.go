@@ -151,6 +151,32 @@ func TestParquetFormat_WriteRows_NullableFieldRoundTrip(t *testing.T) {
 	assert.Nil(t, actualRows[1]["optional_name"])
 }
 
+func TestParquetFormat_WriteRows_MapRoundTrip(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "params", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.BinaryTypes.String), Nullable: true},
+		{Name: "numbers", Type: arrow.MapOf(arrow.BinaryTypes.String, arrow.PrimitiveTypes.Float64), Nullable: true},
+	}, nil)
+
+	rows := []map[string]any{
+		{"params": map[string]any{"currency": "USD", "zip": "00123"}, "numbers": map[string]any{"score": float64(42)}},
+		{"params": nil, "numbers": nil},
+	}
+
+	var buf bytes.Buffer
+	writer, err := NewParquetFormat().NewWriter(&buf, schema)
+	require.NoError(t, err)
+	require.NoError(t, writer.WriteRows(rows))
+	require.NoError(t, writer.Close())
+
+	actualRows := readParquetRows(t, buf.Bytes())
+	require.Len(t, actualRows, 2)
+	// map values are read back as raw bytes of the UTF-8 strings
+	assert.Equal(t, map[string]any{"currency": []byte("USD"), "zip": []byte("00123")}, actualRows[0]["params"])
+	assert.Equal(t, map[string]any{"score": float64(42)}, actualRows[0]["numbers"])
+	assert.Empty(t, actualRows[1]["params"])
+	assert.Empty(t, actualRows[1]["numbers"])
+}
+
 func TestParquetFormatWriter_WriteRows_EmptyRowsAndMultipleBatches(t *testing.T) {
 	t.Run("empty row set", func(t *testing.T) {
 		schema := arrow.NewSchema([]arrow.Field{{Name: "id", Type: arrow.PrimitiveTypes.Int64}}, nil)
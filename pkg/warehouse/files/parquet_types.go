@@ -334,6 +334,33 @@ func (m *parquetArrayTypeMapper) WarehouseToArrow(SpecificParquetType) (warehous
 	return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr("parquet node", parquetMapperName)
 }
 
+type parquetMapTypeMapper struct{}
+
+func (m *parquetMapTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificParquetType, error) {
+	switch {
+	case warehouse.IsStringMap(arrowType.ArrowDataType):
+		return SpecificParquetType{
+			Node: parquet.Map(parquet.String(), parquet.String()),
+			FormatFunc: func(i any, _ arrow.Metadata) (any, error) {
+				return warehouse.ToStringMap(i)
+			},
+		}, nil
+	case warehouse.IsFloat64Map(arrowType.ArrowDataType):
+		return SpecificParquetType{
+			Node: parquet.Map(parquet.String(), parquet.Leaf(parquet.DoubleType)),
+			FormatFunc: func(i any, _ arrow.Metadata) (any, error) {
+				return warehouse.ToFloat64Map(i)
+			},
+		}, nil
+	default:
+		return SpecificParquetType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, parquetMapperName)
+	}
+}
+
+func (m *parquetMapTypeMapper) WarehouseToArrow(SpecificParquetType) (warehouse.ArrowType, error) {
+	return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr("parquet node", parquetMapperName)
+}
+
 type parquetNestedTypeMapper struct {
 	SubMapper warehouse.FieldTypeMapper[SpecificParquetType]
 }
@@ -411,6 +438,7 @@ func NewParquetFieldTypeMapper() warehouse.FieldTypeMapper[SpecificParquetType]
 		&parquetBoolTypeMapper{},
 		&parquetTimestampTypeMapper{},
 		&parquetDate32TypeMapper{},
+		&parquetMapTypeMapper{},
 	}
 
 	var comprehensiveMapper warehouse.FieldTypeMapper[SpecificParquetType]
@@ -0,0 +1,72 @@
+package postgres
+
+import (
+	"fmt"
+	"time"
+)
+
+// PartitionInterval represents the range of the partitions of PostgreSQL tables.
+type PartitionInterval string
+
+const (
+	// PartitionIntervalDay creates a partition per day.
+	PartitionIntervalDay PartitionInterval = "DAY"
+	// PartitionIntervalMonth creates a partition per month (default).
+	PartitionIntervalMonth PartitionInterval = "MONTH"
+	// PartitionIntervalYear creates a partition per year.
+	PartitionIntervalYear PartitionInterval = "YEAR"
+)
+
+// PartitioningConfig represents the configuration of declarative partitioning of PostgreSQL tables.
+type PartitioningConfig struct {
+	// Field is the name of the field to partition by range of. Must be a top-level TIMESTAMP or DATE field.
+	// Tables without the field aren't partitioned.
+	Field string
+	// Interval is the range of a single partition. Partitions are created before the rows
+	// which belong to them are written.
+	Interval PartitionInterval
+}
+
+// bounds returns the range of the partition containing t and the suffix of its name.
+func (i PartitionInterval) bounds(t time.Time) (from, to time.Time, suffix string) {
+	t = t.UTC()
+	switch i {
+	case PartitionIntervalDay:
+		from = time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(0, 0, 1), from.Format("p20060102")
+	case PartitionIntervalYear:
+		from = time.Date(t.Year(), 1, 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(1, 0, 0), from.Format("p2006")
+	default:
+		from = time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+		return from, from.AddDate(0, 1, 0), from.Format("p200601")
+	}
+}
+
+// Option configures a PostgreSQL table driver.
+type Option func(*postgresDriver)
+
+// WithQueryTimeout sets the timeout of DDL and metadata queries.
+func WithQueryTimeout(timeout time.Duration) Option {
+	return func(d *postgresDriver) {
+		d.queryTimeout = timeout
+	}
+}
+
+// WithPartitionBy enables range partitioning of the tables having cfg.Field.
+// Panics if cfg.Field is empty.
+func WithPartitionBy(cfg PartitioningConfig) Option {
+	if cfg.Field == "" {
+		panic("field is required for partitioning")
+	}
+	switch cfg.Interval {
+	case PartitionIntervalDay, PartitionIntervalMonth, PartitionIntervalYear:
+	case "":
+		cfg.Interval = PartitionIntervalMonth
+	default:
+		panic(fmt.Sprintf("unsupported partition interval %q", cfg.Interval))
+	}
+	return func(d *postgresDriver) {
+		d.partitioning = &cfg
+	}
+}
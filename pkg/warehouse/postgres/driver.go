@@ -0,0 +1,381 @@
+// Package postgres provides implementation of PostgreSQL data warehouse
+package postgres
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/util"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/sirupsen/logrus"
+)
+
+type postgresDriver struct {
+	pool            *pgxpool.Pool
+	schema          string
+	queryMapper     *postgresQueryMapper
+	fieldTypeMapper warehouse.FieldTypeMapper[SpecificPostgresType]
+	queryTimeout    time.Duration
+	typeComparer    *warehouse.TypeComparer
+	partitioning    *PartitioningConfig
+	// partitionKeys caches the column each table is partitioned by, empty if it isn't
+	partitionKeys *util.TTLCache[string]
+	// partitions caches the partitions known to exist
+	partitions *util.TTLCache[bool]
+}
+
+// NewPostgresTableDriver creates a new PostgreSQL table driver creating tables in the
+// given schema of the database at connString, a URL or a key/value connection string.
+func NewPostgresTableDriver(
+	ctx context.Context, connString, schema string, opts ...Option,
+) (warehouse.Driver, error) {
+	pool, err := pgxpool.New(ctx, connString)
+	if err != nil {
+		return nil, fmt.Errorf("opening PostgreSQL connection pool: %w", err)
+	}
+
+	d := &postgresDriver{
+		pool:            pool,
+		schema:          schema,
+		queryMapper:     newPostgresQueryMapper(),
+		fieldTypeMapper: NewFieldTypeMapper(),
+		queryTimeout:    30 * time.Second,
+		typeComparer:    warehouse.NewTypeComparer(),
+		partitionKeys:   util.NewTTLCache[string](1 * time.Minute),
+		partitions:      util.NewTTLCache[bool](1 * time.Hour),
+	}
+	for _, opt := range opts {
+		opt(d)
+	}
+	return d, nil
+}
+
+func (d *postgresDriver) rawTableName(table string) string {
+	return fmt.Sprintf("%s.%s", d.schema, table)
+}
+
+// partitionField returns the field of the schema the table should be partitioned by, nil
+// if partitioning is disabled or the schema doesn't have the field.
+func (d *postgresDriver) partitionField(schema *arrow.Schema) (*arrow.Field, error) {
+	if d.partitioning == nil {
+		return nil, nil
+	}
+	indices := schema.FieldIndices(d.partitioning.Field)
+	if len(indices) == 0 {
+		return nil, nil
+	}
+	field := schema.Field(indices[0])
+	if !arrow.TypeEqual(field.Type, arrow.FixedWidthTypes.Date32) &&
+		!arrow.TypeEqual(field.Type, arrow.FixedWidthTypes.Timestamp_s) {
+		return nil, fmt.Errorf("partition field %s must be a DATE or TIMESTAMP, got %s", field.Name, field.Type)
+	}
+	return &field, nil
+}
+
+// CreateTable implements warehouse.Driver. Tables with the partition field are partitioned
+// by range of it, with a default partition holding the rows where it's NULL.
+func (d *postgresDriver) CreateTable(table string, schema *arrow.Schema) error {
+	partitionField, err := d.partitionField(schema)
+	if err != nil {
+		return err
+	}
+
+	fullTableName := quoteFullTableName(d.schema, table)
+	mapper := d.queryMapper
+	if partitionField != nil {
+		mapper = d.queryMapper.withPartitionBy(partitionField.Name)
+	}
+	query, err := warehouse.CreateTableQuery(mapper, fullTableName, schema)
+	if err != nil {
+		return err
+	}
+	queries := []string{query}
+	if partitionField != nil {
+		queries = append(queries, fmt.Sprintf("CREATE TABLE %s PARTITION OF %s DEFAULT",
+			quoteFullTableName(d.schema, table+"_default"), fullTableName))
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.queryTimeout)
+	defer cancel()
+	err = pgx.BeginFunc(ctx, d.pool, func(tx pgx.Tx) error {
+		for _, query := range queries {
+			if _, err := tx.Exec(ctx, query); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		if isAlreadyExistsErr(err) {
+			return warehouse.NewTableAlreadyExistsError(d.rawTableName(table))
+		}
+		return err
+	}
+	return nil
+}
+
+// AddColumn implements warehouse.Driver. Non-nullable columns are added with a default
+// for the existing rows.
+func (d *postgresDriver) AddColumn(table string, field *arrow.Field) error {
+	fieldType, err := d.queryMapper.fieldType(field)
+	if err != nil {
+		return fmt.Errorf("error converting field type: %w", err)
+	}
+
+	query := fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s",
+		quoteFullTableName(d.schema, table), quoteIdentifier(field.Name), fieldType.TypeAsString)
+	if !fieldType.Nullable {
+		query += fmt.Sprintf(" NOT NULL DEFAULT %s", fieldType.DefaultSQLExpression)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), d.queryTimeout)
+	defer cancel()
+	if _, err := d.pool.Exec(ctx, query); err != nil {
+		if isColumnAlreadyExistsErr(err) {
+			return warehouse.NewColumnAlreadyExistsError(d.rawTableName(table), field.Name)
+		}
+		return fmt.Errorf("error adding column: %w", err)
+	}
+	return nil
+}
+
+// columns retrieves all columns from the specified table as Arrow fields
+func (d *postgresDriver) columns(ctx context.Context, table string) ([]*arrow.Field, error) {
+	ctx, cancel := context.WithTimeout(ctx, d.queryTimeout)
+	defer cancel()
+
+	query := `
+		SELECT column_name, data_type, is_nullable
+		FROM information_schema.columns
+		WHERE table_schema = $1 AND table_name = $2
+		ORDER BY ordinal_position
+	`
+
+	rows, err := d.pool.Query(ctx, query, d.schema, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var fields []*arrow.Field
+	for rows.Next() {
+		var columnName, columnType, isNullable string
+		if err := rows.Scan(&columnName, &columnType, &isNullable); err != nil {
+			return nil, err
+		}
+
+		arrowType, err := d.fieldTypeMapper.WarehouseToArrow(SpecificPostgresType{
+			TypeAsString: columnType,
+			Nullable:     isNullable == "YES",
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		fields = append(fields, &arrow.Field{
+			Name:     columnName,
+			Type:     arrowType.ArrowDataType,
+			Nullable: arrowType.Nullable,
+			Metadata: arrowType.Metadata,
+		})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// If no rows were returned, the table doesn't exist
+	if len(fields) == 0 {
+		return nil, warehouse.NewTableNotFoundError(d.rawTableName(table))
+	}
+	return fields, nil
+}
+
+func (d *postgresDriver) MissingColumns(table string, schema *arrow.Schema) ([]*arrow.Field, error) {
+	fields, err := d.columns(context.Background(), table)
+	if err != nil {
+		return nil, err
+	}
+
+	existingFields := make(map[string]*arrow.Field, len(fields))
+	for _, field := range fields {
+		existingFields[field.Name] = field
+	}
+
+	return warehouse.FindMissingColumns(d.rawTableName(table), existingFields, schema, d)
+}
+
+// Write implements warehouse.Driver. Rows are formatted up front, so a row which doesn't
+// match the schema fails the write before anything is sent, then copied in a single COPY
+// statement. Partitions of partitioned tables are created before copying the rows.
+func (d *postgresDriver) Write(ctx context.Context, table string, schema *arrow.Schema, rows []map[string]any) error {
+	if len(rows) == 0 {
+		return nil
+	}
+
+	fields := schema.Fields()
+	columns := make([]string, len(fields))
+	columnTypes := make([]SpecificPostgresType, len(fields))
+	for i := range fields {
+		columnType, err := d.queryMapper.fieldType(&fields[i])
+		if err != nil {
+			return fmt.Errorf("error mapping type for column %s: %w", fields[i].Name, err)
+		}
+		columns[i] = fields[i].Name
+		columnTypes[i] = columnType
+	}
+
+	values := make([][]any, len(rows))
+	for rowIdx, row := range rows {
+		values[rowIdx] = make([]any, len(columns))
+		for i, col := range columns {
+			value, exists := row[col]
+			if !exists {
+				return fmt.Errorf("missing value for column %s", col)
+			}
+			if value == nil && !columnTypes[i].Nullable {
+				return fmt.Errorf("null value for non-nullable column %s", col)
+			}
+			formattedValue, err := columnTypes[i].Format(value, fields[i].Metadata)
+			if err != nil {
+				return fmt.Errorf("error formatting value for column %s: %w", col, err)
+			}
+			values[rowIdx][i] = formattedValue
+		}
+	}
+
+	if err := d.ensurePartitions(ctx, table, schema, values); err != nil {
+		return err
+	}
+
+	if _, err := d.pool.CopyFrom(ctx, pgx.Identifier{d.schema, table}, columns, pgx.CopyFromRows(values)); err != nil {
+		return fmt.Errorf("error copying rows: %w", err)
+	}
+	return nil
+}
+
+// partitionKey returns the column the table is partitioned by, empty if it isn't partitioned.
+func (d *postgresDriver) partitionKey(ctx context.Context, table string) (string, error) {
+	if key, ok := d.partitionKeys.Get(table); ok {
+		return key, nil
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, d.queryTimeout)
+	defer cancel()
+	query := `
+		SELECT a.attname
+		FROM pg_partitioned_table p
+		JOIN pg_attribute a ON a.attrelid = p.partrelid AND a.attnum = p.partattrs[0]
+		WHERE p.partrelid = to_regclass($1)
+	`
+	var key string
+	err := d.pool.QueryRow(ctx, query, quoteFullTableName(d.schema, table)).Scan(&key)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return "", fmt.Errorf("error getting partition key: %w", err)
+	}
+	d.partitionKeys.Set(table, key)
+	return key, nil
+}
+
+func (d *postgresDriver) partitionInterval() PartitionInterval {
+	if d.partitioning == nil {
+		return PartitionIntervalMonth
+	}
+	return d.partitioning.Interval
+}
+
+// ensurePartitions creates the missing partitions of the rows written to a partitioned table.
+func (d *postgresDriver) ensurePartitions(
+	ctx context.Context, table string, schema *arrow.Schema, values [][]any,
+) error {
+	key, err := d.partitionKey(ctx, table)
+	if err != nil || key == "" {
+		return err
+	}
+	indices := schema.FieldIndices(key)
+	if len(indices) == 0 {
+		return fmt.Errorf("missing value for partition column %s", key)
+	}
+	keyIdx := indices[0]
+	boundFormat := "2006-01-02 15:04:05+00" // bounds are in UTC
+	if arrow.TypeEqual(schema.Field(keyIdx).Type, arrow.FixedWidthTypes.Date32) {
+		boundFormat = time.DateOnly
+	}
+
+	interval := d.partitionInterval()
+	missing := make(map[string]string)
+	for _, row := range values {
+		// NULLs are kept in the default partition
+		t, ok := row[keyIdx].(time.Time)
+		if !ok {
+			continue
+		}
+		from, to, suffix := interval.bounds(t)
+		partition := table + "_" + suffix
+		if _, exists := missing[partition]; exists {
+			continue
+		}
+		if _, exists := d.partitions.Get(d.rawTableName(partition)); exists {
+			continue
+		}
+		missing[partition] = fmt.Sprintf("CREATE TABLE IF NOT EXISTS %s PARTITION OF %s FOR VALUES FROM ('%s') TO ('%s')",
+			quoteFullTableName(d.schema, partition), quoteFullTableName(d.schema, table),
+			from.Format(boundFormat), to.Format(boundFormat))
+	}
+
+	partitions := make([]string, 0, len(missing))
+	for partition := range missing {
+		partitions = append(partitions, partition)
+	}
+	sort.Strings(partitions)
+	for _, partition := range partitions {
+		if err := d.createPartition(ctx, missing[partition]); err != nil {
+			return fmt.Errorf("error creating partition %s: %w", partition, err)
+		}
+		d.partitions.Set(d.rawTableName(partition), true)
+		logrus.Debugf("created partition %s of %s", partition, d.rawTableName(table))
+	}
+	return nil
+}
+
+func (d *postgresDriver) createPartition(ctx context.Context, query string) error {
+	ctx, cancel := context.WithTimeout(ctx, d.queryTimeout)
+	defer cancel()
+	// Another writer may have created the partition in the meantime
+	if _, err := d.pool.Exec(ctx, query); err != nil && !isAlreadyExistsErr(err) {
+		return err
+	}
+	return nil
+}
+
+// Close implements warehouse.Driver.
+func (d *postgresDriver) Close() error {
+	d.pool.Close()
+	return nil
+}
+
+// AreFieldsCompatible implements warehouse.FieldCompatibilityChecker. JSONB columns store
+// lists of any type, so their element types aren't compared.
+func (d *postgresDriver) AreFieldsCompatible(existing, input *arrow.Field) (bool, error) {
+	if existing.Nullable != input.Nullable {
+		return false, fmt.Errorf("nullability differs - existing: %t, input: %t", existing.Nullable, input.Nullable)
+	}
+
+	if isJSONB, _ := warehouse.GetArrowMetadataValue(existing.Metadata, metadataJSONB); isJSONB == "true" {
+		if _, isList := input.Type.(*arrow.ListType); !isList {
+			return false, fmt.Errorf("JSONB column %s can't store %s", existing.Name, input.Type)
+		}
+		return true, nil
+	}
+
+	result := d.typeComparer.Compare(existing.Type, input.Type, existing.Name)
+	if !result.Equal {
+		return false, errors.New(result.ErrorMessage)
+	}
+	return true, nil
+}
@@ -0,0 +1,210 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/d8a-tech/d8a/pkg/warehouse/testutils"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/stretchr/testify/suite"
+	"github.com/testcontainers/testcontainers-go"
+	tcpostgres "github.com/testcontainers/testcontainers-go/modules/postgres"
+)
+
+// PostgresTestSuite is a test suite that sets up a single PostgreSQL container
+// for all tests to share, improving test performance
+type PostgresTestSuite struct {
+	suite.Suite
+	container  *tcpostgres.PostgresContainer
+	connString string
+	pool       *pgxpool.Pool
+	driver     warehouse.Driver
+}
+
+// SetupSuite runs once before all tests in the suite
+func (suite *PostgresTestSuite) SetupSuite() {
+	ctx := context.Background()
+
+	var err error
+	suite.container, err = tcpostgres.Run(ctx,
+		"postgres:16-alpine",
+		tcpostgres.WithDatabase("testdb"),
+		tcpostgres.WithUsername("postgres"),
+		tcpostgres.WithPassword("password"),
+		tcpostgres.BasicWaitStrategies(),
+	)
+	suite.Require().NoError(err, "should start PostgreSQL container successfully")
+
+	suite.connString, err = suite.container.ConnectionString(ctx, "sslmode=disable")
+	suite.Require().NoError(err, "should get connection string")
+
+	suite.pool, err = pgxpool.New(ctx, suite.connString)
+	suite.Require().NoError(err, "should connect to PostgreSQL")
+}
+
+// TearDownSuite runs once after all tests in the suite
+func (suite *PostgresTestSuite) TearDownSuite() {
+	if suite.pool != nil {
+		suite.pool.Close()
+	}
+	if suite.container != nil {
+		if err := testcontainers.TerminateContainer(suite.container); err != nil {
+			log.Printf("failed to terminate container: %s", err)
+		}
+	}
+}
+
+// SetupTest runs before each individual test
+func (suite *PostgresTestSuite) SetupTest() {
+	suite.driver = suite.newDriver()
+}
+
+// TearDownTest runs after each individual test to clean up tables
+func (suite *PostgresTestSuite) TearDownTest() {
+	suite.Require().NoError(suite.driver.Close())
+	_, err := suite.pool.Exec(context.Background(), "DROP SCHEMA public CASCADE; CREATE SCHEMA public")
+	suite.Require().NoError(err, "should clean up the schema")
+}
+
+func (suite *PostgresTestSuite) newDriver(opts ...Option) warehouse.Driver {
+	driver, err := NewPostgresTableDriver(context.Background(), suite.connString, "public", opts...)
+	suite.Require().NoError(err, "should create PostgreSQL driver")
+	return driver
+}
+
+func (suite *PostgresTestSuite) TestCreateTable() {
+	// when & then
+	err := suite.driver.CreateTable("analytics_events", testutils.TestSchema())
+	suite.Assert().NoError(err)
+}
+
+func (suite *PostgresTestSuite) TestMissingColumns() {
+	// when & then
+	testutils.TestMissingColumns(suite.T(), suite.driver, "test_missing_columns_table")
+}
+
+func (suite *PostgresTestSuite) TestMissingColumnsOfJSONBColumns() {
+	// given
+	schema := testutils.TestSchema()
+	suite.Require().NoError(suite.driver.CreateTable("test_jsonb_columns", schema))
+	inputSchema := arrow.NewSchema([]arrow.Field{
+		{Name: "tags", Type: arrow.ListOf(arrow.PrimitiveTypes.Int64), Nullable: true},
+		{Name: "properties", Type: arrow.ListOf(arrow.StructOf(
+			arrow.Field{Name: "key", Type: arrow.BinaryTypes.String, Nullable: true},
+			arrow.Field{Name: "added", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+		)), Nullable: true},
+		{Name: "metrics", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+	}, nil)
+
+	// when
+	_, err := suite.driver.MissingColumns("test_jsonb_columns", inputSchema)
+
+	// then - JSONB columns store lists of any type, but not scalars
+	var multiTypeError *warehouse.ErrMultipleTypeIncompatible
+	suite.Require().ErrorAs(err, &multiTypeError)
+	suite.Require().Len(multiTypeError.Errors, 1)
+	suite.Assert().Equal("metrics", multiTypeError.Errors[0].ColumnName)
+}
+
+func (suite *PostgresTestSuite) TestBasicWrites() {
+	tableName := "test_write"
+
+	// when & then - run the standard warehouse test
+	testutils.TestBasicWrites(suite.T(), suite.driver, tableName)
+
+	suite.Run("verify_data_inserted", func() {
+		// when
+		var count int64
+		var firstKey string
+		err := suite.pool.QueryRow(context.Background(), fmt.Sprintf(
+			"SELECT count(*), min(properties->0->>'key') FROM public.%s", tableName,
+		)).Scan(&count, &firstKey)
+
+		// then
+		suite.Require().NoError(err, "should execute count query successfully")
+		suite.Assert().Greater(count, int64(0), "should have inserted at least one row")
+		suite.Assert().Equal("campaign", firstKey, "nested values should be queryable as JSONB")
+	})
+}
+
+func (suite *PostgresTestSuite) TestComplexWrites() {
+	// when & then - run the standard warehouse test
+	testutils.TestComplexWrites(suite.T(), suite.driver, "test_write")
+}
+
+func (suite *PostgresTestSuite) TestAddColumn() {
+	// when & then - run the standard warehouse test
+	testutils.TestAddColumn(suite.T(), suite.driver, "test_add_column")
+}
+
+func (suite *PostgresTestSuite) TestCreateTableAlreadyExists() {
+	// when & then - run the standard warehouse test
+	testutils.TestCreateTable(suite.T(), suite.driver, "test_create_table_already_exists")
+}
+
+func (suite *PostgresTestSuite) TestPartitionedWrites() {
+	// given
+	driver := suite.newDriver(WithPartitionBy(PartitioningConfig{Field: "date_utc", Interval: PartitionIntervalDay}))
+	defer func() { suite.Require().NoError(driver.Close()) }()
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "name", Type: arrow.BinaryTypes.String},
+		{Name: "date_utc", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+	}, nil)
+	suite.Require().NoError(driver.CreateTable("partitioned_events", schema))
+	day := time.Date(2026, 3, 7, 0, 0, 0, 0, time.UTC)
+
+	// when - written twice, the second write reuses the existing partitions
+	for range 2 {
+		err := driver.Write(context.Background(), "partitioned_events", schema, []map[string]any{
+			{"name": "page_view", "date_utc": day},
+			{"name": "purchase", "date_utc": day.AddDate(0, 0, 1)},
+			{"name": "scroll", "date_utc": nil},
+		})
+		suite.Require().NoError(err)
+	}
+
+	// then
+	rows, err := suite.pool.Query(context.Background(), `
+		SELECT tableoid::regclass::text, count(*)
+		FROM public.partitioned_events
+		GROUP BY 1 ORDER BY 1
+	`)
+	suite.Require().NoError(err)
+	counts := make(map[string]int64)
+	for rows.Next() {
+		var partition string
+		var count int64
+		suite.Require().NoError(rows.Scan(&partition, &count))
+		counts[partition] = count
+	}
+	suite.Require().NoError(rows.Err())
+	suite.Assert().Equal(map[string]int64{
+		"partitioned_events_default":   2,
+		"partitioned_events_p20260307": 2,
+		"partitioned_events_p20260308": 2,
+	}, counts)
+}
+
+func (suite *PostgresTestSuite) TestTablesWithoutPartitionFieldArentPartitioned() {
+	// given
+	driver := suite.newDriver(WithPartitionBy(PartitioningConfig{Field: "date_utc"}))
+	defer func() { suite.Require().NoError(driver.Close()) }()
+	schema := arrow.NewSchema([]arrow.Field{{Name: "name", Type: arrow.BinaryTypes.String}}, nil)
+	suite.Require().NoError(driver.CreateTable("sessions", schema))
+
+	// when
+	err := driver.Write(context.Background(), "sessions", schema, []map[string]any{{"name": "session"}})
+
+	// then
+	suite.Require().NoError(err)
+}
+
+// TestPostgresTestSuite runs the entire test suite
+func TestPostgresTestSuite(t *testing.T) {
+	suite.Run(t, new(PostgresTestSuite))
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"fmt"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+)
+
+type postgresQueryMapper struct {
+	fieldTypeMapper warehouse.FieldTypeMapper[SpecificPostgresType]
+	partitionBy     string
+}
+
+// NewPostgresQueryMapper creates a new PostgreSQL query mapper.
+func NewPostgresQueryMapper() warehouse.QueryMapper {
+	return newPostgresQueryMapper()
+}
+
+func newPostgresQueryMapper() *postgresQueryMapper {
+	return &postgresQueryMapper{fieldTypeMapper: NewFieldTypeMapper()}
+}
+
+// withPartitionBy returns a copy of the mapper creating tables partitioned by range of the column.
+func (q *postgresQueryMapper) withPartitionBy(column string) *postgresQueryMapper {
+	return &postgresQueryMapper{fieldTypeMapper: q.fieldTypeMapper, partitionBy: column}
+}
+
+func (q *postgresQueryMapper) TablePredicate(table string) string {
+	return fmt.Sprintf("TABLE %s", table)
+}
+
+// ColumnName implements warehouse.QueryMapper.
+func (q *postgresQueryMapper) ColumnName(name string) string {
+	return quoteIdentifier(name)
+}
+
+func (q *postgresQueryMapper) TableSuffix(_ string) string {
+	if q.partitionBy == "" {
+		return ""
+	}
+	return fmt.Sprintf("PARTITION BY RANGE (%s)", quoteIdentifier(q.partitionBy))
+}
+
+func (q *postgresQueryMapper) Field(field *arrow.Field) (string, error) {
+	fieldType, err := q.fieldType(field)
+	if err != nil {
+		return "", err
+	}
+	if fieldType.Nullable {
+		return fieldType.TypeAsString, nil
+	}
+	return fieldType.TypeAsString + " NOT NULL", nil
+}
+
+func (q *postgresQueryMapper) fieldType(field *arrow.Field) (SpecificPostgresType, error) {
+	return q.fieldTypeMapper.ArrowToWarehouse(warehouse.ArrowType{
+		ArrowDataType: field.Type,
+		Nullable:      field.Nullable,
+		Metadata:      field.Metadata,
+	})
+}
@@ -0,0 +1,65 @@
+package postgres
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/d8a-tech/d8a/pkg/warehouse/testutils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCreateQuery(t *testing.T) {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.BinaryTypes.String},
+		{Name: "date_utc", Type: arrow.FixedWidthTypes.Date32},
+		{Name: "params", Type: arrow.ListOf(arrow.StructOf(
+			arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+		)), Nullable: true},
+	}, nil)
+
+	testCases := []struct {
+		name     string
+		mapper   warehouse.QueryMapper
+		expected string
+	}{
+		{
+			name:   "not partitioned",
+			mapper: NewPostgresQueryMapper(),
+			expected: `CREATE TABLE "public"."events" (
+  "id" TEXT NOT NULL,
+  "date_utc" DATE NOT NULL,
+  "params" JSONB
+)`,
+		},
+		{
+			name:   "partitioned",
+			mapper: newPostgresQueryMapper().withPartitionBy("date_utc"),
+			expected: `CREATE TABLE "public"."events" (
+  "id" TEXT NOT NULL,
+  "date_utc" DATE NOT NULL,
+  "params" JSONB
+) PARTITION BY RANGE ("date_utc")`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			query, err := warehouse.CreateTableQuery(tc.mapper, `"public"."events"`, schema)
+
+			// then
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, query)
+		})
+	}
+}
+
+func TestQueryMapperArrowTypes(t *testing.T) {
+	testutils.TestSupportedArrowTypes(t, NewPostgresQueryMapper())
+}
+
+func TestQueryMapperTypeErrors(t *testing.T) {
+	testutils.TestQueryMapperTypeErrors(t, NewPostgresQueryMapper())
+}
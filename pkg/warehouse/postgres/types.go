@@ -0,0 +1,454 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+)
+
+// MapperName is the name of the PostgreSQL type mapper
+const MapperName = "postgres"
+
+const (
+	metadataParentType = "Postgres.ParentType"
+	// metadataJSONB marks Arrow fields read from JSONB columns. JSONB doesn't tell the
+	// Arrow type of the values it was written from.
+	metadataJSONB = "Postgres.JSONB"
+)
+
+const jsonbTypeName = "JSONB"
+
+// SpecificPostgresType represents a PostgreSQL data type with its string representation and formatting function.
+// Nullability isn't part of PostgreSQL type names, it's a column constraint.
+type SpecificPostgresType struct {
+	TypeAsString         string
+	Nullable             bool
+	DefaultSQLExpression string // SQL expression filling existing rows when a NOT NULL column is added
+	FormatFunc           func(i any, m arrow.Metadata) (any, error)
+}
+
+// Format formats a value according to the PostgreSQL type's formatting function
+func (t SpecificPostgresType) Format(i any, m arrow.Metadata) (any, error) {
+	return t.FormatFunc(i, m)
+}
+
+func isNestedParent(metadata arrow.Metadata) bool {
+	parentType, _ := warehouse.GetArrowMetadataValue(metadata, metadataParentType)
+	return parentType == "array" || parentType == "struct"
+}
+
+// === PRIMITIVE TYPE MAPPERS ===
+
+// postgresPrimitiveTypeMapper maps an Arrow type to a PostgreSQL type and back. Type names
+// are matched case-insensitively, aliases lists the names information_schema reports.
+// Values nested in JSONB are formatted with jsonFormatFunc if it's set.
+type postgresPrimitiveTypeMapper struct {
+	arrowType      arrow.DataType
+	pgType         SpecificPostgresType
+	aliases        []string
+	jsonFormatFunc func(i any, m arrow.Metadata) (any, error)
+}
+
+func (m *postgresPrimitiveTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificPostgresType, error) {
+	if !arrow.TypeEqual(arrowType.ArrowDataType, m.arrowType) {
+		return SpecificPostgresType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+	if m.jsonFormatFunc != nil && isNestedParent(arrowType.Metadata) {
+		pgType := m.pgType
+		pgType.FormatFunc = m.jsonFormatFunc
+		return pgType, nil
+	}
+	return m.pgType, nil
+}
+
+func (m *postgresPrimitiveTypeMapper) WarehouseToArrow(
+	warehouseType SpecificPostgresType,
+) (warehouse.ArrowType, error) {
+	if warehouseType.Nullable {
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+	}
+	if !strings.EqualFold(warehouseType.TypeAsString, m.pgType.TypeAsString) {
+		found := false
+		for _, alias := range m.aliases {
+			if strings.EqualFold(warehouseType.TypeAsString, alias) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+		}
+	}
+	return warehouse.ArrowType{ArrowDataType: m.arrowType}, nil
+}
+
+// === COMPLEX TYPE MAPPERS ===
+
+// postgresListTypeMapper stores lists, including lists of structs, as JSONB arrays.
+type postgresListTypeMapper struct {
+	SubMapper warehouse.FieldTypeMapper[SpecificPostgresType]
+}
+
+func (m *postgresListTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificPostgresType, error) {
+	listType, ok := arrowType.ArrowDataType.(*arrow.ListType)
+	if !ok {
+		return SpecificPostgresType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+	// Only one level of nesting is supported
+	if isNestedParent(arrowType.Metadata) {
+		return SpecificPostgresType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+
+	elementType, err := m.SubMapper.ArrowToWarehouse(warehouse.ArrowType{
+		ArrowDataType: listType.Elem(),
+		Nullable:      listType.ElemField().Nullable,
+		Metadata:      arrow.NewMetadata([]string{metadataParentType}, []string{"array"}),
+	})
+	if err != nil {
+		return SpecificPostgresType{}, err
+	}
+
+	return SpecificPostgresType{
+		TypeAsString:         jsonbTypeName,
+		DefaultSQLExpression: "'[]'::jsonb",
+		FormatFunc: func(i any, metadata arrow.Metadata) (any, error) {
+			slice := []any{}
+			if i != nil {
+				var ok bool
+				slice, ok = i.([]any)
+				if !ok {
+					return nil, fmt.Errorf("expected []any for array, got %T", i)
+				}
+			}
+
+			result := make([]any, len(slice))
+			for idx, elem := range slice {
+				formatted, err := elementType.Format(elem, metadata)
+				if err != nil {
+					return nil, fmt.Errorf("error formatting array element at index %d: %w", idx, err)
+				}
+				result[idx] = formatted
+			}
+			encoded, err := json.Marshal(result)
+			if err != nil {
+				return nil, fmt.Errorf("error encoding array as JSON: %w", err)
+			}
+			return string(encoded), nil
+		},
+	}, nil
+}
+
+func (m *postgresListTypeMapper) WarehouseToArrow(warehouseType SpecificPostgresType) (warehouse.ArrowType, error) {
+	if warehouseType.Nullable || !strings.EqualFold(warehouseType.TypeAsString, jsonbTypeName) {
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+	}
+	return warehouse.ArrowType{
+		ArrowDataType: arrow.BinaryTypes.String,
+		Metadata:      arrow.NewMetadata([]string{metadataJSONB}, []string{"true"}),
+	}, nil
+}
+
+// postgresStructTypeMapper maps structs nested in lists to JSON objects.
+type postgresStructTypeMapper struct {
+	SubMapper warehouse.FieldTypeMapper[SpecificPostgresType]
+}
+
+func (m *postgresStructTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificPostgresType, error) {
+	structType, ok := arrowType.ArrowDataType.(*arrow.StructType)
+	if !ok {
+		return SpecificPostgresType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+
+	// Structs are only supported as elements of lists, the same as in the other warehouses
+	parentType, _ := warehouse.GetArrowMetadataValue(arrowType.Metadata, metadataParentType)
+	if parentType != "array" {
+		return SpecificPostgresType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+
+	fieldTypes := make([]SpecificPostgresType, 0, len(structType.Fields()))
+	for _, field := range structType.Fields() {
+		if _, isStruct := field.Type.(*arrow.StructType); isStruct {
+			return SpecificPostgresType{}, warehouse.NewUnsupportedMappingErr(field.Type, MapperName)
+		}
+		fieldType, err := m.SubMapper.ArrowToWarehouse(warehouse.ArrowType{
+			ArrowDataType: field.Type,
+			Nullable:      field.Nullable,
+			Metadata:      warehouse.MergeArrowMetadata(field.Metadata, metadataParentType, "struct"),
+		})
+		if err != nil {
+			return SpecificPostgresType{}, fmt.Errorf("error mapping field %s: %w", field.Name, err)
+		}
+		fieldTypes = append(fieldTypes, fieldType)
+	}
+
+	return SpecificPostgresType{
+		TypeAsString: jsonbTypeName,
+		FormatFunc: func(i any, metadata arrow.Metadata) (any, error) {
+			record, ok := i.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("expected map[string]any for struct, got %T", i)
+			}
+			result := make(map[string]any, len(structType.Fields()))
+			for idx, field := range structType.Fields() {
+				value := record[field.Name]
+				if value == nil && !field.Nullable {
+					return nil, fmt.Errorf("null value for non-nullable field %s", field.Name)
+				}
+				formatted, err := fieldTypes[idx].Format(value, metadata)
+				if err != nil {
+					return nil, fmt.Errorf("error formatting field %s: %w", field.Name, err)
+				}
+				result[field.Name] = formatted
+			}
+			return result, nil
+		},
+	}, nil
+}
+
+func (m *postgresStructTypeMapper) WarehouseToArrow(warehouseType SpecificPostgresType) (warehouse.ArrowType, error) {
+	// JSONB columns are read back by the list mapper
+	return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+}
+
+type postgresNullableTypeMapper struct {
+	SubMapper warehouse.FieldTypeMapper[SpecificPostgresType]
+}
+
+func (m *postgresNullableTypeMapper) ArrowToWarehouse(arrowType warehouse.ArrowType) (SpecificPostgresType, error) {
+	if !arrowType.Nullable {
+		return SpecificPostgresType{}, warehouse.NewUnsupportedMappingErr(arrowType.ArrowDataType, MapperName)
+	}
+
+	newInstance := arrowType.Copy()
+	newInstance.Nullable = false
+	innerType, err := m.SubMapper.ArrowToWarehouse(newInstance)
+	if err != nil {
+		return SpecificPostgresType{}, err
+	}
+
+	return SpecificPostgresType{
+		TypeAsString: innerType.TypeAsString,
+		Nullable:     true,
+		FormatFunc: func(i any, metadata arrow.Metadata) (any, error) {
+			if i == nil {
+				return nil, nil
+			}
+			return innerType.Format(i, metadata)
+		},
+	}, nil
+}
+
+func (m *postgresNullableTypeMapper) WarehouseToArrow(warehouseType SpecificPostgresType) (warehouse.ArrowType, error) {
+	if !warehouseType.Nullable {
+		return warehouse.ArrowType{}, warehouse.NewUnsupportedMappingErr(warehouseType, MapperName)
+	}
+
+	innerType := warehouseType
+	innerType.Nullable = false
+	innerArrowType, err := m.SubMapper.WarehouseToArrow(innerType)
+	if err != nil {
+		return warehouse.ArrowType{}, err
+	}
+
+	innerArrowType.Nullable = true
+	return innerArrowType, nil
+}
+
+// === TYPE INSTANCES ===
+
+func formatString(i any, _ arrow.Metadata) (any, error) {
+	v, ok := i.(string)
+	if !ok {
+		return nil, fmt.Errorf("expected string, got %T", i)
+	}
+	return v, nil
+}
+
+func formatBool(i any, _ arrow.Metadata) (any, error) {
+	v, ok := i.(bool)
+	if !ok {
+		return nil, fmt.Errorf("expected bool, got %T", i)
+	}
+	return v, nil
+}
+
+func formatInt64(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case int64:
+		return v, nil
+	case int32:
+		return int64(v), nil
+	case int:
+		return int64(v), nil
+	default:
+		return nil, fmt.Errorf("expected int64-compatible type, got %T", i)
+	}
+}
+
+func formatInt32(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case int32:
+		return v, nil
+	case int64:
+		if v > int64(1<<31-1) || v < int64(-1<<31) {
+			return nil, fmt.Errorf("int64 value %d overflows int32 range", v)
+		}
+		return int32(v), nil
+	case int:
+		if v > int(1<<31-1) || v < int(-1<<31) {
+			return nil, fmt.Errorf("int value %d overflows int32 range", v)
+		}
+		return int32(v), nil
+	default:
+		return nil, fmt.Errorf("expected int32-compatible type, got %T", i)
+	}
+}
+
+func formatFloat64(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case float64:
+		return v, nil
+	case float32:
+		return float64(v), nil
+	default:
+		return nil, fmt.Errorf("expected float64-compatible type, got %T", i)
+	}
+}
+
+func formatFloat32(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case float32:
+		return v, nil
+	case float64:
+		if v > float64(3.4028235e+38) || v < float64(-3.4028235e+38) {
+			return nil, fmt.Errorf("float64 value %g overflows float32 range", v)
+		}
+		return float32(v), nil
+	default:
+		return nil, fmt.Errorf("expected float32-compatible type, got %T", i)
+	}
+}
+
+// formatTimestamp truncates timestamps to seconds, the precision of the Arrow type.
+func formatTimestamp(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case int32:
+		return time.Unix(int64(v), 0).UTC(), nil
+	case int64:
+		return time.Unix(v, 0).UTC(), nil
+	case string:
+		t, err := time.Parse(time.RFC3339, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ISO string format: %w", err)
+		}
+		return t.UTC().Truncate(time.Second), nil
+	case time.Time:
+		return v.UTC().Truncate(time.Second), nil
+	default:
+		return nil, fmt.Errorf("expected int32, int64, ISO string or time.Time, got %T", i)
+	}
+}
+
+func formatJSONTimestamp(i any, m arrow.Metadata) (any, error) {
+	t, err := formatTimestamp(i, m)
+	if err != nil {
+		return nil, err
+	}
+	return t.(time.Time).Format(time.RFC3339), nil
+}
+
+func formatDate(i any, _ arrow.Metadata) (any, error) {
+	switch v := i.(type) {
+	case time.Time:
+		return time.Date(v.Year(), v.Month(), v.Day(), 0, 0, 0, 0, time.UTC), nil
+	case string:
+		t, err := time.Parse(time.DateOnly, v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid date format, expected YYYY-MM-DD: %w", err)
+		}
+		return t, nil
+	default:
+		return nil, fmt.Errorf("expected time.Time or date string, got %T", i)
+	}
+}
+
+func formatJSONDate(i any, m arrow.Metadata) (any, error) {
+	t, err := formatDate(i, m)
+	if err != nil {
+		return nil, err
+	}
+	return t.(time.Time).Format(time.DateOnly), nil
+}
+
+var postgresString = SpecificPostgresType{TypeAsString: "TEXT", DefaultSQLExpression: "''", FormatFunc: formatString}
+
+var postgresInt64 = SpecificPostgresType{TypeAsString: "BIGINT", DefaultSQLExpression: "0", FormatFunc: formatInt64}
+
+var postgresInt32 = SpecificPostgresType{TypeAsString: "INTEGER", DefaultSQLExpression: "0", FormatFunc: formatInt32}
+
+var postgresFloat64 = SpecificPostgresType{
+	TypeAsString:         "DOUBLE PRECISION",
+	DefaultSQLExpression: "0",
+	FormatFunc:           formatFloat64,
+}
+
+var postgresFloat32 = SpecificPostgresType{TypeAsString: "REAL", DefaultSQLExpression: "0", FormatFunc: formatFloat32}
+
+var postgresBool = SpecificPostgresType{TypeAsString: "BOOLEAN", DefaultSQLExpression: "false", FormatFunc: formatBool}
+
+var postgresTimestamp = SpecificPostgresType{
+	TypeAsString:         "TIMESTAMPTZ",
+	DefaultSQLExpression: "'1970-01-01 00:00:00+00'",
+	FormatFunc:           formatTimestamp,
+}
+
+var postgresDate = SpecificPostgresType{
+	TypeAsString:         "DATE",
+	DefaultSQLExpression: "'1970-01-01'",
+	FormatFunc:           formatDate,
+}
+
+// NewFieldTypeMapper creates a mapper that supports PostgreSQL types
+func NewFieldTypeMapper() warehouse.FieldTypeMapper[SpecificPostgresType] {
+	baseMappers := []warehouse.FieldTypeMapper[SpecificPostgresType]{
+		&postgresPrimitiveTypeMapper{arrowType: arrow.BinaryTypes.String, pgType: postgresString},
+		&postgresPrimitiveTypeMapper{arrowType: arrow.PrimitiveTypes.Int64, pgType: postgresInt64},
+		&postgresPrimitiveTypeMapper{arrowType: arrow.PrimitiveTypes.Int32, pgType: postgresInt32},
+		&postgresPrimitiveTypeMapper{arrowType: arrow.PrimitiveTypes.Float64, pgType: postgresFloat64},
+		&postgresPrimitiveTypeMapper{arrowType: arrow.PrimitiveTypes.Float32, pgType: postgresFloat32},
+		&postgresPrimitiveTypeMapper{arrowType: arrow.FixedWidthTypes.Boolean, pgType: postgresBool},
+		&postgresPrimitiveTypeMapper{
+			arrowType:      arrow.FixedWidthTypes.Timestamp_s,
+			pgType:         postgresTimestamp,
+			aliases:        []string{"timestamp with time zone"},
+			jsonFormatFunc: formatJSONTimestamp,
+		},
+		&postgresPrimitiveTypeMapper{
+			arrowType:      arrow.FixedWidthTypes.Date32,
+			pgType:         postgresDate,
+			jsonFormatFunc: formatJSONDate,
+		},
+	}
+
+	// deferred mapper for circular dependency resolution
+	var comprehensiveMapper warehouse.FieldTypeMapper[SpecificPostgresType]
+	deferredMapper := warehouse.NewDeferredMapper(func() warehouse.FieldTypeMapper[SpecificPostgresType] {
+		return comprehensiveMapper
+	})
+
+	allMappers := make([]warehouse.FieldTypeMapper[SpecificPostgresType], 0, 3+len(baseMappers))
+	allMappers = append(allMappers,
+		&postgresNullableTypeMapper{SubMapper: deferredMapper},
+		&postgresListTypeMapper{SubMapper: deferredMapper},
+		&postgresStructTypeMapper{SubMapper: deferredMapper},
+	)
+	allMappers = append(allMappers, baseMappers...)
+
+	comprehensiveMapper = warehouse.NewTypeMapper(allMappers)
+
+	return comprehensiveMapper
+}
@@ -0,0 +1,195 @@
+package postgres
+
+import (
+	"testing"
+	"time"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/d8a-tech/d8a/pkg/warehouse"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldTypeMapper_WarehouseToArrow(t *testing.T) {
+	testCases := []struct {
+		name          string
+		pgType        SpecificPostgresType
+		expected      arrow.DataType
+		expectedJSONB bool
+	}{
+		{name: "text", pgType: SpecificPostgresType{TypeAsString: "text"}, expected: arrow.BinaryTypes.String},
+		{name: "bigint", pgType: SpecificPostgresType{TypeAsString: "bigint"}, expected: arrow.PrimitiveTypes.Int64},
+		{
+			name:     "double precision",
+			pgType:   SpecificPostgresType{TypeAsString: "double precision", Nullable: true},
+			expected: arrow.PrimitiveTypes.Float64,
+		},
+		{
+			name:     "timestamp with time zone",
+			pgType:   SpecificPostgresType{TypeAsString: "timestamp with time zone"},
+			expected: arrow.FixedWidthTypes.Timestamp_s,
+		},
+		{
+			name:          "jsonb",
+			pgType:        SpecificPostgresType{TypeAsString: "jsonb", Nullable: true},
+			expected:      arrow.BinaryTypes.String,
+			expectedJSONB: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// when
+			result, err := NewFieldTypeMapper().WarehouseToArrow(tc.pgType)
+
+			// then
+			require.NoError(t, err)
+			assert.True(t, arrow.TypeEqual(tc.expected, result.ArrowDataType))
+			assert.Equal(t, tc.pgType.Nullable, result.Nullable)
+			isJSONB, _ := warehouse.GetArrowMetadataValue(result.Metadata, metadataJSONB)
+			assert.Equal(t, tc.expectedJSONB, isJSONB == "true")
+		})
+	}
+}
+
+func TestFieldTypeMapper_UnsupportedWarehouseType(t *testing.T) {
+	// when
+	_, err := NewFieldTypeMapper().WarehouseToArrow(SpecificPostgresType{TypeAsString: "USER-DEFINED"})
+
+	// then
+	var unsupportedErr *warehouse.ErrUnsupportedMapping
+	assert.ErrorAs(t, err, &unsupportedErr)
+}
+
+func TestFieldTypeMapper_Format(t *testing.T) {
+	testCases := []struct {
+		name        string
+		arrowType   warehouse.ArrowType
+		input       any
+		expected    any
+		expectError bool
+	}{
+		{
+			name:      "timestamp is truncated to seconds in UTC",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.FixedWidthTypes.Timestamp_s},
+			input:     time.Date(2026, 3, 7, 11, 29, 59, 500, time.FixedZone("CET", 3600)),
+			expected:  time.Date(2026, 3, 7, 10, 29, 59, 0, time.UTC),
+		},
+		{
+			name:      "list of strings",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+			input:     []any{"web", "mobile"},
+			expected:  `["web","mobile"]`,
+		},
+		{
+			name:      "null list of non-nullable column",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.BinaryTypes.String)},
+			input:     nil,
+			expected:  `[]`,
+		},
+		{
+			name:      "null list of nullable column",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.BinaryTypes.String), Nullable: true},
+			input:     nil,
+			expected:  nil,
+		},
+		{
+			name: "list of structs with dates and timestamps",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.StructOf(
+				arrow.Field{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "day", Type: arrow.FixedWidthTypes.Date32, Nullable: true},
+				arrow.Field{Name: "at", Type: arrow.FixedWidthTypes.Timestamp_s},
+			))},
+			input: []any{map[string]any{
+				"name": "purchase",
+				"day":  time.Date(2026, 3, 7, 10, 0, 0, 0, time.UTC),
+				"at":   int64(1772877600),
+			}},
+			expected: `[{"at":"2026-03-07T10:00:00Z","day":"2026-03-07","name":"purchase"}]`,
+		},
+		{
+			name: "struct with missing nullable field",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.StructOf(
+				arrow.Field{Name: "key", Type: arrow.BinaryTypes.String, Nullable: true},
+				arrow.Field{Name: "value", Type: arrow.PrimitiveTypes.Float64, Nullable: true},
+			))},
+			input:    []any{map[string]any{"key": "campaign"}},
+			expected: `[{"key":"campaign","value":null}]`,
+		},
+		{
+			name: "struct with missing non-nullable field",
+			arrowType: warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.StructOf(
+				arrow.Field{Name: "key", Type: arrow.BinaryTypes.String},
+			))},
+			input:       []any{map[string]any{}},
+			expectError: true,
+		},
+		{
+			name:        "list element of wrong type",
+			arrowType:   warehouse.ArrowType{ArrowDataType: arrow.ListOf(arrow.PrimitiveTypes.Int64)},
+			input:       []any{"1"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			// given
+			pgType, err := NewFieldTypeMapper().ArrowToWarehouse(tc.arrowType)
+			require.NoError(t, err)
+
+			// when
+			result, err := pgType.Format(tc.input, arrow.Metadata{})
+
+			// then
+			if tc.expectError {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.expected, result)
+		})
+	}
+}
+
+func TestPartitionIntervalBounds(t *testing.T) {
+	at := time.Date(2026, 12, 31, 23, 30, 0, 0, time.FixedZone("CET", 3600))
+
+	testCases := []struct {
+		interval       PartitionInterval
+		expectedFrom   time.Time
+		expectedTo     time.Time
+		expectedSuffix string
+	}{
+		{
+			interval:       PartitionIntervalDay,
+			expectedFrom:   time.Date(2026, 12, 31, 0, 0, 0, 0, time.UTC),
+			expectedTo:     time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedSuffix: "p20261231",
+		},
+		{
+			interval:       PartitionIntervalMonth,
+			expectedFrom:   time.Date(2026, 12, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:     time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedSuffix: "p202612",
+		},
+		{
+			interval:       PartitionIntervalYear,
+			expectedFrom:   time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedTo:     time.Date(2027, 1, 1, 0, 0, 0, 0, time.UTC),
+			expectedSuffix: "p2026",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(string(tc.interval), func(t *testing.T) {
+			// when
+			from, to, suffix := tc.interval.bounds(at)
+
+			// then
+			assert.Equal(t, tc.expectedFrom, from)
+			assert.Equal(t, tc.expectedTo, to)
+			assert.Equal(t, tc.expectedSuffix, suffix)
+		})
+	}
+}
@@ -0,0 +1,50 @@
+package postgres
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+)
+
+// PostgreSQL error codes, see https://www.postgresql.org/docs/current/errcodes-appendix.html
+const (
+	pgCodeUniqueViolation = "23505"
+	pgCodeDuplicateColumn = "42701"
+	pgCodeDuplicateTable  = "42P07"
+)
+
+// quoteIdentifier wraps a PostgreSQL identifier in double quotes, escaping any embedded
+// double quotes by doubling them.
+func quoteIdentifier(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
+
+// quoteFullTableName quotes a schema.table reference for use in DDL/DML.
+func quoteFullTableName(schema, table string) string {
+	return fmt.Sprintf("%s.%s", quoteIdentifier(schema), quoteIdentifier(table))
+}
+
+func hasErrorCode(err error, codes ...string) bool {
+	var pgErr *pgconn.PgError
+	if !errors.As(err, &pgErr) {
+		return false
+	}
+	for _, code := range codes {
+		if pgErr.Code == code {
+			return true
+		}
+	}
+	return false
+}
+
+// isAlreadyExistsErr reports whether creating a relation failed because it exists. Relations
+// created concurrently can also fail on the unique index of their type in the catalog.
+func isAlreadyExistsErr(err error) bool {
+	return hasErrorCode(err, pgCodeDuplicateTable, pgCodeUniqueViolation)
+}
+
+func isColumnAlreadyExistsErr(err error) bool {
+	return hasErrorCode(err, pgCodeDuplicateColumn)
+}
@@ -107,3 +107,68 @@ func NewTypeMapper[T SpecificWarehouseType](
 		Types: types,
 	}
 }
+
+// IsStringMap tells whether the Arrow type is a map of strings to strings. Maps of strings
+// to strings and of strings to float64s are the only map types supported by the drivers.
+func IsStringMap(dataType arrow.DataType) bool {
+	mapType, ok := dataType.(*arrow.MapType)
+	return ok && arrow.TypeEqual(mapType.KeyType(), arrow.BinaryTypes.String) &&
+		arrow.TypeEqual(mapType.ItemType(), arrow.BinaryTypes.String)
+}
+
+// IsFloat64Map tells whether the Arrow type is a map of strings to float64s.
+func IsFloat64Map(dataType arrow.DataType) bool {
+	mapType, ok := dataType.(*arrow.MapType)
+	return ok && arrow.TypeEqual(mapType.KeyType(), arrow.BinaryTypes.String) &&
+		arrow.TypeEqual(mapType.ItemType(), arrow.PrimitiveTypes.Float64)
+}
+
+// ToStringMap converts a value of a map of strings column to a Go map. Nil values, of the
+// column or of its entries, are left out.
+func ToStringMap(i any) (map[string]string, error) {
+	switch v := i.(type) {
+	case nil:
+		return map[string]string{}, nil
+	case map[string]string:
+		return v, nil
+	case map[string]any:
+		result := make(map[string]string, len(v))
+		for key, value := range v {
+			switch value := value.(type) {
+			case nil:
+			case string:
+				result[key] = value
+			default:
+				return nil, fmt.Errorf("expected string value for map key %s, got %T", key, value)
+			}
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("expected map[string]any for map, got %T", i)
+	}
+}
+
+// ToFloat64Map converts a value of a map of float64s column to a Go map. Nil values, of the
+// column or of its entries, are left out.
+func ToFloat64Map(i any) (map[string]float64, error) {
+	switch v := i.(type) {
+	case nil:
+		return map[string]float64{}, nil
+	case map[string]float64:
+		return v, nil
+	case map[string]any:
+		result := make(map[string]float64, len(v))
+		for key, value := range v {
+			switch value := value.(type) {
+			case nil:
+			case float64:
+				result[key] = value
+			default:
+				return nil, fmt.Errorf("expected float64 value for map key %s, got %T", key, value)
+			}
+		}
+		return result, nil
+	default:
+		return nil, fmt.Errorf("expected map[string]any for map, got %T", i)
+	}
+}